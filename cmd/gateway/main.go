@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -13,8 +14,11 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/fermilabs/fermi-api-gateway/internal/clientip"
 	"github.com/fermilabs/fermi-api-gateway/internal/config"
 	"github.com/fermilabs/fermi-api-gateway/internal/database"
 	"github.com/fermilabs/fermi-api-gateway/internal/health"
@@ -25,20 +29,30 @@ import (
 )
 
 func main() {
+	// Remember which variables come from the real environment; on SIGHUP .env is
+	// re-read and must not override them
+	processEnv := environKeys()
+
 	// Load .env file if it exists (ignore error if file doesn't exist)
 	_ = godotenv.Load()
 
 	// Load configuration from environment
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
 
-	// Initialize logger
-	var logger *zap.Logger
-	var err error
+	// Initialize logger (the level can be changed on SIGHUP via LOG_LEVEL)
+	zapConfig := zap.NewDevelopmentConfig()
 	if cfg.Server.Env == "production" {
-		logger, err = zap.NewProduction()
-	} else {
-		logger, err = zap.NewDevelopment()
+		zapConfig = zap.NewProductionConfig()
+	}
+	if cfg.Server.LogLevel != "" {
+		level, _ := zapcore.ParseLevel(cfg.Server.LogLevel) // Checked by Validate
+		zapConfig.Level.SetLevel(level)
 	}
+	logger, err := zapConfig.Build()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -50,92 +64,300 @@ func main() {
 	registry := prometheus.NewRegistry()
 	m.MustRegister(registry)
 
+	// Background context for long-running helpers, canceled on shutdown
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	defer bgCancel()
+
 	// Initialize database connection (optional - gracefully handle if not configured)
+	// The repository starts without a connection and a background goroutine connects
+	// (and reconnects), so the gateway serves immediately and endpoints recover once it's up
 	var repo *database.Repository
-	if cfg.Database.Host != "" && cfg.Database.DBName != "" {
-		db, err := database.NewDB(cfg.Database)
+	var readyChecks []health.Check
+	if cfg.Database.Configured() {
+		repo = database.NewRepository(nil,
+			database.WithLogger(logger),
+			database.WithQueryDuration(m.DBQueryDuration),
+			database.WithSlowQueryLog(logger, time.Duration(cfg.Database.SlowQueryThresholdMs)*time.Millisecond),
+		)
+		defer repo.Close()
+
+		go repo.KeepConnected(bgCtx, cfg.Database, time.Duration(cfg.Database.ReconnectIntervalMs)*time.Millisecond)
+
+		readyChecks = append(readyChecks, health.Check{
+			Name: "database",
+			Check: func() error {
+				if !repo.Available() {
+					return database.ErrDatabaseUnavailable
+				}
+				return nil
+			},
+		})
+	} else {
+		logger.Info("Database not configured - transaction endpoints will have limited functionality")
+	}
+
+	// Client IP resolution shared by rate limiting, logging and proxies;
+	// X-Forwarded-For is only trusted from the configured proxies
+	ipResolver, err := clientip.NewResolver(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+
+	// Per-IP rate limits are shared across replicas through Redis when configured,
+	// otherwise each instance enforces them in memory
+	var redisClient *redis.Client
+	if cfg.RateLimit.RedisURL != "" {
+		opts, err := redis.ParseURL(cfg.RateLimit.RedisURL)
 		if err != nil {
-			logger.Warn("Database connection failed - transaction endpoints will have limited functionality", zap.Error(err))
+			logger.Fatal("Invalid RATE_LIMIT_REDIS_URL", zap.Error(err))
+		}
+		redisClient = redis.NewClient(opts)
+		defer redisClient.Close()
+
+		pingCtx, pingCancel := context.WithTimeout(bgCtx, 5*time.Second)
+		if err := redisClient.Ping(pingCtx).Err(); err != nil {
+			logger.Warn("Redis unreachable at startup - rate limits fail open until it recovers", zap.Error(err))
 		} else {
-			defer db.Close()
-			repo = database.NewRepository(db)
-			logger.Info("Database connected successfully")
+			logger.Info("Using Redis for distributed rate limiting")
 		}
-	} else {
-		logger.Info("Database not configured - transaction endpoints will have limited functionality")
+		pingCancel()
+	}
+	newRateLimiter := func(name string, rpm int) ratelimit.Limiter {
+		if redisClient != nil {
+			return ratelimit.NewRedisRateLimiter(redisClient, name, float64(rpm)/60, rpm)
+		}
+		return ratelimit.NewIPRateLimiter(float64(rpm)/60, rpm)
 	}
 
 	// Initialize proxies
-	rollupProxy := proxy.NewHTTPProxy(cfg.Backend.RollupURL, 15*time.Second)
-	continuumRestProxy := proxy.NewHTTPProxy(cfg.Backend.ContinuumRestURL, 15*time.Second)
+	rollupProxy := proxy.NewHTTPProxy(cfg.Backend.RollupURL, cfg.Timeouts.For("rollup"), ipResolver,
+		proxy.WithPathRewrite(cfg.Backend.PathRewriteFor("rollup")))
+	continuumRestProxy := proxy.NewHTTPProxy(cfg.Backend.ContinuumRestURL, cfg.Timeouts.For("continuum_rest"), ipResolver,
+		proxy.WithPathRewrite(cfg.Backend.PathRewriteFor("continuum_rest")))
+
+	primarySources := make(map[string]proxy.Source, len(cfg.Backend.PrimarySources))
+	for method, source := range cfg.Backend.PrimarySources {
+		primarySources[method] = proxy.Source(source)
+	}
+
+	// Decimals were validated as integers by cfg.Validate
+	marketMetadata := make(map[string]proxy.MarketMetadata)
+	for market, symbol := range cfg.Markets.Symbols {
+		meta := marketMetadata[market]
+		meta.Symbol = symbol
+		marketMetadata[market] = meta
+	}
+	for market, decimals := range cfg.Markets.Decimals {
+		n, _ := strconv.Atoi(decimals)
+		meta := marketMetadata[market]
+		meta.Decimals = &n
+		marketMetadata[market] = meta
+	}
 
-	continuumGrpcProxy, err := proxy.NewGRPCProxy(cfg.Backend.ContinuumGrpcURL, repo, cfg.Backend.ContinuumRestURL, logger)
+	grpcOpts := []proxy.GRPCProxyOption{
+		proxy.WithConnResetCounter(m.GRPCConnResets),
+		proxy.WithCallLatency(m.GRPCCallDuration),
+		proxy.WithMaxMessageSize(cfg.Backend.GrpcMaxRecvMsgBytes, cfg.Backend.GrpcMaxSendMsgBytes),
+		proxy.WithMaxStreams(cfg.Backend.MaxTickStreams),
+		proxy.WithActiveStreamsGauge(m.ActiveTickStreams),
+		proxy.WithDegradedCounter(m.StatusDegraded),
+		proxy.WithPrimarySources(primarySources),
+		proxy.WithJSONStyle(proxy.JSONStyle(cfg.Server.JSONFieldStyle)),
+		proxy.WithSubmitConfirmation(time.Duration(cfg.Backend.SubmitConfirmWaitMs) * time.Millisecond),
+		proxy.WithNonceCache(time.Duration(cfg.Backend.NonceWindowMs)*time.Millisecond, cfg.Backend.NonceCacheSize),
+		proxy.WithSignatureScheme(proxy.SignatureScheme(cfg.Backend.SignatureScheme)),
+	}
+
+	// Optional bounded queue smoothing submission bursts to the sequencer
+	if cfg.Backend.SubmitQueueSize > 0 {
+		submitQueue := proxy.NewSubmitQueue(cfg.Backend.SubmitQueueSize, cfg.Backend.SubmitQueueWorkers, m.SubmitQueueDepth)
+		defer submitQueue.Close()
+		grpcOpts = append(grpcOpts, proxy.WithSubmitQueue(submitQueue))
+	}
+
+	continuumGrpcProxy, err := proxy.NewGRPCProxy(cfg.Backend.ContinuumGrpcURL, repo, cfg.Backend.ContinuumRestURL, logger, grpcOpts...)
 	if err != nil {
 		logger.Fatal("Failed to initialize Continuum gRPC proxy", zap.Error(err))
 	}
 	defer continuumGrpcProxy.Close()
 
+	// Stop routing traffic here while the sequencer is unreachable
+	readyChecks = append(readyChecks, health.Check{
+		Name: "continuum_grpc",
+		Check: func() error {
+			ctx, cancel := context.WithTimeout(bgCtx, 2*time.Second)
+			defer cancel()
+			return continuumGrpcProxy.HealthCheck(ctx)
+		},
+		Required: true,
+	})
+
+	// Rate limiters and CORS origins are built up front so SIGHUP can update them in place
+	globalLimiter := ratelimit.NewGlobalLimiter(float64(cfg.RateLimit.GlobalRPS), globalBurst(cfg.RateLimit))
+	rollupLimiter := newRateLimiter("rollup", cfg.RateLimit.RollupRPM)
+	continuumLimiter := newRateLimiter("continuum", cfg.RateLimit.ContinuumRestRPM)
+	submitLimiter := newRateLimiter("submit", cfg.RateLimit.SubmitRPM)
+	corsOrigins := middleware.NewAllowedOrigins(cfg.CORS.AllowedOrigins)
+	corsOpts := []middleware.CORSOption{
+		middleware.WithCredentials(cfg.CORS.AllowCredentials),
+		middleware.WithPreflightMaxAge(time.Duration(cfg.CORS.MaxAgeSeconds) * time.Second),
+	}
+
+	reloader := &reloadable{
+		current:   cfg,
+		logger:    logger,
+		logLevel:  zapConfig.Level,
+		cors:      corsOrigins,
+		global:    globalLimiter,
+		rollup:    rollupLimiter,
+		continuum: continuumLimiter,
+		submit:    submitLimiter,
+	}
+
+	// Count and log oversized responses (LARGE_RESPONSE_BYTES)
+	largeResponses := middleware.WithLargeResponseWarning(cfg.Server.LargeResponseBytes, logger)
+
+	// Count requests slower than the latency SLO (SLO_LATENCY_MS)
+	sloViolations := middleware.WithSLO(time.Duration(cfg.Server.SLOLatencyMs) * time.Millisecond)
+
+	// Request headers are only logged when enabled, and always with secrets redacted
+	loggingOpts := []middleware.LoggingOption{middleware.WithoutLoggingPaths(cfg.Server.ExcludedPaths...)}
+	if cfg.Server.LogHeaders {
+		loggingOpts = append(loggingOpts, middleware.WithHeaders(middleware.NewHeaderRedactor(cfg.Server.RedactedHeaders...)))
+	}
+
+	// Health checks and scrapes are left out of logs and metrics (EXCLUDED_PATHS)
+	metricsExclusions := middleware.WithoutMetricsPaths(cfg.Server.ExcludedPaths...)
+
+	// Latency observations link to their trace ID when exemplars are enabled (METRICS_EXEMPLARS)
+	metricsOpts := []middleware.MetricsOption{largeResponses, sloViolations, metricsExclusions}
+	if cfg.Server.MetricsExemplars {
+		metricsOpts = append(metricsOpts, middleware.WithExemplars())
+	}
+
+	// Tracks in-flight requests so shutdown can report what was drained
+	drain := middleware.NewDrainTracker()
+
 	// Create router
 	r := chi.NewRouter()
 
 	// Apply global middleware (order matters!)
-	r.Use(middleware.RequestID)                    // Generate request IDs first
-	r.Use(middleware.Recovery(logger))             // Recover from panics
-	r.Use(middleware.Logging(logger))              // Log all requests
-	r.Use(middleware.Metrics(m))                   // Record metrics
-	r.Use(middleware.CORS(cfg.CORS.AllowedOrigins)) // Handle CORS
+	r.Use(middleware.RequestID)                                   // Generate request IDs first
+	r.Use(drain.Middleware)                                       // Count in-flight requests for shutdown
+	r.Use(middleware.Recovery(logger))                            // Recover from panics
+	r.Use(middleware.Logging(logger, ipResolver, loggingOpts...)) // Log all requests
+	r.Use(middleware.WarnUntrustedForwarding(logger, ipResolver)) // Warn once if TRUSTED_PROXIES looks missing
+	r.Use(middleware.Metrics(m, metricsOpts...))                  // Record metrics
+	r.Use(middleware.CORS(corsOrigins, corsOpts...))              // Handle CORS
+
+	// Debug body sampling is opt-in (BODY_SAMPLE_PATHS, BODY_SAMPLE_TOKEN) and never
+	// covers the submit endpoints
+	if len(cfg.Server.BodySamplePaths) > 0 || cfg.Server.BodySampleToken != "" {
+		r.Use(middleware.BodySampling(logger, cfg.Server.BodySampleMaxBytes,
+			middleware.WithSamplePaths(cfg.Server.BodySamplePaths...),
+			middleware.WithSampleToken(cfg.Server.BodySampleToken),
+			middleware.WithSampleDeniedPaths(cfg.Server.BodySampleDenied...),
+		))
+		logger.Warn("Request/response body sampling enabled",
+			zap.Strings("paths", cfg.Server.BodySamplePaths),
+			zap.Bool("token", cfg.Server.BodySampleToken != ""),
+		)
+	}
 
 	// Metrics endpoint (no auth for now)
-	r.Get("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP)
+	r.Get("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: cfg.Server.MetricsExemplars, // Exemplars are only exposed in OpenMetrics
+	}).ServeHTTP)
 
 	// Health check endpoints (no rate limiting)
 	r.Get("/health", health.Handler())
-	r.Get("/ready", health.ReadyHandler())
+	r.Get("/ready", health.ReadyHandler(readyChecks...))
+
+	// Per-route timeouts (ROUTE_TIMEOUTS); slow handlers get 504 Gateway Timeout
+	timeout := func(route string) func(http.Handler) http.Handler {
+		return middleware.Timeout(cfg.Timeouts.For(route))
+	}
 
 	// API v1 routes - clean, versioned endpoints
 	r.Route("/api/v1", func(r chi.Router) {
+		// Reject unexpected Host headers before they are reflected to backends
+		// (health and metrics stay reachable by IP for probes and scrapers)
+		r.Use(middleware.AllowedHosts(cfg.Server.AllowedHosts))
+
+		// Gateway-wide limit, checked before the per-IP limits of each route group
+		// (unlimited unless RATE_LIMIT_GLOBAL_RPS is set)
+		r.Use(ratelimit.GlobalMiddleware(globalLimiter, m.GlobalRateLimitHits.Inc))
+
 		// Rollup API - 1000 req/min = ~16.67 req/sec
-		rollupLimiter := ratelimit.NewIPRateLimiter(float64(cfg.RateLimit.RollupRPM)/60, cfg.RateLimit.RollupRPM)
 		r.Route("/rollup", func(r chi.Router) {
-			r.Use(ratelimit.Middleware(rollupLimiter))
+			r.Use(ratelimit.Middleware(rollupLimiter, ipResolver.ClientIP))
 
 			// Candles endpoint - queries database directly
 			if repo != nil {
 				candlesHandler := proxy.NewCandlesHandler(repo, logger)
-				r.Get("/markets/{marketId}/candles", candlesHandler.GetMarketCandles())
+				r.With(timeout("candles")).Get("/markets/{marketId}/candles", candlesHandler.GetMarketCandles())
+
+				// Markets with prices, plus symbols/decimals from MARKET_SYMBOLS and MARKET_DECIMALS
+				marketsHandler := proxy.NewMarketsHandler(repo, logger, proxy.WithMarketMetadata(marketMetadata))
+				r.With(timeout("markets")).Get("/markets", marketsHandler.ListMarkets())
+				r.With(timeout("markets")).Get("/markets/{marketId}/price", marketsHandler.GetPriceAt())
+
+				// Live per-market prices; browsers must come from an allowed CORS origin
+				priceFeed := proxy.NewPriceFeedHandler(repo, logger, proxy.WithOriginCheck(corsOrigins.Allowed))
+				r.Get("/ws/prices", priceFeed.HandlePriceFeed()) // Long-lived WebSocket, no timeout
 			}
 
-			// Catch-all proxy handler for other rollup routes
-			r.Handle("/*", rollupProxy.Handler())
+			// Catch-all proxy handler for other rollup routes (bounded by the proxy's own timeout)
+			// Methods not allowed by PROXY_ALLOWED_METHODS are rejected before proxying
+			r.With(middleware.AllowedMethods(cfg.Backend.AllowedMethodsFor("rollup"))).Handle("/*", rollupProxy.Handler())
 		})
 
 		// Continuum API - unified endpoint (frontend doesn't need to know about REST vs gRPC)
 		// Use higher rate limit (2000 req/min) since this combines both REST and gRPC traffic
-		continuumLimiter := ratelimit.NewIPRateLimiter(float64(cfg.RateLimit.ContinuumRestRPM)/60, cfg.RateLimit.ContinuumRestRPM)
 		r.Route("/continuum", func(r chi.Router) {
-			r.Use(ratelimit.Middleware(continuumLimiter))
+			r.Use(ratelimit.Middleware(continuumLimiter, ipResolver.ClientIP))
+
+			// Submissions are far more expensive than reads, so they get a stricter per-IP
+			// limit layered on top of the route group limit (default 120 req/min)
+			submit := r.With(ratelimit.Middleware(submitLimiter, ipResolver.ClientIP), timeout("submit"))
+			// Batches are forwarded with a longer deadline than single submissions
+			batch := r.With(ratelimit.Middleware(submitLimiter, ipResolver.ClientIP), timeout("batch"))
 
 			// Transaction endpoints (new - with database support)
-			r.Get("/tx/recent", continuumGrpcProxy.HandleGetRecentTransactions())
-			r.Handle("/tx/*", continuumGrpcProxy.HandleGetTransactionByHash())
-			r.Post("/tx", continuumGrpcProxy.HandleSubmitTransaction())
-			r.Post("/tx/batch", continuumGrpcProxy.HandleSubmitBatch())
+			tx := r.With(timeout("tx"))
+			tx.Get("/tx/recent", continuumGrpcProxy.HandleGetRecentTransactions())
+			tx.Get("/tx/stats", continuumGrpcProxy.HandleGetTransactionStats())
+			tx.Handle("/tx/*", continuumGrpcProxy.HandleGetTransactionByHash())
+			submit.Post("/tx", continuumGrpcProxy.HandleSubmitTransaction())
+			batch.Post("/tx/batch", continuumGrpcProxy.HandleSubmitBatch())
+			// Streamed NDJSON submissions are chunked per line; the buffering route timeout would break streaming
+			r.With(ratelimit.Middleware(submitLimiter, ipResolver.ClientIP)).Post("/tx/ndjson", continuumGrpcProxy.HandleSubmitNDJSON())
 
 			// Legacy gRPC endpoints (keep for backward compatibility)
-			r.Post("/submit-transaction", continuumGrpcProxy.HandleSubmitTransaction())
-			r.Post("/submit-batch", continuumGrpcProxy.HandleSubmitBatch())
-			r.Get("/stream-ticks", continuumGrpcProxy.HandleStreamTicks())
+			submit.Post("/submit-transaction", continuumGrpcProxy.HandleSubmitTransaction())
+			batch.Post("/submit-batch", continuumGrpcProxy.HandleSubmitBatch())
+			r.Get("/stream-ticks", continuumGrpcProxy.HandleStreamTicks()) // Long-lived SSE, no timeout
 
 			// Unified status endpoint - merges REST /status + gRPC GetStatus
-			r.Get("/status", continuumGrpcProxy.HandleUnifiedStatus(cfg.Backend.ContinuumRestURL))
+			r.With(timeout("status")).Get("/status", continuumGrpcProxy.HandleUnifiedStatus(cfg.Backend.ContinuumRestURL))
 
 			// Other gRPC endpoints
-			r.Get("/transaction", continuumGrpcProxy.HandleGetTransaction())
-			r.Get("/tick", continuumGrpcProxy.HandleGetTick())
-			r.Get("/chain-state", continuumGrpcProxy.HandleGetChainState())
+			r.With(timeout("transaction")).Get("/transaction", continuumGrpcProxy.HandleGetTransaction())
+			r.With(timeout("tick")).Get("/tick", continuumGrpcProxy.HandleGetTick())
+			r.With(timeout("chain_state")).Get("/chain-state", continuumGrpcProxy.HandleGetChainState())
+			r.With(timeout("status")).Get("/mempool", continuumGrpcProxy.HandleGetMempool())
+
+			// Tick queries - served from the database written by the tick ingester
+			if repo != nil {
+				ticksHandler := proxy.NewTicksHandler(repo, logger)
+				r.With(timeout("ticks")).Get("/ticks", ticksHandler.GetTicks())
+				r.With(timeout("ticks")).Get("/ticks/stats", ticksHandler.GetTickStats())
+				r.With(timeout("ticks")).Get("/ticks/by-batch-hash/{hash}", ticksHandler.GetTickByBatchHash())
+				r.With(timeout("ticks")).Get("/tick/{number}/transactions", ticksHandler.GetTransactionsByTick())
+			}
 
 			// REST-only endpoints - proxy to REST backend (catch-all for any unmatched routes)
-			r.Handle("/*", continuumRestProxy.Handler())
+			r.With(middleware.AllowedMethods(cfg.Backend.AllowedMethodsFor("continuum_rest"))).Handle("/*", continuumRestProxy.Handler())
 		})
 	})
 
@@ -170,6 +392,24 @@ func main() {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 
+	// Reload rate limits, CORS origins and log level on SIGHUP
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := reloadEnvFile(processEnv); err != nil && !os.IsNotExist(err) {
+				logger.Error("Failed to re-read .env, keeping current configuration", zap.Error(err))
+				continue
+			}
+			next := config.Load()
+			if err := next.Validate(); err != nil {
+				logger.Error("Invalid configuration on reload, keeping current configuration", zap.Error(err))
+				continue
+			}
+			reloader.Apply(next)
+		}
+	}()
+
 	// Block until we receive a signal or an error
 	select {
 	case err := <-serverErrors:
@@ -184,10 +424,30 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		inFlight := drain.StartDrain()
+		m.ShutdownInFlight.Set(float64(inFlight))
+		logger.Info("Draining in-flight requests", zap.Int64("in_flight", inFlight))
+		drainStart := time.Now()
+
 		// Attempt graceful shutdown
-		if err := srv.Shutdown(ctx); err != nil {
+		shutdownErr := srv.Shutdown(ctx)
+		if shutdownErr != nil {
 			srv.Close()
-			logger.Fatal("Could not gracefully shutdown the server", zap.Error(err))
+		}
+
+		// Long-lived streams and WebSockets are not waited for by Shutdown, so they count as aborted
+		stats := drain.Stats()
+		m.ShutdownDrained.WithLabelValues("completed").Add(float64(stats.Completed))
+		m.ShutdownDrained.WithLabelValues("aborted").Add(float64(stats.Aborted))
+		logger.Info("Shutdown drain finished",
+			zap.Int64("in_flight_at_start", stats.InFlightAtStart),
+			zap.Int64("completed", stats.Completed),
+			zap.Int64("aborted", stats.Aborted),
+			zap.Duration("duration", time.Since(drainStart)),
+		)
+
+		if shutdownErr != nil {
+			logger.Fatal("Could not gracefully shutdown the server", zap.Error(shutdownErr))
 		}
 
 		logger.Info("Server stopped gracefully")
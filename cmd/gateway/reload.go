@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/config"
+	"github.com/fermilabs/fermi-api-gateway/internal/middleware"
+	"github.com/fermilabs/fermi-api-gateway/internal/ratelimit"
+)
+
+// reloadable holds the components whose settings can change on SIGHUP without a restart:
+// rate limits, CORS origins and the log level
+type reloadable struct {
+	current  *config.Config
+	logger   *zap.Logger
+	logLevel zap.AtomicLevel
+	cors     *middleware.AllowedOrigins
+
+	global    *ratelimit.GlobalLimiter
+	rollup    ratelimit.Limiter
+	continuum ratelimit.Limiter
+	submit    ratelimit.Limiter
+}
+
+// Apply switches the reloadable settings to next. Settings that need a restart
+// (ports, backends, database, ...) are left as they are and logged if they changed
+func (rl *reloadable) Apply(next *config.Config) {
+	prev := rl.current
+
+	rl.rollup.SetRate(float64(next.RateLimit.RollupRPM)/60, next.RateLimit.RollupRPM)
+	rl.continuum.SetRate(float64(next.RateLimit.ContinuumRestRPM)/60, next.RateLimit.ContinuumRestRPM)
+	rl.submit.SetRate(float64(next.RateLimit.SubmitRPM)/60, next.RateLimit.SubmitRPM)
+	rl.global.SetRate(float64(next.RateLimit.GlobalRPS), globalBurst(next.RateLimit))
+
+	rl.cors.Set(next.CORS.AllowedOrigins)
+
+	if next.Server.LogLevel != "" {
+		if level, err := zapcore.ParseLevel(next.Server.LogLevel); err == nil {
+			rl.logLevel.SetLevel(level)
+		}
+	}
+
+	// Keep the running values of non-reloadable settings so later reloads compare against them
+	applied, ignored := splitReload(prev, next)
+	for _, setting := range ignored {
+		rl.logger.Warn("Configuration change requires a restart and was ignored", zap.String("setting", setting))
+	}
+	rl.current = &applied
+
+	rl.logger.Info("Configuration reloaded",
+		zap.Int("rollup_rpm", next.RateLimit.RollupRPM),
+		zap.Int("continuum_rpm", next.RateLimit.ContinuumRestRPM),
+		zap.Int("submit_rpm", next.RateLimit.SubmitRPM),
+		zap.Int("global_rps", next.RateLimit.GlobalRPS),
+		zap.Strings("allowed_origins", next.CORS.AllowedOrigins),
+		zap.String("log_level", rl.logLevel.String()),
+	)
+}
+
+// reloadedSettings are the settings Apply switches at runtime, as Section.Field
+// of config.Config. Every other setting needs a restart
+var reloadedSettings = map[string]bool{
+	"Server.LogLevel":            true,
+	"CORS.AllowedOrigins":        true,
+	"RateLimit.RollupRPM":        true,
+	"RateLimit.ContinuumRestRPM": true,
+	"RateLimit.SubmitRPM":        true,
+	"RateLimit.GlobalRPS":        true,
+	"RateLimit.GlobalBurst":      true,
+}
+
+// splitReload compares prev and next setting by setting. It returns prev with
+// next's reloaded settings applied, and the changed settings that were ignored
+func splitReload(prev, next *config.Config) (config.Config, []string) {
+	applied := *prev
+	appliedValue := reflect.ValueOf(&applied).Elem()
+	prevValue, nextValue := reflect.ValueOf(prev).Elem(), reflect.ValueOf(next).Elem()
+
+	var ignored []string
+	for i := range prevValue.NumField() {
+		section := prevValue.Type().Field(i)
+		if !section.IsExported() || section.Type.Kind() != reflect.Struct {
+			continue
+		}
+		for j := range section.Type.NumField() {
+			field := section.Type.Field(j)
+			if !field.IsExported() {
+				continue
+			}
+			before, after := prevValue.Field(i).Field(j), nextValue.Field(i).Field(j)
+			if reflect.DeepEqual(before.Interface(), after.Interface()) {
+				continue
+			}
+			name := section.Name + "." + field.Name
+			if reloadedSettings[name] {
+				appliedValue.Field(i).Field(j).Set(after)
+			} else {
+				ignored = append(ignored, name)
+			}
+		}
+	}
+	return applied, ignored
+}
+
+// globalBurst returns the gateway-wide burst, defaulting to the RPS
+func globalBurst(cfg config.RateLimitConfig) int {
+	if cfg.GlobalBurst > 0 {
+		return cfg.GlobalBurst
+	}
+	return cfg.GlobalRPS
+}
+
+// environKeys returns the names of all variables in the process environment
+func environKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		if key, _, ok := strings.Cut(kv, "="); ok {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// reloadEnvFile re-reads .env, overriding previously loaded values but never
+// variables that were set in the real process environment at startup
+func reloadEnvFile(processEnv map[string]bool) error {
+	values, err := godotenv.Read()
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if !processEnv[key] {
+			os.Setenv(key, value)
+		}
+	}
+	return nil
+}
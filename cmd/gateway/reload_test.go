@@ -0,0 +1,207 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/config"
+	"github.com/fermilabs/fermi-api-gateway/internal/middleware"
+	"github.com/fermilabs/fermi-api-gateway/internal/ratelimit"
+)
+
+func newTestReloadable(cfg *config.Config) (*reloadable, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	return &reloadable{
+		current:   cfg,
+		logger:    zap.New(core),
+		logLevel:  zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		cors:      middleware.NewAllowedOrigins(cfg.CORS.AllowedOrigins),
+		global:    ratelimit.NewGlobalLimiter(float64(cfg.RateLimit.GlobalRPS), globalBurst(cfg.RateLimit)),
+		rollup:    ratelimit.NewIPRateLimiter(float64(cfg.RateLimit.RollupRPM)/60, cfg.RateLimit.RollupRPM),
+		continuum: ratelimit.NewIPRateLimiter(float64(cfg.RateLimit.ContinuumRestRPM)/60, cfg.RateLimit.ContinuumRestRPM),
+		submit:    ratelimit.NewIPRateLimiter(float64(cfg.RateLimit.SubmitRPM)/60, cfg.RateLimit.SubmitRPM),
+	}, logs
+}
+
+// ignoredSettings returns the settings logged as needing a restart
+func ignoredSettings(logs *observer.ObservedLogs) []string {
+	var settings []string
+	for _, entry := range logs.FilterMessage("Configuration change requires a restart and was ignored").All() {
+		settings = append(settings, entry.ContextMap()["setting"].(string))
+	}
+	slices.Sort(settings)
+	return settings
+}
+
+func TestReloadable_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(c *config.Config)
+		wantIgnored []string
+		check       func(t *testing.T, rl *reloadable)
+	}{
+		{
+			name:   "nothing changed",
+			change: func(c *config.Config) {},
+		},
+		{
+			name: "rate limits",
+			change: func(c *config.Config) {
+				c.RateLimit.RollupRPM = 7
+				c.RateLimit.ContinuumRestRPM = 8
+				c.RateLimit.SubmitRPM = 9
+			},
+			check: func(t *testing.T, rl *reloadable) {
+				if got := []int{rl.rollup.Limit(), rl.continuum.Limit(), rl.submit.Limit()}; !slices.Equal(got, []int{7, 8, 9}) {
+					t.Errorf("limits = %v, want [7 8 9]", got)
+				}
+				if rl.current.RateLimit.SubmitRPM != 9 {
+					t.Errorf("current SubmitRPM = %d, want 9", rl.current.RateLimit.SubmitRPM)
+				}
+			},
+		},
+		{
+			name: "global rate limit",
+			change: func(c *config.Config) {
+				c.RateLimit.GlobalRPS = 1
+				c.RateLimit.GlobalBurst = 1
+			},
+			check: func(t *testing.T, rl *reloadable) {
+				allowed := 0
+				for range 10 {
+					if rl.global.Allow() {
+						allowed++
+					}
+				}
+				if allowed > 1 {
+					t.Errorf("global limiter allowed %d of 10 requests, want at most the burst of 1", allowed)
+				}
+			},
+		},
+		{
+			name:   "CORS origins",
+			change: func(c *config.Config) { c.CORS.AllowedOrigins = []string{"https://app.example.com"} },
+			check: func(t *testing.T, rl *reloadable) {
+				if !rl.cors.Listed("https://app.example.com") {
+					t.Error("new origin not allowed")
+				}
+			},
+		},
+		{
+			name:   "log level",
+			change: func(c *config.Config) { c.Server.LogLevel = "error" },
+			check: func(t *testing.T, rl *reloadable) {
+				if rl.logLevel.Level() != zapcore.ErrorLevel {
+					t.Errorf("log level = %v, want error", rl.logLevel.Level())
+				}
+			},
+		},
+		{
+			name: "server settings",
+			change: func(c *config.Config) {
+				c.Server.Port = "9999"
+				c.Server.JSONFieldStyle = "camelCase"
+				c.Server.ExcludedPaths = []string{"/health", "/ready"}
+				c.Server.LogHeaders = !c.Server.LogHeaders
+				c.Server.SLOLatencyMs = 1234
+			},
+			wantIgnored: []string{
+				"Server.ExcludedPaths", "Server.JSONFieldStyle", "Server.LogHeaders", "Server.Port", "Server.SLOLatencyMs",
+			},
+			check: func(t *testing.T, rl *reloadable) {
+				if rl.current.Server.JSONFieldStyle == "camelCase" || rl.current.Server.Port == "9999" {
+					t.Errorf("ignored server settings were applied: %+v", rl.current.Server)
+				}
+			},
+		},
+		{
+			name: "route timeouts and markets",
+			change: func(c *config.Config) {
+				c.Timeouts.Routes = map[string]time.Duration{"batch": time.Minute}
+				c.Markets.Symbols = map[string]string{"m1": "BTC-USDC"}
+			},
+			wantIgnored: []string{"Markets.Symbols", "Timeouts.Routes"},
+		},
+		{
+			name: "backend, database, redis and CORS options",
+			change: func(c *config.Config) {
+				c.Backend.RollupURL = "http://other:3000"
+				c.Database.Host = "db.internal"
+				c.RateLimit.RedisURL = "redis://cache:6379"
+				c.RateLimit.ContinuumGrpcRPM++
+				c.CORS.MaxAgeSeconds++
+			},
+			wantIgnored: []string{
+				"Backend.RollupURL", "CORS.MaxAgeSeconds", "Database.Host", "RateLimit.ContinuumGrpcRPM", "RateLimit.RedisURL",
+			},
+		},
+		{
+			name: "applied and ignored together",
+			change: func(c *config.Config) {
+				c.RateLimit.RollupRPM = 5
+				c.Server.TrustedProxies = []string{"10.0.0.0/8"}
+			},
+			wantIgnored: []string{"Server.TrustedProxies"},
+			check: func(t *testing.T, rl *reloadable) {
+				if rl.rollup.Limit() != 5 || rl.current.RateLimit.RollupRPM != 5 {
+					t.Errorf("rollup limit = %d, current = %d, want 5", rl.rollup.Limit(), rl.current.RateLimit.RollupRPM)
+				}
+				if len(rl.current.Server.TrustedProxies) != 0 {
+					t.Errorf("current TrustedProxies = %v, want unchanged", rl.current.Server.TrustedProxies)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := config.Load()
+			rl, logs := newTestReloadable(prev)
+
+			next := *prev
+			tt.change(&next)
+
+			// Ignored settings keep their running values, so they are reported again on the next reload
+			for reload := 1; reload <= 2; reload++ {
+				logs.TakeAll()
+				rl.Apply(&next)
+
+				if got := ignoredSettings(logs); !slices.Equal(got, tt.wantIgnored) {
+					t.Errorf("reload %d: ignored = %v, want %v", reload, got, tt.wantIgnored)
+				}
+				if logs.FilterMessage("Configuration reloaded").Len() != 1 {
+					t.Errorf("reload %d: missing \"Configuration reloaded\" log", reload)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, rl)
+			}
+		})
+	}
+}
+
+func TestGlobalBurst(t *testing.T) {
+	tests := []struct {
+		name       string
+		rps, burst int
+		want       int
+	}{
+		{"explicit burst", 100, 20, 20},
+		{"defaults to RPS", 100, 0, 100},
+		{"negative burst", 100, -1, 100},
+		{"disabled", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := globalBurst(config.RateLimitConfig{GlobalRPS: tt.rps, GlobalBurst: tt.burst}); got != tt.want {
+				t.Errorf("globalBurst() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,16 +16,27 @@ import (
 	"github.com/fermilabs/fermi-api-gateway/internal/stream"
 	"github.com/fermilabs/fermi-api-gateway/internal/writer"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "Read and parse ticks without persisting them (same as OUTPUT_MODE=null)")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := ingestion.LoadConfig()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+	cfg := ingestion.LoadConfig()
+
+	// The flag overrides OUTPUT_MODE before validation so DATABASE_URL isn't required
+	if *dryRun {
+		cfg.SetDryRun()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -43,21 +56,29 @@ func main() {
 		zap.Uint64("start_tick", cfg.StartTick),
 	)
 
+	for _, warning := range cfg.Warnings() {
+		logger.Warn("Configuration warning", zap.String("warning", warning))
+	}
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Initialize components
-	reader := stream.NewGRPCReader(
-		cfg.ContinuumGRPCURL,
-		stream.WithStartTick(cfg.StartTick),
-		stream.WithLogger(logger),
+	parserInstance := parser.NewProtobufParser(
+		parser.WithAllowZeroTickNumber(cfg.AllowZeroTick),
+		parser.WithAllowEmptyProof(cfg.AllowEmptyProof),
 	)
 
-	parserInstance := parser.NewProtobufParser()
-
 	var writerInstance ingestion.Writer
-	if cfg.OutputMode == "console" {
+	var nullWriter *writer.NullWriter
+	startTick := cfg.StartTick // Overridden by a stored checkpoint
+	if cfg.OutputMode == "null" {
+		// Dry run: parse and count ticks, never persist
+		nullWriter = writer.NewNullWriter()
+		writerInstance = nullWriter
+		logger.Info("Using null writer (dry run) - ticks will not be persisted")
+	} else if cfg.OutputMode == "console" {
 		// Console writer for debugging
 		format := writer.FormatJSON
 		switch cfg.OutputFormat {
@@ -65,9 +86,36 @@ func main() {
 			format = writer.FormatCompact
 		case "table":
 			format = writer.FormatTable
+		case "csv":
+			format = writer.FormatCSV
+		case "tsv":
+			format = writer.FormatTSV
 		}
-		writerInstance = writer.NewConsoleWriter(writer.WithFormat(format))
+		writerInstance = writer.NewConsoleWriter(
+			writer.WithFormat(format),
+			writer.WithVerboseTable(cfg.VerboseTable),
+			writer.WithColor(cfg.ColorOutput),
+			writer.WithPayloadBase64(cfg.PayloadBase64),
+			writer.WithSummary(cfg.SummaryEvery, cfg.SummaryInterval),
+		)
 		logger.Info("Using console writer", zap.String("format", cfg.OutputFormat))
+	} else if cfg.OutputMode == "file" {
+		// JSON-lines archive files, rotated by size and age
+		fileWriter, err := writer.NewFileWriter(cfg.OutputPath,
+			writer.WithMaxFileBytes(cfg.FileMaxBytes),
+			writer.WithRotateInterval(cfg.FileRotateInterval),
+			writer.WithCompression(cfg.FileCompress),
+		)
+		if err != nil {
+			logger.Fatal("Failed to create file writer", zap.Error(err))
+		}
+		writerInstance = fileWriter
+		logger.Info("Using file writer",
+			zap.String("output_path", cfg.OutputPath),
+			zap.Int64("max_bytes", cfg.FileMaxBytes),
+			zap.Duration("rotate_interval", cfg.FileRotateInterval),
+			zap.Bool("compress", cfg.FileCompress),
+		)
 	} else {
 		// TimescaleDB writer for production
 		pool, err := connectDatabase(ctx, cfg, logger)
@@ -76,26 +124,85 @@ func main() {
 		}
 		defer pool.Close()
 
-		writerInstance = writer.NewTimescaleWriter(pool, logger)
+		timescaleWriter := writer.NewTimescaleWriter(pool, logger, writer.WithCheckpoint(cfg.CheckpointName))
+		writerInstance = timescaleWriter
 		logger.Info("Using TimescaleDB writer",
 			zap.Int("max_connections", cfg.MaxConnections),
 			zap.Int("batch_size", cfg.BatchSize),
+			zap.String("checkpoint", cfg.CheckpointName),
+		)
+
+		if cfg.CheckpointName != "" && !cfg.IsBackfill() {
+			checkpoint, ok, err := timescaleWriter.Checkpoint(ctx)
+			if err != nil {
+				logger.Fatal("Failed to load checkpoint", zap.Error(err))
+			}
+			if ok {
+				// Replay ticks that may have been in flight; the writer skips stored ones
+				startTick = 1
+				if replay := cfg.CheckpointReplay(); checkpoint > replay {
+					startTick = checkpoint - replay + 1
+				}
+				logger.Info("Resuming from checkpoint",
+					zap.Uint64("checkpoint", checkpoint),
+					zap.Uint64("start_tick", startTick),
+				)
+			}
+		}
+	}
+
+	var reader ingestion.StreamReader
+	if cfg.IsBackfill() {
+		// Backfill a fixed range via GetTick; the pipeline exits once the range is written
+		reader = stream.NewRangeReader(cfg.ContinuumGRPCURL, cfg.BackfillStartTick, cfg.BackfillEndTick, logger)
+		logger.Info("Running in backfill mode",
+			zap.Uint64("backfill_start_tick", cfg.BackfillStartTick),
+			zap.Uint64("backfill_end_tick", cfg.BackfillEndTick),
+		)
+	} else {
+		reader = stream.NewGRPCReader(
+			cfg.ContinuumGRPCURL,
+			stream.WithStartTick(startTick),
+			stream.WithResumeOnReconnect(cfg.StreamResume),
+			stream.WithLogger(logger),
 		)
 	}
 
+	// Isolated registry for the pipeline's metrics, served by the health server
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
 	// Create pipeline
 	pipelineConfig := ingestion.PipelineConfig{
 		BufferSize:    cfg.BufferSize,
 		WorkerCount:   cfg.WorkerCount,
 		BatchSize:     cfg.BatchSize,
+		MaxBatchBytes: cfg.MaxBatchBytes,
 		FlushInterval: cfg.FlushInterval,
+		CheckNonces:   cfg.CheckNonces,
+		DedupWindow:   cfg.DedupWindow,
+		MaxTicks:      cfg.MaxTicks,
+		DrainTimeout:  cfg.DrainTimeout,
+		RetryWrites:   cfg.CheckpointName != "",
+		Adaptive: ingestion.AdaptiveBatchConfig{
+			Enabled:             cfg.AdaptiveBatch,
+			MinBatchSize:        cfg.MinBatchSize,
+			MaxBatchSize:        cfg.MaxBatchSize,
+			TargetWriteDuration: cfg.TargetWriteDuration,
+		},
+		Backpressure:          ingestion.BackpressurePolicy(cfg.BackpressurePolicy),
+		BackpressureWarnAfter: cfg.BackpressureWarnAfter,
+		Registry:              registry,
 	}
 
 	pipeline := ingestion.NewPipeline(reader, parserInstance, writerInstance, logger, pipelineConfig)
 	defer pipeline.Close()
 
 	// Start health check server
-	healthServer := startHealthServer(cfg.HealthCheckPort, logger)
+	healthServer := startHealthServer(cfg.HealthCheckPort, pipeline, cfg.ReadyStaleness, registry, logger)
 	defer func() {
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer shutdownCancel()
@@ -118,26 +225,37 @@ func main() {
 	case sig := <-sigCh:
 		logger.Info("Received shutdown signal", zap.String("signal", sig.String()))
 		cancel() // Trigger graceful shutdown
+
+		// Wait for pipeline to drain (with a margin over its own drain timeout)
+		shutdownTimeout := cfg.DrainTimeout + 5*time.Second
+		shutdownTimer := time.NewTimer(shutdownTimeout)
+		defer shutdownTimer.Stop()
+
+		select {
+		case err := <-pipelineDone:
+			if err != nil {
+				logger.Error("Pipeline shutdown with error", zap.Error(err))
+				os.Exit(1)
+			}
+			logger.Info("Pipeline shut down successfully")
+		case <-shutdownTimer.C:
+			logger.Warn("Pipeline shutdown timed out", zap.Duration("timeout", shutdownTimeout))
+			os.Exit(1)
+		}
 	case err := <-pipelineDone:
+		// Pipeline finished on its own (e.g. MAX_TICKS reached or stream ended)
 		if err != nil {
 			logger.Error("Pipeline error", zap.Error(err))
+			os.Exit(1)
 		}
+		logger.Info("Pipeline finished")
 	}
 
-	// Wait for pipeline to finish (with timeout)
-	shutdownTimer := time.NewTimer(30 * time.Second)
-	defer shutdownTimer.Stop()
-
-	select {
-	case err := <-pipelineDone:
-		if err != nil {
-			logger.Error("Pipeline shutdown with error", zap.Error(err))
-			os.Exit(1)
-		}
-		logger.Info("Pipeline shut down successfully")
-	case <-shutdownTimer.C:
-		logger.Warn("Pipeline shutdown timed out after 30 seconds")
-		os.Exit(1)
+	if nullWriter != nil {
+		logger.Info("Dry run summary",
+			zap.Uint64("ticks_processed", nullWriter.TickCount()),
+			zap.Uint64("transactions_processed", nullWriter.TransactionCount()),
+		)
 	}
 
 	logger.Info("Tick Ingestion Service stopped")
@@ -193,27 +311,10 @@ func connectDatabase(ctx context.Context, cfg *ingestion.Config, logger *zap.Log
 }
 
 // startHealthServer starts an HTTP server for health checks and metrics.
-func startHealthServer(port int, logger *zap.Logger) *http.Server {
-	mux := http.NewServeMux()
-
-	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	})
-
-	// Readiness endpoint
-	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ready"}`))
-	})
-
-	// Prometheus metrics endpoint
-	mux.Handle("/metrics", promhttp.Handler())
-
+func startHealthServer(port int, pipeline *ingestion.Pipeline, staleness time.Duration, registry *prometheus.Registry, logger *zap.Logger) *http.Server {
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
-		Handler: mux,
+		Handler: healthMux(pipeline, staleness, registry),
 	}
 
 	go func() {
@@ -228,3 +329,34 @@ func startHealthServer(port int, logger *zap.Logger) *http.Server {
 
 	return server
 }
+
+// healthMux routes the health, readiness and metrics endpoints.
+func healthMux(pipeline *ingestion.Pipeline, staleness time.Duration, registry *prometheus.Registry) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// Health check endpoint
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	// Readiness endpoint: fails when the stream has ended or gone stale
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := pipeline.Ready(staleness); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{
+				"status": "not_ready",
+				"reason": err.Error(),
+			})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ready"}`))
+	})
+
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	return mux
+}
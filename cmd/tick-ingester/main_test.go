@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+	"github.com/fermilabs/fermi-api-gateway/internal/ingestion"
+	"github.com/fermilabs/fermi-api-gateway/internal/writer"
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// listReader emits the given ticks, then ends the stream.
+type listReader struct {
+	ticks []uint64
+}
+
+func (r *listReader) Read(ctx context.Context) (<-chan *pb.Tick, <-chan error) {
+	ticks := make(chan *pb.Tick)
+	errs := make(chan error)
+	go func() {
+		defer close(ticks)
+		defer close(errs)
+		for _, n := range r.ticks {
+			select {
+			case ticks <- &pb.Tick{TickNumber: n}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ticks, errs
+}
+
+func (r *listReader) Close() error { return nil }
+
+// numberParser turns a protobuf tick into a domain tick carrying only its number.
+type numberParser struct{}
+
+func (numberParser) Parse(tick *pb.Tick) (*domain.Tick, error) {
+	return &domain.Tick{TickNumber: tick.GetTickNumber()}, nil
+}
+
+// newTestPipeline returns a pipeline over reader, with its metrics on registry.
+func newTestPipeline(reader ingestion.StreamReader, registry *prometheus.Registry) *ingestion.Pipeline {
+	return ingestion.NewPipeline(reader, numberParser{}, writer.NewNullWriter(), zap.NewNop(), ingestion.PipelineConfig{
+		WorkerCount: 2,
+		Registry:    registry,
+	})
+}
+
+// get sends GET path to handler.
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestHealthMux(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"health", "/health", http.StatusOK, `{"status":"ok"}`},
+		{"metrics", "/metrics", http.StatusOK, "# TYPE tick_ingester_ticks_total counter"},
+		{"ready after the stream ended", "/ready", http.StatusServiceUnavailable, `{"reason":"stream closed","status":"not_ready"}`},
+		{"unknown path", "/debug", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			p := newTestPipeline(&listReader{ticks: []uint64{1}}, registry)
+			if err := p.Run(context.Background()); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			rec := get(healthMux(p, time.Minute, registry), tt.path)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+// documentedMetrics returns the metric names listed in the README's Metrics section.
+func documentedMetrics(t *testing.T) []string {
+	t.Helper()
+	f, err := os.Open("README.md")
+	if err != nil {
+		t.Fatalf("open README: %v", err)
+	}
+	defer f.Close()
+
+	item := regexp.MustCompile("^- `(tick_ingester_[a-z_]+)")
+	var names []string
+	inSection := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			inSection = line == "### Metrics"
+			continue
+		}
+		if m := item.FindStringSubmatch(line); inSection && m != nil {
+			names = append(names, m[1])
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestHealthMux_MetricsDocumented(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	p := newTestPipeline(&listReader{ticks: []uint64{1, 2, 3}}, registry)
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Labeled metrics only appear once written, which the run above does
+	var served []string
+	for _, line := range strings.Split(get(healthMux(p, time.Minute, registry), "/metrics").Body.String(), "\n") {
+		if name, ok := strings.CutPrefix(line, "# TYPE tick_ingester_"); ok {
+			served = append(served, "tick_ingester_"+strings.Fields(name)[0])
+		}
+	}
+	slices.Sort(served)
+
+	if documented := documentedMetrics(t); !slices.Equal(served, documented) {
+		t.Errorf("/metrics serves %v\nREADME documents %v", served, documented)
+	}
+}
@@ -0,0 +1,133 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver determines the originating client IP of a request.
+// Forwarding headers (X-Forwarded-For, X-Real-IP) are only honored when the
+// direct peer is a trusted proxy; otherwise clients could spoof them.
+// A nil Resolver trusts no proxies.
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// NewResolver creates a resolver that trusts the given proxies.
+// Entries may be CIDRs ("10.0.0.0/8") or single IPs ("127.0.0.1").
+func NewResolver(trustedProxies []string) (*Resolver, error) {
+	r := &Resolver{}
+
+	for _, entry := range trustedProxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				bits = 32
+			}
+			r.trusted = append(r.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		r.trusted = append(r.trusted, network)
+	}
+
+	return r, nil
+}
+
+// ClientIP returns the client IP for the request.
+// If the direct peer is a trusted proxy, X-Forwarded-For is walked from right
+// to left and the first address that isn't a trusted proxy is returned,
+// falling back to X-Real-IP. Otherwise the peer address itself is returned.
+func (r *Resolver) ClientIP(req *http.Request) string {
+	peer := RemoteIP(req)
+	if !r.isTrusted(peer) {
+		return peer
+	}
+
+	// X-Forwarded-For: "client, proxy1, proxy2"; each proxy appends the peer it saw,
+	// so only the entries added by our trusted proxies (rightmost) are reliable
+	if xff := req.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		ips := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if ip == "" {
+				continue
+			}
+			if !r.isTrusted(ip) {
+				return ip
+			}
+			// Every hop was a trusted proxy; the leftmost is the best we know
+			if i == 0 {
+				return ip
+			}
+		}
+	}
+
+	if xri := strings.TrimSpace(req.Header.Get("X-Real-IP")); xri != "" {
+		return xri
+	}
+
+	return peer
+}
+
+// IgnoresForwarding reports whether req carries X-Forwarded-For or X-Real-IP
+// that ClientIP ignores because the direct peer isn't a trusted proxy.
+func (r *Resolver) IgnoresForwarding(req *http.Request) bool {
+	if req.Header.Get("X-Forwarded-For") == "" && req.Header.Get("X-Real-IP") == "" {
+		return false
+	}
+	return !r.isTrusted(RemoteIP(req))
+}
+
+// isTrusted reports whether ip belongs to a trusted proxy network.
+func (r *Resolver) isTrusted(ip string) bool {
+	if r == nil || len(r.trusted) == 0 {
+		return false
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range r.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteIP returns the direct peer IP from RemoteAddr, without consulting
+// any forwarding headers.
+func RemoteIP(req *http.Request) string {
+	ip := req.RemoteAddr
+
+	// RemoteAddr includes port, strip it
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+
+	// If SplitHostPort fails, return as-is (might be IP without port)
+	if ip != "" {
+		return ip
+	}
+
+	// Fallback for empty RemoteAddr
+	return "unknown"
+}
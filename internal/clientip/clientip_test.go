@@ -0,0 +1,181 @@
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRequest(remoteAddr string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+func TestResolver_ClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "no forwarding headers",
+			trusted:    []string{"10.0.0.1"},
+			remoteAddr: "203.0.113.7:5000",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer with X-Forwarded-For",
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer with X-Real-IP",
+			trusted:    []string{"10.0.0.1"},
+			remoteAddr: "203.0.113.7:5000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy",
+			trusted:    []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted chain",
+			trusted:    []string{"10.0.0.1", "10.0.0.2"},
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 10.0.0.2"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "spoofed leftmost entry",
+			trusted:    []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "every hop trusted",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "10.1.1.1, 10.2.2.2"},
+			want:       "10.1.1.1",
+		},
+		{
+			name:       "CIDR",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.20.30.40:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 10.9.9.9"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "outside CIDR",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "11.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "11.0.0.1",
+		},
+		{
+			name:       "IPv6 peer and client",
+			trusted:    []string{"fd00::/8"},
+			remoteAddr: "[fd00::1]:5000",
+			headers:    map[string]string{"X-Forwarded-For": "2001:db8::1"},
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "untrusted IPv6 peer",
+			trusted:    []string{"fd00::1"},
+			remoteAddr: "[2001:db8::2]:5000",
+			headers:    map[string]string{"X-Forwarded-For": "2001:db8::1"},
+			want:       "2001:db8::2",
+		},
+		{
+			name:       "X-Real-IP fallback",
+			trusted:    []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "RemoteAddr without port",
+			remoteAddr: "203.0.113.7",
+			want:       "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewResolver(tt.trusted)
+			if err != nil {
+				t.Fatalf("NewResolver() error = %v", err)
+			}
+			if got := r.ClientIP(newRequest(tt.remoteAddr, tt.headers)); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_NilTrustsNothing(t *testing.T) {
+	var r *Resolver
+	req := newRequest("10.0.0.1:5000", map[string]string{"X-Forwarded-For": "198.51.100.1"})
+	if got := r.ClientIP(req); got != "10.0.0.1" {
+		t.Errorf("ClientIP() = %q, want the peer", got)
+	}
+}
+
+func TestNewResolver_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		wantErr bool
+	}{
+		{"empty entries skipped", []string{"", " "}, false},
+		{"IP and CIDR", []string{"127.0.0.1", " 10.0.0.0/8 ", "::1"}, false},
+		{"bad IP", []string{"not-an-ip"}, true},
+		{"bad CIDR", []string{"10.0.0.0/99"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewResolver(tt.trusted); (err != nil) != tt.wantErr {
+				t.Errorf("NewResolver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolver_IgnoresForwarding(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       bool
+	}{
+		{"no headers", "203.0.113.7:5000", nil, false},
+		{"X-Forwarded-For from untrusted peer", "203.0.113.7:5000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, true},
+		{"X-Real-IP from untrusted peer", "203.0.113.7:5000", map[string]string{"X-Real-IP": "198.51.100.1"}, true},
+		{"trusted peer", "10.0.0.1:5000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, false},
+	}
+
+	r, err := NewResolver([]string{"10.0.0.1"})
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.IgnoresForwarding(newRequest(tt.remoteAddr, tt.headers)); got != tt.want {
+				t.Errorf("IgnoresForwarding() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package clock
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fired returns the indexes of the timers whose channel has a value, draining them.
+func fired(timers []Timer) []int {
+	var got []int
+	for i, timer := range timers {
+		select {
+		case <-timer.C():
+			got = append(got, i)
+		default:
+		}
+	}
+	return got
+}
+
+func TestFake_Timers(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []time.Duration // One timer each
+		steps     []time.Duration // Advance by each in turn
+		want      [][]int         // Timers fired by each step
+	}{
+		{"not due", []time.Duration{time.Second}, []time.Duration{999 * time.Millisecond}, [][]int{nil}},
+		{"exactly due", []time.Duration{time.Second}, []time.Duration{time.Second}, [][]int{{0}}},
+		{"fires once", []time.Duration{time.Second}, []time.Duration{time.Second, time.Hour}, [][]int{{0}, nil}},
+		{
+			"in deadline order",
+			[]time.Duration{3 * time.Second, time.Second, 2 * time.Second},
+			[]time.Duration{time.Second, 1500 * time.Millisecond, time.Second},
+			[][]int{{1}, {2}, {0}},
+		},
+		{"several at once", []time.Duration{time.Second, 2 * time.Second}, []time.Duration{time.Minute}, [][]int{{0, 1}}},
+		{"non-positive fires immediately", []time.Duration{0, -time.Second}, []time.Duration{0}, [][]int{{0, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := NewFake(epoch)
+			var timers []Timer
+			for _, d := range tt.durations {
+				timers = append(timers, clk.NewTimer(d))
+			}
+
+			for i, step := range tt.steps {
+				clk.Advance(step)
+				if got := fired(timers); !slices.Equal(got, tt.want[i]) {
+					t.Errorf("step %d: fired %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFake_StopReset(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      func(timer Timer) bool
+		wantPending bool // Returned by the action
+		wantTimers  int
+		wantFired   bool // After advancing one second
+	}{
+		{"stop", func(timer Timer) bool { return timer.Stop() }, true, 0, false},
+		{"stop twice", func(timer Timer) bool { timer.Stop(); return timer.Stop() }, false, 0, false},
+		{"reset later", func(timer Timer) bool { return timer.Reset(time.Minute) }, true, 1, false},
+		{"reset sooner", func(timer Timer) bool { return timer.Reset(time.Second) }, true, 1, true},
+		{"reset after stop", func(timer Timer) bool { timer.Stop(); return timer.Reset(time.Second) }, false, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := NewFake(epoch)
+			timer := clk.NewTimer(10 * time.Second)
+
+			if got := tt.action(timer); got != tt.wantPending {
+				t.Errorf("action returned %v, want %v", got, tt.wantPending)
+			}
+			if got := clk.Timers(); got != tt.wantTimers {
+				t.Errorf("Timers() = %d, want %d", got, tt.wantTimers)
+			}
+			clk.Advance(time.Second)
+			if got := len(fired([]Timer{timer})) == 1; got != tt.wantFired {
+				t.Errorf("fired = %v, want %v", got, tt.wantFired)
+			}
+		})
+	}
+}
+
+func TestFake_Now(t *testing.T) {
+	clk := NewFake(epoch)
+	start := clk.Now()
+
+	tests := []struct {
+		name      string
+		move      func()
+		wantNow   time.Time
+		wantSince time.Duration
+	}{
+		{"start", func() {}, epoch, 0},
+		{"advance", func() { clk.Advance(time.Minute) }, epoch.Add(time.Minute), time.Minute},
+		{"set", func() { clk.Set(epoch.Add(time.Hour)) }, epoch.Add(time.Hour), time.Hour},
+		{"set backwards", func() { clk.Set(epoch.Add(-time.Second)) }, epoch.Add(-time.Second), -time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.move()
+			if got := clk.Now(); !got.Equal(tt.wantNow) {
+				t.Errorf("Now() = %s, want %s", got, tt.wantNow)
+			}
+			if got := clk.Since(start); got != tt.wantSince {
+				t.Errorf("Since(start) = %s, want %s", got, tt.wantSince)
+			}
+		})
+	}
+}
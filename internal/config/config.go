@@ -1,8 +1,17 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 )
 
 // Config holds all application configuration
@@ -12,34 +21,134 @@ type Config struct {
 	Backend   BackendConfig
 	Database  DatabaseConfig
 	RateLimit RateLimitConfig
+	Timeouts  TimeoutConfig
+	Markets   MarketsConfig
+
+	// Env vars that were set but couldn't be parsed (their defaults were used)
+	malformed []error
+}
+
+// intEnvKeys lists the integer env vars, so malformed values can be reported by Validate
+var intEnvKeys = []string{
+	"LARGE_RESPONSE_BYTES",
+	"SLO_LATENCY_MS",
+	"CORS_MAX_AGE_SECONDS",
+	"BODY_SAMPLE_MAX_BYTES",
+	"DB_SLOW_QUERY_THRESHOLD_MS",
+	"DB_CONNECT_RETRIES",
+	"DB_CONNECT_RETRY_INTERVAL_MS",
+	"DB_RECONNECT_INTERVAL_MS",
+	"RATE_LIMIT_ROLLUP",
+	"RATE_LIMIT_CONTINUUM_GRPC",
+	"RATE_LIMIT_CONTINUUM_REST",
+	"RATE_LIMIT_SUBMIT",
+	"RATE_LIMIT_GLOBAL_RPS",
+	"RATE_LIMIT_GLOBAL_BURST",
+	"SUBMIT_QUEUE_SIZE",
+	"SUBMIT_QUEUE_WORKERS",
+	"SUBMIT_CONFIRM_WAIT_MS",
+	"NONCE_WINDOW_MS",
+	"NONCE_CACHE_SIZE",
+	"MAX_TICK_STREAMS",
+	"GRPC_MAX_RECV_MSG_BYTES",
+	"GRPC_MAX_SEND_MSG_BYTES",
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port string
-	Env  string // development, staging, production
+	Port           string
+	Env            string   // development, staging, production
+	TrustedProxies []string // CIDRs/IPs whose X-Forwarded-For is honored for client IPs
+	AllowedHosts   []string // Host headers accepted on API routes (empty = any host)
+	LogLevel       string   // debug, info, warn, error (empty = info in production, debug otherwise)
+
+	LargeResponseBytes int  // Responses above this size are counted and logged (0 = disabled)
+	SLOLatencyMs       int  // Requests slower than this count as SLO violations (0 = disabled)
+	MetricsExemplars   bool // Attach trace ID exemplars to request latency (served in OpenMetrics format)
+
+	JSONFieldStyle string // Field names in protobuf JSON responses: snake_case or camelCase
+
+	LogHeaders      bool     // Include (redacted) request headers in request logs
+	RedactedHeaders []string // Headers redacted in logs in addition to Authorization, Cookie, X-API-Key, ...
+
+	ExcludedPaths []string // Path prefixes (e.g. /health, /metrics) left out of request logs and HTTP metrics
+
+	BodySamplePaths    []string // Path prefixes whose request/response bodies are logged for debugging
+	BodySampleToken    string   // Requests with X-Debug-Body set to this have their bodies logged (empty = disabled)
+	BodySampleMaxBytes int      // Bytes of each body logged when sampling
+	BodySampleDenied   []string // Path prefixes never sampled, on top of the submit endpoints
 }
 
 // CORSConfig holds CORS middleware configuration
 type CORSConfig struct {
-	AllowedOrigins []string
+	AllowedOrigins   []string // "*" allows any origin, only without credentials
+	AllowCredentials bool     // Send Access-Control-Allow-Credentials (cookies, Authorization)
+	MaxAgeSeconds    int      // How long browsers may cache preflight responses
 }
 
 // BackendConfig holds backend service URLs
 type BackendConfig struct {
-	RollupURL         string
-	ContinuumGrpcURL  string
-	ContinuumRestURL  string
+	RollupURL        string
+	ContinuumGrpcURL string
+	ContinuumRestURL string
+
+	PrimarySources map[string]string // Backend ("grpc" or "rest") queried first per unified method, e.g. "status"
+
+	// HTTP methods forwarded per catch-all proxy route ("rollup", "continuum_rest");
+	// routes not listed forward every method
+	AllowedMethods map[string][]string
+
+	// Backend path prefixes per catch-all proxy route: StripPrefixes are removed
+	// from the forwarded path, then AddPrefixes are prepended
+	StripPrefixes map[string]string
+	AddPrefixes   map[string]string
+
+	SubmitQueueSize     int // Submissions queued for the sequencer before rejecting with 503 (0 = no queue)
+	SubmitQueueWorkers  int // Concurrent submissions sent from the queue
+	SubmitConfirmWaitMs int // How long single submissions wait for tick inclusion before responding (0 = don't wait)
+
+	NonceWindowMs  int // How long a submitted (public key, nonce) pair is rejected as a replay (0 = no replay check)
+	NonceCacheSize int // Most (public key, nonce) pairs remembered for the replay check
+
+	SignatureScheme string // Signature/public key lengths submissions are checked against: ed25519 or secp256k1 (empty = unchecked)
+
+	MaxTickStreams int // Concurrent SSE tick streams (each holds a gRPC stream) before 503 (0 = unlimited)
+
+	GrpcMaxRecvMsgBytes int // Largest gRPC response accepted from the sequencer
+	GrpcMaxSendMsgBytes int // Largest gRPC request sent to the sequencer
+}
+
+// AllowedMethodsFor returns the methods forwarded by the named proxy route (nil = all)
+func (c BackendConfig) AllowedMethodsFor(route string) []string {
+	return c.AllowedMethods[route]
+}
+
+// PathRewriteFor returns the prefixes stripped from and added to paths forwarded
+// by the named proxy route (empty = unchanged)
+func (c BackendConfig) PathRewriteFor(route string) (strip, add string) {
+	return c.StripPrefixes[route], c.AddPrefixes[route]
 }
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
+	URL      string // Full connection URL (DATABASE_URL); takes precedence over the individual fields
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+
+	SlowQueryThresholdMs   int // Log queries slower than this (0 = disabled)
+	ConnectRetries         int // Connection attempts after the first one (in the background at startup)
+	ConnectRetryIntervalMs int // Initial backoff between attempts (doubles each retry)
+	ReconnectIntervalMs    int // Background health check / reconnect interval
+}
+
+// MarketsConfig holds market metadata served by the markets endpoint, keyed by market ID
+type MarketsConfig struct {
+	Symbols  map[string]string // e.g. "BTC-USDC"
+	Decimals map[string]string // Price decimals, validated as integers
 }
 
 // RateLimitConfig holds rate limiting configuration per route
@@ -47,37 +156,358 @@ type RateLimitConfig struct {
 	RollupRPM        int // Requests per minute
 	ContinuumGrpcRPM int
 	ContinuumRestRPM int
+	SubmitRPM        int // Stricter per-IP limit for transaction submission routes, on top of ContinuumRestRPM
+	GlobalRPS        int // Gateway-wide requests per second across all IPs (0 = disabled)
+	GlobalBurst      int // Gateway-wide burst size (0 = same as GlobalRPS)
+
+	RedisURL string // Shares per-IP limits across replicas when set (in-memory per instance otherwise)
+}
+
+// TimeoutConfig holds request timeouts per route, keyed by route name
+// (e.g. "candles", "status", "submit", "rollup")
+type TimeoutConfig struct {
+	Routes map[string]time.Duration
+}
+
+// defaultRouteTimeouts are used for routes not overridden by ROUTE_TIMEOUTS
+var defaultRouteTimeouts = map[string]time.Duration{
+	"candles":        10 * time.Second,
+	"markets":        5 * time.Second,
+	"status":         3 * time.Second,
+	"tx":             10 * time.Second,
+	"submit":         10 * time.Second,
+	"batch":          30 * time.Second, // Matches HandleSubmitBatch's own deadline
+	"transaction":    5 * time.Second,
+	"tick":           5 * time.Second,
+	"chain_state":    5 * time.Second,
+	"ticks":          10 * time.Second,
+	"rollup":         15 * time.Second,
+	"continuum_rest": 15 * time.Second,
+}
+
+// For returns the timeout for the named route (0 = no timeout)
+func (c TimeoutConfig) For(route string) time.Duration {
+	return c.Routes[route]
+}
+
+// Configured reports whether enough settings are present to connect to the database
+func (c DatabaseConfig) Configured() bool {
+	return c.URL != "" || (c.Host != "" && c.DBName != "")
+}
+
+// DSN returns the connection string for the database
+// If URL is set it is used as-is, except that SSLMode is applied when the URL has no sslmode
+func (c DatabaseConfig) DSN() (string, error) {
+	if c.URL == "" {
+		return fmt.Sprintf(
+			"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
+		), nil
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return "", fmt.Errorf("invalid DATABASE_URL: unsupported scheme %q", u.Scheme)
+	}
+
+	if c.SSLMode != "" {
+		query := u.Query()
+		if query.Get("sslmode") == "" {
+			query.Set("sslmode", c.SSLMode)
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	return u.String(), nil
 }
 
 // Load reads configuration from environment variables
 func Load() *Config {
-	return &Config{
+	// When a full DATABASE_URL is provided, only apply DB_SSLMODE if it was set explicitly
+	databaseURL := getEnv("DATABASE_URL", "")
+	sslModeDefault := "disable"
+	if databaseURL != "" {
+		sslModeDefault = ""
+	}
+
+	var malformed []error
+
+	cfg := &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Env:  getEnv("ENV", "development"),
+			Port:           getEnv("PORT", "8080"),
+			Env:            getEnv("ENV", "development"),
+			TrustedProxies: getEnvSlice("TRUSTED_PROXIES", nil),
+			AllowedHosts:   getEnvSlice("ALLOWED_HOSTS", nil),
+			LogLevel:       getEnv("LOG_LEVEL", ""),
+
+			LargeResponseBytes: getEnvInt("LARGE_RESPONSE_BYTES", 1024*1024),
+			SLOLatencyMs:       getEnvInt("SLO_LATENCY_MS", 250),
+			MetricsExemplars:   getEnv("METRICS_EXEMPLARS", "false") == "true",
+
+			JSONFieldStyle: getEnv("JSON_FIELD_STYLE", "snake_case"),
+
+			LogHeaders:      getEnv("LOG_HEADERS", "false") == "true",
+			RedactedHeaders: getEnvSlice("LOG_REDACT_HEADERS", nil),
+
+			ExcludedPaths: getEnvSlice("EXCLUDED_PATHS", nil),
+
+			BodySamplePaths:    getEnvSlice("BODY_SAMPLE_PATHS", nil),
+			BodySampleToken:    getEnv("BODY_SAMPLE_TOKEN", ""),
+			BodySampleMaxBytes: getEnvInt("BODY_SAMPLE_MAX_BYTES", 4096),
+			BodySampleDenied:   getEnvSlice("BODY_SAMPLE_DENY_PATHS", nil),
 		},
 		CORS: CORSConfig{
-			AllowedOrigins: getEnvSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
+			AllowedOrigins:   getEnvSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
+			AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "true") == "true",
+			MaxAgeSeconds:    getEnvInt("CORS_MAX_AGE_SECONDS", 86400),
 		},
 		Backend: BackendConfig{
 			RollupURL:        getEnv("ROLLUP_URL", "http://localhost:3000"),
 			ContinuumGrpcURL: getEnv("CONTINUUM_GRPC_URL", "localhost:9090"),
 			ContinuumRestURL: getEnv("CONTINUUM_REST_URL", "http://localhost:8081"),
+
+			PrimarySources: getEnvStringMap("BACKEND_PRIMARY", &malformed),
+			AllowedMethods: getEnvMethodMap("PROXY_ALLOWED_METHODS", &malformed),
+			StripPrefixes:  getEnvStringMap("PROXY_STRIP_PREFIX", &malformed),
+			AddPrefixes:    getEnvStringMap("PROXY_ADD_PREFIX", &malformed),
+
+			SubmitQueueSize:     getEnvInt("SUBMIT_QUEUE_SIZE", 0),
+			SubmitQueueWorkers:  getEnvInt("SUBMIT_QUEUE_WORKERS", 4),
+			SubmitConfirmWaitMs: getEnvInt("SUBMIT_CONFIRM_WAIT_MS", 0),
+
+			NonceWindowMs:  getEnvInt("NONCE_WINDOW_MS", 0),
+			NonceCacheSize: getEnvInt("NONCE_CACHE_SIZE", 100000),
+
+			SignatureScheme: getEnv("SIGNATURE_SCHEME", ""),
+
+			MaxTickStreams: getEnvInt("MAX_TICK_STREAMS", 1000),
+
+			GrpcMaxRecvMsgBytes: getEnvInt("GRPC_MAX_RECV_MSG_BYTES", 10*1024*1024),
+			GrpcMaxSendMsgBytes: getEnvInt("GRPC_MAX_SEND_MSG_BYTES", 10*1024*1024),
 		},
 		Database: DatabaseConfig{
+			URL:      databaseURL,
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", ""),
 			DBName:   getEnv("DB_NAME", "continuum"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			SSLMode:  getEnv("DB_SSLMODE", sslModeDefault),
+
+			SlowQueryThresholdMs:   getEnvInt("DB_SLOW_QUERY_THRESHOLD_MS", 500),
+			ConnectRetries:         getEnvInt("DB_CONNECT_RETRIES", 5),
+			ConnectRetryIntervalMs: getEnvInt("DB_CONNECT_RETRY_INTERVAL_MS", 1000),
+			ReconnectIntervalMs:    getEnvInt("DB_RECONNECT_INTERVAL_MS", 10000),
 		},
 		RateLimit: RateLimitConfig{
 			RollupRPM:        getEnvInt("RATE_LIMIT_ROLLUP", 1000),
 			ContinuumGrpcRPM: getEnvInt("RATE_LIMIT_CONTINUUM_GRPC", 500),
 			ContinuumRestRPM: getEnvInt("RATE_LIMIT_CONTINUUM_REST", 2000),
+			SubmitRPM:        getEnvInt("RATE_LIMIT_SUBMIT", 120),
+			GlobalRPS:        getEnvInt("RATE_LIMIT_GLOBAL_RPS", 0),
+			GlobalBurst:      getEnvInt("RATE_LIMIT_GLOBAL_BURST", 0),
+
+			RedisURL: getEnv("RATE_LIMIT_REDIS_URL", ""),
+		},
+		Timeouts: TimeoutConfig{
+			Routes: getEnvDurationMap("ROUTE_TIMEOUTS", defaultRouteTimeouts, &malformed),
 		},
+		Markets: MarketsConfig{
+			Symbols:  getEnvStringMap("MARKET_SYMBOLS", &malformed),
+			Decimals: getEnvStringMap("MARKET_DECIMALS", &malformed),
+		},
+	}
+
+	for _, key := range intEnvKeys {
+		if value := os.Getenv(key); value != "" {
+			if _, err := strconv.Atoi(value); err != nil {
+				malformed = append(malformed, fmt.Errorf("%s must be an integer, got %q", key, value))
+			}
+		}
+	}
+	cfg.malformed = malformed
+
+	return cfg
+}
+
+// Validate checks the loaded configuration and reports every problem found
+func (c *Config) Validate() error {
+	var errs []error
+
+	errs = append(errs, c.malformed...)
+
+	for route, timeout := range c.Timeouts.Routes {
+		if timeout < 0 {
+			errs = append(errs, fmt.Errorf("ROUTE_TIMEOUTS: timeout for %q must not be negative", route))
+		}
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT must be between 1 and 65535, got %q", c.Server.Port))
+	}
+
+	for key, prefixes := range map[string][]string{
+		"EXCLUDED_PATHS":         c.Server.ExcludedPaths,
+		"BODY_SAMPLE_PATHS":      c.Server.BodySamplePaths,
+		"BODY_SAMPLE_DENY_PATHS": c.Server.BodySampleDenied,
+	} {
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(prefix, "/") {
+				errs = append(errs, fmt.Errorf("%s: prefix must start with /, got %q", key, prefix))
+			}
+		}
+	}
+
+	if c.Server.BodySampleMaxBytes < 1 || c.Server.BodySampleMaxBytes > 64*1024 {
+		errs = append(errs, fmt.Errorf("BODY_SAMPLE_MAX_BYTES must be between 1 and 65536, got %d", c.Server.BodySampleMaxBytes))
 	}
+
+	for key, value := range map[string]string{
+		"ROLLUP_URL":         c.Backend.RollupURL,
+		"CONTINUUM_REST_URL": c.Backend.ContinuumRestURL,
+	} {
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s must be an http(s) URL, got %q", key, value))
+		}
+	}
+
+	for method, source := range c.Backend.PrimarySources {
+		if source != "grpc" && source != "rest" {
+			errs = append(errs, fmt.Errorf("BACKEND_PRIMARY: source for %q must be grpc or rest, got %q", method, source))
+		}
+	}
+
+	for route, methods := range c.Backend.AllowedMethods {
+		if route != "rollup" && route != "continuum_rest" {
+			errs = append(errs, fmt.Errorf("PROXY_ALLOWED_METHODS: unknown route %q (expected rollup or continuum_rest)", route))
+		}
+		for _, method := range methods {
+			if !validMethods[method] {
+				errs = append(errs, fmt.Errorf("PROXY_ALLOWED_METHODS: unknown method %q for %q", method, route))
+			}
+		}
+	}
+
+	for key, prefixes := range map[string]map[string]string{
+		"PROXY_STRIP_PREFIX": c.Backend.StripPrefixes,
+		"PROXY_ADD_PREFIX":   c.Backend.AddPrefixes,
+	} {
+		for route, prefix := range prefixes {
+			if route != "rollup" && route != "continuum_rest" {
+				errs = append(errs, fmt.Errorf("%s: unknown route %q (expected rollup or continuum_rest)", key, route))
+			}
+			if !strings.HasPrefix(prefix, "/") {
+				errs = append(errs, fmt.Errorf("%s: prefix for %q must start with /, got %q", key, route, prefix))
+			}
+		}
+	}
+
+	if c.Backend.SubmitQueueSize < 0 {
+		errs = append(errs, fmt.Errorf("SUBMIT_QUEUE_SIZE must not be negative, got %d", c.Backend.SubmitQueueSize))
+	}
+	if c.Backend.MaxTickStreams < 0 {
+		errs = append(errs, fmt.Errorf("MAX_TICK_STREAMS must not be negative, got %d", c.Backend.MaxTickStreams))
+	}
+	if c.Backend.GrpcMaxRecvMsgBytes <= 0 || c.Backend.GrpcMaxSendMsgBytes <= 0 {
+		errs = append(errs, fmt.Errorf("GRPC_MAX_RECV_MSG_BYTES and GRPC_MAX_SEND_MSG_BYTES must be positive, got %d and %d",
+			c.Backend.GrpcMaxRecvMsgBytes, c.Backend.GrpcMaxSendMsgBytes))
+	}
+	if c.Backend.SubmitConfirmWaitMs < 0 {
+		errs = append(errs, fmt.Errorf("SUBMIT_CONFIRM_WAIT_MS must not be negative, got %d", c.Backend.SubmitConfirmWaitMs))
+	}
+	if c.Backend.NonceWindowMs < 0 {
+		errs = append(errs, fmt.Errorf("NONCE_WINDOW_MS must not be negative, got %d", c.Backend.NonceWindowMs))
+	}
+	if c.Backend.NonceWindowMs > 0 && c.Backend.NonceCacheSize < 1 {
+		errs = append(errs, fmt.Errorf("NONCE_CACHE_SIZE must be positive, got %d", c.Backend.NonceCacheSize))
+	}
+	for market, decimals := range c.Markets.Decimals {
+		if n, err := strconv.Atoi(decimals); err != nil || n < 0 || n > 18 {
+			errs = append(errs, fmt.Errorf("MARKET_DECIMALS: decimals for %q must be an integer between 0 and 18, got %q", market, decimals))
+		}
+	}
+
+	switch c.Backend.SignatureScheme {
+	case "", "ed25519", "secp256k1":
+	default:
+		errs = append(errs, fmt.Errorf("SIGNATURE_SCHEME must be ed25519 or secp256k1 (or empty), got %q", c.Backend.SignatureScheme))
+	}
+	if c.Backend.SubmitQueueSize > 0 && c.Backend.SubmitQueueWorkers < 1 {
+		errs = append(errs, fmt.Errorf("SUBMIT_QUEUE_WORKERS must be positive, got %d", c.Backend.SubmitQueueWorkers))
+	}
+
+	if c.Server.JSONFieldStyle != "snake_case" && c.Server.JSONFieldStyle != "camelCase" {
+		errs = append(errs, fmt.Errorf("JSON_FIELD_STYLE must be snake_case or camelCase, got %q", c.Server.JSONFieldStyle))
+	}
+
+	if c.Server.LogLevel != "" {
+		if _, err := zapcore.ParseLevel(c.Server.LogLevel); err != nil {
+			errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+		}
+	}
+
+	if c.Backend.ContinuumGrpcURL == "" {
+		errs = append(errs, fmt.Errorf("CONTINUUM_GRPC_URL is required"))
+	}
+
+	for key, value := range map[string]int{
+		"RATE_LIMIT_ROLLUP":         c.RateLimit.RollupRPM,
+		"RATE_LIMIT_CONTINUUM_GRPC": c.RateLimit.ContinuumGrpcRPM,
+		"RATE_LIMIT_CONTINUUM_REST": c.RateLimit.ContinuumRestRPM,
+		"RATE_LIMIT_SUBMIT":         c.RateLimit.SubmitRPM,
+	} {
+		if value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", key, value))
+		}
+	}
+
+	if c.CORS.MaxAgeSeconds < 0 {
+		errs = append(errs, fmt.Errorf("CORS_MAX_AGE_SECONDS must not be negative, got %d", c.CORS.MaxAgeSeconds))
+	}
+
+	if c.RateLimit.GlobalRPS < 0 || c.RateLimit.GlobalBurst < 0 {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_GLOBAL_RPS and RATE_LIMIT_GLOBAL_BURST must not be negative"))
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				errs = append(errs, fmt.Errorf("ALLOWED_ORIGINS=* requires CORS_ALLOW_CREDENTIALS=false"))
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs = append(errs, fmt.Errorf("ALLOWED_ORIGINS entry %q must be an origin like https://example.com", origin))
+		}
+	}
+
+	if c.Database.ConnectRetries < 0 {
+		errs = append(errs, fmt.Errorf("DB_CONNECT_RETRIES must not be negative, got %d", c.Database.ConnectRetries))
+	}
+	if c.Database.ConnectRetryIntervalMs <= 0 {
+		errs = append(errs, fmt.Errorf("DB_CONNECT_RETRY_INTERVAL_MS must be positive, got %d", c.Database.ConnectRetryIntervalMs))
+	}
+	if c.Database.SlowQueryThresholdMs < 0 {
+		errs = append(errs, fmt.Errorf("DB_SLOW_QUERY_THRESHOLD_MS must not be negative, got %d", c.Database.SlowQueryThresholdMs))
+	}
+	if c.Database.ReconnectIntervalMs <= 0 {
+		errs = append(errs, fmt.Errorf("DB_RECONNECT_INTERVAL_MS must be positive, got %d", c.Database.ReconnectIntervalMs))
+	}
+
+	if c.Database.URL != "" {
+		if _, err := c.Database.DSN(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Sort for stable output; map iteration order is random
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+
+	return errors.Join(errs...)
 }
 
 // Helper functions to read environment variables with defaults
@@ -97,6 +527,76 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvDurationMap parses "name=duration,name=duration" into a copy of defaults,
+// overriding the listed entries. Malformed entries are reported to malformed
+func getEnvDurationMap(key string, defaults map[string]time.Duration, malformed *[]error) map[string]time.Duration {
+	result := make(map[string]time.Duration, len(defaults))
+	for name, d := range defaults {
+		result[name] = d
+	}
+
+	for _, entry := range getEnvSlice(key, nil) {
+		name, value, ok := strings.Cut(entry, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil {
+			*malformed = append(*malformed, fmt.Errorf("%s entry %q must be name=duration", key, entry))
+			continue
+		}
+		result[strings.TrimSpace(name)] = d
+	}
+
+	return result
+}
+
+// getEnvStringMap parses "name=value,name=value". Malformed entries are reported to malformed
+func getEnvStringMap(key string, malformed *[]error) map[string]string {
+	result := make(map[string]string)
+
+	for _, entry := range getEnvSlice(key, nil) {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			*malformed = append(*malformed, fmt.Errorf("%s entry %q must be name=value", key, entry))
+			continue
+		}
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return result
+}
+
+// validMethods are the HTTP methods accepted in PROXY_ALLOWED_METHODS
+var validMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// getEnvMethodMap parses "route=GET|POST,route=GET" into uppercased method lists per route.
+// Malformed entries are reported to malformed
+func getEnvMethodMap(key string, malformed *[]error) map[string][]string {
+	result := make(map[string][]string)
+
+	for name, value := range getEnvStringMap(key, malformed) {
+		var methods []string
+		for _, method := range strings.Split(value, "|") {
+			if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			*malformed = append(*malformed, fmt.Errorf("%s entry for %q must list at least one method", key, name))
+			continue
+		}
+		result[name] = methods
+	}
+
+	return result
+}
+
 func getEnvSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// Simple split by comma for now
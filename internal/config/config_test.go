@@ -0,0 +1,424 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validateWith returns Validate's error for the default configuration changed by mutate
+func validateWith(mutate func(c *Config)) error {
+	c := Load()
+	mutate(c)
+	return c.Validate()
+}
+
+func TestValidate_Database(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string // Empty = valid
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"reconnect interval positive", func(c *Config) { c.Database.ReconnectIntervalMs = 1 }, ""},
+		{"reconnect interval zero", func(c *Config) { c.Database.ReconnectIntervalMs = 0 }, "DB_RECONNECT_INTERVAL_MS must be positive"},
+		{"reconnect interval negative", func(c *Config) { c.Database.ReconnectIntervalMs = -5 }, "DB_RECONNECT_INTERVAL_MS must be positive"},
+		{"no connect retries", func(c *Config) { c.Database.ConnectRetries = 0 }, ""},
+		{"negative connect retries", func(c *Config) { c.Database.ConnectRetries = -1 }, "DB_CONNECT_RETRIES must not be negative"},
+		{"retry interval zero", func(c *Config) { c.Database.ConnectRetryIntervalMs = 0 }, "DB_CONNECT_RETRY_INTERVAL_MS must be positive"},
+		{"retry interval negative", func(c *Config) { c.Database.ConnectRetryIntervalMs = -100 }, "DB_CONNECT_RETRY_INTERVAL_MS must be positive"},
+		{"slow query log disabled", func(c *Config) { c.Database.SlowQueryThresholdMs = 0 }, ""},
+		{"slow query threshold negative", func(c *Config) { c.Database.SlowQueryThresholdMs = -1 }, "DB_SLOW_QUERY_THRESHOLD_MS must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWith(tt.mutate)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string // Empty = valid
+	}{
+		{"port", func(c *Config) { c.Server.Port = "443" }, ""},
+		{"port not a number", func(c *Config) { c.Server.Port = "http" }, `PORT must be between 1 and 65535, got "http"`},
+		{"port zero", func(c *Config) { c.Server.Port = "0" }, "PORT must be between 1 and 65535"},
+		{"port too large", func(c *Config) { c.Server.Port = "70000" }, "PORT must be between 1 and 65535"},
+		{"https backend", func(c *Config) { c.Backend.RollupURL = "https://rollup.internal" }, ""},
+		{"empty rollup URL", func(c *Config) { c.Backend.RollupURL = "" }, "ROLLUP_URL must be an http(s) URL"},
+		{"rest URL without scheme", func(c *Config) { c.Backend.ContinuumRestURL = "localhost:8081" }, "CONTINUUM_REST_URL must be an http(s) URL"},
+		{"empty gRPC URL", func(c *Config) { c.Backend.ContinuumGrpcURL = "" }, "CONTINUUM_GRPC_URL is required"},
+		{"zero rate limit", func(c *Config) { c.RateLimit.RollupRPM = 0 }, "RATE_LIMIT_ROLLUP must be positive, got 0"},
+		{"negative rate limit", func(c *Config) { c.RateLimit.SubmitRPM = -1 }, "RATE_LIMIT_SUBMIT must be positive, got -1"},
+		{"negative global RPS", func(c *Config) { c.RateLimit.GlobalRPS = -1 }, "RATE_LIMIT_GLOBAL_RPS and RATE_LIMIT_GLOBAL_BURST must not be negative"},
+		{"origin", func(c *Config) { c.CORS.AllowedOrigins = []string{"https://app.fermi.xyz", "http://localhost:3000/"} }, ""},
+		{"origin without scheme", func(c *Config) { c.CORS.AllowedOrigins = []string{"app.fermi.xyz"} }, `ALLOWED_ORIGINS entry "app.fermi.xyz" must be an origin`},
+		{"origin with path", func(c *Config) { c.CORS.AllowedOrigins = []string{"https://app.fermi.xyz/login"} }, "must be an origin like https://example.com"},
+		{"any origin without credentials", func(c *Config) { c.CORS.AllowedOrigins, c.CORS.AllowCredentials = []string{"*"}, false }, ""},
+		{"any origin with credentials", func(c *Config) { c.CORS.AllowedOrigins = []string{"*", "https://app.fermi.xyz"} }, "ALLOWED_ORIGINS=* requires CORS_ALLOW_CREDENTIALS=false"},
+		{"no preflight caching", func(c *Config) { c.CORS.MaxAgeSeconds = 0 }, ""},
+		{"negative preflight max age", func(c *Config) { c.CORS.MaxAgeSeconds = -1 }, "CORS_MAX_AGE_SECONDS must not be negative, got -1"},
+		{"malformed database URL", func(c *Config) { c.Database.URL = "postgres://%zz" }, "DATABASE_URL"},
+		{"primary source", func(c *Config) { c.Backend.PrimarySources = map[string]string{"status": "grpc"} }, ""},
+		{"camelCase", func(c *Config) { c.Server.JSONFieldStyle = "camelCase" }, ""},
+		{"unknown JSON style", func(c *Config) { c.Server.JSONFieldStyle = "kebab-case" }, `JSON_FIELD_STYLE must be snake_case or camelCase, got "kebab-case"`},
+		{"unknown primary source", func(c *Config) { c.Backend.PrimarySources = map[string]string{"status": "db"} }, `BACKEND_PRIMARY: source for "status" must be grpc or rest, got "db"`},
+		{"allowed methods", func(c *Config) { c.Backend.AllowedMethods = map[string][]string{"rollup": {"GET", "POST"}} }, ""},
+		{"allowed methods, unknown route", func(c *Config) { c.Backend.AllowedMethods = map[string][]string{"grpc": {"GET"}} }, `PROXY_ALLOWED_METHODS: unknown route "grpc"`},
+		{"allowed methods, unknown method", func(c *Config) { c.Backend.AllowedMethods = map[string][]string{"rollup": {"FETCH"}} }, `PROXY_ALLOWED_METHODS: unknown method "FETCH" for "rollup"`},
+		{"submit queue", func(c *Config) { c.Backend.SubmitQueueSize = 100 }, ""},
+		{"negative submit queue size", func(c *Config) { c.Backend.SubmitQueueSize = -1 }, "SUBMIT_QUEUE_SIZE must not be negative, got -1"},
+		{"submit queue without workers", func(c *Config) { c.Backend.SubmitQueueSize, c.Backend.SubmitQueueWorkers = 10, 0 }, "SUBMIT_QUEUE_WORKERS must be positive, got 0"},
+		{"no queue, no workers", func(c *Config) { c.Backend.SubmitQueueWorkers = 0 }, ""},
+		{"submit confirmation wait", func(c *Config) { c.Backend.SubmitConfirmWaitMs = 500 }, ""},
+		{"negative submit confirmation wait", func(c *Config) { c.Backend.SubmitConfirmWaitMs = -1 }, "SUBMIT_CONFIRM_WAIT_MS must not be negative, got -1"},
+		{"unlimited tick streams", func(c *Config) { c.Backend.MaxTickStreams = 0 }, ""},
+		{"negative tick streams", func(c *Config) { c.Backend.MaxTickStreams = -1 }, "MAX_TICK_STREAMS must not be negative, got -1"},
+		{"excluded paths", func(c *Config) { c.Server.ExcludedPaths = []string{"/health", "/metrics"} }, ""},
+		{"relative excluded path", func(c *Config) { c.Server.ExcludedPaths = []string{"/health", "ready"} }, `EXCLUDED_PATHS: prefix must start with /, got "ready"`},
+		{"body sampling", func(c *Config) { c.Server.BodySamplePaths = []string{"/api/v1/rollup"} }, ""},
+		{"largest body sample", func(c *Config) { c.Server.BodySampleMaxBytes = 65536 }, ""},
+		{"relative body sample path", func(c *Config) { c.Server.BodySamplePaths = []string{"api"} }, `BODY_SAMPLE_PATHS: prefix must start with /, got "api"`},
+		{"relative body sample denied path", func(c *Config) { c.Server.BodySampleDenied = []string{"orders"} }, `BODY_SAMPLE_DENY_PATHS: prefix must start with /, got "orders"`},
+		{"zero body sample size", func(c *Config) { c.Server.BodySampleMaxBytes = 0 }, "BODY_SAMPLE_MAX_BYTES must be between 1 and 65536, got 0"},
+		{"body sample size over the cap", func(c *Config) { c.Server.BodySampleMaxBytes = 65537 }, "BODY_SAMPLE_MAX_BYTES must be between 1 and 65536, got 65537"},
+		{"path rewrite", func(c *Config) { c.Backend.StripPrefixes = map[string]string{"continuum_rest": "/v1"} }, ""},
+		{"path rewrite, unknown route", func(c *Config) { c.Backend.AddPrefixes = map[string]string{"grpc": "/v2"} }, `PROXY_ADD_PREFIX: unknown route "grpc" (expected rollup or continuum_rest)`},
+		{"path rewrite, relative prefix", func(c *Config) { c.Backend.StripPrefixes = map[string]string{"rollup": "v1"} }, `PROXY_STRIP_PREFIX: prefix for "rollup" must start with /, got "v1"`},
+		{"nonce cache", func(c *Config) { c.Backend.NonceWindowMs = 60000 }, ""},
+		{"negative nonce window", func(c *Config) { c.Backend.NonceWindowMs = -1 }, "NONCE_WINDOW_MS must not be negative, got -1"},
+		{"nonce cache without room", func(c *Config) { c.Backend.NonceWindowMs, c.Backend.NonceCacheSize = 60000, 0 }, "NONCE_CACHE_SIZE must be positive, got 0"},
+		{"no nonce cache, no room", func(c *Config) { c.Backend.NonceCacheSize = 0 }, ""},
+		{"ed25519 signatures", func(c *Config) { c.Backend.SignatureScheme = "ed25519" }, ""},
+		{"secp256k1 signatures", func(c *Config) { c.Backend.SignatureScheme = "secp256k1" }, ""},
+		{"market decimals", func(c *Config) { c.Markets.Decimals = map[string]string{"m1": "6"} }, ""},
+		{"market decimals not a number", func(c *Config) { c.Markets.Decimals = map[string]string{"m1": "six"} }, `MARKET_DECIMALS: decimals for "m1" must be an integer between 0 and 18, got "six"`},
+		{"market decimals too large", func(c *Config) { c.Markets.Decimals = map[string]string{"m1": "19"} }, `MARKET_DECIMALS: decimals for "m1" must be an integer between 0 and 18, got "19"`},
+		{"negative market decimals", func(c *Config) { c.Markets.Decimals = map[string]string{"m1": "-1"} }, "between 0 and 18"},
+		{"unknown signature scheme", func(c *Config) { c.Backend.SignatureScheme = "rsa" }, `SIGNATURE_SCHEME must be ed25519 or secp256k1 (or empty), got "rsa"`},
+		{"gRPC message sizes", func(c *Config) { c.Backend.GrpcMaxRecvMsgBytes = 100 * 1024 * 1024 }, ""},
+		{"zero gRPC receive size", func(c *Config) { c.Backend.GrpcMaxRecvMsgBytes = 0 }, "GRPC_MAX_RECV_MSG_BYTES and GRPC_MAX_SEND_MSG_BYTES must be positive, got 0 and 10485760"},
+		{"negative gRPC send size", func(c *Config) { c.Backend.GrpcMaxSendMsgBytes = -1 }, "GRPC_MAX_RECV_MSG_BYTES and GRPC_MAX_SEND_MSG_BYTES must be positive, got 10485760 and -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWith(tt.mutate)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_MalformedEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string // Empty = valid
+	}{
+		{"integer", "RATE_LIMIT_ROLLUP", "100", ""},
+		{"not an integer", "RATE_LIMIT_ROLLUP", "100rpm", `RATE_LIMIT_ROLLUP must be an integer, got "100rpm"`},
+		{"max age not an integer", "CORS_MAX_AGE_SECONDS", "1h", `CORS_MAX_AGE_SECONDS must be an integer, got "1h"`},
+		{"float", "DB_CONNECT_RETRIES", "2.5", `DB_CONNECT_RETRIES must be an integer, got "2.5"`},
+		{"allowed methods", "PROXY_ALLOWED_METHODS", "rollup=GET|HEAD", ""},
+		{"allowed methods without methods", "PROXY_ALLOWED_METHODS", "rollup=|", `PROXY_ALLOWED_METHODS entry for "rollup" must list at least one method`},
+		{"prefix without a route", "PROXY_STRIP_PREFIX", "/v1", `PROXY_STRIP_PREFIX entry "/v1" must be name=value`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			err := Load().Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	err := validateWith(func(c *Config) {
+		c.Server.Port = "0"
+		c.Backend.ContinuumGrpcURL = ""
+		c.RateLimit.RollupRPM = 0
+	})
+	if err == nil {
+		t.Fatal("Validate() error = nil")
+	}
+
+	want := []string{
+		"CONTINUUM_GRPC_URL is required",
+		`PORT must be between 1 and 65535, got "0"`,
+		"RATE_LIMIT_ROLLUP must be positive, got 0",
+	}
+	if got := strings.Split(err.Error(), "\n"); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Validate() errors = %q, want %q (sorted)", got, want)
+	}
+}
+
+func TestTimeoutConfig_Defaults(t *testing.T) {
+	tests := []struct {
+		route string
+		want  time.Duration
+	}{
+		{"submit", 10 * time.Second},
+		{"batch", 30 * time.Second},
+		{"unknown", 0},
+	}
+
+	c := Load()
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			if got := c.Timeouts.For(tt.route); got != tt.want {
+				t.Errorf("For(%q) = %s, want %s", tt.route, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDatabaseConfig_DSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DatabaseConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "individual fields",
+			config: DatabaseConfig{Host: "db", Port: "5432", User: "gw", Password: "secret", DBName: "continuum", SSLMode: "require"},
+			want:   "host=db port=5432 user=gw password=secret dbname=continuum sslmode=require",
+		},
+		{
+			name:   "URL without sslmode",
+			config: DatabaseConfig{URL: "postgres://gw:secret@db:5432/continuum", SSLMode: "verify-full"},
+			want:   "postgres://gw:secret@db:5432/continuum?sslmode=verify-full",
+		},
+		{
+			name:   "URL sslmode wins",
+			config: DatabaseConfig{URL: "postgresql://db/continuum?sslmode=disable", SSLMode: "require"},
+			want:   "postgresql://db/continuum?sslmode=disable",
+		},
+		{
+			name:   "URL used as is",
+			config: DatabaseConfig{URL: "postgres://db/continuum?application_name=gateway", Host: "ignored"},
+			want:   "postgres://db/continuum?application_name=gateway",
+		},
+		{
+			name:    "unsupported scheme",
+			config:  DatabaseConfig{URL: "mysql://db/continuum"},
+			wantErr: "unsupported scheme",
+		},
+		{
+			name:    "unparsable URL",
+			config:  DatabaseConfig{URL: "postgres://db:port/continuum"},
+			wantErr: "invalid DATABASE_URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.DSN()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DSN() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("DSN() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_DatabaseSSLMode(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"fields default to disable", map[string]string{}, "disable"},
+		{"fields with DB_SSLMODE", map[string]string{"DB_SSLMODE": "require"}, "require"},
+		{"URL keeps its own sslmode", map[string]string{"DATABASE_URL": "postgres://db/continuum"}, ""},
+		{"URL with explicit DB_SSLMODE", map[string]string{"DATABASE_URL": "postgres://db/continuum", "DB_SSLMODE": "verify-ca"}, "verify-ca"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DATABASE_URL", "")
+			t.Setenv("DB_SSLMODE", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			if got := Load().Database.SSLMode; got != tt.want {
+				t.Errorf("SSLMode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDatabaseConfig_Configured(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DatabaseConfig
+		want   bool
+	}{
+		{"nothing set", DatabaseConfig{}, false},
+		{"URL", DatabaseConfig{URL: "postgres://db/continuum"}, true},
+		{"host and name", DatabaseConfig{Host: "db", DBName: "continuum"}, true},
+		{"host only", DatabaseConfig{Host: "db"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Configured(); got != tt.want {
+				t.Errorf("Configured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c := Load()
+	if c.RateLimit.SubmitRPM != 120 || c.RateLimit.SubmitRPM >= c.RateLimit.ContinuumRestRPM {
+		t.Errorf("SubmitRPM = %d, want 120 and stricter than ContinuumRestRPM (%d)", c.RateLimit.SubmitRPM, c.RateLimit.ContinuumRestRPM)
+	}
+	if len(c.Server.AllowedHosts) != 0 {
+		t.Errorf("AllowedHosts = %v, want any host by default", c.Server.AllowedHosts)
+	}
+	if c.RateLimit.RedisURL != "" {
+		t.Errorf("RedisURL = %q, want in-memory limits by default", c.RateLimit.RedisURL)
+	}
+	if c.RateLimit.GlobalRPS != 0 {
+		t.Errorf("GlobalRPS = %d, want 0 (disabled)", c.RateLimit.GlobalRPS)
+	}
+	if c.Backend.SubmitQueueSize != 0 || c.Backend.SubmitQueueWorkers != 4 {
+		t.Errorf("submit queue = %d with %d workers, want no queue and 4 workers", c.Backend.SubmitQueueSize, c.Backend.SubmitQueueWorkers)
+	}
+	if c.Backend.SubmitConfirmWaitMs != 0 {
+		t.Errorf("SubmitConfirmWaitMs = %d, want 0 (don't wait)", c.Backend.SubmitConfirmWaitMs)
+	}
+	if c.Backend.MaxTickStreams != 1000 {
+		t.Errorf("MaxTickStreams = %d, want 1000", c.Backend.MaxTickStreams)
+	}
+	if len(c.Server.BodySamplePaths) != 0 || c.Server.BodySampleToken != "" || c.Server.BodySampleMaxBytes != 4096 {
+		t.Errorf("body sampling = %v with token %q and %d bytes, want off and 4096 bytes",
+			c.Server.BodySamplePaths, c.Server.BodySampleToken, c.Server.BodySampleMaxBytes)
+	}
+	if c.Backend.NonceWindowMs != 0 || c.Backend.NonceCacheSize != 100000 {
+		t.Errorf("nonce cache = %dms window with %d entries, want disabled with 100000", c.Backend.NonceWindowMs, c.Backend.NonceCacheSize)
+	}
+	if c.Server.MetricsExemplars {
+		t.Error("MetricsExemplars = true, want disabled by default")
+	}
+	if !c.CORS.AllowCredentials || c.CORS.MaxAgeSeconds != 86400 {
+		t.Errorf("CORS credentials = %v with max age %d, want on with 86400", c.CORS.AllowCredentials, c.CORS.MaxAgeSeconds)
+	}
+	if c.Backend.GrpcMaxRecvMsgBytes != 10*1024*1024 || c.Backend.GrpcMaxSendMsgBytes != 10*1024*1024 {
+		t.Errorf("gRPC message sizes = %d and %d, want 10MB each", c.Backend.GrpcMaxRecvMsgBytes, c.Backend.GrpcMaxSendMsgBytes)
+	}
+}
+
+func TestLoad_Env(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		check func(c *Config) bool
+	}{
+		{"submit RPM", "RATE_LIMIT_SUBMIT", "30", func(c *Config) bool { return c.RateLimit.SubmitRPM == 30 }},
+		{"redis URL", "RATE_LIMIT_REDIS_URL", "redis://cache:6379/1", func(c *Config) bool { return c.RateLimit.RedisURL == "redis://cache:6379/1" }},
+		{"global RPS", "RATE_LIMIT_GLOBAL_RPS", "500", func(c *Config) bool { return c.RateLimit.GlobalRPS == 500 }},
+		{"global burst", "RATE_LIMIT_GLOBAL_BURST", "50", func(c *Config) bool { return c.RateLimit.GlobalBurst == 50 }},
+		{"allowed hosts", "ALLOWED_HOSTS", "api.fermi.xyz,localhost", func(c *Config) bool {
+			return strings.Join(c.Server.AllowedHosts, "|") == "api.fermi.xyz|localhost"
+		}},
+		{"primary sources", "BACKEND_PRIMARY", "status=grpc, tick=rest", func(c *Config) bool {
+			return len(c.Backend.PrimarySources) == 2 && c.Backend.PrimarySources["status"] == "grpc" && c.Backend.PrimarySources["tick"] == "rest"
+		}},
+		{"JSON field style", "JSON_FIELD_STYLE", "camelCase", func(c *Config) bool { return c.Server.JSONFieldStyle == "camelCase" }},
+		{"log headers", "LOG_HEADERS", "true", func(c *Config) bool { return c.Server.LogHeaders }},
+		{"redacted headers", "LOG_REDACT_HEADERS", "X-Admin-Token,X-Session", func(c *Config) bool {
+			return strings.Join(c.Server.RedactedHeaders, "|") == "X-Admin-Token|X-Session"
+		}},
+		{"SLO latency", "SLO_LATENCY_MS", "100", func(c *Config) bool { return c.Server.SLOLatencyMs == 100 }},
+		{"body sample paths", "BODY_SAMPLE_PATHS", "/api/v1/rollup,/api/v1/continuum/ticks", func(c *Config) bool {
+			return strings.Join(c.Server.BodySamplePaths, "|") == "/api/v1/rollup|/api/v1/continuum/ticks"
+		}},
+		{"body sample token", "BODY_SAMPLE_TOKEN", "s3cret", func(c *Config) bool { return c.Server.BodySampleToken == "s3cret" }},
+		{"body sample size", "BODY_SAMPLE_MAX_BYTES", "512", func(c *Config) bool { return c.Server.BodySampleMaxBytes == 512 }},
+		{"body sample denied paths", "BODY_SAMPLE_DENY_PATHS", "/api/v1/rollup/orders", func(c *Config) bool {
+			return strings.Join(c.Server.BodySampleDenied, "|") == "/api/v1/rollup/orders"
+		}},
+		{"metrics exemplars", "METRICS_EXEMPLARS", "true", func(c *Config) bool { return c.Server.MetricsExemplars }},
+		{"large response bytes", "LARGE_RESPONSE_BYTES", "65536", func(c *Config) bool { return c.Server.LargeResponseBytes == 65536 }},
+		{"allowed methods", "PROXY_ALLOWED_METHODS", "continuum_rest=get| head ,rollup=GET|POST", func(c *Config) bool {
+			return strings.Join(c.Backend.AllowedMethodsFor("continuum_rest"), "|") == "GET|HEAD" &&
+				strings.Join(c.Backend.AllowedMethodsFor("rollup"), "|") == "GET|POST"
+		}},
+		{"submit queue size", "SUBMIT_QUEUE_SIZE", "64", func(c *Config) bool { return c.Backend.SubmitQueueSize == 64 }},
+		{"submit queue workers", "SUBMIT_QUEUE_WORKERS", "8", func(c *Config) bool { return c.Backend.SubmitQueueWorkers == 8 }},
+		{"submit confirmation wait", "SUBMIT_CONFIRM_WAIT_MS", "500", func(c *Config) bool { return c.Backend.SubmitConfirmWaitMs == 500 }},
+		{"max tick streams", "MAX_TICK_STREAMS", "50", func(c *Config) bool { return c.Backend.MaxTickStreams == 50 }},
+		{"excluded paths", "EXCLUDED_PATHS", "/health,/metrics", func(c *Config) bool {
+			return strings.Join(c.Server.ExcludedPaths, "|") == "/health|/metrics"
+		}},
+		{"path rewrite", "PROXY_STRIP_PREFIX", "continuum_rest=/v1, rollup=/api", func(c *Config) bool {
+			strip, add := c.Backend.PathRewriteFor("continuum_rest")
+			rollup, _ := c.Backend.PathRewriteFor("rollup")
+			return strip == "/v1" && add == "" && rollup == "/api"
+		}},
+		{"path prefix", "PROXY_ADD_PREFIX", "rollup=/api/v2", func(c *Config) bool {
+			strip, add := c.Backend.PathRewriteFor("rollup")
+			return strip == "" && add == "/api/v2"
+		}},
+		{"nonce window", "NONCE_WINDOW_MS", "60000", func(c *Config) bool { return c.Backend.NonceWindowMs == 60000 }},
+		{"nonce cache size", "NONCE_CACHE_SIZE", "500", func(c *Config) bool { return c.Backend.NonceCacheSize == 500 }},
+		{"market symbols", "MARKET_SYMBOLS", "m1=BTC-USDC, m2=ETH-USDC", func(c *Config) bool {
+			return len(c.Markets.Symbols) == 2 && c.Markets.Symbols["m1"] == "BTC-USDC" && c.Markets.Symbols["m2"] == "ETH-USDC"
+		}},
+		{"market decimals", "MARKET_DECIMALS", "m1=2", func(c *Config) bool { return len(c.Markets.Decimals) == 1 && c.Markets.Decimals["m1"] == "2" }},
+		{"CORS without credentials", "CORS_ALLOW_CREDENTIALS", "false", func(c *Config) bool { return !c.CORS.AllowCredentials }},
+		{"CORS preflight max age", "CORS_MAX_AGE_SECONDS", "600", func(c *Config) bool { return c.CORS.MaxAgeSeconds == 600 }},
+		{"signature scheme", "SIGNATURE_SCHEME", "secp256k1", func(c *Config) bool { return c.Backend.SignatureScheme == "secp256k1" }},
+		{"gRPC receive size", "GRPC_MAX_RECV_MSG_BYTES", "104857600", func(c *Config) bool { return c.Backend.GrpcMaxRecvMsgBytes == 104857600 }},
+		{"gRPC send size", "GRPC_MAX_SEND_MSG_BYTES", "1048576", func(c *Config) bool { return c.Backend.GrpcMaxSendMsgBytes == 1048576 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			if c := Load(); !tt.check(c) {
+				t.Errorf("%s=%s not loaded: %+v", tt.key, tt.value, c)
+			}
+		})
+	}
+}
@@ -2,61 +2,115 @@ package database
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
 
 	"github.com/fermilabs/fermi-api-gateway/internal/config"
 )
 
-// DB wraps the database connection pool
+// DB wraps the pgx connection pool
+// pgx caches prepared statements per connection, so repeated queries
+// (e.g. candles) skip the parse/plan round trip.
 type DB struct {
-	*sql.DB
+	*pgxpool.Pool
 }
 
 // NewDB creates a new database connection pool
 func NewDB(cfg config.DatabaseConfig) (*DB, error) {
-	// Build connection string
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
-
-	// Open database connection
-	db, err := sql.Open("postgres", dsn)
+	// Build connection string (DATABASE_URL takes precedence over individual fields)
+	dsn, err := cfg.DSN()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-	db.SetConnMaxIdleTime(1 * time.Minute)
+	poolConfig.MaxConns = 25
+	poolConfig.MinConns = 5
+	poolConfig.MaxConnLifetime = 5 * time.Minute
+	poolConfig.MaxConnIdleTime = 1 * time.Minute
 
-	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := db.PingContext(ctx); err != nil {
-		db.Close()
+	// Open database connection pool
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Test connection
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{pool}, nil
+}
+
+// ConnectWithRetry opens the database, retrying with exponential backoff so the
+// gateway can start slightly before the database is ready.
+// Retries are bounded by cfg.ConnectRetries and aborted when ctx is canceled.
+func ConnectWithRetry(ctx context.Context, cfg config.DatabaseConfig, logger *zap.Logger) (*DB, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	backoff := time.Duration(cfg.ConnectRetryIntervalMs) * time.Millisecond
+	maxBackoff := 30 * time.Second
+
+	var lastErr error
+	for attempt := 0; attempt <= cfg.ConnectRetries; attempt++ {
+		db, err := NewDB(cfg)
+		if err == nil {
+			return db, nil
+		}
+		lastErr = err
+
+		if attempt == cfg.ConnectRetries {
+			break
+		}
+
+		logger.Warn("Database connection attempt failed, retrying",
+			zap.Int("attempt", attempt+1),
+			zap.Int("max_retries", cfg.ConnectRetries),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("database connection canceled: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return nil, fmt.Errorf("database connection failed after %d attempts: %w", cfg.ConnectRetries+1, lastErr)
 }
 
-// Close closes the database connection
+// Close closes the database connection pool
 func (db *DB) Close() error {
-	if db.DB != nil {
-		return db.DB.Close()
+	if db.Pool != nil {
+		db.Pool.Close()
 	}
 	return nil
 }
 
 // Health checks database connectivity
 func (db *DB) Health(ctx context.Context) error {
-	return db.PingContext(ctx)
+	return db.Ping(ctx)
 }
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestConnectWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		retries      int
+		cancelAfter  time.Duration
+		wantErr      string
+		wantCanceled bool
+		wantWarnings int // One per failed attempt that is retried
+	}{
+		{"no retries", 0, time.Minute, "after 1 attempts", false, 0},
+		{"retries exhausted", 2, time.Minute, "after 3 attempts", false, 2},
+		{"canceled during backoff", 5, 100 * time.Millisecond, "canceled", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.WarnLevel)
+			ctx, cancel := context.WithTimeout(context.Background(), tt.cancelAfter)
+			defer cancel()
+
+			// The first backoff is 10ms, or long enough to be canceled in
+			retryIntervalMs := 10
+			if tt.wantCanceled {
+				retryIntervalMs = 10_000
+			}
+			db, err := ConnectWithRetry(ctx, unreachableDB(tt.retries, retryIntervalMs), zap.New(core))
+
+			if db != nil || err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ConnectWithRetry() = %v, %v; want error containing %q", db, err, tt.wantErr)
+			}
+			if canceled := errors.Is(err, context.DeadlineExceeded); canceled != tt.wantCanceled {
+				t.Errorf("error = %v, want canceled = %v", err, tt.wantCanceled)
+			}
+			if got := logs.FilterMessage("Database connection attempt failed, retrying").Len(); got != tt.wantWarnings {
+				t.Errorf("logged %d retries, want %d", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestNewDB_InvalidDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{"unsupported scheme", "mysql://localhost/continuum", "unsupported scheme"},
+		{"bad sslmode", "postgres://localhost/continuum?sslmode=sometimes", "failed to parse database config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := unreachableDB(0, 1)
+			cfg.URL = tt.url
+			if _, err := NewDB(cfg); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewDB() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
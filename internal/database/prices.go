@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// MarketPrice is a single market price observation (price in USDC micro-units)
+type MarketPrice struct {
+	MarketID  string    `json:"market_id"`
+	Price     float64   `json:"price"`
+	Timestamp time.Time `json:"ts"`
+}
+
+// GetLatestPrices retrieves the most recent price of each of the given markets.
+// Markets without any price are omitted.
+func (r *Repository) GetLatestPrices(ctx context.Context, marketIDs []string) ([]MarketPrice, error) {
+	defer r.observeQuery("get_latest_prices", time.Now(), zap.Strings("market_ids", marketIDs))
+
+	// DISTINCT ON walks idx_market_prices_market_ts (market_id, ts DESC), one row per market
+	query := `
+		SELECT DISTINCT ON (market_id) market_id::text, price, ts
+		FROM market_prices
+		WHERE market_id = ANY($1::uuid[])
+		ORDER BY market_id, ts DESC
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, marketIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []MarketPrice
+	for rows.Next() {
+		var p MarketPrice
+		if err := rows.Scan(&p.MarketID, &p.Price, &p.Timestamp); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		prices = append(prices, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+
+	return prices, nil
+}
+
+// GetPriceAt retrieves the most recent price of a market at or before at.
+// Returns nil if the market has no price by then.
+func (r *Repository) GetPriceAt(ctx context.Context, marketID string, at time.Time) (*MarketPrice, error) {
+	defer r.observeQuery("get_price_at", time.Now(),
+		zap.String("market_id", marketID),
+		zap.Time("at", at),
+	)
+
+	// One backward probe on idx_market_prices_market_ts (market_id, ts DESC)
+	query := `
+		SELECT market_id::text, price, ts
+		FROM market_prices
+		WHERE market_id = $1::uuid AND ts <= $2
+		ORDER BY ts DESC
+		LIMIT 1
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	var p MarketPrice
+	err = db.QueryRow(ctx, query, marketID, at).Scan(&p.MarketID, &p.Price, &p.Timestamp)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return &p, nil
+}
+
+// Market is a market that has prices, with its latest price (in USDC micro-units)
+type Market struct {
+	MarketID    string    `json:"market_id"`
+	LastPrice   float64   `json:"last_price"`
+	LastPriceAt time.Time `json:"last_price_at"`
+}
+
+// ListMarkets retrieves every market with at least one price, ordered by market ID.
+func (r *Repository) ListMarkets(ctx context.Context) ([]Market, error) {
+	defer r.observeQuery("list_markets", time.Now())
+
+	// market_prices has no market list, and DISTINCT over it would read every price.
+	// The recursive CTE skips from one market_id to the next on idx_market_prices_market_ts
+	// (one index probe per market), then the latest price of each is one more probe
+	query := `
+		WITH RECURSIVE ids AS (
+			(SELECT market_id FROM market_prices ORDER BY market_id LIMIT 1)
+			UNION ALL
+			SELECT (
+				SELECT market_id FROM market_prices
+				WHERE market_id > ids.market_id
+				ORDER BY market_id
+				LIMIT 1
+			)
+			FROM ids
+			WHERE ids.market_id IS NOT NULL
+		)
+		SELECT ids.market_id::text, latest.price, latest.ts
+		FROM ids
+		CROSS JOIN LATERAL (
+			SELECT price, ts FROM market_prices p
+			WHERE p.market_id = ids.market_id
+			ORDER BY ts DESC
+			LIMIT 1
+		) latest
+		WHERE ids.market_id IS NOT NULL
+		ORDER BY ids.market_id
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var markets []Market
+	for rows.Next() {
+		var m Market
+		if err := rows.Scan(&m.MarketID, &m.LastPrice, &m.LastPriceAt); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		markets = append(markets, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+
+	return markets, nil
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// priceEpoch is the timestamp of the first price in createMarketPrices
+var priceEpoch = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// createMarketPrices creates market_prices in the test schema with prices for two
+// markets: 100, 120 and 110 a minute apart for the first, a single 5 for the second
+func createMarketPrices(t *testing.T, db *DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := db.Exec(ctx, "CREATE TABLE market_prices (market_id UUID, ts TIMESTAMPTZ, price DOUBLE PRECISION, size DOUBLE PRECISION)")
+	if err != nil {
+		t.Fatalf("create market_prices: %v", err)
+	}
+	prices := []struct {
+		market string
+		after  time.Duration
+		price  float64
+	}{
+		{"bbbbbbbb-0000-0000-0000-000000000000", 0, 5},
+		{"aaaaaaaa-0000-0000-0000-000000000000", 0, 100},
+		{"aaaaaaaa-0000-0000-0000-000000000000", 2 * time.Minute, 110}, // Inserted out of order
+		{"aaaaaaaa-0000-0000-0000-000000000000", time.Minute, 120},
+	}
+	for _, p := range prices {
+		_, err := db.Exec(ctx, "INSERT INTO market_prices VALUES ($1, $2, $3, 1)", p.market, priceEpoch.Add(p.after), p.price)
+		if err != nil {
+			t.Fatalf("insert price: %v", err)
+		}
+	}
+}
+
+func TestRepository_ListMarkets(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices bool // Whether createMarketPrices fills market_prices
+		want   []Market
+	}{
+		{"no prices", false, nil},
+		{"latest price per market", true, []Market{
+			{MarketID: "aaaaaaaa-0000-0000-0000-000000000000", LastPrice: 110, LastPriceAt: priceEpoch.Add(2 * time.Minute)},
+			{MarketID: "bbbbbbbb-0000-0000-0000-000000000000", LastPrice: 5, LastPriceAt: priceEpoch},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testDB(t)
+			ctx := context.Background()
+			if tt.prices {
+				createMarketPrices(t, db)
+			} else if _, err := db.Exec(ctx, "CREATE TABLE market_prices (market_id UUID, ts TIMESTAMPTZ, price DOUBLE PRECISION, size DOUBLE PRECISION)"); err != nil {
+				t.Fatalf("create market_prices: %v", err)
+			}
+
+			got, err := NewRepository(db).ListMarkets(ctx)
+			if err != nil {
+				t.Fatalf("ListMarkets() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListMarkets() = %+v, want %+v", got, tt.want)
+			}
+			for i, m := range got {
+				want := tt.want[i]
+				if m.MarketID != want.MarketID || m.LastPrice != want.LastPrice || !m.LastPriceAt.Equal(want.LastPriceAt) {
+					t.Errorf("market %d = %+v, want %+v", i, m, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRepository_ListMarketsWithoutDatabase(t *testing.T) {
+	repo := NewRepository(nil)
+	if got, err := repo.ListMarkets(context.Background()); got != nil || err != ErrDatabaseUnavailable {
+		t.Errorf("ListMarkets() = %v, %v; want nil, ErrDatabaseUnavailable", got, err)
+	}
+}
+
+func TestRepository_GetPriceAt(t *testing.T) {
+	db := testDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	createMarketPrices(t, db)
+
+	const market = "aaaaaaaa-0000-0000-0000-000000000000"
+	tests := []struct {
+		name   string
+		market string
+		at     time.Time
+		want   *MarketPrice // nil = no price by then
+	}{
+		{"before the first price", market, priceEpoch.Add(-time.Second), nil},
+		{"exactly at a price", market, priceEpoch, &MarketPrice{MarketID: market, Price: 100, Timestamp: priceEpoch}},
+		{"between prices", market, priceEpoch.Add(90 * time.Second), &MarketPrice{MarketID: market, Price: 120, Timestamp: priceEpoch.Add(time.Minute)}},
+		{"after the last price", market, priceEpoch.Add(time.Hour), &MarketPrice{MarketID: market, Price: 110, Timestamp: priceEpoch.Add(2 * time.Minute)}},
+		{"other market", "bbbbbbbb-0000-0000-0000-000000000000", priceEpoch.Add(time.Hour), &MarketPrice{MarketID: "bbbbbbbb-0000-0000-0000-000000000000", Price: 5, Timestamp: priceEpoch}},
+		{"uppercase market id", "AAAAAAAA-0000-0000-0000-000000000000", priceEpoch, &MarketPrice{MarketID: market, Price: 100, Timestamp: priceEpoch}},
+		{"market without prices", "cccccccc-0000-0000-0000-000000000000", priceEpoch.Add(time.Hour), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetPriceAt(ctx, tt.market, tt.at)
+			if err != nil {
+				t.Fatalf("GetPriceAt() error = %v", err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("GetPriceAt() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.MarketID != tt.want.MarketID || got.Price != tt.want.Price || !got.Timestamp.Equal(tt.want.Timestamp) {
+				t.Errorf("GetPriceAt() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_GetPriceAtWithoutDatabase(t *testing.T) {
+	repo := NewRepository(nil)
+	if got, err := repo.GetPriceAt(context.Background(), "aaaaaaaa-0000-0000-0000-000000000000", time.Now()); got != nil || err != ErrDatabaseUnavailable {
+		t.Errorf("GetPriceAt() = %v, %v; want nil, ErrDatabaseUnavailable", got, err)
+	}
+}
@@ -4,10 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/config"
 )
 
+// ErrDatabaseUnavailable is returned when the repository has no live database connection
+var ErrDatabaseUnavailable = errors.New("database unavailable")
+
 // Transaction represents a transaction stored in the database
 type Transaction struct {
 	TxHash             string          `json:"tx_hash"`
@@ -21,6 +32,8 @@ type Transaction struct {
 	IngestionTimestamp uint64          `json:"ingestion_timestamp"`
 	TickNumber         uint64          `json:"tick_number"`
 	CreatedAt          time.Time       `json:"created_at"`
+	PayloadSize        *int64          `json:"payload_size,omitempty"` // nil when the column is NULL
+	Version            *int64          `json:"version,omitempty"`      // nil when the column is NULL
 	Metadata           json.RawMessage `json:"metadata,omitempty"`
 }
 
@@ -31,20 +44,195 @@ type OHLCCandle struct {
 	High      float64   `json:"h"` // high price
 	Low       float64   `json:"l"` // low price
 	Close     float64   `json:"c"` // close price
+	VWAP      *float64  `json:"vwap,omitempty"` // volume-weighted average price, when requested and trade sizes exist
+}
+
+// TransactionCountBucket represents the number of transactions processed within a time bucket
+type TransactionCountBucket struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int64     `json:"count"`
+}
+
+// TransactionStats holds aggregate transaction counts over a time window
+type TransactionStats struct {
+	Total   int64                    `json:"total"`
+	Buckets []TransactionCountBucket `json:"buckets"`
+}
+
+// bucketIntervals maps API timeframe/bucket names to PostgreSQL intervals
+var bucketIntervals = map[string]string{
+	"1m":  "1 minute",
+	"5m":  "5 minutes",
+	"15m": "15 minutes",
+	"1h":  "1 hour",
+	"4h":  "4 hours",
+	"1d":  "1 day",
+}
+
+// ValidBucket reports whether name is a supported timeframe/bucket (1m, 5m, 15m, 1h, 4h, 1d)
+func ValidBucket(name string) bool {
+	_, ok := bucketIntervals[name]
+	return ok
 }
 
 // Repository handles database operations for transactions
 type Repository struct {
-	db *DB
+	db                 atomic.Pointer[DB] // nil until connected; swapped by KeepConnected
+	queryDuration      *prometheus.HistogramVec
+	logger             *zap.Logger
+	slowQueryThreshold time.Duration
+	tradeSize          atomic.Bool // market_prices was found to have a size column
+}
+
+// RepositoryOption is a functional option for configuring Repository.
+type RepositoryOption func(*Repository)
+
+// WithQueryDuration records per-query latency in the given histogram (labeled by query name).
+func WithQueryDuration(histogram *prometheus.HistogramVec) RepositoryOption {
+	return func(r *Repository) {
+		r.queryDuration = histogram
+	}
+}
+
+// WithSlowQueryLog logs queries that take longer than threshold at warn level (0 disables).
+func WithSlowQueryLog(logger *zap.Logger, threshold time.Duration) RepositoryOption {
+	return func(r *Repository) {
+		r.logger = logger
+		r.slowQueryThreshold = threshold
+	}
+}
+
+// WithLogger sets the logger used for connection state changes.
+func WithLogger(logger *zap.Logger) RepositoryOption {
+	return func(r *Repository) {
+		r.logger = logger
+	}
 }
 
 // NewRepository creates a new repository instance
-func NewRepository(db *DB) *Repository {
-	return &Repository{db: db}
+// db may be nil; queries then fail with ErrDatabaseUnavailable until a
+// connection is swapped in by SetDB or KeepConnected.
+func NewRepository(db *DB, opts ...RepositoryOption) *Repository {
+	r := &Repository{
+		logger: zap.NewNop(),
+	}
+	if db != nil {
+		r.db.Store(db)
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// SetDB atomically swaps in a new database connection, closing the previous one
+func (r *Repository) SetDB(db *DB) {
+	if old := r.db.Swap(db); old != nil && old != db {
+		old.Close()
+	}
+}
+
+// Available reports whether the repository currently has a database connection
+func (r *Repository) Available() bool {
+	return r.db.Load() != nil
+}
+
+// Close closes the current database connection, if any
+func (r *Repository) Close() error {
+	if db := r.db.Swap(nil); db != nil {
+		return db.Close()
+	}
+	return nil
+}
+
+// conn returns the current database connection or ErrDatabaseUnavailable
+func (r *Repository) conn() (*DB, error) {
+	db := r.db.Load()
+	if db == nil {
+		return nil, ErrDatabaseUnavailable
+	}
+	return db, nil
+}
+
+// KeepConnected establishes the database connection in the background and
+// re-establishes it whenever a health check fails, checking every interval.
+// Without a connection it first connects with ConnectWithRetry, so callers can
+// serve (with Available reporting false) while the database comes up.
+// It blocks until ctx is canceled.
+func (r *Repository) KeepConnected(ctx context.Context, cfg config.DatabaseConfig, interval time.Duration) {
+	if r.db.Load() == nil {
+		db, err := ConnectWithRetry(ctx, cfg, r.logger)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Warn("Database connection failed - endpoints will have limited functionality until it recovers", zap.Error(err))
+		} else {
+			r.SetDB(db)
+			r.logger.Info("Database connected successfully")
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		db := r.db.Load()
+		if db == nil {
+			newDB, err := NewDB(cfg)
+			if err != nil {
+				r.logger.Debug("Database still unavailable", zap.Error(err))
+				continue
+			}
+			r.SetDB(newDB)
+			r.logger.Info("Database connection established")
+			continue
+		}
+
+		healthCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := db.Health(healthCtx)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			// Drop the connection so endpoints fall back immediately and the next tick reconnects
+			r.logger.Warn("Database health check failed, reconnecting", zap.Error(err))
+			if r.db.CompareAndSwap(db, nil) {
+				db.Close()
+			}
+		}
+	}
+}
+
+// observeQuery records how long the named query took since start and logs it if it was slow.
+// params are the key query parameters, included in the slow-query log only.
+func (r *Repository) observeQuery(name string, start time.Time, params ...zap.Field) {
+	duration := time.Since(start)
+
+	if r.queryDuration != nil {
+		r.queryDuration.WithLabelValues(name).Observe(duration.Seconds())
+	}
+
+	if r.slowQueryThreshold > 0 && duration > r.slowQueryThreshold {
+		fields := append([]zap.Field{
+			zap.String("query", name),
+			zap.Duration("duration", duration),
+			zap.Duration("threshold", r.slowQueryThreshold),
+		}, params...)
+		r.logger.Warn("Slow database query", fields...)
+	}
 }
 
 // GetTransaction retrieves a transaction by hash
 func (r *Repository) GetTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	defer r.observeQuery("get_transaction", time.Now(), zap.String("tx_hash", txHash))
+
 	query := `
 		SELECT
 			tick_number, sequence_number, tx_hash, tx_id, nonce,
@@ -55,8 +243,13 @@ func (r *Repository) GetTransaction(ctx context.Context, txHash string) (*Transa
 		LIMIT 1
 	`
 
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
 	var tx Transaction
-	err := r.db.QueryRowContext(ctx, query, txHash).Scan(
+	err = db.QueryRow(ctx, query, txHash).Scan(
 		&tx.TickNumber,
 		&tx.SequenceNumber,
 		&tx.TxHash,
@@ -70,7 +263,7 @@ func (r *Repository) GetTransaction(ctx context.Context, txHash string) (*Transa
 		&tx.CreatedAt,
 	)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, fmt.Errorf("transaction not found")
 	}
 	if err != nil {
@@ -82,6 +275,8 @@ func (r *Repository) GetTransaction(ctx context.Context, txHash string) (*Transa
 
 // GetRecentTransactions retrieves the most recent transactions
 func (r *Repository) GetRecentTransactions(ctx context.Context, limit int) ([]Transaction, error) {
+	defer r.observeQuery("get_recent_transactions", time.Now(), zap.Int("limit", limit))
+
 	query := `
 		SELECT
 			tick_number, sequence_number, tx_hash, tx_id, nonce,
@@ -92,7 +287,12 @@ func (r *Repository) GetRecentTransactions(ctx context.Context, limit int) ([]Tr
 		LIMIT $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -122,6 +322,12 @@ func (r *Repository) GetRecentTransactions(ctx context.Context, limit int) ([]Tr
 		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
+		if payloadSize.Valid {
+			tx.PayloadSize = &payloadSize.Int64
+		}
+		if version.Valid {
+			tx.Version = &version.Int64
+		}
 		transactions = append(transactions, tx)
 	}
 
@@ -132,21 +338,114 @@ func (r *Repository) GetRecentTransactions(ctx context.Context, limit int) ([]Tr
 	return transactions, nil
 }
 
+// GetTransactionStats retrieves transaction counts bucketed by processed_at within a time range
+// bucket: one of 1m, 5m, 15m, 1h, 4h, 1d
+func (r *Repository) GetTransactionStats(ctx context.Context, from, to time.Time, bucket string) (*TransactionStats, error) {
+	defer r.observeQuery("get_transaction_stats", time.Now(),
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.String("bucket", bucket),
+	)
+
+	interval, ok := bucketIntervals[bucket]
+	if !ok {
+		return nil, fmt.Errorf("invalid bucket: %s", bucket)
+	}
+
+	// Counting happens entirely in the database so callers never pull rows just to count them
+	query := `
+		SELECT time_bucket($1::interval, processed_at) AS bucket, COUNT(*) AS tx_count
+		FROM transactions
+		WHERE processed_at >= $2 AND processed_at <= $3
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, interval, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &TransactionStats{Buckets: []TransactionCountBucket{}}
+	for rows.Next() {
+		var b TransactionCountBucket
+		if err := rows.Scan(&b.Bucket, &b.Count); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		stats.Total += b.Count
+		stats.Buckets = append(stats.Buckets, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+
+	return stats, nil
+}
+
+// HasTradeSize reports whether market_prices records trade sizes (a size column),
+// which VWAP needs. Only a positive answer is cached, so a column added by a later
+// migration is picked up without a restart
+func (r *Repository) HasTradeSize(ctx context.Context) (bool, error) {
+	if r.tradeSize.Load() {
+		return true, nil
+	}
+
+	db, err := r.conn()
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema()
+			  AND table_name = 'market_prices' AND column_name = 'size'
+		)
+	`).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query failed: %w", err)
+	}
+
+	if exists {
+		r.tradeSize.Store(true)
+	}
+	return exists, nil
+}
+
+// vwapColumn returns the select expression for the VWAP column of a candle query over
+// rows aliased alias: SUM(price*size)/SUM(size) when vwap is set, NULL otherwise
+// (so both variants scan the same way). Buckets without size data get NULL
+func vwapColumn(alias string, vwap bool) string {
+	if !vwap {
+		return "NULL::double precision AS vwap"
+	}
+	return fmt.Sprintf("SUM(%[1]s.price * %[1]s.size) / NULLIF(SUM(%[1]s.size), 0) AS vwap", alias)
+}
+
 // GetMarketCandles retrieves OHLC candles for a market within a time range
 // This queries the market_prices table (or equivalent) using TimescaleDB's time_bucket function
 // limit: maximum number of candles to return (Binance-style: default 500, max 1000)
-func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, timeframe string, from, to time.Time, limit int) ([]OHLCCandle, error) {
-	// Map timeframe to PostgreSQL interval
-	intervalMap := map[string]string{
-		"1m":  "1 minute",
-		"5m":  "5 minutes",
-		"15m": "15 minutes",
-		"1h":  "1 hour",
-		"4h":  "4 hours",
-		"1d":  "1 day",
-	}
+// vwap: also compute each candle's VWAP; requires trade sizes (see HasTradeSize)
+func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, timeframe string, from, to time.Time, limit int, vwap bool) ([]OHLCCandle, error) {
+	defer r.observeQuery("get_market_candles", time.Now(),
+		zap.String("market_id", marketID),
+		zap.String("timeframe", timeframe),
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.Int("limit", limit),
+		zap.Bool("vwap", vwap),
+	)
 
-	interval, ok := intervalMap[timeframe]
+	// Map timeframe to PostgreSQL interval
+	interval, ok := bucketIntervals[timeframe]
 	if !ok {
 		return nil, fmt.Errorf("invalid timeframe: %s", timeframe)
 	}
@@ -166,9 +465,13 @@ func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, time
 	// - idx_market_prices_market_ts_price (covering index for index-only scans)
 	//
 	// Note: Includes incomplete buckets (latest candle) so users can see current price.
+	sizeColumn := ""
+	if vwap {
+		sizeColumn = ", size"
+	}
 	query := `
 		WITH bucketed AS (
-			SELECT time_bucket($1::interval, ts) AS bucket, price, ts
+			SELECT time_bucket($1::interval, ts) AS bucket, price, ts` + sizeColumn + `
 			FROM market_prices
 			WHERE market_id = $2::uuid AND ts >= $3 AND ts <= $4
 		),
@@ -187,7 +490,8 @@ func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, time
 			fp.open_price,
 			MAX(b.price) AS high_price,
 			MIN(b.price) AS low_price,
-			lp.close_price
+			lp.close_price,
+			` + vwapColumn("b", vwap) + `
 		FROM bucketed b
 		INNER JOIN first_prices fp ON b.bucket = fp.bucket
 		INNER JOIN last_prices lp ON b.bucket = lp.bucket
@@ -196,7 +500,12 @@ func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, time
 		LIMIT $5
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, interval, marketID, from, to, limit)
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, interval, marketID, from, to, limit)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -211,6 +520,7 @@ func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, time
 			&candle.High,
 			&candle.Low,
 			&candle.Close,
+			&candle.VWAP,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
@@ -230,3 +540,69 @@ func (r *Repository) GetMarketCandles(ctx context.Context, marketID string, time
 
 	return candles, nil
 }
+
+// GetLatestCandle retrieves only the most recent (possibly incomplete) candle for a market
+// Returns nil if the market has no prices
+// vwap: also compute the candle's VWAP; requires trade sizes (see HasTradeSize)
+func (r *Repository) GetLatestCandle(ctx context.Context, marketID string, timeframe string, vwap bool) (*OHLCCandle, error) {
+	defer r.observeQuery("get_latest_candle", time.Now(),
+		zap.String("market_id", marketID),
+		zap.String("timeframe", timeframe),
+		zap.Bool("vwap", vwap),
+	)
+
+	interval, ok := bucketIntervals[timeframe]
+	if !ok {
+		return nil, fmt.Errorf("invalid timeframe: %s", timeframe)
+	}
+
+	// The newest price (ORDER BY ts DESC LIMIT 1 on idx_market_prices_market_ts) fixes the
+	// current bucket; only rows from that bucket's start are aggregated, never the full range
+	query := `
+		WITH latest AS (
+			SELECT time_bucket($1::interval, ts) AS bucket, price AS close_price
+			FROM market_prices
+			WHERE market_id = $2::uuid
+			ORDER BY ts DESC
+			LIMIT 1
+		)
+		SELECT
+			l.bucket,
+			(
+				SELECT price FROM market_prices
+				WHERE market_id = $2::uuid AND ts >= l.bucket
+				ORDER BY ts ASC
+				LIMIT 1
+			) AS open_price,
+			MAX(p.price) AS high_price,
+			MIN(p.price) AS low_price,
+			l.close_price,
+			` + vwapColumn("p", vwap) + `
+		FROM latest l
+		INNER JOIN market_prices p ON p.market_id = $2::uuid AND p.ts >= l.bucket
+		GROUP BY l.bucket, l.close_price
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	var candle OHLCCandle
+	err = db.QueryRow(ctx, query, interval, marketID).Scan(
+		&candle.Timestamp,
+		&candle.Open,
+		&candle.High,
+		&candle.Low,
+		&candle.Close,
+		&candle.VWAP,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return &candle, nil
+}
@@ -0,0 +1,596 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/config"
+)
+
+// testDB connects to TEST_DATABASE_URL with a fresh, empty schema first on the
+// search path, dropped after the test. Tests using it are skipped without the variable
+func testDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("gateway_test_%d", time.Now().UnixNano())
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close()
+	})
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parse DSN: %v", err)
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return &DB{pool}
+}
+
+// unreachableDB points at a port nothing listens on, so every connection attempt fails fast
+func unreachableDB(retries, retryIntervalMs int) config.DatabaseConfig {
+	return config.DatabaseConfig{
+		URL:                    "postgres://gateway@127.0.0.1:1/continuum?connect_timeout=1",
+		SSLMode:                "disable",
+		ConnectRetries:         retries,
+		ConnectRetryIntervalMs: retryIntervalMs,
+	}
+}
+
+func TestRepository_KeepConnectedWithoutDatabase(t *testing.T) {
+	tests := []struct {
+		name            string
+		retries         int
+		retryIntervalMs int
+		cancelAfter     time.Duration
+	}{
+		{"canceled while retrying", 5, 1000, 50 * time.Millisecond},
+		{"retries exhausted", 1, 1, 200 * time.Millisecond},
+		{"no retries", 0, 1000, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewRepository(nil)
+			if repo.Available() {
+				t.Fatal("Available() = true before connecting")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.cancelAfter)
+			defer cancel()
+
+			done := make(chan struct{})
+			go func() {
+				repo.KeepConnected(ctx, unreachableDB(tt.retries, tt.retryIntervalMs), time.Hour)
+				close(done)
+			}()
+
+			// Callers keep serving while it tries; it returns promptly once canceled
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("KeepConnected did not return after cancel")
+			}
+			if repo.Available() {
+				t.Error("Available() = true without a database")
+			}
+			if _, err := repo.conn(); err != ErrDatabaseUnavailable {
+				t.Errorf("conn() error = %v, want ErrDatabaseUnavailable", err)
+			}
+		})
+	}
+}
+
+func TestValidBucket(t *testing.T) {
+	tests := []struct {
+		bucket string
+		want   bool
+	}{
+		{"1m", true},
+		{"5m", true},
+		{"15m", true},
+		{"1h", true},
+		{"4h", true},
+		{"1d", true},
+		{"", false},
+		{"2m", false},
+		{"1H", false},
+		{"1 minute", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucket, func(t *testing.T) {
+			if got := ValidBucket(tt.bucket); got != tt.want {
+				t.Errorf("ValidBucket(%q) = %v, want %v", tt.bucket, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_HasTradeSize(t *testing.T) {
+	db := testDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	// Each step runs against the same repository, in order
+	steps := []struct {
+		name string
+		ddl  string
+		want bool
+	}{
+		{"no market_prices table", "", false},
+		{"no size column", "CREATE TABLE market_prices (market_id TEXT, ts TIMESTAMPTZ, price DOUBLE PRECISION)", false},
+		{"size column added later", "ALTER TABLE market_prices ADD COLUMN size DOUBLE PRECISION", true},
+		{"positive answer cached", "ALTER TABLE market_prices DROP COLUMN size", true},
+	}
+
+	for _, step := range steps {
+		if step.ddl != "" {
+			if _, err := db.Exec(ctx, step.ddl); err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+		}
+		got, err := repo.HasTradeSize(ctx)
+		if err != nil {
+			t.Fatalf("%s: HasTradeSize() error = %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: HasTradeSize() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestRepository_HasTradeSizeWithoutDatabase(t *testing.T) {
+	repo := NewRepository(nil)
+	if got, err := repo.HasTradeSize(context.Background()); got || err != ErrDatabaseUnavailable {
+		t.Errorf("HasTradeSize() = %v, %v; want false, ErrDatabaseUnavailable", got, err)
+	}
+}
+
+// querySamples returns the number of latency observations per query label
+func querySamples(t *testing.T, registry *prometheus.Registry) map[string]uint64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	samples := make(map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "query" {
+					samples[label.GetValue()] = metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return samples
+}
+
+func TestRepository_QueryDuration(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	tests := []struct {
+		query string
+		call  func(r *Repository) error
+	}{
+		{"get_transaction", func(r *Repository) error { _, err := r.GetTransaction(ctx, "abc"); return err }},
+		{"get_recent_transactions", func(r *Repository) error { _, err := r.GetRecentTransactions(ctx, 10); return err }},
+		{"get_transaction_stats", func(r *Repository) error {
+			_, err := r.GetTransactionStats(ctx, now.Add(-time.Hour), now, "1m")
+			return err
+		}},
+		{"get_market_candles", func(r *Repository) error {
+			_, err := r.GetMarketCandles(ctx, "m1", "1h", now.Add(-time.Hour), now, 10, false)
+			return err
+		}},
+		{"get_latest_candle", func(r *Repository) error { _, err := r.GetLatestCandle(ctx, "m1", "1h", false); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "db_query_duration_seconds"}, []string{"query"})
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(histogram)
+			repo := NewRepository(nil, WithQueryDuration(histogram))
+
+			// Failed queries are timed too
+			if err := tt.call(repo); err != ErrDatabaseUnavailable {
+				t.Errorf("error = %v, want ErrDatabaseUnavailable", err)
+			}
+			samples := querySamples(t, registry)
+			if samples[tt.query] != 1 || len(samples) != 1 {
+				t.Errorf("samples = %v, want one for %s", samples, tt.query)
+			}
+		})
+	}
+}
+
+func TestRepository_WithoutQueryDuration(t *testing.T) {
+	// Without a histogram queries still run (and fail) normally
+	if _, err := NewRepository(nil).GetTransaction(context.Background(), "abc"); err != ErrDatabaseUnavailable {
+		t.Errorf("error = %v, want ErrDatabaseUnavailable", err)
+	}
+}
+
+func TestRepository_SlowQueryLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		wantLog   bool
+	}{
+		{"disabled", 0, time.Second, false},
+		{"under threshold", time.Minute, time.Millisecond, false},
+		{"over threshold", 10 * time.Millisecond, time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			repo := NewRepository(nil, WithSlowQueryLog(zap.New(core), tt.threshold))
+
+			repo.observeQuery("get_ticks", time.Now().Add(-tt.elapsed), zap.Uint64("from", 1))
+
+			entries := logs.FilterMessage("Slow database query").All()
+			if (len(entries) == 1) != tt.wantLog || len(entries) > 1 {
+				t.Fatalf("logged %d slow queries, want logged = %v", len(entries), tt.wantLog)
+			}
+			if !tt.wantLog {
+				return
+			}
+			fields := entries[0].ContextMap()
+			if entries[0].Level != zapcore.WarnLevel || fields["query"] != "get_ticks" || fields["from"] != uint64(1) {
+				t.Errorf("entry = %v %v, want a warning with the query name and parameters", entries[0].Level, fields)
+			}
+			if _, ok := fields["threshold"]; !ok {
+				t.Errorf("fields = %v, want the threshold", fields)
+			}
+		})
+	}
+}
+
+// lazyDB returns a DB that never connected, so its health checks fail
+func lazyDB(t *testing.T) *DB {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), unreachableDB(0, 1).URL)
+	if err != nil {
+		t.Fatalf("pgxpool.New() error = %v", err)
+	}
+	return &DB{pool}
+}
+
+func TestRepository_KeepConnectedReconnects(t *testing.T) {
+	tests := []struct {
+		name        string
+		initial     bool // Start with a (broken) connection
+		wantMessage string
+	}{
+		{"retries while unavailable", false, "Database still unavailable"},
+		{"drops a failing connection", true, "Database health check failed, reconnecting"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			repo := NewRepository(nil, WithLogger(zap.New(core)))
+			if tt.initial {
+				repo.SetDB(lazyDB(t))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			repo.KeepConnected(ctx, unreachableDB(0, 1), 20*time.Millisecond)
+
+			if got := logs.FilterMessage(tt.wantMessage).Len(); got == 0 {
+				t.Errorf("no %q log; got %v", tt.wantMessage, logs.All())
+			}
+			// After dropping the connection it keeps trying to reconnect
+			if got := logs.FilterMessage("Database still unavailable").Len(); got < 2 {
+				t.Errorf("logged %d reconnect attempts, want several", got)
+			}
+			if repo.Available() {
+				t.Error("Available() = true with a failing database")
+			}
+		})
+	}
+}
+
+func TestRepository_SetDB(t *testing.T) {
+	repo := NewRepository(nil)
+	first, second := lazyDB(t), lazyDB(t)
+
+	repo.SetDB(first)
+	if !repo.Available() {
+		t.Fatal("Available() = false after SetDB")
+	}
+	repo.SetDB(first) // Swapping in the same connection keeps it open
+	if _, err := first.Acquire(context.Background()); err == nil || strings.Contains(err.Error(), "closed pool") {
+		t.Fatalf("Acquire() error = %v, want a dial error from an open pool", err)
+	}
+
+	repo.SetDB(second)
+	if db, err := repo.conn(); err != nil || db != second {
+		t.Errorf("conn() = %p, %v; want the second connection", db, err)
+	}
+	// The replaced pool is closed, so it fails without dialing
+	if _, err := first.Acquire(context.Background()); err == nil || !strings.Contains(err.Error(), "closed pool") {
+		t.Errorf("replaced pool Acquire() error = %v, want closed pool", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if repo.Available() {
+		t.Error("Available() = true after Close")
+	}
+}
+
+// createTransactions creates the sequencer's transactions table in the test schema
+// and inserts one row per hash, processed one second apart in order
+func createTransactions(t *testing.T, db *DB, start time.Time, hashes ...string) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := db.Exec(ctx, `
+		CREATE TABLE transactions (
+			tick_number BIGINT NOT NULL,
+			sequence_number BIGINT NOT NULL,
+			tx_hash TEXT NOT NULL,
+			tx_id TEXT NOT NULL,
+			nonce BIGINT NOT NULL,
+			payload BYTEA,
+			timestamp_us BIGINT NOT NULL,
+			public_key BYTEA NOT NULL,
+			signature BYTEA NOT NULL,
+			ingestion_timestamp BIGINT NOT NULL,
+			processed_at TIMESTAMPTZ NOT NULL,
+			payload_size BIGINT,
+			version BIGINT
+		)`)
+	if err != nil {
+		t.Fatalf("create transactions: %v", err)
+	}
+	for i, hash := range hashes {
+		_, err := db.Exec(ctx, `
+			INSERT INTO transactions VALUES ($1, $1, $2, $3, $1, 'payload', 1700000000000000, 'key', 'sig', 1700000000000000, $4, NULL, 1)`,
+			i+1, hash, "id-"+hash, start.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("insert %s: %v", hash, err)
+		}
+	}
+}
+
+func TestNewDB_Pool(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := NewDB(config.DatabaseConfig{URL: dsn})
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	defer db.Close()
+
+	if got := db.Config().MaxConns; got != 25 {
+		t.Errorf("MaxConns = %d, want 25", got)
+	}
+	if err := db.Health(context.Background()); err != nil {
+		t.Errorf("Health() error = %v", err)
+	}
+}
+
+func TestRepository_GetTransaction(t *testing.T) {
+	db := testDB(t)
+	createTransactions(t, db, time.Now().Add(-time.Minute), "aa", "bb")
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		hash     string
+		wantTick uint64
+		wantErr  string
+	}{
+		{"aa", 1, ""},
+		{"bb", 2, ""},
+		{"cc", 0, "transaction not found"},
+	}
+
+	// Each lookup runs twice, the second time through pgx's cached prepared statement
+	for range 2 {
+		for _, tt := range tests {
+			tx, err := repo.GetTransaction(ctx, tt.hash)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("GetTransaction(%q) error = %v, want %q", tt.hash, err, tt.wantErr)
+				}
+				continue
+			}
+			if err != nil || tx.TickNumber != tt.wantTick || tx.TxID != "id-"+tt.hash {
+				t.Errorf("GetTransaction(%q) = %+v, %v; want tick %d", tt.hash, tx, err, tt.wantTick)
+			}
+		}
+	}
+
+	recent, err := repo.GetRecentTransactions(ctx, 10)
+	if err != nil || len(recent) != 2 || recent[0].TxHash != "bb" {
+		t.Fatalf("GetRecentTransactions() = %+v, %v; want bb then aa", recent, err)
+	}
+	if recent[0].PayloadSize != nil || recent[0].Version == nil || *recent[0].Version != 1 {
+		t.Errorf("nullable columns = %v, %v; want nil payload size and version 1", recent[0].PayloadSize, recent[0].Version)
+	}
+}
+
+func TestRepository_GetRecentTransactionsSizes(t *testing.T) {
+	db := testDB(t)
+	createTransactions(t, db, time.Now())
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	ptr := func(n int64) *int64 { return &n }
+	tests := []struct {
+		hash        string
+		payloadSize *int64
+		version     *int64
+	}{
+		{"aa", ptr(256), ptr(2)},
+		{"bb", nil, ptr(1)},
+		{"cc", ptr(0), nil},
+		{"dd", nil, nil},
+	}
+
+	start := time.Now().Add(-time.Minute)
+	for i, tt := range tests {
+		_, err := db.Exec(ctx, `
+			INSERT INTO transactions VALUES ($1, $1, $2, $2, $1, 'payload', 1700000000000000, 'key', 'sig', 1700000000000000, $3, $4, $5)`,
+			i+1, tt.hash, start.Add(-time.Duration(i)*time.Second), tt.payloadSize, tt.version)
+		if err != nil {
+			t.Fatalf("insert %s: %v", tt.hash, err)
+		}
+	}
+
+	recent, err := repo.GetRecentTransactions(ctx, len(tests))
+	if err != nil || len(recent) != len(tests) {
+		t.Fatalf("GetRecentTransactions() = %d transactions, %v; want %d", len(recent), err, len(tests))
+	}
+	equal := func(a, b *int64) bool { return (a == nil && b == nil) || (a != nil && b != nil && *a == *b) }
+	for i, tt := range tests {
+		got := recent[i]
+		if got.TxHash != tt.hash || !equal(got.PayloadSize, tt.payloadSize) || !equal(got.Version, tt.version) {
+			t.Errorf("transaction %d = %s size %v version %v; want %s size %v version %v",
+				i, got.TxHash, got.PayloadSize, got.Version, tt.hash, tt.payloadSize, tt.version)
+		}
+	}
+}
+
+func TestTransaction_JSONSizes(t *testing.T) {
+	size, version := int64(256), int64(2)
+	tests := []struct {
+		name        string
+		tx          Transaction
+		wantPresent []string
+		wantAbsent  []string
+	}{
+		{"both set", Transaction{PayloadSize: &size, Version: &version}, []string{`"payload_size":256`, `"version":2`}, nil},
+		{"NULL columns", Transaction{}, nil, []string{`"payload_size"`, `"version"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.tx)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			for _, field := range tt.wantPresent {
+				if !strings.Contains(string(data), field) {
+					t.Errorf("JSON %s missing %s", data, field)
+				}
+			}
+			for _, field := range tt.wantAbsent {
+				if strings.Contains(string(data), field) {
+					t.Errorf("JSON %s has %s", data, field)
+				}
+			}
+		})
+	}
+}
+
+func TestRepository_GetLatestCandle(t *testing.T) {
+	db := testDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	const market = "11111111-1111-1111-1111-111111111111"
+	_, err := db.Exec(ctx, "CREATE TABLE market_prices (market_id UUID, ts TIMESTAMPTZ, price DOUBLE PRECISION, size DOUBLE PRECISION)")
+	if err != nil {
+		t.Fatalf("create market_prices: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	prices := []struct {
+		after       time.Duration
+		price, size float64
+	}{
+		{0, 100, 1},
+		{30 * time.Minute, 120, 1},
+		{65 * time.Minute, 110, 1}, // The 11:00 bucket starts here
+		{80 * time.Minute, 130, 2},
+		{100 * time.Minute, 105, 1},
+	}
+	for _, p := range prices {
+		_, err := db.Exec(ctx, "INSERT INTO market_prices VALUES ($1, $2, $3, $4)", market, start.Add(p.after), p.price, p.size)
+		if err != nil {
+			t.Fatalf("insert price: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		market    string
+		timeframe string
+		vwap      bool
+		want      *OHLCCandle
+		wantErr   bool
+	}{
+		{"hourly", market, "1h", false, &OHLCCandle{Timestamp: start.Add(time.Hour), Open: 110, High: 130, Low: 105, Close: 105}, false},
+		{"hourly with VWAP", market, "1h", true, &OHLCCandle{Timestamp: start.Add(time.Hour), Open: 110, High: 130, Low: 105, Close: 105}, false},
+		{"daily", market, "1d", false, &OHLCCandle{Timestamp: start.Add(-10 * time.Hour), Open: 100, High: 130, Low: 100, Close: 105}, false},
+		{"single price bucket", market, "1m", false, &OHLCCandle{Timestamp: start.Add(100 * time.Minute), Open: 105, High: 105, Low: 105, Close: 105}, false},
+		{"no prices", "22222222-2222-2222-2222-222222222222", "1h", false, nil, false},
+		{"invalid timeframe", market, "2h", false, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetLatestCandle(ctx, tt.market, tt.timeframe, tt.vwap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLatestCandle() error = %v, want error = %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("GetLatestCandle() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("GetLatestCandle() = nil, want a candle")
+			}
+			if !got.Timestamp.Equal(tt.want.Timestamp) || got.Open != tt.want.Open || got.High != tt.want.High ||
+				got.Low != tt.want.Low || got.Close != tt.want.Close {
+				t.Errorf("GetLatestCandle() = %+v, want %+v", got, tt.want)
+			}
+			// (110*1 + 130*2 + 105*1) / 4
+			if tt.vwap != (got.VWAP != nil) || (got.VWAP != nil && *got.VWAP != 118.75) {
+				t.Errorf("VWAP = %v, want 118.75 only with vwap = true (vwap = %v)", got.VWAP, tt.vwap)
+			}
+		})
+	}
+}
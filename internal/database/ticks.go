@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// TickStats summarizes the transactions in one tick
+type TickStats struct {
+	TickNumber       uint64 `json:"tick_number"`
+	TransactionCount int64  `json:"transaction_count"`
+	PayloadBytes     int64  `json:"payload_bytes"` // Sum of transaction payload sizes
+}
+
+// ErrTickNotFound is returned when no stored tick matches a lookup
+var ErrTickNotFound = errors.New("tick not found")
+
+// tickColumns selects a tick joined with its VDF proof; scanned by scanTick
+const tickColumns = `
+	t.tick_number, t.timestamp, t.batch_hash,
+	v.input, v.output, v.proof, v.iterations
+`
+
+// Tick represents a tick written by the tick ingester
+type Tick struct {
+	TickNumber uint64    `json:"tick_number"`
+	Timestamp  time.Time `json:"timestamp"`
+	BatchHash  string    `json:"batch_hash"`
+	VDFProof   *VDFProof `json:"vdf_proof,omitempty"` // nil if the proof row is missing
+}
+
+// VDFProof is the VDF proof stored alongside a tick (hex-encoded strings)
+type VDFProof struct {
+	Input      string `json:"input"`
+	Output     string `json:"output"`
+	Proof      string `json:"proof"`
+	Iterations uint64 `json:"iterations"`
+}
+
+// GetTicks retrieves ticks with numbers in [fromTick, toTick], ascending, with their VDF proofs
+func (r *Repository) GetTicks(ctx context.Context, fromTick, toTick uint64, limit int) ([]Tick, error) {
+	defer r.observeQuery("get_ticks", time.Now(),
+		zap.Uint64("from_tick", fromTick),
+		zap.Uint64("to_tick", toTick),
+		zap.Int("limit", limit),
+	)
+
+	query := `
+		SELECT` + tickColumns + `
+		FROM ticks t
+		LEFT JOIN vdf_proofs v ON v.tick_number = t.tick_number
+		WHERE t.tick_number >= $1 AND t.tick_number <= $2
+		ORDER BY t.tick_number ASC
+		LIMIT $3
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, int64(fromTick), int64(toTick), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	ticks := []Tick{}
+	for rows.Next() {
+		tick, err := scanTick(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		ticks = append(ticks, *tick)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+
+	return ticks, nil
+}
+
+// GetTickByBatchHash retrieves the tick whose transaction batch hash is batchHash
+func (r *Repository) GetTickByBatchHash(ctx context.Context, batchHash string) (*Tick, error) {
+	defer r.observeQuery("get_tick_by_batch_hash", time.Now(), zap.String("batch_hash", batchHash))
+
+	query := `
+		SELECT` + tickColumns + `
+		FROM ticks t
+		LEFT JOIN vdf_proofs v ON v.tick_number = t.tick_number
+		WHERE t.batch_hash = $1
+		LIMIT 1
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	tick, err := scanTick(db.QueryRow(ctx, query, batchHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTickNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return tick, nil
+}
+
+// scanTick scans a row selected with tickColumns
+func scanTick(row pgx.Row) (*Tick, error) {
+	var tick Tick
+	var input, output, proof sql.NullString
+	var iterations sql.NullInt64
+
+	if err := row.Scan(
+		&tick.TickNumber,
+		&tick.Timestamp,
+		&tick.BatchHash,
+		&input,
+		&output,
+		&proof,
+		&iterations,
+	); err != nil {
+		return nil, err
+	}
+
+	if input.Valid {
+		tick.VDFProof = &VDFProof{
+			Input:      input.String,
+			Output:     output.String,
+			Proof:      proof.String,
+			Iterations: uint64(iterations.Int64),
+		}
+	}
+
+	return &tick, nil
+}
+
+// GetTransactionsByTick retrieves the transactions in a tick, in sequence order
+func (r *Repository) GetTransactionsByTick(ctx context.Context, tickNumber uint64) ([]Transaction, error) {
+	defer r.observeQuery("get_transactions_by_tick", time.Now(), zap.Uint64("tick_number", tickNumber))
+
+	query := `
+		SELECT
+			tick_number, sequence_number, tx_hash, tx_id, nonce,
+			payload, timestamp, public_key, signature, tick_timestamp,
+			octet_length(payload)
+		FROM tick_transactions
+		WHERE tick_number = $1
+		ORDER BY sequence_number
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, int64(tickNumber))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var tx Transaction
+		var clientTimestamp time.Time
+		var payloadSize sql.NullInt64
+
+		if err := rows.Scan(
+			&tx.TickNumber,
+			&tx.SequenceNumber,
+			&tx.TxHash,
+			&tx.TxID,
+			&tx.Nonce,
+			&tx.Payload,
+			&clientTimestamp,
+			&tx.PublicKey,
+			&tx.Signature,
+			&tx.CreatedAt,
+			&payloadSize,
+		); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		tx.ClientTimestamp = uint64(clientTimestamp.UnixMicro())
+		if payloadSize.Valid {
+			tx.PayloadSize = &payloadSize.Int64
+		}
+		transactions = append(transactions, tx)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTickStats computes per-tick transaction counts and payload byte totals for ticks in [fromTick, toTick]
+func (r *Repository) GetTickStats(ctx context.Context, fromTick, toTick uint64) ([]TickStats, error) {
+	defer r.observeQuery("get_tick_stats", time.Now(),
+		zap.Uint64("from_tick", fromTick),
+		zap.Uint64("to_tick", toTick),
+	)
+
+	// Aggregation happens in the database; ticks without transactions report zeros
+	query := `
+		SELECT
+			t.tick_number,
+			COUNT(tt.tx_hash) AS transaction_count,
+			COALESCE(SUM(octet_length(tt.payload)), 0) AS payload_bytes
+		FROM ticks t
+		LEFT JOIN tick_transactions tt ON tt.tick_number = t.tick_number
+		WHERE t.tick_number >= $1 AND t.tick_number <= $2
+		GROUP BY t.tick_number
+		ORDER BY t.tick_number ASC
+	`
+
+	db, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(ctx, query, int64(fromTick), int64(toTick))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []TickStats{}
+	for rows.Next() {
+		var s TickStats
+		if err := rows.Scan(&s.TickNumber, &s.TransactionCount, &s.PayloadBytes); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+
+	return stats, nil
+}
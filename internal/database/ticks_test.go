@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// tickEpoch is the timestamp of tick 0 in createTicks
+var tickEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// createTicks creates the ingester's tick tables in the test schema and inserts
+// ticks 1-3 with batch hashes ba01-ba03. Tick 1 has a proof and two transactions,
+// tick 2 has neither and tick 3 has a proof and one transaction without a payload
+func createTicks(t *testing.T, db *DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	statements := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{sql: `CREATE TABLE ticks (
+			tick_number BIGINT NOT NULL UNIQUE,
+			timestamp TIMESTAMPTZ NOT NULL,
+			batch_hash TEXT NOT NULL
+		)`},
+		{sql: `CREATE TABLE vdf_proofs (
+			tick_number BIGINT PRIMARY KEY,
+			input TEXT NOT NULL,
+			output TEXT NOT NULL,
+			proof TEXT NOT NULL,
+			iterations BIGINT NOT NULL
+		)`},
+		{sql: `CREATE TABLE tick_transactions (
+			tx_hash TEXT NOT NULL UNIQUE,
+			tx_id TEXT NOT NULL,
+			tick_number BIGINT NOT NULL,
+			sequence_number BIGINT NOT NULL,
+			payload BYTEA,
+			signature BYTEA NOT NULL,
+			public_key BYTEA NOT NULL,
+			nonce BIGINT NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			tick_timestamp TIMESTAMPTZ NOT NULL
+		)`},
+		{sql: `INSERT INTO ticks VALUES
+			(1, $1::timestamptz + interval '1 second', 'ba01'),
+			(2, $1::timestamptz + interval '2 seconds', 'ba02'),
+			(3, $1::timestamptz + interval '3 seconds', 'ba03')`, args: []interface{}{tickEpoch}},
+		{sql: `INSERT INTO vdf_proofs VALUES (1, 'in1', 'out1', 'proof1', 10), (3, 'in3', 'out3', 'proof3', 30)`},
+		// Inserted out of sequence order
+		{sql: `INSERT INTO tick_transactions VALUES
+			('tx12', 'id12', 1, 12, 'abcd', 'sig', 'key', 2, $1, $1),
+			('tx11', 'id11', 1, 11, 'ab', 'sig', 'key', 1, $1, $1),
+			('tx31', 'id31', 3, 31, NULL, 'sig', 'key', 3, $1, $1)`, args: []interface{}{tickEpoch}},
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(ctx, statement.sql, statement.args...); err != nil {
+			t.Fatalf("create ticks: %v", err)
+		}
+	}
+}
+
+func TestRepository_GetTickByBatchHash(t *testing.T) {
+	db := testDB(t)
+	createTicks(t, db)
+	repo := NewRepository(db)
+
+	tests := []struct {
+		hash      string
+		wantTick  uint64
+		wantProof string // VDF proof output, empty for none
+		wantErr   error
+	}{
+		{"ba01", 1, "out1", nil},
+		{"ba02", 2, "", nil},
+		{"ba03", 3, "out3", nil},
+		{"ba04", 0, "", ErrTickNotFound},
+		{"BA01", 0, "", ErrTickNotFound}, // Hashes are matched exactly
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hash, func(t *testing.T) {
+			tick, err := repo.GetTickByBatchHash(context.Background(), tt.hash)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetTickByBatchHash() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTickByBatchHash() error = %v", err)
+			}
+			if tick.TickNumber != tt.wantTick || tick.BatchHash != tt.hash {
+				t.Errorf("tick = %d %s, want %d %s", tick.TickNumber, tick.BatchHash, tt.wantTick, tt.hash)
+			}
+			if want := tickEpoch.Add(time.Duration(tt.wantTick) * time.Second); !tick.Timestamp.Equal(want) {
+				t.Errorf("timestamp = %v, want %v", tick.Timestamp, want)
+			}
+			if (tick.VDFProof == nil) != (tt.wantProof == "") || (tick.VDFProof != nil && tick.VDFProof.Output != tt.wantProof) {
+				t.Errorf("VDF proof = %+v, want output %q", tick.VDFProof, tt.wantProof)
+			}
+		})
+	}
+}
+
+func TestRepository_GetTransactionsByTick(t *testing.T) {
+	db := testDB(t)
+	createTicks(t, db)
+	repo := NewRepository(db)
+
+	tests := []struct {
+		name       string
+		tick       uint64
+		wantHashes []string // In sequence order
+		wantSizes  []int64  // -1 for a NULL payload
+	}{
+		{"several transactions", 1, []string{"tx11", "tx12"}, []int64{2, 4}},
+		{"no transactions", 2, []string{}, []int64{}},
+		{"no payload", 3, []string{"tx31"}, []int64{-1}},
+		{"unknown tick", 4, []string{}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, err := repo.GetTransactionsByTick(context.Background(), tt.tick)
+			if err != nil {
+				t.Fatalf("GetTransactionsByTick() error = %v", err)
+			}
+			if transactions == nil {
+				t.Error("GetTransactionsByTick() = nil, want an empty slice")
+			}
+			hashes := []string{}
+			sizes := []int64{}
+			for _, tx := range transactions {
+				hashes = append(hashes, tx.TxHash)
+				size := int64(-1)
+				if tx.PayloadSize != nil {
+					size = *tx.PayloadSize
+				}
+				sizes = append(sizes, size)
+				if tx.TickNumber != tt.tick {
+					t.Errorf("%s: tick = %d, want %d", tx.TxHash, tx.TickNumber, tt.tick)
+				}
+				if tx.ClientTimestamp != uint64(tickEpoch.UnixMicro()) {
+					t.Errorf("%s: client timestamp = %d, want %d", tx.TxHash, tx.ClientTimestamp, tickEpoch.UnixMicro())
+				}
+			}
+			if !slices.Equal(hashes, tt.wantHashes) || !slices.Equal(sizes, tt.wantSizes) {
+				t.Errorf("transactions = %v sizes %v, want %v sizes %v", hashes, sizes, tt.wantHashes, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestRepository_GetTickStats(t *testing.T) {
+	db := testDB(t)
+	createTicks(t, db)
+	repo := NewRepository(db)
+
+	tests := []struct {
+		name     string
+		from, to uint64
+		want     []TickStats
+	}{
+		{"all ticks", 1, 3, []TickStats{{1, 2, 6}, {2, 0, 0}, {3, 1, 0}}},
+		{"single tick", 1, 1, []TickStats{{1, 2, 6}}},
+		{"tick without transactions", 2, 2, []TickStats{{2, 0, 0}}},
+		{"range past the last tick", 3, 10, []TickStats{{3, 1, 0}}},
+		{"no ticks", 4, 10, []TickStats{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := repo.GetTickStats(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetTickStats() error = %v", err)
+			}
+			if stats == nil || !slices.Equal(stats, tt.want) {
+				t.Errorf("GetTickStats(%d, %d) = %+v, want %+v", tt.from, tt.to, stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_TicksWithoutDatabase(t *testing.T) {
+	repo := NewRepository(nil)
+	if _, err := repo.GetTickByBatchHash(context.Background(), "ba01"); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Errorf("GetTickByBatchHash() error = %v, want ErrDatabaseUnavailable", err)
+	}
+	if _, err := repo.GetTransactionsByTick(context.Background(), 1); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Errorf("GetTransactionsByTick() error = %v, want ErrDatabaseUnavailable", err)
+	}
+	if _, err := repo.GetTickStats(context.Background(), 1, 2); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Errorf("GetTickStats() error = %v, want ErrDatabaseUnavailable", err)
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Equal reports whether t and other hold the same tick data.
+// ReceivedAt is ignored since it's set by whichever ingester received the tick.
+func (t *Tick) Equal(other *Tick) bool {
+	return len(t.Diff(other)) == 0
+}
+
+// Diff returns the JSON names of the fields that differ between t and other,
+// e.g. "batch_hash" or "transactions[2].payload". ReceivedAt is ignored.
+// A nil tick only equals another nil tick.
+func (t *Tick) Diff(other *Tick) []string {
+	if t == nil || other == nil {
+		if t == other {
+			return nil
+		}
+		return []string{"tick"}
+	}
+
+	var diffs []string
+	if t.TickNumber != other.TickNumber {
+		diffs = append(diffs, "tick_number")
+	}
+	if !t.Timestamp.Equal(other.Timestamp) {
+		diffs = append(diffs, "timestamp")
+	}
+	if t.VDFProof != other.VDFProof {
+		diffs = append(diffs, "vdf_proof")
+	}
+	if t.BatchHash != other.BatchHash {
+		diffs = append(diffs, "batch_hash")
+	}
+	if t.PrevOutput != other.PrevOutput {
+		diffs = append(diffs, "previous_output")
+	}
+
+	if len(t.Transactions) != len(other.Transactions) {
+		return append(diffs, "transactions")
+	}
+	for i := range t.Transactions {
+		for _, field := range t.Transactions[i].diff(&other.Transactions[i]) {
+			diffs = append(diffs, fmt.Sprintf("transactions[%d].%s", i, field))
+		}
+	}
+
+	return diffs
+}
+
+// diff returns the JSON names of the fields that differ between tx and other.
+func (tx *Transaction) diff(other *Transaction) []string {
+	var diffs []string
+	if tx.TxID != other.TxID {
+		diffs = append(diffs, "tx_id")
+	}
+	if tx.TxHash != other.TxHash {
+		diffs = append(diffs, "tx_hash")
+	}
+	if !bytes.Equal(tx.Payload, other.Payload) {
+		diffs = append(diffs, "payload")
+	}
+	if !bytes.Equal(tx.Signature, other.Signature) {
+		diffs = append(diffs, "signature")
+	}
+	if !bytes.Equal(tx.PublicKey, other.PublicKey) {
+		diffs = append(diffs, "public_key")
+	}
+	if tx.Nonce != other.Nonce {
+		diffs = append(diffs, "nonce")
+	}
+	if !tx.ClientTimestamp.Equal(other.ClientTimestamp) {
+		diffs = append(diffs, "client_timestamp")
+	}
+	if tx.SequenceNumber != other.SequenceNumber {
+		diffs = append(diffs, "sequence_number")
+	}
+	if !tx.IngestionTimestamp.Equal(other.IngestionTimestamp) {
+		diffs = append(diffs, "ingestion_timestamp")
+	}
+	return diffs
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// diffTick returns a tick with every field set, for diffing against modified copies.
+func diffTick() *Tick {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Tick{
+		TickNumber: 7,
+		Timestamp:  ts,
+		VDFProof:   VDFProof{Input: "in", Output: "out", Proof: "proof", Iterations: 100},
+		BatchHash:  "batch",
+		PrevOutput: "prev",
+		ReceivedAt: ts.Add(time.Second),
+		Transactions: []Transaction{
+			{TxID: "id-0", TxHash: "hash-0", Payload: []byte{1}, Signature: []byte{2}, PublicKey: []byte{3}, Nonce: 1, ClientTimestamp: ts, SequenceNumber: 0},
+			{TxID: "id-1", TxHash: "hash-1", Payload: []byte{4}, Signature: []byte{5}, PublicKey: []byte{6}, Nonce: 2, ClientTimestamp: ts, SequenceNumber: 1},
+		},
+	}
+}
+
+func TestTick_Diff(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *Tick)
+		want   []string
+	}{
+		{"equal", func(t *Tick) {}, nil},
+		{"received at is ignored", func(t *Tick) { t.ReceivedAt = t.ReceivedAt.Add(time.Hour) }, nil},
+		{"same instant in another zone", func(t *Tick) { t.Timestamp = t.Timestamp.In(time.FixedZone("UTC+2", 2*3600)) }, nil},
+		{"tick number", func(t *Tick) { t.TickNumber++ }, []string{"tick_number"}},
+		{"timestamp", func(t *Tick) { t.Timestamp = t.Timestamp.Add(time.Microsecond) }, []string{"timestamp"}},
+		{"vdf proof", func(t *Tick) { t.VDFProof.Iterations++ }, []string{"vdf_proof"}},
+		{"batch hash and previous output", func(t *Tick) { t.BatchHash, t.PrevOutput = "other", "other" }, []string{"batch_hash", "previous_output"}},
+		{"transaction count", func(t *Tick) { t.Transactions = t.Transactions[:1] }, []string{"transactions"}},
+		{"transaction payload", func(t *Tick) { t.Transactions[1].Payload = []byte{9} }, []string{"transactions[1].payload"}},
+		{
+			"several transaction fields",
+			func(t *Tick) {
+				t.Transactions[0].TxHash = "other"
+				t.Transactions[0].Nonce++
+				t.Transactions[1].IngestionTimestamp = t.Timestamp
+			},
+			[]string{"transactions[0].tx_hash", "transactions[0].nonce", "transactions[1].ingestion_timestamp"},
+		},
+		{"emptied signature", func(t *Tick) { t.Transactions[0].Signature = []byte{} }, []string{"transactions[0].signature"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := diffTick(), diffTick()
+			tt.change(b)
+
+			if got := a.Diff(b); !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+			if got := b.Diff(a); !slices.Equal(got, tt.want) {
+				t.Errorf("reversed Diff() = %v, want %v", got, tt.want)
+			}
+			if got := a.Equal(b); got != (len(tt.want) == 0) {
+				t.Errorf("Equal() = %v, want %v", got, len(tt.want) == 0)
+			}
+		})
+	}
+}
+
+func TestTick_DiffNil(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Tick
+		want []string
+	}{
+		{"both nil", nil, nil, nil},
+		{"nil and tick", nil, diffTick(), []string{"tick"}},
+		{"tick and nil", diffTick(), nil, []string{"tick"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Diff(tt.b); !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSONOptions controls optional fields added when marshaling ticks.
+type JSONOptions struct {
+	// PayloadBase64 adds a base64-encoded "payload_base64" field next to each
+	// transaction's (hex-encoded) payload.
+	PayloadBase64 bool
+}
+
+// PayloadHex returns the transaction payload hex-encoded.
+func (tx *Transaction) PayloadHex() string {
+	return hex.EncodeToString(tx.Payload)
+}
+
+// PayloadBase64 returns the transaction payload base64-encoded (standard encoding).
+func (tx *Transaction) PayloadBase64() string {
+	return base64.StdEncoding.EncodeToString(tx.Payload)
+}
+
+// DecodePayload decodes a JSON payload into v.
+// Payloads are application-specific; this only succeeds for applications that submit JSON.
+func (tx *Transaction) DecodePayload(v any) error {
+	if len(tx.Payload) == 0 {
+		return fmt.Errorf("payload is empty")
+	}
+	if err := json.Unmarshal(tx.Payload, v); err != nil {
+		return fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSONWithOptions marshals the tick like MarshalJSON, plus the optional fields in opts.
+func (t *Tick) MarshalJSONWithOptions(opts JSONOptions) ([]byte, error) {
+	if !opts.PayloadBase64 {
+		return t.MarshalJSON()
+	}
+
+	transactions := make([]transactionJSON, len(t.Transactions))
+	for i := range t.Transactions {
+		transactions[i] = t.Transactions[i].toJSON()
+		transactions[i].PayloadBase64 = t.Transactions[i].PayloadBase64()
+	}
+
+	type Alias Tick
+	return json.Marshal(&struct {
+		*Alias
+		Timestamp    string            `json:"timestamp"`
+		ReceivedAt   string            `json:"received_at"`
+		Transactions []transactionJSON `json:"transactions"`
+	}{
+		Alias:        (*Alias)(t),
+		Timestamp:    t.Timestamp.Format(time.RFC3339Nano),
+		ReceivedAt:   t.ReceivedAt.Format(time.RFC3339Nano),
+		Transactions: transactions,
+	})
+}
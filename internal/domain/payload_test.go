@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransaction_PayloadEncodings(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    []byte
+		wantHex    string
+		wantBase64 string
+	}{
+		{"empty", nil, "", ""},
+		{"bytes", []byte{0x00, 0xff, 0x10}, "00ff10", "AP8Q"},
+		{"text", []byte("fermi"), "6665726d69", "ZmVybWk="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &Transaction{Payload: tt.payload}
+			if got := tx.PayloadHex(); got != tt.wantHex {
+				t.Errorf("PayloadHex() = %q, want %q", got, tt.wantHex)
+			}
+			if got := tx.PayloadBase64(); got != tt.wantBase64 {
+				t.Errorf("PayloadBase64() = %q, want %q", got, tt.wantBase64)
+			}
+		})
+	}
+}
+
+func TestTransaction_DecodePayload(t *testing.T) {
+	type order struct {
+		Side  string `json:"side"`
+		Price int    `json:"price"`
+	}
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    order
+		wantErr string
+	}{
+		{"JSON payload", []byte(`{"side":"buy","price":100}`), order{"buy", 100}, ""},
+		{"empty payload", nil, order{}, "payload is empty"},
+		{"binary payload", []byte{0x01, 0x02}, order{}, "payload is not valid JSON"},
+		{"wrong shape", []byte(`["buy",100]`), order{}, "payload is not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got order
+			err := (&Transaction{Payload: tt.payload}).DecodePayload(&got)
+			if tt.wantErr == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("DecodePayload() = %+v, %v; want %+v", got, err, tt.want)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodePayload() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTick_MarshalJSONWithOptions(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := &Tick{
+		TickNumber:   7,
+		Timestamp:    ts,
+		ReceivedAt:   ts,
+		Transactions: []Transaction{{TxHash: "h1", Payload: []byte("fermi")}, {TxHash: "h2"}},
+	}
+
+	tests := []struct {
+		name       string
+		opts       JSONOptions
+		wantBase64 []string // payload_base64 of each transaction (empty = absent)
+	}{
+		{"default", JSONOptions{}, []string{"", ""}},
+		{"base64 payloads", JSONOptions{PayloadBase64: true}, []string{"ZmVybWk=", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tick.MarshalJSONWithOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("MarshalJSONWithOptions() error = %v", err)
+			}
+
+			var got struct {
+				TickNumber   uint64                       `json:"tick_number"`
+				Timestamp    string                       `json:"timestamp"`
+				Transactions []map[string]json.RawMessage `json:"transactions"`
+			}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("decode %s: %v", data, err)
+			}
+			if got.TickNumber != 7 || got.Timestamp != ts.Format(time.RFC3339Nano) {
+				t.Errorf("tick = %d at %q, want 7 at %s", got.TickNumber, got.Timestamp, ts.Format(time.RFC3339Nano))
+			}
+			if len(got.Transactions) != len(tt.wantBase64) {
+				t.Fatalf("got %d transactions, want %d: %s", len(got.Transactions), len(tt.wantBase64), data)
+			}
+			for i, want := range tt.wantBase64 {
+				tx := got.Transactions[i]
+				// The payload field is always hex, like the rest of the API
+				if wantHex, _ := json.Marshal(tick.Transactions[i].PayloadHex()); string(tx["payload"]) != string(wantHex) {
+					t.Errorf("transaction %d payload = %s, want %s", i, tx["payload"], wantHex)
+				}
+				raw, ok := tx["payload_base64"]
+				if want == "" {
+					if ok {
+						t.Errorf("transaction %d has payload_base64 %s, want none", i, raw)
+					}
+					continue
+				}
+				if string(raw) != `"`+want+`"` {
+					t.Errorf("transaction %d payload_base64 = %s, want %q", i, raw, want)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -39,9 +40,21 @@ type Transaction struct {
 	IngestionTimestamp time.Time `json:"ingestion_timestamp"`
 }
 
+// ValidationOptions relaxes specific validations, e.g. for non-production sequencers
+// that produce tick 0 or placeholder VDF proofs. The zero value is fully strict.
+type ValidationOptions struct {
+	AllowZeroTickNumber bool // Accept tick_number 0
+	AllowEmptyProof     bool // Accept a VDF proof with empty fields or zero iterations
+}
+
 // Validate checks if the Tick has valid required fields.
 func (t *Tick) Validate() error {
-	if t.TickNumber == 0 {
+	return t.ValidateWith(ValidationOptions{})
+}
+
+// ValidateWith checks the Tick's required fields, skipping the checks relaxed by opts.
+func (t *Tick) ValidateWith(opts ValidationOptions) error {
+	if t.TickNumber == 0 && !opts.AllowZeroTickNumber {
 		return fmt.Errorf("tick_number cannot be zero")
 	}
 
@@ -49,7 +62,7 @@ func (t *Tick) Validate() error {
 		return fmt.Errorf("timestamp cannot be zero")
 	}
 
-	if t.VDFProof.Output == "" {
+	if t.VDFProof.Output == "" && !opts.AllowEmptyProof {
 		return fmt.Errorf("vdf_proof.output is required")
 	}
 
@@ -70,6 +83,32 @@ func (t *Tick) TransactionCount() int {
 	return len(t.Transactions)
 }
 
+// PayloadSize returns the total size in bytes of all transaction payloads.
+func (t *Tick) PayloadSize() int {
+	size := 0
+	for i := range t.Transactions {
+		size += len(t.Transactions[i].Payload)
+	}
+	return size
+}
+
+// IngestionLatency returns how long after its production the tick was received
+// by the ingester (ReceivedAt - Timestamp).
+// Returns zero if either timestamp is unset or if ReceivedAt precedes Timestamp
+// due to clock skew between the sequencer and the ingester.
+func (t *Tick) IngestionLatency() time.Duration {
+	if t.Timestamp.IsZero() || t.ReceivedAt.IsZero() {
+		return 0
+	}
+
+	latency := t.ReceivedAt.Sub(t.Timestamp)
+	if latency < 0 {
+		return 0
+	}
+
+	return latency
+}
+
 // MarshalJSON implements custom JSON marshaling.
 func (t *Tick) MarshalJSON() ([]byte, error) {
 	type Alias Tick
@@ -84,6 +123,39 @@ func (t *Tick) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// transactionJSON is the JSON form of a Transaction: byte fields are hex-encoded
+// (like the rest of the API) and timestamps are RFC3339.
+type transactionJSON struct {
+	*transactionAlias
+	Payload            string `json:"payload"`
+	Signature          string `json:"signature"`
+	PublicKey          string `json:"public_key"`
+	ClientTimestamp    string `json:"client_timestamp"`
+	IngestionTimestamp string `json:"ingestion_timestamp"`
+	PayloadBase64      string `json:"payload_base64,omitempty"` // Only with JSONOptions.PayloadBase64
+}
+
+// transactionAlias drops Transaction's MarshalJSON so its fields can be embedded.
+type transactionAlias Transaction
+
+func (tx *Transaction) toJSON() transactionJSON {
+	return transactionJSON{
+		transactionAlias:   (*transactionAlias)(tx),
+		Payload:            hex.EncodeToString(tx.Payload),
+		Signature:          hex.EncodeToString(tx.Signature),
+		PublicKey:          hex.EncodeToString(tx.PublicKey),
+		ClientTimestamp:    tx.ClientTimestamp.Format(time.RFC3339Nano),
+		IngestionTimestamp: tx.IngestionTimestamp.Format(time.RFC3339Nano),
+	}
+}
+
+// MarshalJSON implements custom JSON marshaling.
+// Payload, signature and public key are hex-encoded rather than base64.
+// It has a value receiver so transactions marshal the same way when not addressable.
+func (tx Transaction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tx.toJSON())
+}
+
 // Validate checks if the VDFProof has valid required fields.
 func (v *VDFProof) Validate() error {
 	if v.Input == "" {
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestTick_IngestionLatency(t *testing.T) {
+	produced := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		timestamp  time.Time
+		receivedAt time.Time
+		want       time.Duration
+	}{
+		{"received later", produced, produced.Add(250 * time.Millisecond), 250 * time.Millisecond},
+		{"received instantly", produced, produced, 0},
+		{"clock skew", produced, produced.Add(-time.Second), 0},
+		{"no timestamp", time.Time{}, produced, 0},
+		{"not received", produced, time.Time{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tick := &Tick{Timestamp: tt.timestamp, ReceivedAt: tt.receivedAt}
+			if got := tick.IngestionLatency(); got != tt.want {
+				t.Errorf("IngestionLatency() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTick_PayloadSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads [][]byte
+		want     int
+	}{
+		{"no transactions", nil, 0},
+		{"empty payload", [][]byte{nil}, 0},
+		{"one transaction", [][]byte{make([]byte, 42)}, 42},
+		{"several transactions", [][]byte{make([]byte, 10), nil, make([]byte, 5)}, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tick := &Tick{}
+			for _, payload := range tt.payloads {
+				tick.Transactions = append(tick.Transactions, Transaction{Payload: payload})
+			}
+			if got := tick.PayloadSize(); got != tt.want {
+				t.Errorf("PayloadSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransaction_MarshalJSON(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 500, time.UTC)
+	tx := Transaction{
+		TxID:               "id-1",
+		TxHash:             "hash-1",
+		Payload:            []byte{0xde, 0xad},
+		Signature:          []byte{0xbe, 0xef},
+		PublicKey:          []byte{0x01},
+		Nonce:              3,
+		ClientTimestamp:    ts,
+		SequenceNumber:     4,
+		IngestionTimestamp: ts.Add(time.Second),
+	}
+	want := map[string]string{
+		"tx_id":               `"id-1"`,
+		"tx_hash":             `"hash-1"`,
+		"payload":             `"dead"`,
+		"signature":           `"beef"`,
+		"public_key":          `"01"`,
+		"nonce":               `3`,
+		"client_timestamp":    `"2024-01-01T12:00:00.0000005Z"`,
+		"sequence_number":     `4`,
+		"ingestion_timestamp": `"2024-01-01T12:00:01.0000005Z"`,
+	}
+	empty := maps.Clone(want)
+	empty["payload"], empty["signature"], empty["public_key"] = `""`, `""`, `""`
+
+	tests := []struct {
+		name    string
+		marshal func() ([]byte, error)
+		want    map[string]string
+	}{
+		{"value", func() ([]byte, error) { return json.Marshal(tx) }, want},
+		{"pointer", func() ([]byte, error) { return json.Marshal(&tx) }, want},
+		{"nil byte fields", func() ([]byte, error) {
+			bare := tx
+			bare.Payload, bare.Signature, bare.PublicKey = nil, nil, nil
+			return json.Marshal(bare)
+		}, empty},
+		{"inside a tick", func() ([]byte, error) {
+			data, err := json.Marshal(&Tick{Transactions: []Transaction{tx}})
+			if err != nil {
+				return nil, err
+			}
+			var tick struct{ Transactions []json.RawMessage }
+			if err := json.Unmarshal(data, &tick); err != nil {
+				return nil, err
+			}
+			return tick.Transactions[0], nil
+		}, want},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.marshal()
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatalf("decode %s: %v", data, err)
+			}
+			got := make(map[string]string, len(fields))
+			for k, v := range fields {
+				got[k] = string(v)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("Marshal() = %s, want %v", data, tt.want)
+			}
+		})
+	}
+}
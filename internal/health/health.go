@@ -8,9 +8,17 @@ import (
 
 // Status represents the health status of the service
 type Status struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Version   string    `json:"version"`
+	Status    string            `json:"status"`
+	Timestamp time.Time         `json:"timestamp"`
+	Version   string            `json:"version"`
+	Checks    map[string]string `json:"checks,omitempty"`
+}
+
+// Check reports the state of a dependency for readiness checks
+type Check struct {
+	Name     string
+	Check    func() error
+	Required bool // If true, a failing check makes the service not ready
 }
 
 // Handler returns an HTTP handler for health checks
@@ -30,20 +38,34 @@ func Handler() http.HandlerFunc {
 
 // ReadyHandler returns an HTTP handler for readiness checks
 // This is useful in Kubernetes/container environments
-func ReadyHandler() http.HandlerFunc {
+// Each check's state is reported in the response; only failing required
+// checks make the service not ready (503)
+func ReadyHandler(checks ...Check) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// In the future, we can add checks for:
-		// - Redis connection
-		// - Backend service availability
-		// For now, if the server is running, it's ready
 		status := Status{
 			Status:    "ready",
 			Timestamp: time.Now(),
 			Version:   "1.0.0",
 		}
+		statusCode := http.StatusOK
+
+		if len(checks) > 0 {
+			status.Checks = make(map[string]string, len(checks))
+			for _, check := range checks {
+				if err := check.Check(); err != nil {
+					status.Checks[check.Name] = err.Error()
+					if check.Required {
+						status.Status = "not_ready"
+						statusCode = http.StatusServiceUnavailable
+					}
+					continue
+				}
+				status.Checks[check.Name] = "ok"
+			}
+		}
 
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(statusCode)
 		json.NewEncoder(w).Encode(status)
 	}
 }
@@ -0,0 +1,53 @@
+package ingestion
+
+import "time"
+
+// batchSizer adapts a batch writer's effective batch size to observed load.
+// It grows while batches fill up and write well within targetWrite (high input
+// rate, room for larger COPYs), shrinks when writes exceed targetWrite, and moves
+// towards the observed batch size when batches are flushed by the timer (low
+// input rate), so quiet periods flush on count instead of waiting the full interval.
+// The size always stays within [min, max].
+// Not safe for concurrent use; each batch writer owns its own sizer.
+type batchSizer struct {
+	size        int
+	min         int
+	max         int
+	targetWrite time.Duration
+}
+
+// newBatchSizer returns a sizer starting at initial, clamped to [lo, hi].
+func newBatchSizer(initial, lo, hi int, targetWrite time.Duration) *batchSizer {
+	return &batchSizer{
+		size:        clampInt(initial, lo, hi),
+		min:         lo,
+		max:         hi,
+		targetWrite: targetWrite,
+	}
+}
+
+// Size returns the current effective batch size.
+func (s *batchSizer) Size() int {
+	return s.size
+}
+
+// Observe adjusts the size after a batch of n ticks was written in elapsed.
+// full reports whether the batch was flushed because it reached Size.
+func (s *batchSizer) Observe(n int, elapsed time.Duration, full bool) {
+	switch {
+	case elapsed > s.targetWrite:
+		// Slow write: shrink by a quarter
+		s.size -= max(s.size/4, 1)
+	case full && elapsed < s.targetWrite/2:
+		// Batches fill up and write quickly: grow by a quarter
+		s.size += max(s.size/4, 1)
+	case !full:
+		// Flushed by timer or byte limit: converge on what actually arrived
+		s.size = (s.size + n) / 2
+	}
+	s.size = clampInt(s.size, s.min, s.max)
+}
+
+func clampInt(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
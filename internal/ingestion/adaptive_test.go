@@ -0,0 +1,74 @@
+package ingestion
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// batchObservation is one written batch fed to a batchSizer.
+type batchObservation struct {
+	n       int
+	elapsed time.Duration
+	full    bool
+}
+
+func TestBatchSizer_Observe(t *testing.T) {
+	const target = 100 * time.Millisecond
+	fast := func(n int) batchObservation { return batchObservation{n, 10 * time.Millisecond, true} }
+	slow := func(n int) batchObservation { return batchObservation{n, 300 * time.Millisecond, true} }
+	timer := func(n int) batchObservation { return batchObservation{n, 10 * time.Millisecond, false} }
+
+	tests := []struct {
+		name         string
+		initial      int
+		lo, hi       int
+		observations []batchObservation
+		want         []int // Size after each observation
+	}{
+		{"fast full batches grow", 100, 10, 1000, []batchObservation{fast(100), fast(125), fast(156)}, []int{125, 156, 195}},
+		{"slow writes shrink", 100, 10, 1000, []batchObservation{slow(100), slow(75)}, []int{75, 57}},
+		{"slow partial batches shrink too", 100, 10, 1000, []batchObservation{{20, 300 * time.Millisecond, false}}, []int{75}},
+		{"moderate full batches hold", 100, 10, 1000, []batchObservation{{100, 75 * time.Millisecond, true}}, []int{100}},
+		{"at the target holds", 100, 10, 1000, []batchObservation{{100, target, true}}, []int{100}},
+		{"timer flushes converge on the input rate", 100, 10, 1000, []batchObservation{timer(20), timer(20), timer(20)}, []int{60, 40, 30}},
+		{"grows to the max", 900, 10, 1000, []batchObservation{fast(900), fast(1000)}, []int{1000, 1000}},
+		{"shrinks to the min", 14, 10, 1000, []batchObservation{slow(14), slow(11), slow(10)}, []int{11, 10, 10}},
+		{"small sizes still move", 2, 1, 10, []batchObservation{fast(2), slow(3), slow(2)}, []int{3, 2, 1}},
+		{"grows again after slowing", 100, 10, 1000, []batchObservation{slow(100), fast(75)}, []int{75, 93}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newBatchSizer(tt.initial, tt.lo, tt.hi, target)
+			var got []int
+			for _, o := range tt.observations {
+				s.Observe(o.n, o.elapsed, o.full)
+				got = append(got, s.Size())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBatchSizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial int
+		want    int
+	}{
+		{"within bounds", 250, 250},
+		{"below min", 1, 10},
+		{"above max", 10000, 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newBatchSizer(tt.initial, 10, 5000, time.Second).Size(); got != tt.want {
+				t.Errorf("Size() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
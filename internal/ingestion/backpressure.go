@@ -0,0 +1,95 @@
+package ingestion
+
+import (
+	"context"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+	"go.uber.org/zap"
+)
+
+// BackpressurePolicy decides what parse workers do when batch writers fall
+// behind and the parsed-tick channel is full.
+type BackpressurePolicy string
+
+const (
+	// BackpressureBlock waits for room, backing up the stream reader (no tick is lost).
+	BackpressureBlock BackpressurePolicy = "block"
+	// BackpressureDropOldest discards the oldest buffered tick to make room.
+	// Only suitable for non-critical deployments (e.g. live dashboards).
+	BackpressureDropOldest BackpressurePolicy = "drop_oldest"
+)
+
+// backpressureLogInterval rate-limits writer lag warnings across parse workers.
+const backpressureLogInterval = 10 * time.Second
+
+// sendParsed hands a parsed tick to the batch writers according to the
+// backpressure policy. It returns false if the drain was aborted.
+func (p *Pipeline) sendParsed(ctx context.Context, id int, tickCh chan *domain.Tick, tick *domain.Tick) bool {
+	select {
+	case tickCh <- tick:
+		return true
+	default:
+	}
+
+	if p.backpressure == BackpressureDropOldest {
+		p.sendDropOldest(tickCh, tick)
+		return true
+	}
+
+	start := p.clock.Now()
+	timer := p.clock.NewTimer(p.backpressureWarnAfter)
+	defer timer.Stop()
+
+	for {
+		select {
+		case tickCh <- tick:
+			p.metrics.ObserveBackpressureWait(p.clock.Since(start).Seconds())
+			return true
+		case <-timer.C():
+			p.metrics.RecordBackpressureStall()
+			if p.shouldLogBackpressure() {
+				p.logger.Warn("Batch writers are falling behind; parse worker blocked",
+					zap.Int("worker_id", id),
+					zap.Duration("blocked_for", p.clock.Since(start)),
+					zap.Int("buffered", len(tickCh)),
+				)
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// sendDropOldest sends tick, discarding buffered ticks until there is room.
+func (p *Pipeline) sendDropOldest(tickCh chan *domain.Tick, tick *domain.Tick) {
+	for {
+		select {
+		case tickCh <- tick:
+			return
+		default:
+		}
+
+		select {
+		case dropped := <-tickCh:
+			p.metrics.RecordBackpressureDrop()
+			if p.shouldLogBackpressure() {
+				p.logger.Warn("Batch writers are falling behind; dropping oldest buffered ticks",
+					zap.Uint64("dropped_tick_number", dropped.TickNumber),
+				)
+			}
+		default:
+		}
+	}
+}
+
+// shouldLogBackpressure reports whether a lag warning is due, allowing at most
+// one per backpressureLogInterval across all parse workers.
+func (p *Pipeline) shouldLogBackpressure() bool {
+	now := p.clock.Now().UnixNano()
+	last := p.lastBackpressureLog.Load()
+	if now-last < int64(backpressureLogInterval) {
+		return false
+	}
+	return p.lastBackpressureLog.CompareAndSwap(last, now)
+}
@@ -0,0 +1,195 @@
+package ingestion
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// histogramCount returns the number of observations of the named histogram.
+func histogramCount(t *testing.T, registry *prometheus.Registry, name string) uint64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestPipeline_SendParsed(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    BackpressurePolicy
+		buffered  []uint64      // Ticks already in the channel (filling it)
+		room      bool          // The channel has room for one more tick
+		drainIn   time.Duration // When the writer takes a tick (0 = never)
+		cancel    bool          // Cancel the drain while blocked
+		want      []uint64      // Channel contents afterwards
+		wantSent  bool
+		wantWaits uint64
+		wantStall float64
+		wantDrops float64
+		wantLog   string
+	}{
+		{
+			name: "room", policy: BackpressureBlock, room: true,
+			want: []uint64{3}, wantSent: true,
+		},
+		{
+			name: "brief block", policy: BackpressureBlock, buffered: []uint64{1, 2}, drainIn: 5 * time.Millisecond,
+			want: []uint64{2, 3}, wantSent: true, wantWaits: 1,
+		},
+		{
+			name: "writer lag", policy: BackpressureBlock, buffered: []uint64{1, 2}, drainIn: 300 * time.Millisecond,
+			want: []uint64{2, 3}, wantSent: true, wantWaits: 1, wantStall: 1,
+			wantLog: "Batch writers are falling behind; parse worker blocked",
+		},
+		{
+			name: "canceled while blocked", policy: BackpressureBlock, buffered: []uint64{1, 2}, cancel: true,
+			want: []uint64{1, 2}, wantStall: 1,
+			wantLog: "Batch writers are falling behind; parse worker blocked",
+		},
+		{
+			name: "drop oldest", policy: BackpressureDropOldest, buffered: []uint64{1, 2},
+			want: []uint64{2, 3}, wantSent: true, wantDrops: 1,
+			wantLog: "Batch writers are falling behind; dropping oldest buffered ticks",
+		},
+		{
+			name: "drop oldest with room", policy: BackpressureDropOldest, room: true,
+			want: []uint64{3}, wantSent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			core, logs := observer.New(zapcore.WarnLevel)
+			p := NewPipeline(nil, stubParser{}, &recordingWriter{}, zap.New(core), PipelineConfig{
+				Registry:              registry,
+				Backpressure:          tt.policy,
+				BackpressureWarnAfter: 100 * time.Millisecond,
+			})
+
+			size := len(tt.buffered)
+			if tt.room {
+				size++
+			}
+			tickCh := make(chan *domain.Tick, size)
+			for _, n := range tt.buffered {
+				tickCh <- &domain.Tick{TickNumber: n}
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			drained := make(chan uint64, 1)
+			if tt.drainIn > 0 {
+				time.AfterFunc(tt.drainIn, func() { drained <- (<-tickCh).TickNumber })
+			}
+			if tt.cancel {
+				time.AfterFunc(300*time.Millisecond, cancel)
+			}
+
+			if got := p.sendParsed(ctx, 0, tickCh, &domain.Tick{TickNumber: 3}); got != tt.wantSent {
+				t.Errorf("sendParsed() = %v, want %v", got, tt.wantSent)
+			}
+			if tt.drainIn > 0 {
+				if n := <-drained; n != 1 {
+					t.Errorf("writer took tick %d, want 1", n)
+				}
+			}
+
+			var got []uint64
+			for len(tickCh) > 0 {
+				got = append(got, (<-tickCh).TickNumber)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("channel = %v, want %v", got, tt.want)
+			}
+			if got := histogramCount(t, registry, "tick_ingester_backpressure_wait_seconds"); got != tt.wantWaits {
+				t.Errorf("backpressure waits = %d, want %d", got, tt.wantWaits)
+			}
+			if got := sum(counterValues(t, registry, "tick_ingester_backpressure_stalls_total")); got != tt.wantStall {
+				t.Errorf("backpressure stalls = %v, want %v", got, tt.wantStall)
+			}
+			if got := sum(counterValues(t, registry, "tick_ingester_backpressure_dropped_ticks_total")); got != tt.wantDrops {
+				t.Errorf("dropped ticks = %v, want %v", got, tt.wantDrops)
+			}
+			if tt.wantLog == "" {
+				if logs.Len() != 0 {
+					t.Errorf("logged %v, want nothing", logs.All())
+				}
+			} else if logs.FilterMessage(tt.wantLog).Len() != 1 {
+				t.Errorf("logs = %v, want one %q", logs.All(), tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestPipeline_SlowWriterBackpressure(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    BackpressurePolicy
+		wantStall bool
+		wantDrops bool
+	}{
+		{"block", BackpressureBlock, true, false},
+		{"drop oldest", BackpressureDropOldest, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &recordingWriter{gate: make(chan struct{})}
+			registry := prometheus.NewRegistry()
+			p := newTestPipeline(reader, writer, PipelineConfig{
+				BufferSize:            2,
+				WorkerCount:           1,
+				BatchSize:             1,
+				Registry:              registry,
+				Backpressure:          tt.policy,
+				BackpressureWarnAfter: 10 * time.Millisecond,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+
+			// The writer is stuck on its first batch, so the parsed-tick channel fills up
+			lagging := func() bool {
+				stalls := sum(counterValues(t, registry, "tick_ingester_backpressure_stalls_total"))
+				drops := sum(counterValues(t, registry, "tick_ingester_backpressure_dropped_ticks_total"))
+				return (stalls > 0) == tt.wantStall && (drops > 0) == tt.wantDrops
+			}
+			deadline := time.Now().Add(5 * time.Second)
+			for n := uint64(1); !lagging(); {
+				if time.Now().After(deadline) {
+					t.Fatal("writer lag not recorded")
+				}
+				// Once everything backs up the reader blocks too
+				select {
+				case reader.ticks <- &pb.Tick{TickNumber: n}:
+					n++
+				case <-time.After(time.Millisecond):
+				}
+			}
+
+			close(writer.gate)
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+		})
+	}
+}
@@ -16,6 +16,11 @@ type Config struct {
 	// gRPC Stream
 	ContinuumGRPCURL string
 	StartTick        uint64
+	StreamResume     bool // On reconnect, resume after the last received tick instead of StartTick
+
+	// Backfill (GetTick per tick instead of StreamTicks; enabled when BackfillEndTick > 0)
+	BackfillStartTick uint64
+	BackfillEndTick   uint64
 
 	// Database
 	DatabaseURL      string
@@ -28,24 +33,60 @@ type Config struct {
 	BufferSize    int
 	WorkerCount   int
 	BatchSize     int
+	MaxBatchBytes int // Flush once batch payload bytes reach this (0 = disabled)
 	FlushInterval time.Duration
+	CheckNonces   bool
+	DedupWindow   int           // Drop ticks whose number is among the last N seen (0 = disabled)
+	MaxTicks      uint64        // Stop after N ticks (0 = unlimited)
+	DrainTimeout  time.Duration // Max time to write buffered ticks on shutdown
+
+	// Adaptive batch size (BatchSize becomes the starting point)
+	AdaptiveBatch       bool
+	MinBatchSize        int
+	MaxBatchSize        int
+	TargetWriteDuration time.Duration // Shrink batches whose write takes longer than this
+
+	// Crash recovery checkpoint (OUTPUT_MODE=timescale)
+	CheckpointName string // Checkpoint row advanced with every batch (empty = disabled)
+
+	// Backpressure (batch writers falling behind)
+	BackpressurePolicy    string        // "block" or "drop_oldest"
+	BackpressureWarnAfter time.Duration // Log and count a stall once a parse worker blocks this long
+
+	// Parser strictness (relax for non-production sequencers)
+	AllowZeroTick   bool // Accept tick number 0
+	AllowEmptyProof bool // Accept placeholder VDF proofs
 
 	// Output Mode
-	OutputMode   string // "console" or "timescale"
-	OutputFormat string // "json", "compact", or "table" (for console mode)
+	OutputMode      string        // "console", "timescale", "file", or "null" (dry run)
+	OutputFormat    string        // "json", "compact", "table", "csv", or "tsv" (for console mode)
+	VerboseTable    bool          // List individual transactions in table format
+	ColorOutput     bool          // Colorize console table headers and summaries
+	PayloadBase64   bool          // Add base64-encoded payloads to console JSON output
+	SummaryEvery    int           // Emit a console summary every N ticks (0 = disabled)
+	SummaryInterval time.Duration // Emit a console summary at this interval (0 = disabled)
+
+	// File output (OUTPUT_MODE=file)
+	OutputPath         string        // JSON-lines archive path; segments are named after it
+	FileMaxBytes       int64         // Rotate segments at this size (0 = disabled)
+	FileRotateInterval time.Duration // Rotate segments at this age (0 = disabled)
+	FileCompress       bool          // gzip segments
 
 	// Health Check
 	HealthCheckPort int
+	ReadyStaleness  time.Duration // /ready fails if no tick arrives within this window (0 = disabled)
 }
 
 // LoadConfig loads configuration from environment variables.
-func LoadConfig() (*Config, error) {
+// Callers apply any overrides (e.g. from flags) and then call Validate.
+func LoadConfig() *Config {
 	cfg := &Config{
 		// Defaults
 		ServiceName:      getEnv("SERVICE_NAME", "tick-ingester"),
 		Environment:      getEnv("ENV", "development"),
 		ContinuumGRPCURL: getEnv("CONTINUUM_GRPC_URL", "localhost:50051"),
 		StartTick:        getEnvUint64("START_TICK", 0),
+		StreamResume:     getEnvBool("STREAM_RESUME", true),
 		DatabaseURL:      getEnv("DATABASE_URL", ""),
 		MaxConnections:   getEnvInt("DB_MAX_CONNECTIONS", 100),
 		MinConnections:   getEnvInt("DB_MIN_CONNECTIONS", 10),
@@ -54,18 +95,53 @@ func LoadConfig() (*Config, error) {
 		BufferSize:       getEnvInt("BUFFER_SIZE", 10000),
 		WorkerCount:      getEnvInt("WORKER_COUNT", 8),
 		BatchSize:        getEnvInt("BATCH_SIZE", 250),
+		MaxBatchBytes:    getEnvInt("MAX_BATCH_BYTES", 0),
 		FlushInterval:    getEnvDuration("FLUSH_INTERVAL", 100*time.Millisecond),
-		OutputMode:       getEnv("OUTPUT_MODE", "timescale"),
-		OutputFormat:     getEnv("OUTPUT_FORMAT", "json"),
-		HealthCheckPort:  getEnvInt("HEALTH_CHECK_PORT", 8081),
-	}
+		DrainTimeout:     getEnvDuration("DRAIN_TIMEOUT", 30*time.Second),
+
+		AdaptiveBatch:       getEnvBool("ADAPTIVE_BATCH", false),
+		MinBatchSize:        getEnvInt("MIN_BATCH_SIZE", 10),
+		MaxBatchSize:        getEnvInt("MAX_BATCH_SIZE", 5000),
+		TargetWriteDuration: getEnvDuration("TARGET_WRITE_DURATION", 100*time.Millisecond),
+
+		CheckpointName: getEnv("CHECKPOINT_NAME", ""),
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, err
+		BackpressurePolicy:    getEnv("BACKPRESSURE_POLICY", "block"),
+		BackpressureWarnAfter: getEnvDuration("BACKPRESSURE_WARN_AFTER", time.Second),
+
+		CheckNonces:     getEnvBool("CHECK_NONCES", false),
+		AllowZeroTick:   getEnvBool("ALLOW_ZERO_TICK", false),
+		AllowEmptyProof: getEnvBool("ALLOW_EMPTY_VDF_PROOF", false),
+		DedupWindow:     getEnvInt("DEDUP_WINDOW", 0),
+		MaxTicks:        getEnvUint64("MAX_TICKS", 0),
+		OutputMode:      getEnv("OUTPUT_MODE", "timescale"),
+		OutputFormat:    getEnv("OUTPUT_FORMAT", "json"),
+		VerboseTable:    getEnvBool("VERBOSE_TABLE", false),
+		ColorOutput:     getEnvBool("COLOR_OUTPUT", false),
+		PayloadBase64:   getEnvBool("PAYLOAD_BASE64", false),
+		SummaryEvery:    getEnvInt("SUMMARY_EVERY", 0),
+		SummaryInterval: getEnvDuration("SUMMARY_INTERVAL", 0),
+
+		OutputPath:         getEnv("OUTPUT_PATH", "./ticks.jsonl"),
+		FileMaxBytes:       int64(getEnvInt("FILE_MAX_BYTES", 256*1024*1024)),
+		FileRotateInterval: getEnvDuration("FILE_ROTATE_INTERVAL", time.Hour),
+		FileCompress:       getEnvBool("FILE_COMPRESS", false),
+
+		HealthCheckPort: getEnvInt("HEALTH_CHECK_PORT", 8081),
+		ReadyStaleness:  getEnvDuration("READY_STALENESS", 2*time.Minute),
+
+		BackfillStartTick: getEnvUint64("BACKFILL_START_TICK", 0),
+		BackfillEndTick:   getEnvUint64("BACKFILL_END_TICK", 0),
 	}
 
-	return cfg, nil
+	return cfg
+}
+
+// SetDryRun switches output to the null writer and disables checkpointing, so
+// nothing is persisted whatever the environment configures.
+func (c *Config) SetDryRun() {
+	c.OutputMode = "null"
+	c.CheckpointName = ""
 }
 
 // Validate checks if the configuration is valid.
@@ -74,16 +150,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("CONTINUUM_GRPC_URL is required")
 	}
 
+	if c.BackfillEndTick > 0 && c.BackfillStartTick > c.BackfillEndTick {
+		return fmt.Errorf("BACKFILL_START_TICK (%d) must not exceed BACKFILL_END_TICK (%d)", c.BackfillStartTick, c.BackfillEndTick)
+	}
+
 	if c.OutputMode == "timescale" && c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required when OUTPUT_MODE=timescale")
 	}
 
-	if c.OutputMode != "console" && c.OutputMode != "timescale" {
-		return fmt.Errorf("OUTPUT_MODE must be 'console' or 'timescale', got: %s", c.OutputMode)
+	if c.OutputMode != "console" && c.OutputMode != "timescale" && c.OutputMode != "file" && c.OutputMode != "null" {
+		return fmt.Errorf("OUTPUT_MODE must be 'console', 'timescale', 'file', or 'null', got: %s", c.OutputMode)
 	}
 
-	if c.OutputFormat != "json" && c.OutputFormat != "compact" && c.OutputFormat != "table" {
-		return fmt.Errorf("OUTPUT_FORMAT must be 'json', 'compact', or 'table', got: %s", c.OutputFormat)
+	if c.OutputMode == "file" && c.OutputPath == "" {
+		return fmt.Errorf("OUTPUT_PATH is required when OUTPUT_MODE=file")
+	}
+
+	if c.FileMaxBytes < 0 || c.FileRotateInterval < 0 {
+		return fmt.Errorf("FILE_MAX_BYTES and FILE_ROTATE_INTERVAL must not be negative")
+	}
+
+	switch c.OutputFormat {
+	case "json", "compact", "table", "csv", "tsv":
+	default:
+		return fmt.Errorf("OUTPUT_FORMAT must be 'json', 'compact', 'table', 'csv', or 'tsv', got: %s", c.OutputFormat)
 	}
 
 	if c.BufferSize <= 0 {
@@ -98,9 +188,109 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("BATCH_SIZE must be positive, got: %d", c.BatchSize)
 	}
 
+	if c.DedupWindow < 0 {
+		return fmt.Errorf("DEDUP_WINDOW must not be negative, got: %d", c.DedupWindow)
+	}
+
+	if c.MaxBatchBytes < 0 {
+		return fmt.Errorf("MAX_BATCH_BYTES must not be negative, got: %d", c.MaxBatchBytes)
+	}
+
+	if c.CheckpointName != "" && c.OutputMode != "timescale" {
+		return fmt.Errorf("CHECKPOINT_NAME requires OUTPUT_MODE=timescale, got: %s", c.OutputMode)
+	}
+
+	// Dropped ticks can fall behind the checkpoint, where no restart replays them
+	if c.CheckpointName != "" && c.BackpressurePolicy == "drop_oldest" {
+		return fmt.Errorf("CHECKPOINT_NAME cannot be used with BACKPRESSURE_POLICY=drop_oldest")
+	}
+
+	if c.DrainTimeout <= 0 {
+		return fmt.Errorf("DRAIN_TIMEOUT must be positive, got: %s", c.DrainTimeout)
+	}
+
+	if c.BackpressurePolicy != "block" && c.BackpressurePolicy != "drop_oldest" {
+		return fmt.Errorf("BACKPRESSURE_POLICY must be 'block' or 'drop_oldest', got: %s", c.BackpressurePolicy)
+	}
+
+	if c.BackpressureWarnAfter <= 0 {
+		return fmt.Errorf("BACKPRESSURE_WARN_AFTER must be positive, got: %s", c.BackpressureWarnAfter)
+	}
+
+	if c.AdaptiveBatch {
+		if c.MinBatchSize <= 0 || c.MinBatchSize > c.MaxBatchSize {
+			return fmt.Errorf("MIN_BATCH_SIZE (%d) must be positive and not exceed MAX_BATCH_SIZE (%d)", c.MinBatchSize, c.MaxBatchSize)
+		}
+		if c.TargetWriteDuration <= 0 {
+			return fmt.Errorf("TARGET_WRITE_DURATION must be positive, got: %s", c.TargetWriteDuration)
+		}
+	}
+
+	// Cross-field checks: each worker needs buffer room, and a batch larger than
+	// the buffer can never fill, so every write would wait for the flush timer
+	if c.BufferSize < c.WorkerCount {
+		return fmt.Errorf("BUFFER_SIZE (%d) must be at least WORKER_COUNT (%d)", c.BufferSize, c.WorkerCount)
+	}
+
+	if c.BatchSize > c.BufferSize {
+		return fmt.Errorf("BATCH_SIZE (%d) must not exceed BUFFER_SIZE (%d)", c.BatchSize, c.BufferSize)
+	}
+
+	if c.OutputMode == "timescale" && c.MinConnections > c.MaxConnections {
+		return fmt.Errorf("DB_MIN_CONNECTIONS (%d) must not exceed DB_MAX_CONNECTIONS (%d)", c.MinConnections, c.MaxConnections)
+	}
+
 	return nil
 }
 
+// Warnings returns non-fatal notes about configurations that are valid but
+// likely to underperform. Callers should log them at startup.
+func (c *Config) Warnings() []string {
+	var warnings []string
+
+	if c.BatchSize*c.WorkerCount > c.BufferSize {
+		warnings = append(warnings, fmt.Sprintf(
+			"BATCH_SIZE (%d) x WORKER_COUNT (%d) exceeds BUFFER_SIZE (%d); most batches will be flushed by FLUSH_INTERVAL before they fill",
+			c.BatchSize, c.WorkerCount, c.BufferSize))
+	}
+
+	if c.BackpressurePolicy == "drop_oldest" && c.OutputMode == "timescale" {
+		warnings = append(warnings,
+			"BACKPRESSURE_POLICY=drop_oldest discards ticks when writers fall behind; the database will have gaps")
+	}
+
+	if c.AdaptiveBatch && c.MaxBatchSize > c.BufferSize {
+		warnings = append(warnings, fmt.Sprintf(
+			"MAX_BATCH_SIZE (%d) exceeds BUFFER_SIZE (%d); adaptive batches cannot grow past the buffer",
+			c.MaxBatchSize, c.BufferSize))
+	}
+
+	if c.OutputMode == "timescale" && c.WorkerCount > c.MaxConnections {
+		warnings = append(warnings, fmt.Sprintf(
+			"WORKER_COUNT (%d) exceeds DB_MAX_CONNECTIONS (%d); batch writers will queue for database connections",
+			c.WorkerCount, c.MaxConnections))
+	}
+
+	return warnings
+}
+
+// CheckpointReplay returns how many ticks to replay behind the checkpoint on
+// restart: every tick that could have been buffered or in an uncommitted batch
+// when the checkpoint was last advanced.
+func (c *Config) CheckpointReplay() uint64 {
+	batchSize := c.BatchSize
+	if c.AdaptiveBatch {
+		batchSize = max(batchSize, c.MaxBatchSize)
+	}
+	return uint64(2*c.BufferSize + c.WorkerCount*batchSize)
+}
+
+// IsBackfill reports whether the service should backfill a fixed tick range
+// instead of following the live stream.
+func (c *Config) IsBackfill() bool {
+	return c.BackfillEndTick > 0
+}
+
 // Helper functions for environment variable parsing
 
 func getEnv(key, defaultValue string) string {
@@ -128,6 +318,15 @@ func getEnvUint64(key string, defaultValue uint64) uint64 {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
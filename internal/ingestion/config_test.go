@@ -0,0 +1,206 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validateWith returns Validate's error for the default configuration (with a
+// database URL) changed by mutate.
+func validateWith(mutate func(c *Config)) error {
+	c := LoadConfig()
+	c.DatabaseURL = "postgres://localhost/ticks"
+	mutate(c)
+	return c.Validate()
+}
+
+// validateCase is a Validate table case; an empty wantErr means valid.
+type validateCase struct {
+	name    string
+	mutate  func(c *Config)
+	wantErr string
+}
+
+// checkValidate runs table cases against validateWith.
+func checkValidate(t *testing.T, tests []validateCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWith(tt.mutate)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_OutputMode(t *testing.T) {
+	checkValidate(t, []validateCase{
+		{"defaults", func(c *Config) {}, ""},
+		{"timescale without database", func(c *Config) { c.DatabaseURL = "" }, "DATABASE_URL is required"},
+		{"null without database", func(c *Config) { c.OutputMode, c.DatabaseURL = "null", "" }, ""},
+		{"console without database", func(c *Config) { c.OutputMode, c.DatabaseURL = "console", "" }, ""},
+		{"unknown mode", func(c *Config) { c.OutputMode = "kafka" }, "OUTPUT_MODE must be"},
+		{"file without database", func(c *Config) { c.OutputMode, c.DatabaseURL = "file", "" }, ""},
+		{"file without path", func(c *Config) { c.OutputMode, c.OutputPath = "file", "" }, "OUTPUT_PATH is required"},
+		{"negative file size", func(c *Config) { c.OutputMode, c.FileMaxBytes = "file", -1 }, "FILE_MAX_BYTES and FILE_ROTATE_INTERVAL must not be negative"},
+		{"negative rotate interval", func(c *Config) { c.OutputMode, c.FileRotateInterval = "file", -time.Second }, "must not be negative"},
+		{"dry run overrides timescale", func(c *Config) {
+			c.DatabaseURL, c.CheckpointName = "", "main"
+			c.SetDryRun()
+		}, ""},
+	})
+}
+
+func TestValidate_OutputFormat(t *testing.T) {
+	var tests []validateCase
+	for _, format := range []string{"json", "compact", "table", "csv", "tsv"} {
+		tests = append(tests, validateCase{format, func(c *Config) { c.OutputFormat = format }, ""})
+	}
+	tests = append(tests,
+		validateCase{"unknown", func(c *Config) { c.OutputFormat = "xml" }, "OUTPUT_FORMAT must be"},
+		validateCase{"case sensitive", func(c *Config) { c.OutputFormat = "CSV" }, "OUTPUT_FORMAT must be"},
+	)
+	checkValidate(t, tests)
+}
+
+func TestValidate_Backfill(t *testing.T) {
+	checkValidate(t, []validateCase{
+		{"no backfill", func(c *Config) { c.BackfillStartTick = 100 }, ""},
+		{"range", func(c *Config) { c.BackfillStartTick, c.BackfillEndTick = 100, 200 }, ""},
+		{"single tick", func(c *Config) { c.BackfillStartTick, c.BackfillEndTick = 100, 100 }, ""},
+		{"from genesis", func(c *Config) { c.BackfillEndTick = 200 }, ""},
+		{"start after end", func(c *Config) { c.BackfillStartTick, c.BackfillEndTick = 201, 200 }, "must not exceed BACKFILL_END_TICK"},
+	})
+}
+
+func TestConfig_IsBackfill(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end uint64
+		want       bool
+	}{
+		{"unset", 0, 0, false},
+		{"start only", 100, 0, false},
+		{"end set", 0, 200, true},
+		{"range", 100, 200, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{BackfillStartTick: tt.start, BackfillEndTick: tt.end}
+			if got := c.IsBackfill(); got != tt.want {
+				t.Errorf("IsBackfill() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_Sizes(t *testing.T) {
+	checkValidate(t, []validateCase{
+		{"zero buffer", func(c *Config) { c.BufferSize = 0 }, "BUFFER_SIZE must be positive"},
+		{"zero workers", func(c *Config) { c.WorkerCount = 0 }, "WORKER_COUNT must be positive"},
+		{"negative batch", func(c *Config) { c.BatchSize = -1 }, "BATCH_SIZE must be positive"},
+		{"buffer smaller than workers", func(c *Config) { c.BufferSize, c.WorkerCount, c.BatchSize = 4, 8, 1 }, "BUFFER_SIZE (4) must be at least WORKER_COUNT (8)"},
+		{"buffer equals workers", func(c *Config) { c.BufferSize, c.WorkerCount, c.BatchSize = 8, 8, 1 }, ""},
+		{"batch larger than buffer", func(c *Config) { c.BufferSize, c.BatchSize = 100, 101 }, "BATCH_SIZE (101) must not exceed BUFFER_SIZE (100)"},
+		{"batch equals buffer", func(c *Config) { c.BufferSize, c.BatchSize = 100, 100 }, ""},
+		{"negative dedup window", func(c *Config) { c.DedupWindow = -1 }, "DEDUP_WINDOW must not be negative"},
+		{"dedup window", func(c *Config) { c.DedupWindow = 1000 }, ""},
+		{"negative max batch bytes", func(c *Config) { c.MaxBatchBytes = -1 }, "MAX_BATCH_BYTES must not be negative"},
+		{"max batch bytes", func(c *Config) { c.MaxBatchBytes = 1 << 20 }, ""},
+		{"min connections above max", func(c *Config) { c.MinConnections, c.MaxConnections = 20, 10 }, "DB_MIN_CONNECTIONS (20) must not exceed DB_MAX_CONNECTIONS (10)"},
+		{"connections ignored without database", func(c *Config) {
+			c.OutputMode, c.MinConnections, c.MaxConnections = "console", 20, 10
+		}, ""},
+		{"adaptive batch", func(c *Config) { c.AdaptiveBatch = true }, ""},
+		{"adaptive batch of one", func(c *Config) { c.AdaptiveBatch, c.MinBatchSize, c.MaxBatchSize = true, 1, 1 }, ""},
+		{"zero min batch", func(c *Config) { c.AdaptiveBatch, c.MinBatchSize = true, 0 }, "MIN_BATCH_SIZE (0) must be positive"},
+		{"min batch above max", func(c *Config) { c.AdaptiveBatch, c.MinBatchSize, c.MaxBatchSize = true, 100, 50 }, "MIN_BATCH_SIZE (100) must be positive and not exceed MAX_BATCH_SIZE (50)"},
+		{"zero target write", func(c *Config) { c.AdaptiveBatch, c.TargetWriteDuration = true, 0 }, "TARGET_WRITE_DURATION must be positive"},
+		{"bounds ignored when not adaptive", func(c *Config) { c.MinBatchSize, c.TargetWriteDuration = 0, 0 }, ""},
+		{"drop oldest", func(c *Config) { c.BackpressurePolicy = "drop_oldest" }, ""},
+		{"unknown backpressure policy", func(c *Config) { c.BackpressurePolicy = "spill" }, "BACKPRESSURE_POLICY must be 'block' or 'drop_oldest', got: spill"},
+		{"zero backpressure warning", func(c *Config) { c.BackpressureWarnAfter = 0 }, "BACKPRESSURE_WARN_AFTER must be positive"},
+	})
+}
+
+func TestConfig_Warnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		want   []string // Substrings, one per expected warning
+	}{
+		{"defaults", func(c *Config) {}, nil},
+		{"batches cannot all fill", func(c *Config) { c.BufferSize, c.WorkerCount, c.BatchSize = 1000, 8, 250 }, []string{"BATCH_SIZE (250) x WORKER_COUNT (8) exceeds BUFFER_SIZE (1000)"}},
+		{"more workers than connections", func(c *Config) { c.WorkerCount, c.MaxConnections = 16, 8 }, []string{"WORKER_COUNT (16) exceeds DB_MAX_CONNECTIONS (8)"}},
+		{"connections ignored without database", func(c *Config) { c.OutputMode, c.WorkerCount, c.MaxConnections = "null", 16, 8 }, nil},
+		{"adaptive batches beyond the buffer", func(c *Config) { c.AdaptiveBatch, c.MaxBatchSize = true, c.BufferSize+1 }, []string{"adaptive batches cannot grow past the buffer"}},
+		{"max batch ignored when not adaptive", func(c *Config) { c.MaxBatchSize = c.BufferSize + 1 }, nil},
+		{"dropping ticks into the database", func(c *Config) { c.BackpressurePolicy = "drop_oldest" }, []string{"the database will have gaps"}},
+		{"dropping ticks on the console", func(c *Config) { c.BackpressurePolicy, c.OutputMode = "drop_oldest", "console" }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := LoadConfig()
+			tt.mutate(c)
+
+			got := c.Warnings()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Warnings() = %q, want %d", got, len(tt.want))
+			}
+			for i, warning := range got {
+				if !strings.Contains(warning, tt.want[i]) {
+					t.Errorf("warning %d = %q, want %q", i, warning, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSetDryRun(t *testing.T) {
+	c := LoadConfig()
+	c.CheckpointName = "main"
+	c.SetDryRun()
+	if c.OutputMode != "null" || c.CheckpointName != "" {
+		t.Errorf("after SetDryRun: OUTPUT_MODE = %q, CHECKPOINT_NAME = %q; want null and none", c.OutputMode, c.CheckpointName)
+	}
+}
+
+func TestLoadConfig_Env(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		check func(c *Config) bool
+	}{
+		{"VERBOSE_TABLE", "VERBOSE_TABLE", "true", func(c *Config) bool { return c.VerboseTable }},
+		{"COLOR_OUTPUT", "COLOR_OUTPUT", "true", func(c *Config) bool { return c.ColorOutput }},
+		{"SUMMARY_EVERY", "SUMMARY_EVERY", "100", func(c *Config) bool { return c.SummaryEvery == 100 }},
+		{"MAX_BATCH_BYTES", "MAX_BATCH_BYTES", "1048576", func(c *Config) bool { return c.MaxBatchBytes == 1<<20 }},
+		{"MAX_TICKS", "MAX_TICKS", "1000", func(c *Config) bool { return c.MaxTicks == 1000 }},
+		{"STREAM_RESUME", "STREAM_RESUME", "false", func(c *Config) bool { return !c.StreamResume }},
+		{"DEDUP_WINDOW", "DEDUP_WINDOW", "1000", func(c *Config) bool { return c.DedupWindow == 1000 }},
+		{"BACKFILL_START_TICK", "BACKFILL_START_TICK", "100", func(c *Config) bool { return c.BackfillStartTick == 100 }},
+		{"BACKFILL_END_TICK", "BACKFILL_END_TICK", "200", func(c *Config) bool { return c.BackfillEndTick == 200 }},
+		{"SUMMARY_INTERVAL", "SUMMARY_INTERVAL", "10s", func(c *Config) bool { return c.SummaryInterval == 10*time.Second }},
+		{"READY_STALENESS", "READY_STALENESS", "30s", func(c *Config) bool { return c.ReadyStaleness == 30*time.Second }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			if c := LoadConfig(); !tt.check(c) {
+				t.Errorf("%s=%s not loaded: %+v", tt.key, tt.value, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package ingestion
+
+import "container/list"
+
+// tickDeduper remembers the most recently seen tick numbers so ticks replayed
+// at a stream reconnect boundary can be dropped before parsing.
+// It is bounded to size entries, evicting the least recently seen first.
+// Not safe for concurrent use; the pipeline only calls it from readFromStream.
+type tickDeduper struct {
+	size  int
+	order *list.List               // Front = most recently seen
+	seen  map[uint64]*list.Element // Tick number → element in order
+}
+
+// newTickDeduper returns a deduper remembering up to size tick numbers,
+// or nil if size is not positive (deduplication disabled).
+func newTickDeduper(size int) *tickDeduper {
+	if size <= 0 {
+		return nil
+	}
+	return &tickDeduper{
+		size:  size,
+		order: list.New(),
+		seen:  make(map[uint64]*list.Element, size),
+	}
+}
+
+// Seen records tickNumber and reports whether it was already present.
+func (d *tickDeduper) Seen(tickNumber uint64) bool {
+	if elem, ok := d.seen[tickNumber]; ok {
+		d.order.MoveToFront(elem)
+		return true
+	}
+
+	d.seen[tickNumber] = d.order.PushFront(tickNumber)
+	if d.order.Len() > d.size {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.seen, oldest.Value.(uint64))
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package ingestion
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestTickDeduper_Seen(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		ticks []uint64
+		want  []bool // Seen result per tick
+	}{
+		{"no repeats", 3, []uint64{1, 2, 3, 4}, []bool{false, false, false, false}},
+		{"immediate repeat", 3, []uint64{1, 1}, []bool{false, true}},
+		{"replay within window", 3, []uint64{1, 2, 3, 2, 3}, []bool{false, false, false, true, true}},
+		{"evicted after window", 2, []uint64{1, 2, 3, 1}, []bool{false, false, false, false}},
+		{"repeat refreshes recency", 2, []uint64{1, 2, 1, 3, 1, 2}, []bool{false, false, true, false, true, false}},
+		{"window of one", 1, []uint64{5, 5, 6, 5}, []bool{false, true, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTickDeduper(tt.size)
+			var got []bool
+			for _, n := range tt.ticks {
+				got = append(got, d.Seen(n))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Seen() = %v, want %v", got, tt.want)
+			}
+			if len(d.seen) > tt.size || d.order.Len() != len(d.seen) {
+				t.Errorf("remembers %d ticks (%d in order), want at most %d", len(d.seen), d.order.Len(), tt.size)
+			}
+		})
+	}
+}
+
+func TestNewTickDeduper_Disabled(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		if d := newTickDeduper(size); d != nil {
+			t.Errorf("newTickDeduper(%d) = %v, want nil", size, d)
+		}
+	}
+}
+
+func TestPipeline_DedupWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		window        int
+		ticks         []uint64
+		wantWritten   []uint64
+		wantDuplicate float64
+	}{
+		{"disabled", 0, []uint64{1, 2, 2, 3}, []uint64{1, 2, 2, 3}, 0},
+		{"reconnect replay dropped", 10, []uint64{1, 2, 3, 2, 3, 4}, []uint64{1, 2, 3, 4}, 2},
+		{"replay beyond window kept", 2, []uint64{1, 2, 3, 1}, []uint64{1, 1, 2, 3}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &recordingWriter{}
+			registry := prometheus.NewRegistry()
+			p := newTestPipeline(reader, writer, PipelineConfig{WorkerCount: 2, DedupWindow: tt.window, Registry: registry})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			for _, n := range tt.ticks {
+				reader.ticks <- &pb.Tick{TickNumber: n}
+			}
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if got := writer.written(); !slices.Equal(got, tt.wantWritten) {
+				t.Errorf("written ticks = %v, want %v", got, tt.wantWritten)
+			}
+			if got := counterValues(t, registry, "tick_ingester_duplicate_ticks_total")[""]; got != tt.wantDuplicate {
+				t.Errorf("duplicate_ticks_total = %v, want %v", got, tt.wantDuplicate)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package ingestion
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// streamErrorLogInterval is how long identical stream errors are collapsed
+// into one log line before a summary is emitted.
+const streamErrorLogInterval = 30 * time.Second
+
+// streamErrorLog collapses repeated identical stream errors (e.g. during a
+// reconnect storm): the first occurrence is logged, repeats within the interval
+// are counted, and a summary with the count is logged when the interval ends,
+// a different error arrives, or Flush is called.
+// Not safe for concurrent use; the pipeline only calls it from readFromStream.
+type streamErrorLog struct {
+	logger   *zap.Logger
+	interval time.Duration
+
+	last       string    // Message of the error being collapsed
+	since      time.Time // When last was first logged in this window
+	suppressed int       // Repeats of last not logged yet
+}
+
+func newStreamErrorLog(logger *zap.Logger, interval time.Duration) *streamErrorLog {
+	return &streamErrorLog{logger: logger, interval: interval}
+}
+
+// Log records err at now, logging it unless it repeats the current error within the interval.
+func (l *streamErrorLog) Log(err error, now time.Time) {
+	msg := err.Error()
+	if msg == l.last && now.Sub(l.since) < l.interval {
+		l.suppressed++
+		return
+	}
+
+	l.Flush(now)
+	l.logger.Error("Stream error", zap.Error(err))
+	l.last = msg
+	l.since = now
+}
+
+// Flush logs a summary of suppressed repeats, if any, and ends the current window.
+func (l *streamErrorLog) Flush(now time.Time) {
+	if l.suppressed > 0 {
+		l.logger.Error("Stream error repeated",
+			zap.String("error", l.last),
+			zap.Int("repeats", l.suppressed),
+			zap.Duration("window", now.Sub(l.since)),
+		)
+	}
+	l.last = ""
+	l.suppressed = 0
+}
@@ -0,0 +1,139 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/clock"
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// loggedErrors summarizes the observed stream error logs, with the repeat
+// count and window of each summary.
+func loggedErrors(logs *observer.ObservedLogs) []string {
+	var got []string
+	for _, entry := range logs.All() {
+		fields := entry.ContextMap()
+		if entry.Message == "Stream error repeated" {
+			got = append(got, fmt.Sprintf("%s x%d in %s", fields["error"], fields["repeats"], fields["window"]))
+			continue
+		}
+		got = append(got, fmt.Sprint(fields["error"]))
+	}
+	return got
+}
+
+func TestStreamErrorLog(t *testing.T) {
+	// An event logs err at the given offset, or flushes if err is empty
+	type event struct {
+		at  time.Duration
+		err string
+	}
+
+	tests := []struct {
+		name   string
+		events []event
+		want   []string
+	}{
+		{
+			name:   "single error",
+			events: []event{{0, "unavailable"}},
+			want:   []string{"unavailable"},
+		},
+		{
+			name:   "repeats are collapsed",
+			events: []event{{0, "unavailable"}, {time.Second, "unavailable"}, {2 * time.Second, "unavailable"}, {3 * time.Second, ""}},
+			want:   []string{"unavailable", "unavailable x2 in 3s"},
+		},
+		{
+			name:   "flush without repeats logs nothing",
+			events: []event{{0, "unavailable"}, {time.Second, ""}},
+			want:   []string{"unavailable"},
+		},
+		{
+			name:   "different error ends the window",
+			events: []event{{0, "unavailable"}, {time.Second, "unavailable"}, {2 * time.Second, "reset"}, {3 * time.Second, "reset"}},
+			want:   []string{"unavailable", "unavailable x1 in 2s", "reset"},
+		},
+		{
+			name:   "repeat after the interval is logged again",
+			events: []event{{0, "unavailable"}, {10 * time.Second, "unavailable"}, {30 * time.Second, "unavailable"}},
+			want:   []string{"unavailable", "unavailable x1 in 30s", "unavailable"},
+		},
+		{
+			name:   "error after a flush is logged again",
+			events: []event{{0, "unavailable"}, {time.Second, ""}, {2 * time.Second, "unavailable"}},
+			want:   []string{"unavailable", "unavailable"},
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.ErrorLevel)
+			l := newStreamErrorLog(zap.New(core), 30*time.Second)
+
+			for _, e := range tt.events {
+				if e.err == "" {
+					l.Flush(start.Add(e.at))
+				} else {
+					l.Log(errors.New(e.err), start.Add(e.at))
+				}
+			}
+			if got := loggedErrors(logs); !slices.Equal(got, tt.want) {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_RepeatedStreamErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		repeats int
+		tick    bool // Send a tick after the errors
+		want    []string
+	}{
+		{"one error", 1, false, []string{"connection refused"}},
+		{"storm", 1000, false, []string{"connection refused", "connection refused x999 in 0s"}},
+		{"storm ended by a tick", 1000, true, []string{"connection refused", "connection refused x999 in 0s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &recordingWriter{}
+			core, logs := observer.New(zapcore.ErrorLevel)
+			clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			p := NewPipeline(reader, stubParser{}, writer, zap.New(core), PipelineConfig{WorkerCount: 1, Clock: clk})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			for range tt.repeats {
+				reader.errs <- errors.New("connection refused")
+			}
+			if tt.tick {
+				reader.ticks <- &pb.Tick{TickNumber: 1}
+				// The summary is logged as soon as ticks flow again
+				eventually(t, func() bool { return logs.Len() == len(tt.want) })
+			}
+
+			// Otherwise it is logged on exit
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got := loggedErrors(logs); !slices.Equal(got, tt.want) {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
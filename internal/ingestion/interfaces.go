@@ -38,6 +38,17 @@ type Parser interface {
 	Parse(tick *pb.Tick) (*domain.Tick, error)
 }
 
+// BatchParser is an optional extension of Parser for parsing many ticks at once,
+// e.g. a chunk of a backfill range.
+type BatchParser interface {
+	Parser
+
+	// ParseBatch parses each tick independently. The returned slices align with
+	// ticks: for index i, either ticks[i] or errs[i] is non-nil. errs is nil when
+	// every tick parsed.
+	ParseBatch(ticks []*pb.Tick) ([]*domain.Tick, []error)
+}
+
 // Writer persists or outputs processed ticks.
 // Implementations must be safe for concurrent use from multiple goroutines.
 //
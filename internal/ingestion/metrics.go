@@ -1,6 +1,8 @@
 package ingestion
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -17,6 +19,10 @@ type Metrics struct {
 	WriteDuration prometheus.Histogram
 	BatchSize     prometheus.Histogram
 
+	// Per-worker write distribution (cardinality bounded by worker count)
+	WorkerBatches *prometheus.CounterVec
+	WorkerTicks   *prometheus.CounterVec
+
 	// Stream reconnections
 	StreamReconnects prometheus.Counter
 
@@ -25,12 +31,30 @@ type Metrics struct {
 
 	// Write errors
 	WriteErrors prometheus.Counter
+
+	// Ingestion lag (tick timestamp → received by ingester)
+	IngestionLatency prometheus.Histogram
+
+	// Per-public-key nonce decreases/repeats (only when nonce checking is enabled)
+	NonceAnomalies prometheus.Counter
+
+	// Ticks dropped as duplicates (only when deduplication is enabled)
+	DuplicateTicks prometheus.Counter
+
+	// Writer lag: parse workers waiting on a full parsed-tick channel
+	BackpressureWait   prometheus.Histogram
+	BackpressureStalls prometheus.Counter
+	BackpressureDrops  prometheus.Counter
 }
 
-// NewMetrics creates and registers all Prometheus metrics.
-func NewMetrics(namespace string) *Metrics {
+// NewMetrics creates all Prometheus metrics and registers them with reg.
+// Pass a dedicated registry per pipeline so several pipelines can coexist in
+// one process; a nil reg leaves the metrics unregistered.
+func NewMetrics(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+
 	return &Metrics{
-		TicksTotal: promauto.NewCounterVec(
+		TicksTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "ticks_total",
@@ -39,7 +63,7 @@ func NewMetrics(namespace string) *Metrics {
 			[]string{"status"},
 		),
 
-		BufferSize: promauto.NewGauge(
+		BufferSize: factory.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
 				Name:      "buffer_size",
@@ -47,7 +71,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		WriteDuration: promauto.NewHistogram(
+		WriteDuration: factory.NewHistogram(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "write_duration_seconds",
@@ -56,7 +80,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		BatchSize: promauto.NewHistogram(
+		BatchSize: factory.NewHistogram(
 			prometheus.HistogramOpts{
 				Namespace: namespace,
 				Name:      "batch_size",
@@ -65,7 +89,25 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		StreamReconnects: promauto.NewCounter(
+		WorkerBatches: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "worker_batches_total",
+				Help:      "Total number of batches successfully written, labeled by batch writer worker ID",
+			},
+			[]string{"worker_id"},
+		),
+
+		WorkerTicks: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "worker_ticks_total",
+				Help:      "Total number of ticks successfully written, labeled by batch writer worker ID",
+			},
+			[]string{"worker_id"},
+		),
+
+		StreamReconnects: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "stream_reconnects_total",
@@ -73,7 +115,7 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		ParseErrors: promauto.NewCounter(
+		ParseErrors: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "parse_errors_total",
@@ -81,13 +123,63 @@ func NewMetrics(namespace string) *Metrics {
 			},
 		),
 
-		WriteErrors: promauto.NewCounter(
+		WriteErrors: factory.NewCounter(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Name:      "write_errors_total",
 				Help:      "Total number of database write errors",
 			},
 		),
+
+		IngestionLatency: factory.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "ingestion_latency_seconds",
+				Help:      "Time between a tick's on-chain timestamp and its receipt by the ingester",
+				Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+			},
+		),
+
+		NonceAnomalies: factory.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "nonce_anomalies_total",
+				Help:      "Total number of transactions whose nonce decreased or repeated for their public key",
+			},
+		),
+
+		DuplicateTicks: factory.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "duplicate_ticks_total",
+				Help:      "Total number of ticks dropped because their tick number was recently seen",
+			},
+		),
+
+		BackpressureWait: factory.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "backpressure_wait_seconds",
+				Help:      "Time parse workers waited to hand a tick to batch writers, for sends that found the channel full",
+				Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+			},
+		),
+
+		BackpressureStalls: factory.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "backpressure_stalls_total",
+				Help:      "Total number of times a parse worker stayed blocked past the backpressure warning threshold",
+			},
+		),
+
+		BackpressureDrops: factory.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "backpressure_dropped_ticks_total",
+				Help:      "Total number of parsed ticks dropped to make room (drop_oldest policy only)",
+			},
+		),
 	}
 }
 
@@ -111,6 +203,31 @@ func (m *Metrics) RecordWriteError() {
 	m.WriteErrors.Inc()
 }
 
+// RecordNonceAnomaly increments the nonce anomaly counter.
+func (m *Metrics) RecordNonceAnomaly() {
+	m.NonceAnomalies.Inc()
+}
+
+// RecordDuplicateTick increments the duplicate tick counter.
+func (m *Metrics) RecordDuplicateTick() {
+	m.DuplicateTicks.Inc()
+}
+
+// ObserveBackpressureWait records how long a parse worker waited on a full channel.
+func (m *Metrics) ObserveBackpressureWait(seconds float64) {
+	m.BackpressureWait.Observe(seconds)
+}
+
+// RecordBackpressureStall increments the backpressure stall counter.
+func (m *Metrics) RecordBackpressureStall() {
+	m.BackpressureStalls.Inc()
+}
+
+// RecordBackpressureDrop increments the backpressure drop counter.
+func (m *Metrics) RecordBackpressureDrop() {
+	m.BackpressureDrops.Inc()
+}
+
 // RecordStreamReconnect increments the reconnection counter.
 func (m *Metrics) RecordStreamReconnect() {
 	m.StreamReconnects.Inc()
@@ -130,3 +247,15 @@ func (m *Metrics) ObserveWriteDuration(seconds float64) {
 func (m *Metrics) ObserveBatchSize(size int) {
 	m.BatchSize.Observe(float64(size))
 }
+
+// ObserveIngestionLatency records how far behind the chain a tick was received.
+func (m *Metrics) ObserveIngestionLatency(seconds float64) {
+	m.IngestionLatency.Observe(seconds)
+}
+
+// RecordWorkerBatch records a successful batch write by the given worker.
+func (m *Metrics) RecordWorkerBatch(workerID int, size int) {
+	label := strconv.Itoa(workerID)
+	m.WorkerBatches.WithLabelValues(label).Inc()
+	m.WorkerTicks.WithLabelValues(label).Add(float64(size))
+}
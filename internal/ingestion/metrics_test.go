@@ -0,0 +1,178 @@
+package ingestion
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// counterValues returns the values of the named counter family, keyed by the
+// value of its first label ("" if unlabeled).
+func counterValues(t *testing.T, registry *prometheus.Registry, name string) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	values := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			label := ""
+			if labels := metric.GetLabel(); len(labels) > 0 {
+				label = labels[0].GetValue()
+			}
+			values[label] = metric.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+func sum(values map[string]float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestMetrics_RecordWorkerBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		batches     [][2]int // Worker ID and batch size
+		wantBatches map[string]float64
+		wantTicks   map[string]float64
+	}{
+		{
+			name:        "single worker",
+			batches:     [][2]int{{0, 10}, {0, 5}},
+			wantBatches: map[string]float64{"0": 2},
+			wantTicks:   map[string]float64{"0": 15},
+		},
+		{
+			name:        "several workers",
+			batches:     [][2]int{{0, 10}, {3, 250}, {1, 1}, {3, 250}},
+			wantBatches: map[string]float64{"0": 1, "1": 1, "3": 2},
+			wantTicks:   map[string]float64{"0": 10, "1": 1, "3": 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			m := NewMetrics("test", registry)
+			for _, batch := range tt.batches {
+				m.RecordWorkerBatch(batch[0], batch[1])
+			}
+
+			if got := counterValues(t, registry, "test_worker_batches_total"); !maps.Equal(got, tt.wantBatches) {
+				t.Errorf("worker_batches_total = %v, want %v", got, tt.wantBatches)
+			}
+			if got := counterValues(t, registry, "test_worker_ticks_total"); !maps.Equal(got, tt.wantTicks) {
+				t.Errorf("worker_ticks_total = %v, want %v", got, tt.wantTicks)
+			}
+		})
+	}
+}
+
+func TestPipeline_WorkerBatchMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		workers  int
+		batch    int
+		numTicks int
+	}{
+		{"single worker", 1, 10, 25},
+		{"several workers", 4, 7, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			registry := prometheus.NewRegistry()
+			p := newTestPipeline(reader, &recordingWriter{}, PipelineConfig{
+				WorkerCount: tt.workers,
+				BatchSize:   tt.batch,
+				Registry:    registry,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			for i := 1; i <= tt.numTicks; i++ {
+				reader.ticks <- &pb.Tick{TickNumber: uint64(i)}
+			}
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			ticks := counterValues(t, registry, "tick_ingester_worker_ticks_total")
+			if got := sum(ticks); got != float64(tt.numTicks) {
+				t.Errorf("worker_ticks_total sums to %v, want %d", got, tt.numTicks)
+			}
+			if len(ticks) > tt.workers {
+				t.Errorf("worker_ticks_total has %d worker labels, want at most %d", len(ticks), tt.workers)
+			}
+			batches := counterValues(t, registry, "tick_ingester_worker_batches_total")
+			if got, wantMin := sum(batches), float64((tt.numTicks+tt.batch-1)/tt.batch); got < wantMin {
+				t.Errorf("worker_batches_total sums to %v, want at least %v", got, wantMin)
+			}
+		})
+	}
+}
+
+func TestNewMetrics_Registry(t *testing.T) {
+	shared := prometheus.NewRegistry()
+
+	tests := []struct {
+		name       string
+		registries []*prometheus.Registry // One pipeline per entry; nil leaves it unregistered
+		wantPanic  bool
+	}{
+		{"unregistered", []*prometheus.Registry{nil, nil}, false},
+		{"one registry each", []*prometheus.Registry{prometheus.NewRegistry(), prometheus.NewRegistry()}, false},
+		{"shared registry", []*prometheus.Registry{shared, shared}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+
+			for _, registry := range tt.registries {
+				config := PipelineConfig{}
+				if registry != nil {
+					config.Registry = registry
+				}
+				newTestPipeline(newChanReader(), &recordingWriter{}, config)
+
+				if registry != nil && len(counterValues(t, registry, "tick_ingester_parse_errors_total")) != 1 {
+					t.Error("pipeline metrics missing from its registry")
+				}
+			}
+		})
+	}
+}
+
+func TestNewMetrics_NotDefaultRegistry(t *testing.T) {
+	newTestPipeline(newChanReader(), &recordingWriter{}, PipelineConfig{Registry: prometheus.NewRegistry()})
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, family := range families {
+		if strings.HasPrefix(family.GetName(), "tick_ingester_") {
+			t.Errorf("%s registered on the default registry", family.GetName())
+		}
+	}
+}
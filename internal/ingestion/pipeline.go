@@ -4,29 +4,58 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/fermilabs/fermi-api-gateway/internal/clock"
 	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+	"github.com/fermilabs/fermi-api-gateway/internal/parser"
 	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
 // Pipeline orchestrates the tick ingestion process:
 // StreamReader → Parser → Worker Pool → Batch Accumulator → Writer
 type Pipeline struct {
-	reader      StreamReader
-	parser      Parser
-	writer      Writer
-	logger      *zap.Logger
-	metrics     *Metrics
-	bufferSize  int
-	workerCount int
-	batchSize   int
+	reader        StreamReader
+	parser        Parser
+	writer        Writer
+	logger        *zap.Logger
+	metrics       *Metrics
+	clock         clock.Clock
+	bufferSize    int
+	workerCount   int
+	batchSize     int
+	maxBatchBytes int
 	flushInterval time.Duration
+	nonceTracker  *parser.NonceTracker // nil unless nonce checking is enabled
+	maxTicks      uint64               // Stop after reading this many ticks (0 = unlimited)
+	deduper       *tickDeduper         // nil unless deduplication is enabled
+	drainTimeout  time.Duration
+	adaptive      AdaptiveBatchConfig
+	retryWrites   bool
+	writeGate     sync.RWMutex // Held shared by every write, exclusively while a failed batch is retried
+
+	backpressure          BackpressurePolicy
+	backpressureWarnAfter time.Duration
+	lastBackpressureLog   atomic.Int64 // Unix nanos of the last writer lag warning
 
 	// Internal state
-	wg     sync.WaitGroup
-	stopCh chan struct{}
+	wg        sync.WaitGroup
+	stopCh    chan struct{}
+	stopOnce  sync.Once     // Guards close(stopCh) so Run and Close can both trigger shutdown
+	runDone   chan struct{} // Closed when Run returns, so Close doesn't close the writer mid-drain
+	closeOnce sync.Once
+	closeErr  error
+
+	started atomic.Bool // Set by the first Run; later calls fail
+
+	// Stream health, read by Ready
+	startedAt     atomic.Int64          // Unix nanos when Run started (0 = not running)
+	lastTickAt    atomic.Int64          // Unix nanos of the last tick received from the stream
+	streamClosed  atomic.Bool           // Reader closed its tick channel (stream ended or fatal error)
+	lastStreamErr atomic.Pointer[error] // Most recent error reported by the reader
 }
 
 // PipelineConfig holds configuration for the pipeline.
@@ -34,7 +63,42 @@ type PipelineConfig struct {
 	BufferSize    int           // Buffered channel capacity (default: 10000)
 	WorkerCount   int           // Number of worker goroutines (default: 8)
 	BatchSize     int           // Number of ticks per batch (default: 250)
+	MaxBatchBytes int           // Flush once summed transaction payload bytes reach this (default: 0 = disabled)
 	FlushInterval time.Duration // Max time before flushing batch (default: 100ms)
+	CheckNonces   bool          // Track per-public-key nonces and flag decreases/repeats (default: false)
+	MaxTicks      uint64        // Stop after processing this many ticks (default: 0 = unlimited)
+	DedupWindow   int           // Drop ticks whose number is among the last N seen (default: 0 = disabled)
+	DrainTimeout  time.Duration // Max time to write buffered ticks on shutdown before dropping them (default: 30s)
+
+	// RetryWrites retries a failed batch until it is written (or the drain is
+	// aborted), pausing the other batch writers meanwhile so no later batch
+	// commits past it. Needed with a writer checkpoint (default: false = log and drop)
+	RetryWrites bool
+
+	// Adaptive replaces the fixed BatchSize with one tuned per batch writer (default: disabled)
+	Adaptive AdaptiveBatchConfig
+
+	// What parse workers do when batch writers fall behind (default: BackpressureBlock)
+	Backpressure BackpressurePolicy
+	// Count and log a stall once a blocked send waits this long (default: 1s)
+	BackpressureWarnAfter time.Duration
+
+	// Clock drives flush timing and tick arrival times (default: real time)
+	Clock clock.Clock
+
+	// Registry receives the pipeline's metrics (default: nil = unregistered).
+	// Each pipeline in a process needs its own registry.
+	Registry prometheus.Registerer
+}
+
+// AdaptiveBatchConfig bounds the adaptive batch size. When enabled, each batch
+// writer starts at BatchSize and adjusts it between MinBatchSize and MaxBatchSize
+// based on how quickly batches fill and how long they take to write.
+type AdaptiveBatchConfig struct {
+	Enabled             bool
+	MinBatchSize        int           // Lower bound (default: 10)
+	MaxBatchSize        int           // Upper bound (default: 5000)
+	TargetWriteDuration time.Duration // Shrink batches whose write takes longer than this (default: 100ms)
 }
 
 // DefaultPipelineConfig returns the default configuration.
@@ -44,6 +108,7 @@ func DefaultPipelineConfig() PipelineConfig {
 		WorkerCount:   8,
 		BatchSize:     250,
 		FlushInterval: 100 * time.Millisecond,
+		DrainTimeout:  30 * time.Second,
 	}
 }
 
@@ -61,82 +126,172 @@ func NewPipeline(reader StreamReader, parser Parser, writer Writer, logger *zap.
 	if config.FlushInterval == 0 {
 		config.FlushInterval = DefaultPipelineConfig().FlushInterval
 	}
+	if config.Clock == nil {
+		config.Clock = clock.Real()
+	}
+	if config.DrainTimeout == 0 {
+		config.DrainTimeout = DefaultPipelineConfig().DrainTimeout
+	}
+	if config.Backpressure == "" {
+		config.Backpressure = BackpressureBlock
+	}
+	if config.BackpressureWarnAfter == 0 {
+		config.BackpressureWarnAfter = time.Second
+	}
+	if config.Adaptive.MinBatchSize == 0 {
+		config.Adaptive.MinBatchSize = 10
+	}
+	if config.Adaptive.MaxBatchSize == 0 {
+		config.Adaptive.MaxBatchSize = 5000
+	}
+	if config.Adaptive.TargetWriteDuration == 0 {
+		config.Adaptive.TargetWriteDuration = 100 * time.Millisecond
+	}
 
 	return &Pipeline{
 		reader:        reader,
 		parser:        parser,
 		writer:        writer,
 		logger:        logger,
-		metrics:       NewMetrics("tick_ingester"),
+		metrics:       NewMetrics("tick_ingester", config.Registry),
+		clock:         config.Clock,
 		bufferSize:    config.BufferSize,
 		workerCount:   config.WorkerCount,
 		batchSize:     config.BatchSize,
+		maxBatchBytes: config.MaxBatchBytes,
 		flushInterval: config.FlushInterval,
+		nonceTracker:  newNonceTracker(config.CheckNonces),
+		maxTicks:      config.MaxTicks,
+		deduper:       newTickDeduper(config.DedupWindow),
+		drainTimeout:  config.DrainTimeout,
+		adaptive:      config.Adaptive,
+		retryWrites:   config.RetryWrites,
 		stopCh:        make(chan struct{}),
+		runDone:       make(chan struct{}),
+
+		backpressure:          config.Backpressure,
+		backpressureWarnAfter: config.BackpressureWarnAfter,
+	}
+}
+
+// newNonceTracker returns a nonce tracker if nonce checking is enabled, nil otherwise.
+func newNonceTracker(enabled bool) *parser.NonceTracker {
+	if !enabled {
+		return nil
 	}
+	return parser.NewNonceTracker()
 }
 
-// Run starts the pipeline and blocks until context is canceled.
+// Run starts the pipeline and blocks until context is canceled, Close is called,
+// or the pipeline finishes on its own (stream ended or MaxTicks reached).
+// On shutdown only the reader stops immediately; ticks already buffered are
+// parsed and written (up to DrainTimeout) before Run returns.
+// A pipeline runs at most once: further calls return an error.
 func (p *Pipeline) Run(ctx context.Context) error {
+	if !p.started.CompareAndSwap(false, true) {
+		return fmt.Errorf("pipeline already started")
+	}
+	defer close(p.runDone)
+
+	select {
+	case <-p.stopCh:
+		return fmt.Errorf("pipeline already stopped")
+	default:
+	}
+
 	p.logger.Info("Starting tick ingestion pipeline",
 		zap.Int("buffer_size", p.bufferSize),
 		zap.Int("worker_count", p.workerCount),
 		zap.Int("batch_size", p.batchSize),
+		zap.Int("max_batch_bytes", p.maxBatchBytes),
 		zap.Duration("flush_interval", p.flushInterval),
+		zap.Bool("adaptive_batch", p.adaptive.Enabled),
+		zap.String("backpressure", string(p.backpressure)),
 	)
 
+	p.startedAt.Store(p.clock.Now().UnixNano())
+
+	// Parsers and batch writers outlive ctx so buffered ticks can still be
+	// written while draining; workCtx is only canceled if the drain times out
+	workCtx, abortWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer abortWork()
+
 	// Create buffered channel for protobuf ticks
 	pbTickCh := make(chan *pb.Tick, p.bufferSize)
 
 	// Start reading from stream
 	p.wg.Add(1)
-	go p.readFromStream(ctx, pbTickCh)
+	go p.readFromStream(ctx, workCtx, pbTickCh)
 
 	// Create parsed tick channel
 	parsedTickCh := make(chan *domain.Tick, p.bufferSize)
 
 	// Start parser workers
 	p.wg.Add(1)
-	go p.parseWorkers(ctx, pbTickCh, parsedTickCh)
+	go p.parseWorkers(workCtx, pbTickCh, parsedTickCh)
 
 	// Start batch writers
 	p.wg.Add(p.workerCount)
 	for i := 0; i < p.workerCount; i++ {
-		go p.batchWriter(ctx, i, parsedTickCh)
+		go p.batchWriter(workCtx, i, parsedTickCh)
 	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
-	p.logger.Info("Shutdown signal received, draining pipeline...")
-
-	// Close stop channel to signal graceful shutdown
-	close(p.stopCh)
-
-	// Wait for all workers to finish
+	// Track when all workers have finished
 	done := make(chan struct{})
 	go func() {
 		p.wg.Wait()
 		close(done)
 	}()
 
-	// Wait for graceful shutdown or timeout
+	// Wait for context cancellation, Close being called, or the pipeline finishing on its own
+	select {
+	case <-ctx.Done():
+	case <-p.stopCh:
+	case <-done:
+		p.logger.Info("Pipeline finished: all ticks processed")
+		p.stop()
+		return nil
+	}
+	p.logger.Info("Shutdown signal received, draining pipeline...",
+		zap.Int("buffered_ticks", len(pbTickCh)+len(parsedTickCh)),
+	)
+
+	// Stop the reader; closing its channel lets parsers and batch writers
+	// work through what's buffered and exit
+	p.stop()
+
+	// Wait for the drain to finish or give up on the remaining ticks
+	drainTimer := p.clock.NewTimer(p.drainTimeout)
+	defer drainTimer.Stop()
+
 	select {
 	case <-done:
 		p.logger.Info("Pipeline shut down gracefully")
-	case <-time.After(30 * time.Second):
-		p.logger.Warn("Pipeline shutdown timed out after 30s")
+	case <-drainTimer.C():
+		p.logger.Warn("Pipeline drain timed out, dropping buffered ticks",
+			zap.Duration("drain_timeout", p.drainTimeout),
+			zap.Int("dropped_ticks", len(pbTickCh)+len(parsedTickCh)),
+		)
+		abortWork()
 	}
 
 	return nil
 }
 
 // readFromStream reads ticks from the stream reader and forwards to the channel.
-func (p *Pipeline) readFromStream(ctx context.Context, tickCh chan<- *pb.Tick) {
+// It stops reading when ctx is canceled or the pipeline stops; workCtx only
+// unblocks a pending send once the drain has been aborted.
+func (p *Pipeline) readFromStream(ctx, workCtx context.Context, tickCh chan<- *pb.Tick) {
 	defer p.wg.Done()
 	defer close(tickCh)
 
 	pbTickCh, errCh := p.reader.Read(ctx)
 
+	// Collapse repeated errors; a summary is logged once ticks flow again or on exit
+	errLog := newStreamErrorLog(p.logger, streamErrorLogInterval)
+	defer func() { errLog.Flush(p.clock.Now()) }()
+
+	var forwarded uint64
 	for {
 		select {
 		case <-ctx.Done():
@@ -146,22 +301,49 @@ func (p *Pipeline) readFromStream(ctx context.Context, tickCh chan<- *pb.Tick) {
 		case tick, ok := <-pbTickCh:
 			if !ok {
 				p.logger.Info("Stream closed")
+				p.streamClosed.Store(true)
+				return
+			}
+			now := p.clock.Now()
+			p.lastTickAt.Store(now.UnixNano())
+			errLog.Flush(now)
+
+			if p.deduper != nil && p.deduper.Seen(tick.GetTickNumber()) {
+				p.logger.Debug("Dropping duplicate tick", zap.Uint64("tick_number", tick.GetTickNumber()))
+				p.metrics.RecordDuplicateTick()
+				continue
+			}
+
+			select {
+			case tickCh <- tick:
+			case <-workCtx.Done():
+				return
+			}
+
+			forwarded++
+			if p.maxTicks > 0 && forwarded >= p.maxTicks {
+				// Closing tickCh lets parsers and batch writers drain and exit
+				p.logger.Info("Reached max ticks limit, stopping stream",
+					zap.Uint64("max_ticks", p.maxTicks),
+				)
 				return
 			}
-			tickCh <- tick
 		case err, ok := <-errCh:
 			if !ok {
-				return
+				// Readers may close errCh first; ticks still buffered must be read
+				errCh = nil
+				continue
 			}
 			if err != nil {
-				p.logger.Error("Stream error", zap.Error(err))
+				errLog.Log(err, p.clock.Now())
+				p.lastStreamErr.Store(&err)
 			}
 		}
 	}
 }
 
-// parseWorkers runs multiple parser goroutines.
-func (p *Pipeline) parseWorkers(ctx context.Context, pbTickCh <-chan *pb.Tick, parsedTickCh chan<- *domain.Tick) {
+// parseWorkers runs multiple parser goroutines until pbTickCh is closed and drained.
+func (p *Pipeline) parseWorkers(ctx context.Context, pbTickCh <-chan *pb.Tick, parsedTickCh chan *domain.Tick) {
 	defer p.wg.Done()
 	defer close(parsedTickCh)
 
@@ -180,13 +362,12 @@ func (p *Pipeline) parseWorkers(ctx context.Context, pbTickCh <-chan *pb.Tick, p
 }
 
 // parseWorker parses individual ticks.
-func (p *Pipeline) parseWorker(ctx context.Context, id int, pbTickCh <-chan *pb.Tick, parsedTickCh chan<- *domain.Tick) {
+// parsedTickCh is bidirectional so the drop_oldest policy can discard buffered ticks.
+func (p *Pipeline) parseWorker(ctx context.Context, id int, pbTickCh <-chan *pb.Tick, parsedTickCh chan *domain.Tick) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-p.stopCh:
-			return
 		case pbTick, ok := <-pbTickCh:
 			if !ok {
 				return
@@ -203,23 +384,51 @@ func (p *Pipeline) parseWorker(ctx context.Context, id int, pbTickCh <-chan *pb.
 				continue
 			}
 
-			select {
-			case parsedTickCh <- tick:
-			case <-ctx.Done():
-				return
-			case <-p.stopCh:
+			p.metrics.ObserveIngestionLatency(tick.IngestionLatency().Seconds())
+
+			if p.nonceTracker != nil {
+				for _, anomaly := range p.nonceTracker.Check(tick) {
+					p.logger.Warn("Nonce anomaly detected",
+						zap.String("public_key", anomaly.PublicKey),
+						zap.String("tx_hash", anomaly.TxHash),
+						zap.Uint64("tick_number", anomaly.TickNumber),
+						zap.Uint64("sequence_number", anomaly.SequenceNumber),
+						zap.Uint64("nonce", anomaly.Nonce),
+						zap.Uint64("previous_nonce", anomaly.PreviousNonce),
+						zap.String("previous_tx_hash", anomaly.PreviousTxHash),
+					)
+					p.metrics.RecordNonceAnomaly()
+				}
+			}
+
+			if !p.sendParsed(ctx, id, parsedTickCh, tick) {
 				return
 			}
 		}
 	}
 }
 
-// batchWriter accumulates ticks and writes them in batches.
+// batchWriter accumulates ticks and writes them in batches until tickCh is
+// closed and drained. If ctx is canceled (drain aborted) the pending batch is dropped.
 func (p *Pipeline) batchWriter(ctx context.Context, id int, tickCh <-chan *domain.Tick) {
 	defer p.wg.Done()
 
 	batch := make([]*domain.Tick, 0, p.batchSize)
-	timer := time.NewTimer(p.flushInterval)
+	batchBytes := 0          // Summed transaction payload bytes in the current batch
+	var batchStart time.Time // Arrival time of the oldest tick in the current batch
+
+	// Effective batch size; fixed unless adaptive batching is enabled
+	var sizer *batchSizer
+	batchSize := p.batchSize
+	if p.adaptive.Enabled {
+		sizer = newBatchSizer(p.batchSize, p.adaptive.MinBatchSize, p.adaptive.MaxBatchSize, p.adaptive.TargetWriteDuration)
+		batchSize = sizer.Size()
+	}
+
+	// The timer only runs while a batch is pending and always measures from the
+	// batch's first tick, so no tick waits longer than flushInterval (plus write time)
+	timer := p.clock.NewTimer(p.flushInterval)
+	timer.Stop()
 	defer timer.Stop()
 
 	flushBatch := func() {
@@ -227,42 +436,53 @@ func (p *Pipeline) batchWriter(ctx context.Context, id int, tickCh <-chan *domai
 			return
 		}
 
-		batchSize := len(batch)
-		start := time.Now()
-		if err := p.writer.WriteBatch(ctx, batch); err != nil {
+		n := len(batch)
+		start := p.clock.Now()
+		err := p.writeBatch(ctx, batch)
+		duration := p.clock.Since(start)
+		if err != nil && p.retryWrites {
+			err = p.retryBatch(ctx, id, batch, err)
+		}
+		if err != nil {
 			p.logger.Error("Failed to write batch",
 				zap.Int("worker_id", id),
-				zap.Int("batch_size", batchSize),
+				zap.Int("batch_size", n),
 				zap.Error(err),
 			)
 			p.metrics.RecordWriteError()
 		} else {
-			duration := time.Since(start)
 			p.logger.Debug("Wrote batch",
 				zap.Int("worker_id", id),
-				zap.Int("batch_size", batchSize),
+				zap.Int("batch_size", n),
 				zap.Duration("duration", duration),
 			)
-			p.metrics.RecordTickSuccess(batchSize)
-			p.metrics.ObserveBatchSize(batchSize)
+			p.metrics.RecordTickSuccess(n)
+			p.metrics.ObserveBatchSize(n)
+			p.metrics.RecordWorkerBatch(id, n)
 			p.metrics.ObserveWriteDuration(duration.Seconds())
 		}
 
-		// Reset batch
+		// Failed writes count too: a timeout is the clearest sign the batch was too large
+		if sizer != nil {
+			sizer.Observe(n, duration, n >= batchSize)
+			batchSize = sizer.Size()
+		}
+
+		// Reset batch; the timer is restarted when the next batch begins
 		batch = batch[:0]
-		timer.Reset(p.flushInterval)
+		batchBytes = 0
+		timer.Stop()
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			// Flush remaining batch before exit
-			flushBatch()
-			return
-
-		case <-p.stopCh:
-			// Flush remaining batch before exit
-			flushBatch()
+			if len(batch) > 0 {
+				p.logger.Warn("Dropping unwritten batch",
+					zap.Int("worker_id", id),
+					zap.Int("batch_size", len(batch)),
+				)
+			}
 			return
 
 		case tick, ok := <-tickCh:
@@ -272,37 +492,148 @@ func (p *Pipeline) batchWriter(ctx context.Context, id int, tickCh <-chan *domai
 				return
 			}
 
+			if len(batch) == 0 {
+				batchStart = p.clock.Now()
+				timer.Reset(p.flushInterval)
+			}
 			batch = append(batch, tick)
+			batchBytes += tick.PayloadSize()
 
-			// Flush if batch is full
-			if len(batch) >= p.batchSize {
+			// Flush if batch is full (by count or bytes) or has been pending for too long
+			if len(batch) >= batchSize ||
+				(p.maxBatchBytes > 0 && batchBytes >= p.maxBatchBytes) ||
+				p.clock.Since(batchStart) >= p.flushInterval {
 				flushBatch()
 			}
 
-		case <-timer.C:
+		case <-timer.C():
 			// Flush on timer
 			flushBatch()
 		}
 	}
 }
 
-// Close gracefully shuts down the pipeline.
-func (p *Pipeline) Close() error {
-	p.logger.Info("Closing pipeline resources")
+// Backoff between attempts to write a failed batch when RetryWrites is set.
+const (
+	writeRetryInitialBackoff = 100 * time.Millisecond
+	writeRetryMaxBackoff     = 10 * time.Second
+)
+
+// writeBatch writes batch, holding the write gate shared so a retrying batch
+// writer can pause the others.
+func (p *Pipeline) writeBatch(ctx context.Context, batch []*domain.Tick) error {
+	if p.retryWrites {
+		p.writeGate.RLock()
+		defer p.writeGate.RUnlock()
+	}
+	return p.writer.WriteBatch(ctx, batch)
+}
+
+// retryBatch retries a batch whose write failed with err until it succeeds or
+// ctx is canceled, returning the last error in that case. It holds the write
+// gate exclusively, so no other batch commits (and advances the checkpoint)
+// while this one is missing; parse workers and the reader back up behind it.
+func (p *Pipeline) retryBatch(ctx context.Context, id int, batch []*domain.Tick, err error) error {
+	p.writeGate.Lock()
+	defer p.writeGate.Unlock()
+
+	backoff := writeRetryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		p.logger.Warn("Failed to write batch, retrying",
+			zap.Int("worker_id", id),
+			zap.Int("batch_size", len(batch)),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		p.metrics.RecordWriteError()
+
+		timer := p.clock.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C():
+		}
 
-	var errs []error
+		if err = p.writer.WriteBatch(ctx, batch); err == nil {
+			return nil
+		}
+		backoff = min(backoff*2, writeRetryMaxBackoff)
+	}
+}
+
+// Ready reports whether the pipeline is receiving ticks. It returns an error
+// if the pipeline isn't running, the stream has ended, or no tick has arrived
+// within staleness (measured from Run's start until the first tick).
+// A zero staleness disables the staleness check.
+func (p *Pipeline) Ready(staleness time.Duration) error {
+	startedAt := p.startedAt.Load()
+	if startedAt == 0 {
+		return fmt.Errorf("pipeline not started")
+	}
+
+	if p.streamClosed.Load() {
+		if errp := p.lastStreamErr.Load(); errp != nil {
+			return fmt.Errorf("stream closed: %w", *errp)
+		}
+		return fmt.Errorf("stream closed")
+	}
 
-	if err := p.reader.Close(); err != nil {
-		errs = append(errs, fmt.Errorf("reader close error: %w", err))
+	if staleness <= 0 {
+		return nil
 	}
 
-	if err := p.writer.Close(); err != nil {
-		errs = append(errs, fmt.Errorf("writer close error: %w", err))
+	last := p.lastTickAt.Load()
+	if last == 0 {
+		last = startedAt
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("pipeline close errors: %v", errs)
+	if age := p.clock.Since(time.Unix(0, last)); age > staleness {
+		if errp := p.lastStreamErr.Load(); errp != nil {
+			return fmt.Errorf("no tick received for %s (last stream error: %v)", age.Round(time.Second), *errp)
+		}
+		return fmt.Errorf("no tick received for %s", age.Round(time.Second))
 	}
 
 	return nil
 }
+
+// stop signals all pipeline goroutines to exit. Safe to call multiple times.
+func (p *Pipeline) stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+}
+
+// Close gracefully shuts down the pipeline.
+// It is idempotent and may be called before, during, or after Run.
+func (p *Pipeline) Close() error {
+	p.closeOnce.Do(func() {
+		p.logger.Info("Closing pipeline resources")
+
+		// Make sure workers stop even if Run's context was never canceled,
+		// and let a running pipeline finish draining first. Run checks stopCh
+		// after setting started, so one that starts after this check exits at once
+		p.stop()
+		if p.started.Load() {
+			<-p.runDone
+		}
+
+		var errs []error
+
+		if err := p.reader.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("reader close error: %w", err))
+		}
+
+		if err := p.writer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("writer close error: %w", err))
+		}
+
+		if len(errs) > 0 {
+			p.closeErr = fmt.Errorf("pipeline close errors: %v", errs)
+		}
+	})
+
+	return p.closeErr
+}
@@ -0,0 +1,838 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/clock"
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"go.uber.org/zap"
+)
+
+// chanReader is a StreamReader fed by the test through its ticks channel.
+type chanReader struct {
+	ticks chan *pb.Tick
+	errs  chan error
+}
+
+func newChanReader() *chanReader {
+	return &chanReader{ticks: make(chan *pb.Tick), errs: make(chan error)}
+}
+
+func (r *chanReader) Read(ctx context.Context) (<-chan *pb.Tick, <-chan error) {
+	return r.ticks, r.errs
+}
+
+func (r *chanReader) Close() error { return nil }
+
+// sequenceReader emits ticks 1..last (endlessly if last is 0) until ctx is
+// canceled, then closes its channels.
+type sequenceReader struct {
+	last uint64
+}
+
+func (r *sequenceReader) Read(ctx context.Context) (<-chan *pb.Tick, <-chan error) {
+	ticks := make(chan *pb.Tick)
+	errs := make(chan error)
+	go func() {
+		defer close(ticks)
+		defer close(errs)
+		for n := uint64(1); r.last == 0 || n <= r.last; n++ {
+			select {
+			case ticks <- &pb.Tick{TickNumber: n}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ticks, errs
+}
+
+func (r *sequenceReader) Close() error { return nil }
+
+// stubParser turns a protobuf tick into a domain tick carrying only its number.
+type stubParser struct{}
+
+func (stubParser) Parse(tick *pb.Tick) (*domain.Tick, error) {
+	return &domain.Tick{TickNumber: tick.GetTickNumber()}, nil
+}
+
+// recordingWriter records the tick numbers of every written batch. If gate is
+// set, writes wait until it is closed.
+type recordingWriter struct {
+	mu      sync.Mutex
+	ticks   []uint64
+	batches [][]uint64 // Tick numbers of each batch, in write order
+	gate    chan struct{}
+	closed  int
+}
+
+func (w *recordingWriter) Write(ctx context.Context, tick *domain.Tick) error {
+	return w.WriteBatch(ctx, []*domain.Tick{tick})
+}
+
+func (w *recordingWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick) error {
+	if w.gate != nil {
+		<-w.gate
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var batch []uint64
+	for _, tick := range ticks {
+		w.ticks = append(w.ticks, tick.TickNumber)
+		batch = append(batch, tick.TickNumber)
+	}
+	w.batches = append(w.batches, batch)
+	return nil
+}
+
+func (w *recordingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed++
+	return nil
+}
+
+// written returns the recorded tick numbers in ascending order.
+func (w *recordingWriter) written() []uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ticks := slices.Clone(w.ticks)
+	slices.Sort(ticks)
+	return ticks
+}
+
+func newTestPipeline(reader StreamReader, writer Writer, config PipelineConfig) *Pipeline {
+	return NewPipeline(reader, stubParser{}, writer, zap.NewNop(), config)
+}
+
+// runAsync runs p in the background, returning a channel that receives Run's result.
+func runAsync(ctx context.Context, p *Pipeline) <-chan error {
+	result := make(chan error, 1)
+	go func() { result <- p.Run(ctx) }()
+	return result
+}
+
+func waitResult(t *testing.T, result <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func TestPipeline_DrainsBufferedTicksOnShutdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		workers  int
+		batch    int
+		numTicks int
+	}{
+		{"single worker", 1, 10, 25},
+		{"several workers", 4, 7, 200},
+		{"batches never fill", 4, 1000, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &recordingWriter{gate: make(chan struct{})}
+			p := newTestPipeline(reader, writer, PipelineConfig{
+				BufferSize:    1000,
+				WorkerCount:   tt.workers,
+				BatchSize:     tt.batch,
+				FlushInterval: time.Hour, // Only shutdown flushes partial batches
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+
+			// reader.ticks is unbuffered, so each send returns once the pipeline has the tick
+			for i := 1; i <= tt.numTicks; i++ {
+				reader.ticks <- &pb.Tick{TickNumber: uint64(i)}
+			}
+
+			// Writers are stuck, so the ticks are buffered when shutdown begins
+			cancel()
+			close(writer.gate)
+
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			got := writer.written()
+			if len(got) != tt.numTicks {
+				t.Fatalf("wrote %d ticks, want %d", len(got), tt.numTicks)
+			}
+			for i, n := range got {
+				if n != uint64(i+1) {
+					t.Fatalf("written ticks = %v, want 1..%d exactly once", got, tt.numTicks)
+				}
+			}
+		})
+	}
+}
+
+func TestPipeline_RunTwice(t *testing.T) {
+	tests := []struct {
+		name       string
+		concurrent bool
+	}{
+		{"sequential", false},
+		{"concurrent", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPipeline(newChanReader(), &recordingWriter{}, PipelineConfig{WorkerCount: 2})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			first := runAsync(ctx, p)
+			if !tt.concurrent {
+				// Let the first Run get going before the second
+				for p.startedAt.Load() == 0 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+			second := runAsync(ctx, p)
+
+			// Exactly one of the calls fails straight away; the other runs until canceled
+			var running <-chan error
+			var err error
+			select {
+			case err = <-first:
+				running = second
+			case err = <-second:
+				running = first
+			case <-time.After(5 * time.Second):
+				t.Fatal("neither Run returned")
+			}
+			if err == nil {
+				t.Fatal("second Run() error = nil, want already started")
+			}
+
+			cancel()
+			if err := waitResult(t, running); err != nil {
+				t.Fatalf("first Run() error = %v", err)
+			}
+			if err := p.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPipeline_ConcurrentShutdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		runs   int
+		closes int
+		cancel bool // Also cancel Run's context
+	}{
+		{"close only", 0, 4, false},
+		{"close while running", 1, 4, false},
+		{"close and cancel while running", 1, 4, true},
+		{"concurrent runs and closes", 3, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &recordingWriter{}
+			p := newTestPipeline(newChanReader(), writer, PipelineConfig{WorkerCount: 2})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for range tt.runs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					p.Run(ctx)
+				}()
+			}
+			for range tt.closes {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					if err := p.Close(); err != nil {
+						t.Errorf("Close() error = %v", err)
+					}
+				}()
+			}
+			if tt.cancel {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					cancel()
+				}()
+			}
+
+			close(start)
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run/Close did not return")
+			}
+
+			writer.mu.Lock()
+			defer writer.mu.Unlock()
+			if writer.closed != 1 {
+				t.Errorf("writer closed %d times, want 1", writer.closed)
+			}
+			if err := p.Run(ctx); err == nil {
+				t.Error("Run() after Close error = nil, want error")
+			}
+		})
+	}
+}
+
+// timedWriter records when each tick was written.
+type timedWriter struct {
+	mu        sync.Mutex
+	writtenAt map[uint64]time.Time
+}
+
+func (w *timedWriter) Write(ctx context.Context, tick *domain.Tick) error {
+	return w.WriteBatch(ctx, []*domain.Tick{tick})
+}
+
+func (w *timedWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick) error {
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, tick := range ticks {
+		w.writtenAt[tick.TickNumber] = now
+	}
+	return nil
+}
+
+func (w *timedWriter) Close() error { return nil }
+
+func (w *timedWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.writtenAt)
+}
+
+func TestPipeline_FlushesBatchesByAge(t *testing.T) {
+	const (
+		flushInterval = 50 * time.Millisecond
+		slack         = 150 * time.Millisecond // Scheduling and write time
+	)
+
+	tests := []struct {
+		name     string
+		idle     time.Duration // Before the first tick
+		gap      time.Duration // Between ticks
+		numTicks int
+	}{
+		{"first tick after idle", 4 * flushInterval, 0, 1},
+		{"ticks trickle in", 0, 10 * time.Millisecond, 30},
+		{"partial batches in succession", 0, 2 * flushInterval, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &timedWriter{writtenAt: make(map[uint64]time.Time)}
+			p := newTestPipeline(reader, writer, PipelineConfig{
+				WorkerCount:   1,
+				BatchSize:     1000, // Batches never fill, so only age flushes them
+				FlushInterval: flushInterval,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+
+			time.Sleep(tt.idle)
+			sentAt := make(map[uint64]time.Time)
+			for i := 1; i <= tt.numTicks; i++ {
+				if i > 1 {
+					time.Sleep(tt.gap)
+				}
+				sentAt[uint64(i)] = time.Now()
+				reader.ticks <- &pb.Tick{TickNumber: uint64(i)}
+			}
+
+			deadline := time.Now().Add(5 * time.Second)
+			for writer.count() < tt.numTicks && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			writer.mu.Lock()
+			defer writer.mu.Unlock()
+			for n, sent := range sentAt {
+				written, ok := writer.writtenAt[n]
+				if !ok {
+					t.Errorf("tick %d was not written before shutdown", n)
+					continue
+				}
+				if wait := written.Sub(sent); wait > flushInterval+slack {
+					t.Errorf("tick %d waited %v to be written, want at most %v", n, wait, flushInterval+slack)
+				}
+			}
+		})
+	}
+}
+
+func TestPipeline_MaxTicks(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxTicks  uint64
+		streamEnd uint64 // Last tick the stream sends (0 = endless)
+		workers   int
+		want      int
+	}{
+		{"single tick", 1, 0, 1, 1},
+		{"limit reached", 10, 0, 1, 10},
+		{"limit reached, several workers", 250, 0, 4, 250},
+		{"stream ends first", 50, 20, 2, 20},
+		{"unlimited, stream ends", 0, 30, 2, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &recordingWriter{}
+			p := newTestPipeline(&sequenceReader{last: tt.streamEnd}, writer, PipelineConfig{
+				WorkerCount:   tt.workers,
+				BatchSize:     7,
+				FlushInterval: time.Hour,
+				MaxTicks:      tt.maxTicks,
+			})
+
+			// Run returns on its own, without being canceled
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := waitResult(t, runAsync(ctx, p)); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			got := writer.written()
+			if len(got) != tt.want {
+				t.Fatalf("wrote %d ticks, want %d", len(got), tt.want)
+			}
+			for i, n := range got {
+				if n != uint64(i+1) {
+					t.Fatalf("written ticks = %v, want 1..%d", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// eventually polls cond until it holds, failing the test after a few seconds.
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPipeline_Ready(t *testing.T) {
+	const staleness = time.Minute
+
+	tests := []struct {
+		name      string
+		staleness time.Duration
+		steps     func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake)
+		wantErr   string // Empty means ready
+	}{
+		{
+			name:  "just started",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {},
+		},
+		{
+			name: "recent tick",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				clk.Advance(2 * staleness)
+				reader.ticks <- &pb.Tick{TickNumber: 1}
+				eventually(t, func() bool { return p.Ready(staleness) == nil })
+				clk.Advance(staleness)
+			},
+		},
+		{
+			name: "no tick since start",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				clk.Advance(staleness + time.Second)
+			},
+			wantErr: "no tick received for 1m1s",
+		},
+		{
+			name: "stale tick",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				reader.ticks <- &pb.Tick{TickNumber: 1}
+				eventually(t, func() bool { return p.lastTickAt.Load() != 0 })
+				clk.Advance(2 * staleness)
+			},
+			wantErr: "no tick received for 2m0s",
+		},
+		{
+			name: "stale with stream error",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				reader.errs <- errors.New("connection refused")
+				clk.Advance(2 * staleness)
+			},
+			wantErr: "(last stream error: connection refused)",
+		},
+		{
+			name:      "staleness disabled",
+			staleness: -1,
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				clk.Advance(time.Hour)
+			},
+		},
+		{
+			name: "stream ended",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				close(reader.ticks)
+			},
+			wantErr: "stream closed",
+		},
+		{
+			name: "stream ended after error",
+			steps: func(t *testing.T, p *Pipeline, reader *chanReader, clk *clock.Fake) {
+				reader.errs <- errors.New("max retries exceeded")
+				close(reader.ticks)
+			},
+			wantErr: "stream closed: max retries exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			p := newTestPipeline(reader, &recordingWriter{}, PipelineConfig{WorkerCount: 1, Clock: clk})
+
+			if err := p.Ready(staleness); err == nil || err.Error() != "pipeline not started" {
+				t.Fatalf("Ready() before Run = %v, want not started", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			eventually(t, func() bool { return p.startedAt.Load() != 0 })
+
+			tt.steps(t, p, reader, clk)
+
+			check := staleness
+			if tt.staleness != 0 {
+				check = tt.staleness
+			}
+			wantReady := func() bool {
+				err := p.Ready(check)
+				if tt.wantErr == "" {
+					return err == nil
+				}
+				return err != nil && strings.Contains(err.Error(), tt.wantErr)
+			}
+			// The reader goroutine records ticks and errors just after receiving them
+			eventually(t, wantReady)
+
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+		})
+	}
+}
+
+// bufferedReader queues all its ticks up front, then closes errs before ticks,
+// as the stream readers do on exit.
+type bufferedReader struct {
+	numTicks int
+}
+
+func (r *bufferedReader) Read(ctx context.Context) (<-chan *pb.Tick, <-chan error) {
+	ticks := make(chan *pb.Tick, r.numTicks)
+	errs := make(chan error)
+	for i := 1; i <= r.numTicks; i++ {
+		ticks <- &pb.Tick{TickNumber: uint64(i)}
+	}
+	close(errs)
+	close(ticks)
+	return ticks, errs
+}
+
+func (r *bufferedReader) Close() error { return nil }
+
+func TestPipeline_ReadsTicksAfterErrorsClose(t *testing.T) {
+	tests := []struct {
+		name     string
+		numTicks int
+	}{
+		{"no ticks", 0},
+		{"one tick", 1},
+		{"full reader buffer", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &recordingWriter{}
+			p := newTestPipeline(&bufferedReader{numTicks: tt.numTicks}, writer, PipelineConfig{WorkerCount: 2})
+
+			if err := waitResult(t, runAsync(context.Background(), p)); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got := len(writer.written()); got != tt.numTicks {
+				t.Errorf("wrote %d ticks, want %d", got, tt.numTicks)
+			}
+			if err := p.Ready(0); err == nil || err.Error() != "stream closed" {
+				t.Errorf("Ready() = %v, want stream closed", err)
+			}
+		})
+	}
+}
+
+// payloadParser gives each tick one transaction with a payload of the size
+// listed for its number.
+type payloadParser struct {
+	sizes map[uint64]int
+}
+
+func (p payloadParser) Parse(tick *pb.Tick) (*domain.Tick, error) {
+	n := tick.GetTickNumber()
+	return &domain.Tick{
+		TickNumber:   n,
+		Transactions: []domain.Transaction{{Payload: make([]byte, p.sizes[n])}},
+	}, nil
+}
+
+func TestPipeline_FlushesBatchesByBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBytes int
+		sizes    []int // Payload bytes of ticks 1..n
+		want     [][]uint64
+	}{
+		{"disabled", 0, []int{100, 100, 100}, [][]uint64{{1, 2, 3}}},
+		{"limit reached exactly", 200, []int{100, 100, 100, 100}, [][]uint64{{1, 2}, {3, 4}}},
+		{"limit crossed", 250, []int{100, 100, 100, 50}, [][]uint64{{1, 2, 3}, {4}}},
+		{"tick larger than the limit", 50, []int{100, 10}, [][]uint64{{1}, {2}}},
+		{"empty payloads", 100, []int{0, 0, 100, 0}, [][]uint64{{1, 2, 3}, {4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := payloadParser{sizes: make(map[uint64]int)}
+			for i, size := range tt.sizes {
+				parser.sizes[uint64(i+1)] = size
+			}
+
+			reader := newChanReader()
+			writer := &recordingWriter{}
+			// A single worker keeps ticks in order; only bytes and shutdown flush
+			p := NewPipeline(reader, parser, writer, zap.NewNop(), PipelineConfig{
+				WorkerCount:   1,
+				BatchSize:     1000,
+				MaxBatchBytes: tt.maxBytes,
+				FlushInterval: time.Hour,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			for i := range tt.sizes {
+				reader.ticks <- &pb.Tick{TickNumber: uint64(i + 1)}
+			}
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			writer.mu.Lock()
+			defer writer.mu.Unlock()
+			if !slices.EqualFunc(writer.batches, tt.want, slices.Equal) {
+				t.Errorf("batches = %v, want %v", writer.batches, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_AdaptiveBatchSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		adaptive AdaptiveBatchConfig
+		numTicks int
+		want     [][]uint64
+	}{
+		{
+			"disabled",
+			AdaptiveBatchConfig{},
+			10,
+			[][]uint64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10}},
+		},
+		{
+			// Every write is well within the target, so full batches grow
+			"fast writes grow batches",
+			AdaptiveBatchConfig{Enabled: true, MinBatchSize: 1, MaxBatchSize: 100, TargetWriteDuration: time.Hour},
+			22,
+			[][]uint64{{1, 2, 3, 4}, {5, 6, 7, 8, 9}, {10, 11, 12, 13, 14, 15}, {16, 17, 18, 19, 20, 21, 22}},
+		},
+		{
+			"growth stops at the max",
+			AdaptiveBatchConfig{Enabled: true, MinBatchSize: 1, MaxBatchSize: 5, TargetWriteDuration: time.Hour},
+			14,
+			[][]uint64{{1, 2, 3, 4}, {5, 6, 7, 8, 9}, {10, 11, 12, 13, 14}},
+		},
+		{
+			// Every write exceeds the target, so batches shrink
+			"slow writes shrink batches",
+			AdaptiveBatchConfig{Enabled: true, MinBatchSize: 1, MaxBatchSize: 100, TargetWriteDuration: time.Nanosecond},
+			11,
+			[][]uint64{{1, 2, 3, 4}, {5, 6, 7}, {8, 9}, {10}, {11}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &recordingWriter{}
+			// A single worker keeps ticks in order; only the batch size and shutdown flush
+			p := newTestPipeline(reader, writer, PipelineConfig{
+				WorkerCount:   1,
+				BatchSize:     4,
+				FlushInterval: time.Hour,
+				Adaptive:      tt.adaptive,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			for i := range tt.numTicks {
+				reader.ticks <- &pb.Tick{TickNumber: uint64(i + 1)}
+			}
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			writer.mu.Lock()
+			defer writer.mu.Unlock()
+			if !slices.EqualFunc(writer.batches, tt.want, slices.Equal) {
+				t.Errorf("batches = %v, want %v", writer.batches, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_FlushInterval(t *testing.T) {
+	const interval = time.Second
+
+	// send queues a tick and waits for the batch writer to start the flush timer
+	send := func(t *testing.T, reader *chanReader, clk *clock.Fake, n uint64) {
+		t.Helper()
+		reader.ticks <- &pb.Tick{TickNumber: n}
+		eventually(t, func() bool { return clk.Timers() == 1 })
+	}
+
+	tests := []struct {
+		name      string
+		batchSize int
+		steps     func(t *testing.T, reader *chanReader, clk *clock.Fake, writer *recordingWriter)
+		want      [][]uint64
+	}{
+		{
+			name:      "flushes after the interval",
+			batchSize: 1000,
+			steps: func(t *testing.T, reader *chanReader, clk *clock.Fake, writer *recordingWriter) {
+				send(t, reader, clk, 1)
+				clk.Advance(interval - time.Millisecond)
+				checkBatches(t, writer, nil)
+				clk.Advance(time.Millisecond)
+				eventually(t, func() bool { return len(writer.written()) == 1 })
+			},
+			want: [][]uint64{{1}},
+		},
+		{
+			name:      "interval starts with the first tick of a batch",
+			batchSize: 1000,
+			steps: func(t *testing.T, reader *chanReader, clk *clock.Fake, writer *recordingWriter) {
+				send(t, reader, clk, 1)
+				clk.Advance(interval)
+				eventually(t, func() bool { return len(writer.written()) == 1 })
+
+				// No timer runs between batches, so idle time is not counted
+				clk.Advance(time.Hour)
+				send(t, reader, clk, 2)
+				clk.Advance(interval - time.Millisecond)
+				checkBatches(t, writer, [][]uint64{{1}})
+				clk.Advance(time.Millisecond)
+				eventually(t, func() bool { return len(writer.written()) == 2 })
+			},
+			want: [][]uint64{{1}, {2}},
+		},
+		{
+			name:      "full batch stops the timer",
+			batchSize: 2,
+			steps: func(t *testing.T, reader *chanReader, clk *clock.Fake, writer *recordingWriter) {
+				send(t, reader, clk, 1)
+				reader.ticks <- &pb.Tick{TickNumber: 2}
+				eventually(t, func() bool { return len(writer.written()) == 2 && clk.Timers() == 0 })
+				clk.Advance(time.Hour)
+			},
+			want: [][]uint64{{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newChanReader()
+			writer := &recordingWriter{}
+			clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			p := newTestPipeline(reader, writer, PipelineConfig{
+				WorkerCount:   1,
+				BatchSize:     tt.batchSize,
+				FlushInterval: interval,
+				Clock:         clk,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			result := runAsync(ctx, p)
+			tt.steps(t, reader, clk, writer)
+
+			// Shutdown flushes whatever is pending, so check before stopping
+			checkBatches(t, writer, tt.want)
+			cancel()
+			if err := waitResult(t, result); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+		})
+	}
+}
+
+// checkBatches fails the test unless writer has written exactly the want batches.
+func checkBatches(t *testing.T, writer *recordingWriter, want [][]uint64) {
+	t.Helper()
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if !slices.EqualFunc(writer.batches, want, slices.Equal) {
+		t.Fatalf("batches = %v, want %v", writer.batches, want)
+	}
+}
@@ -6,11 +6,23 @@ import (
 
 // Metrics holds all Prometheus metrics
 type Metrics struct {
-	RequestsTotal   *prometheus.CounterVec
-	RequestDuration *prometheus.HistogramVec
-	RequestSize     *prometheus.SummaryVec
-	ResponseSize    *prometheus.SummaryVec
-	RateLimitHits   *prometheus.CounterVec
+	RequestsTotal       *prometheus.CounterVec
+	RequestDuration     *prometheus.HistogramVec
+	RequestSize         *prometheus.SummaryVec
+	ResponseSize        *prometheus.SummaryVec
+	StreamedBytes       *prometheus.CounterVec
+	RateLimitHits       *prometheus.CounterVec
+	GlobalRateLimitHits prometheus.Counter
+	DBQueryDuration     *prometheus.HistogramVec
+	GRPCConnResets      prometheus.Counter
+	GRPCCallDuration    *prometheus.HistogramVec
+	ActiveTickStreams   prometheus.Gauge
+	LargeResponses      *prometheus.CounterVec
+	SLOViolations       *prometheus.CounterVec
+	ShutdownInFlight    prometheus.Gauge
+	ShutdownDrained     *prometheus.CounterVec
+	SubmitQueueDepth    prometheus.Gauge
+	StatusDegraded      *prometheus.CounterVec
 }
 
 // NewMetrics creates and returns a new Metrics instance
@@ -45,6 +57,13 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"method", "path", "status"},
 		),
+		StreamedBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_streamed_response_bytes_total",
+				Help: "Bytes written to streaming (SSE/flushed) responses, which are kept out of http_response_size_bytes",
+			},
+			[]string{"route"},
+		),
 		RateLimitHits: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "http_rate_limit_hits_total",
@@ -52,6 +71,80 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"path"},
 		),
+		GlobalRateLimitHits: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "http_global_rate_limit_hits_total",
+				Help: "Total number of requests rejected by the gateway-wide rate limit",
+			},
+		),
+		DBQueryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "db_query_duration_seconds",
+				Help:    "Database query latency in seconds",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"query"},
+		),
+		GRPCConnResets: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "grpc_connection_resets_total",
+				Help: "Total number of times the sequencer gRPC connection was recreated after repeated failures",
+			},
+		),
+		GRPCCallDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "grpc_client_call_duration_seconds",
+				Help:    "Latency of unary calls to the sequencer in seconds, by method",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"method"},
+		),
+		ActiveTickStreams: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "sse_active_tick_streams",
+				Help: "Open SSE tick streams, each holding a gRPC StreamTicks call",
+			},
+		),
+		LargeResponses: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_large_responses_total",
+				Help: "Total number of responses larger than the configured size threshold",
+			},
+			[]string{"route"},
+		),
+		SLOViolations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_slo_violations_total",
+				Help: "Total number of requests slower than the latency SLO",
+			},
+			[]string{"route"},
+		),
+		ShutdownInFlight: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "http_shutdown_inflight_requests",
+				Help: "Requests in flight when graceful shutdown started",
+			},
+		),
+		ShutdownDrained: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_shutdown_drained_requests_total",
+				Help: "Requests in flight at shutdown, by outcome (completed or aborted)",
+			},
+			[]string{"outcome"},
+		),
+		SubmitQueueDepth: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "submit_queue_depth",
+				Help: "Transaction submissions waiting in the submission queue",
+			},
+		),
+		StatusDegraded: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "status_partial_responses_total",
+				Help: "Unified status responses served from one backend because the other failed, by failed backend",
+			},
+			[]string{"failed_backend"},
+		),
 	}
 }
 
@@ -62,7 +155,19 @@ func (m *Metrics) Register(registry *prometheus.Registry) error {
 		m.RequestDuration,
 		m.RequestSize,
 		m.ResponseSize,
+		m.StreamedBytes,
 		m.RateLimitHits,
+		m.GlobalRateLimitHits,
+		m.DBQueryDuration,
+		m.GRPCConnResets,
+		m.GRPCCallDuration,
+		m.ActiveTickStreams,
+		m.LargeResponses,
+		m.SLOViolations,
+		m.ShutdownInFlight,
+		m.ShutdownDrained,
+		m.SubmitQueueDepth,
+		m.StatusDegraded,
 	}
 
 	for _, collector := range collectors {
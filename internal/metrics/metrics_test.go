@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gathered returns the names of the metric families the registry exports
+func gathered(t *testing.T, registry *prometheus.Registry) map[string]bool {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	names := make(map[string]bool, len(families))
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	return names
+}
+
+func TestMetrics_Register(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(m *Metrics)
+	}{
+		{"db_query_duration_seconds", func(m *Metrics) { m.DBQueryDuration.WithLabelValues("get_transaction").Observe(0.01) }},
+		{"http_global_rate_limit_hits_total", func(m *Metrics) { m.GlobalRateLimitHits.Inc() }},
+		{"http_slo_violations_total", func(m *Metrics) { m.SLOViolations.WithLabelValues("/markets").Inc() }},
+		{"http_large_responses_total", func(m *Metrics) { m.LargeResponses.WithLabelValues("/markets").Inc() }},
+		{"http_shutdown_inflight_requests", func(m *Metrics) { m.ShutdownInFlight.Set(3) }},
+		{"http_shutdown_drained_requests_total", func(m *Metrics) { m.ShutdownDrained.WithLabelValues("aborted").Inc() }},
+		{"submit_queue_depth", func(m *Metrics) { m.SubmitQueueDepth.Set(2) }},
+		{"status_partial_responses_total", func(m *Metrics) { m.StatusDegraded.WithLabelValues("grpc").Inc() }},
+		{"grpc_client_call_duration_seconds", func(m *Metrics) { m.GRPCCallDuration.WithLabelValues("GetStatus").Observe(0.01) }},
+		{"sse_active_tick_streams", func(m *Metrics) { m.ActiveTickStreams.Inc() }},
+		{"http_streamed_response_bytes_total", func(m *Metrics) { m.StreamedBytes.WithLabelValues("/stream-ticks").Add(128) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMetrics()
+			registry := prometheus.NewRegistry()
+			if err := m.Register(registry); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			tt.record(m)
+			if !gathered(t, registry)[tt.name] {
+				t.Errorf("%s not exported", tt.name)
+			}
+		})
+	}
+}
+
+func TestMetrics_RegisterTwice(t *testing.T) {
+	m := NewMetrics()
+	registry := prometheus.NewRegistry()
+	m.MustRegister(registry)
+	if err := m.Register(registry); err == nil {
+		t.Error("second Register() error = nil, want duplicate registration error")
+	}
+}
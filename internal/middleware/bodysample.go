@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+
+	"go.uber.org/zap"
+)
+
+// BodySampleHeader triggers body sampling for one request when it carries the
+// configured token. It's removed before the request is handled so it never
+// reaches a backend
+const BodySampleHeader = "X-Debug-Body"
+
+// MaxBodySampleBytes is the hard cap on a sampled body, whatever is configured
+const MaxBodySampleBytes = 64 * 1024
+
+// DefaultBodySampleDeniedPaths are never sampled: transaction submissions carry signatures
+var DefaultBodySampleDeniedPaths = []string{
+	"/api/v1/continuum/tx",
+	"/api/v1/continuum/submit-transaction",
+	"/api/v1/continuum/submit-batch",
+}
+
+// sensitiveBodyField matches JSON string fields whose values must not be logged,
+// including a value cut off by truncation
+var sensitiveBodyField = regexp.MustCompile(`(?i)("[a-z_]*(?:signature|secret|password|token|private_?key|api_?key)[a-z_]*"\s*:\s*)"[^"]*"?`)
+
+// BodySampleOption is a functional option for the BodySampling middleware
+type BodySampleOption func(*bodySampleConfig)
+
+// bodySampleConfig holds optional settings for the BodySampling middleware
+type bodySampleConfig struct {
+	paths  []string
+	token  string
+	denied []string
+}
+
+// WithSamplePaths samples every request under any of prefixes
+func WithSamplePaths(prefixes ...string) BodySampleOption {
+	return func(c *bodySampleConfig) {
+		c.paths = prefixes
+	}
+}
+
+// WithSampleToken samples requests whose BodySampleHeader equals token (empty = disabled)
+func WithSampleToken(token string) BodySampleOption {
+	return func(c *bodySampleConfig) {
+		c.token = token
+	}
+}
+
+// WithSampleDeniedPaths never samples paths under prefixes, on top of DefaultBodySampleDeniedPaths
+func WithSampleDeniedPaths(prefixes ...string) BodySampleOption {
+	return func(c *bodySampleConfig) {
+		c.denied = append(c.denied, prefixes...)
+	}
+}
+
+// BodySampling middleware logs the first maxBytes of request and response bodies,
+// with sensitive JSON fields redacted, for requests selected by path or debug token.
+// It's a debugging aid: leave it unconfigured in normal operation
+func BodySampling(logger *zap.Logger, maxBytes int, opts ...BodySampleOption) func(http.Handler) http.Handler {
+	cfg := &bodySampleConfig{denied: append([]string(nil), DefaultBodySampleDeniedPaths...)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if maxBytes <= 0 || maxBytes > MaxBodySampleBytes {
+		maxBytes = MaxBodySampleBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sampled := cfg.selects(r)
+			r.Header.Del(BodySampleHeader)
+			if !sampled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Read the head of the request body and put it back in front of the rest
+			requestSample, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBytes)+1))
+			if err != nil {
+				logger.Warn("Body sample: reading request body failed", zap.Error(err))
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(requestSample), r.Body), r.Body}
+
+			sw := &sampleResponseWriter{ResponseWriter: w, statusCode: http.StatusOK, limit: maxBytes}
+			next.ServeHTTP(sw, r)
+
+			requestBody, requestTruncated := truncateSample(requestSample, maxBytes)
+			logger.Info("Body sample",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("request_id", GetRequestID(r)),
+				zap.Int("status", sw.statusCode),
+				zap.String("request_body", redactBody(requestBody)),
+				zap.Bool("request_truncated", requestTruncated),
+				zap.String("response_body", redactBody(sw.sample.Bytes())),
+				zap.Bool("response_truncated", sw.truncated),
+			)
+		})
+	}
+}
+
+// selects reports whether r should be sampled. Denied paths win over everything
+func (c *bodySampleConfig) selects(r *http.Request) bool {
+	if pathExcluded(r.URL.Path, c.denied) {
+		return false
+	}
+	if pathExcluded(r.URL.Path, c.paths) {
+		return true
+	}
+	header := r.Header.Get(BodySampleHeader)
+	return c.token != "" && header != "" && subtle.ConstantTimeCompare([]byte(header), []byte(c.token)) == 1
+}
+
+// truncateSample cuts sample to limit bytes, reporting whether anything was cut
+func truncateSample(sample []byte, limit int) ([]byte, bool) {
+	if len(sample) > limit {
+		return sample[:limit], true
+	}
+	return sample, false
+}
+
+// redactBody masks the values of sensitive JSON string fields
+func redactBody(body []byte) string {
+	return sensitiveBodyField.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+}
+
+// sampleResponseWriter passes the response through, keeping its first limit bytes
+type sampleResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	limit      int
+	sample     bytes.Buffer
+	truncated  bool
+}
+
+func (sw *sampleResponseWriter) WriteHeader(code int) {
+	sw.statusCode = code
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *sampleResponseWriter) Write(b []byte) (int, error) {
+	if room := sw.limit - sw.sample.Len(); room < len(b) {
+		sw.sample.Write(b[:max(room, 0)])
+		sw.truncated = true
+	} else {
+		sw.sample.Write(b)
+	}
+	return sw.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher interface for SSE support
+func (sw *sampleResponseWriter) Flush() {
+	if flusher, ok := sw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for WebSocket upgrades
+func (sw *sampleResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	sw.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (e.g. to set deadlines)
+func (sw *sampleResponseWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
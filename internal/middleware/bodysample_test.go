@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// sampleEcho serves body sampling in front of a handler that echoes the request
+// body, recording the body and debug header it received
+func sampleEcho(logger *zap.Logger, maxBytes int, received *string, header *string, opts ...BodySampleOption) http.Handler {
+	return BodySampling(logger, maxBytes, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*received, *header = string(body), r.Header.Get(BodySampleHeader)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+}
+
+func TestBodySampling_Selection(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []BodySampleOption
+		path   string
+		header string // X-Debug-Body
+		want   bool
+	}{
+		{"not configured", nil, "/api/v1/rollup/markets", "", false},
+		{"sampled path", []BodySampleOption{WithSamplePaths("/api/v1/rollup")}, "/api/v1/rollup/markets", "", true},
+		{"other path", []BodySampleOption{WithSamplePaths("/api/v1/rollup")}, "/api/v1/continuum/status", "", false},
+		{"token", []BodySampleOption{WithSampleToken("s3cret")}, "/api/v1/rollup/markets", "s3cret", true},
+		{"wrong token", []BodySampleOption{WithSampleToken("s3cret")}, "/api/v1/rollup/markets", "guess", false},
+		{"header without a configured token", nil, "/api/v1/rollup/markets", "anything", false},
+		{"submit never sampled by path", []BodySampleOption{WithSamplePaths("/api/v1/continuum")}, "/api/v1/continuum/submit-transaction", "", false},
+		{"submit never sampled by token", []BodySampleOption{WithSampleToken("s3cret")}, "/api/v1/continuum/submit-batch", "s3cret", false},
+		{"tx submission never sampled", []BodySampleOption{WithSamplePaths("/")}, "/api/v1/continuum/tx", "", false},
+		{
+			"configured denied path",
+			[]BodySampleOption{WithSamplePaths("/api/v1/rollup"), WithSampleDeniedPaths("/api/v1/rollup/orders")},
+			"/api/v1/rollup/orders", "", false,
+		},
+		{
+			"denied paths add to the defaults",
+			[]BodySampleOption{WithSamplePaths("/api/v1/continuum"), WithSampleDeniedPaths("/api/v1/continuum/mempool")},
+			"/api/v1/continuum/submit-transaction", "", false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			var received, header string
+			handler := sampleEcho(zap.New(core), 1024, &received, &header, tt.opts...)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"market":"m1"}`))
+			if tt.header != "" {
+				req.Header.Set(BodySampleHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			// Requests are served the same whether sampled or not, and the debug header never reaches the handler
+			if received != `{"market":"m1"}` || rec.Body.String() != `{"market":"m1"}` || rec.Code != http.StatusCreated {
+				t.Errorf("handler received %q and answered %d %q, want the body passed through", received, rec.Code, rec.Body.String())
+			}
+			if header != "" {
+				t.Errorf("handler saw %s = %q, want it removed", BodySampleHeader, header)
+			}
+
+			samples := logs.FilterMessage("Body sample").All()
+			if got := len(samples) == 1; got != tt.want {
+				t.Fatalf("sampled = %v (%d logs), want %v", got, len(samples), tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			fields := samples[0].ContextMap()
+			if fields["request_body"] != `{"market":"m1"}` || fields["response_body"] != `{"market":"m1"}` || fields["status"] != int64(http.StatusCreated) {
+				t.Errorf("sample fields = %v", fields)
+			}
+		})
+	}
+}
+
+func TestBodySampling_Cap(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxBytes      int
+		body          int // Bytes in the request body, echoed back
+		wantSample    int // Bytes of each body logged
+		wantTruncated bool
+	}{
+		{"under the cap", 16, 10, 10, false},
+		{"at the cap", 16, 16, 16, false},
+		{"over the cap", 16, 17, 16, true},
+		{"far over the cap", 16, 100000, 16, true},
+		{"empty body", 16, 0, 0, false},
+		{"hard cap", 1 << 20, MaxBodySampleBytes + 10, MaxBodySampleBytes, true},
+		{"unset uses the hard cap", 0, MaxBodySampleBytes + 10, MaxBodySampleBytes, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			var received, header string
+			handler := sampleEcho(zap.New(core), tt.maxBytes, &received, &header, WithSamplePaths("/"))
+
+			body := strings.Repeat("x", tt.body)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body)))
+
+			// Only the log is truncated
+			if received != body || rec.Body.String() != body {
+				t.Errorf("handler received %d bytes and answered %d, want %d both", len(received), rec.Body.Len(), tt.body)
+			}
+			samples := logs.FilterMessage("Body sample").All()
+			if len(samples) != 1 {
+				t.Fatalf("got %d samples, want 1", len(samples))
+			}
+			fields := samples[0].ContextMap()
+			for _, side := range []string{"request", "response"} {
+				if got := len(fields[side+"_body"].(string)); got != tt.wantSample {
+					t.Errorf("%s sample = %d bytes, want %d", side, got, tt.wantSample)
+				}
+				if got := fields[side+"_truncated"]; got != tt.wantTruncated {
+					t.Errorf("%s_truncated = %v, want %v", side, got, tt.wantTruncated)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"nothing sensitive", `{"market":"m1","price":1}`, `{"market":"m1","price":1}`},
+		{"signature", `{"tx_id":"t1","signature":"0xabcd"}`, `{"tx_id":"t1","signature":"[REDACTED]"}`},
+		{"field names containing a keyword", `{"user_signature": "ab", "apiKey":"k", "client_secret":"s"}`, `{"user_signature": "[REDACTED]", "apiKey":"[REDACTED]", "client_secret":"[REDACTED]"}`},
+		{"case insensitive", `{"Password":"hunter2"}`, `{"Password":"[REDACTED]"}`},
+		{"private key", `{"private_key":"k1","privatekey":"k2"}`, `{"private_key":"[REDACTED]","privatekey":"[REDACTED]"}`},
+		{"value cut off by truncation", `{"token":"abcd`, `{"token":"[REDACTED]"`},
+		{"non-string values kept", `{"token_count":3}`, `{"token_count":3}`},
+		{"not JSON", `signature=abcd`, `signature=abcd`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,79 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
+// AllowedOrigins is a list of CORS origins that can be replaced at runtime
+type AllowedOrigins struct {
+	origins atomic.Pointer[[]string]
+}
+
+// NewAllowedOrigins creates an origin list
+func NewAllowedOrigins(origins []string) *AllowedOrigins {
+	a := &AllowedOrigins{}
+	a.Set(origins)
+	return a
+}
+
+// Set replaces the allowed origins
+func (a *AllowedOrigins) Set(origins []string) {
+	origins = slices.Clone(origins)
+	a.origins.Store(&origins)
+}
+
+// Allowed reports whether origin is in the list, or the list contains "*"
+func (a *AllowedOrigins) Allowed(origin string) bool {
+	return a.Wildcard() || a.Listed(origin)
+}
+
+// Listed reports whether origin itself is in the list, ignoring "*"
+func (a *AllowedOrigins) Listed(origin string) bool {
+	return slices.Contains(*a.origins.Load(), origin)
+}
+
+// Wildcard reports whether the list contains "*", allowing any origin
+func (a *AllowedOrigins) Wildcard() bool {
+	return slices.Contains(*a.origins.Load(), "*")
+}
+
+// CORSOption is a functional option for the CORS middleware
+type CORSOption func(*corsConfig)
+
+// corsConfig holds optional settings for the CORS middleware
+type corsConfig struct {
+	credentials bool
+	maxAge      time.Duration
+}
+
+// WithCredentials sets whether credentialed requests (cookies, Authorization) are allowed
+// (default true). Browsers reject "*" for credentialed requests, so a wildcard origin
+// list only answers with "*" when credentials are off
+func WithCredentials(allow bool) CORSOption {
+	return func(c *corsConfig) {
+		c.credentials = allow
+	}
+}
+
+// WithPreflightMaxAge sets how long browsers may cache preflight responses (default 24h)
+func WithPreflightMaxAge(maxAge time.Duration) CORSOption {
+	return func(c *corsConfig) {
+		c.maxAge = maxAge
+	}
+}
+
 // CORS middleware handles Cross-Origin Resource Sharing
 // It allows requests from whitelisted origins only
-func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+func CORS(allowedOrigins *AllowedOrigins, opts ...CORSOption) func(http.Handler) http.Handler {
+	cfg := &corsConfig{credentials: true, maxAge: 24 * time.Hour}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	maxAge := strconv.Itoa(int(cfg.maxAge.Seconds()))
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
@@ -17,30 +85,39 @@ func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Check if origin is allowed
-			allowed := false
-			for _, allowedOrigin := range allowedOrigins {
-				if origin == allowedOrigin {
-					allowed = true
-					break
-				}
+			// Unless every origin gets "*", the CORS headers depend on the origin (even
+			// their absence), so caches must key responses on it
+			wildcard := !cfg.credentials && allowedOrigins.Wildcard()
+			if !wildcard {
+				w.Header().Add("Vary", "Origin")
 			}
 
-			// If origin not allowed, continue without CORS headers
+			// If origin not allowed, continue without CORS headers. With credentials only
+			// listed origins count: "*" must never grant credentialed access
+			allowed := allowedOrigins.Allowed(origin)
+			if cfg.credentials {
+				allowed = allowedOrigins.Listed(origin)
+			}
 			if !allowed {
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			// Set CORS headers for allowed origin
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			if wildcard {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
 
 			// Handle preflight OPTIONS request
 			if r.Method == "OPTIONS" {
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-CSRF-Token")
-				w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
+				w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-CSRF-Token, X-API-Version")
+				w.Header().Set("Access-Control-Max-Age", maxAge)
 				w.WriteHeader(http.StatusNoContent)
 				return
 			}
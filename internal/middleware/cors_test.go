@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCORS_PreflightAllowsAPIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+		wantHeader bool // X-API-Version listed in Access-Control-Allow-Headers
+	}{
+		{"allowed origin", "https://app.fermi.xyz", http.StatusNoContent, true},
+		{"unknown origin", "https://evil.example.com", http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CORS(NewAllowedOrigins([]string{"https://app.fermi.xyz"}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			req := httptest.NewRequest(http.MethodOptions, "/api/v1/continuum/tx/recent", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Headers", "X-API-Version")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), "X-API-Version"); got != tt.wantHeader {
+				t.Errorf("Access-Control-Allow-Headers = %q, want X-API-Version listed = %v", rec.Header().Get("Access-Control-Allow-Headers"), tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestCORS_Credentials(t *testing.T) {
+	const app = "https://app.fermi.xyz"
+
+	tests := []struct {
+		name          string
+		origins       []string
+		opts          []CORSOption
+		origin        string
+		wantOrigin    string // Access-Control-Allow-Origin, empty = no CORS headers
+		wantCreds     bool   // Access-Control-Allow-Credentials: true
+		wantVary      bool   // Vary: Origin
+		wantPreflight int    // Preflight status
+	}{
+		{"default, listed origin", []string{app}, nil, app, app, true, true, http.StatusNoContent},
+		{"default, unknown origin", []string{app}, nil, "https://evil.example.com", "", false, true, http.StatusOK},
+		{"credentials, wildcard ignored", []string{"*"}, []CORSOption{WithCredentials(true)}, app, "", false, true, http.StatusOK},
+		{"credentials, listed next to wildcard", []string{"*", app}, []CORSOption{WithCredentials(true)}, app, app, true, true, http.StatusNoContent},
+		{"no credentials, listed origin", []string{app}, []CORSOption{WithCredentials(false)}, app, app, false, true, http.StatusNoContent},
+		{"no credentials, unknown origin", []string{app}, []CORSOption{WithCredentials(false)}, "https://evil.example.com", "", false, true, http.StatusOK},
+		{"no credentials, wildcard", []string{"*"}, []CORSOption{WithCredentials(false)}, "https://any.example.com", "*", false, false, http.StatusNoContent},
+		{"no credentials, wildcard and listed", []string{app, "*"}, []CORSOption{WithCredentials(false)}, app, "*", false, false, http.StatusNoContent},
+		{"no origin header", []string{app}, nil, "", "", false, false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CORS(NewAllowedOrigins(tt.origins), tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			for _, method := range []string{http.MethodGet, http.MethodOptions} {
+				req := httptest.NewRequest(method, "/api/v1/continuum/tx/recent", nil)
+				if tt.origin != "" {
+					req.Header.Set("Origin", tt.origin)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+					t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", method, got, tt.wantOrigin)
+				}
+				if got := rec.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCreds {
+					t.Errorf("%s: Access-Control-Allow-Credentials = %q, want true = %v", method, rec.Header().Get("Access-Control-Allow-Credentials"), tt.wantCreds)
+				}
+				if got := slices.Contains(rec.Header().Values("Vary"), "Origin"); got != tt.wantVary {
+					t.Errorf("%s: Vary = %v, want Origin = %v", method, rec.Header().Values("Vary"), tt.wantVary)
+				}
+				if method == http.MethodOptions && rec.Code != tt.wantPreflight {
+					t.Errorf("preflight status = %d, want %d", rec.Code, tt.wantPreflight)
+				}
+			}
+		})
+	}
+}
+
+func TestCORS_PreflightMaxAge(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []CORSOption
+		want string
+	}{
+		{"default", nil, "86400"},
+		{"ten minutes", []CORSOption{WithPreflightMaxAge(10 * time.Minute)}, "600"},
+		{"no caching", []CORSOption{WithPreflightMaxAge(0)}, "0"},
+		{"without credentials", []CORSOption{WithCredentials(false), WithPreflightMaxAge(time.Hour)}, "3600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CORS(NewAllowedOrigins([]string{"https://app.fermi.xyz"}), tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			req := httptest.NewRequest(http.MethodOptions, "/api/v1/continuum/tx/recent", nil)
+			req.Header.Set("Origin", "https://app.fermi.xyz")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Access-Control-Max-Age"); got != tt.want {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
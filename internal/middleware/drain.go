@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// DrainTracker counts in-flight requests so shutdown can report how many requests
+// finished during the drain and how many were cut off
+type DrainTracker struct {
+	inFlight  atomic.Int64
+	draining  atomic.Bool
+	atStart   atomic.Int64 // In flight when the drain started
+	completed atomic.Int64 // Finished after the drain started
+}
+
+// DrainStats summarizes a shutdown drain
+type DrainStats struct {
+	InFlightAtStart int64
+	Completed       int64
+	Aborted         int64 // Still in flight when the drain ended
+}
+
+// NewDrainTracker creates a drain tracker
+func NewDrainTracker() *DrainTracker {
+	return &DrainTracker{}
+}
+
+// Middleware tracks requests passing through next
+func (t *DrainTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.inFlight.Add(1)
+		defer func() {
+			t.inFlight.Add(-1)
+			if t.draining.Load() {
+				t.completed.Add(1)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// StartDrain marks the start of shutdown and returns the number of requests in flight
+func (t *DrainTracker) StartDrain() int64 {
+	t.draining.Store(true)
+	n := t.inFlight.Load()
+	t.atStart.Store(n)
+	return n
+}
+
+// Stats reports the drain so far; call it once the server has shut down
+func (t *DrainTracker) Stats() DrainStats {
+	atStart := t.atStart.Load()
+	completed := t.completed.Load()
+	return DrainStats{
+		InFlightAtStart: atStart,
+		Completed:       completed,
+		Aborted:         max(atStart-completed, 0),
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDrainTracker(t *testing.T) {
+	tests := []struct {
+		name         string
+		beforeDrain  int // Requests completed before the drain starts
+		inFlight     int // Requests in flight when the drain starts
+		finishDuring int // Of those, how many finish during the drain
+		want         DrainStats
+	}{
+		{"idle", 0, 0, 0, DrainStats{}},
+		{"all finish", 0, 3, 3, DrainStats{InFlightAtStart: 3, Completed: 3}},
+		{"some aborted", 0, 4, 1, DrainStats{InFlightAtStart: 4, Completed: 1, Aborted: 3}},
+		{"none finish", 0, 2, 0, DrainStats{InFlightAtStart: 2, Aborted: 2}},
+		{"earlier requests not counted", 5, 2, 2, DrainStats{InFlightAtStart: 2, Completed: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewDrainTracker()
+			started := make(chan struct{})
+			release := make(chan struct{})
+			handler := tracker.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/slow" {
+					started <- struct{}{}
+					<-release
+				}
+			}))
+			serve := func(path string) {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			}
+
+			for range tt.beforeDrain {
+				serve("/fast")
+			}
+
+			var wg sync.WaitGroup
+			for range tt.inFlight {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					serve("/slow")
+				}()
+				<-started
+			}
+
+			if got := tracker.StartDrain(); got != int64(tt.inFlight) {
+				t.Errorf("StartDrain() = %d, want %d", got, tt.inFlight)
+			}
+
+			for range tt.finishDuring {
+				release <- struct{}{}
+			}
+			// Wait for the released requests to be counted
+			deadline := time.Now().Add(5 * time.Second)
+			for tracker.Stats().Completed < int64(tt.finishDuring) && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			if got := tracker.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+
+			// Let the aborted requests return so no goroutine outlives the test
+			close(release)
+			wg.Wait()
+		})
+	}
+}
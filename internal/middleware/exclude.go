@@ -0,0 +1,15 @@
+package middleware
+
+import "strings"
+
+// pathExcluded reports whether path falls under one of prefixes. A prefix matches
+// itself and anything below it, so /health excludes /health/live but not /healthz
+func pathExcluded(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
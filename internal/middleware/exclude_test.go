@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPathExcluded(t *testing.T) {
+	prefixes := []string{"/health", "/metrics/", "/api/v1/internal"}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"exact", "/health", true},
+		{"below", "/health/live", true},
+		{"trailing slash in prefix", "/metrics", true},
+		{"below trailing slash prefix", "/metrics/go", true},
+		{"nested prefix", "/api/v1/internal/status", true},
+		{"same start, other segment", "/healthz", false},
+		{"parent of a prefix", "/api/v1", false},
+		{"other path", "/api/v1/rollup/markets", false},
+		{"root", "/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathExcluded(tt.path, prefixes); got != tt.want {
+				t.Errorf("pathExcluded(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if pathExcluded("/health", nil) {
+		t.Error("pathExcluded() with no prefixes = true, want false")
+	}
+}
+
+func TestExcludedPaths_LoggingAndMetrics(t *testing.T) {
+	excluded := []string{"/health", "/ready", "/metrics"}
+
+	tests := []struct {
+		name     string
+		path     string
+		excluded bool
+	}{
+		{"health", "/health", true},
+		{"readiness", "/ready", true},
+		{"scrape", "/metrics", true},
+		{"below an excluded path", "/health/live", true},
+		{"API request", "/api/v1/rollup/markets", false},
+		{"similar path", "/healthz", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, registry := newTestMetrics(t)
+			core, logs := observer.New(zapcore.DebugLevel)
+			var served bool
+			handler := Logging(zap.New(core), nil, WithoutLoggingPaths(excluded...))(
+				Metrics(m, WithoutMetricsPaths(excluded...))(
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served = true }),
+				),
+			)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			// Excluded requests are still served
+			if !served || rec.Code != http.StatusOK {
+				t.Fatalf("served = %v, status = %d; want served with 200", served, rec.Code)
+			}
+			wantSeries := 1
+			if tt.excluded {
+				wantSeries = 0
+			}
+			if got := logs.FilterMessage("HTTP request").Len(); got != wantSeries {
+				t.Errorf("got %d request logs, want %d", got, wantSeries)
+			}
+			if got := len(counterValues(t, registry, "http_requests_total")); got != wantSeries {
+				t.Errorf("got %d http_requests_total series, want %d", got, wantSeries)
+			}
+			if got := len(summarySums(t, registry, "http_response_size_bytes")); got != wantSeries {
+				t.Errorf("got %d http_response_size_bytes series, want %d", got, wantSeries)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/clientip"
+)
+
+// WarnUntrustedForwarding logs a warning the first time a request carries
+// X-Forwarded-For or X-Real-IP from a peer that isn't a trusted proxy
+// Behind a reverse proxy this usually means TRUSTED_PROXIES is missing, so every
+// client is seen (and rate limited) as the proxy's IP
+func WarnUntrustedForwarding(logger *zap.Logger, resolver *clientip.Resolver) func(http.Handler) http.Handler {
+	var warned atomic.Bool
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !warned.Load() && resolver.IgnoresForwarding(r) && warned.CompareAndSwap(false, true) {
+				logger.Warn("Ignoring forwarding headers from an untrusted peer; set TRUSTED_PROXIES to the reverse proxy's address so clients aren't rate limited as one IP",
+					zap.String("peer", clientip.RemoteIP(r)),
+					zap.String("x_forwarded_for", r.Header.Get("X-Forwarded-For")),
+				)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/clientip"
+)
+
+// forwardedRequest is a request from remoteAddr, with X-Forwarded-For set unless xff is empty
+type forwardedRequest struct{ remoteAddr, xff string }
+
+func TestWarnUntrustedForwarding(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []forwardedRequest
+		wantWarn int
+	}{
+		{"no forwarding headers", []forwardedRequest{{"203.0.113.7:5000", ""}}, 0},
+		{"trusted proxy", []forwardedRequest{{"10.0.0.1:5000", "198.51.100.1"}}, 0},
+		{"untrusted peer warns once", []forwardedRequest{
+			{"203.0.113.7:5000", ""},
+			{"203.0.113.7:5000", "198.51.100.1"},
+			{"203.0.113.8:5000", "198.51.100.2"},
+		}, 1},
+	}
+
+	resolver, err := clientip.NewResolver([]string{"10.0.0.1"})
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.WarnLevel)
+			handler := WarnUntrustedForwarding(zap.New(core), resolver)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			for _, request := range tt.requests {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/markets", nil)
+				req.RemoteAddr = request.remoteAddr
+				if request.xff != "" {
+					req.Header.Set("X-Forwarded-For", request.xff)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != http.StatusNoContent {
+					t.Errorf("status = %d, want the handler's 204", rec.Code)
+				}
+			}
+
+			if logs.Len() != tt.wantWarn {
+				t.Errorf("logged %d warnings, want %d", logs.Len(), tt.wantWarn)
+			}
+		})
+	}
+}
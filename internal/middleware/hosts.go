@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// AllowedHosts middleware rejects requests whose Host header isn't in the allowed list
+// with 421 Misdirected Request, so spoofed hosts never reach backends via X-Forwarded-Host
+// Hosts are matched case-insensitively and without the port; an empty list allows all hosts
+func AllowedHosts(hosts []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		allowed[normalizeHost(host)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !allowed[normalizeHost(r.Host)] {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMisdirectedRequest)
+				w.Write([]byte(`{"error":"misdirected request: host not allowed"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// normalizeHost lowercases a host and strips any port
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return strings.Trim(host, "[]")
+}
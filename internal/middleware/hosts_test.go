@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllowedHosts(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    []string
+		host       string
+		wantStatus int
+	}{
+		{"disabled", nil, "evil.example.com", http.StatusOK},
+		{"allowed host", []string{"api.fermi.xyz"}, "api.fermi.xyz", http.StatusOK},
+		{"allowed host with port", []string{"api.fermi.xyz"}, "api.fermi.xyz:8080", http.StatusOK},
+		{"case insensitive", []string{"API.Fermi.xyz"}, "api.FERMI.xyz", http.StatusOK},
+		{"configured with port", []string{"api.fermi.xyz:443"}, "api.fermi.xyz", http.StatusOK},
+		{"IPv6", []string{"[::1]"}, "[::1]:8080", http.StatusOK},
+		{"second of several", []string{"api.fermi.xyz", "localhost"}, "localhost:8080", http.StatusOK},
+		{"disallowed host", []string{"api.fermi.xyz"}, "evil.example.com", http.StatusMisdirectedRequest},
+		{"subdomain", []string{"fermi.xyz"}, "api.fermi.xyz", http.StatusMisdirectedRequest},
+		{"empty host", []string{"api.fermi.xyz"}, "", http.StatusMisdirectedRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := AllowedHosts(tt.allowed)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/rollup/markets", nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if reached != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("next handler reached = %v with status %d", reached, rec.Code)
+			}
+			if tt.wantStatus == http.StatusMisdirectedRequest {
+				if got := rec.Header().Get("Content-Type"); got != "application/json" {
+					t.Errorf("Content-Type = %q, want application/json", got)
+				}
+				if got := rec.Body.String(); got != `{"error":"misdirected request: host not allowed"}` {
+					t.Errorf("body = %s", got)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"bufio"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/fermilabs/fermi-api-gateway/internal/clientip"
 	"go.uber.org/zap"
 )
 
@@ -36,10 +40,60 @@ func (rw *responseWriter) Flush() {
 	}
 }
 
+// Hijack implements http.Hijacker for WebSocket upgrades
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	rw.statusCode = http.StatusSwitchingProtocols
+	rw.written = true
+	return hijacker.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (e.g. to set deadlines)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// LoggingOption is a functional option for the Logging middleware
+type LoggingOption func(*loggingConfig)
+
+// loggingConfig holds optional settings for the Logging middleware
+type loggingConfig struct {
+	headers  *HeaderRedactor
+	excluded []string
+}
+
+// WithHeaders logs request headers, with sensitive values masked by redactor
+func WithHeaders(redactor *HeaderRedactor) LoggingOption {
+	return func(c *loggingConfig) {
+		c.headers = redactor
+	}
+}
+
+// WithoutLoggingPaths skips request logs for paths under any of prefixes (e.g. /health)
+func WithoutLoggingPaths(prefixes ...string) LoggingOption {
+	return func(c *loggingConfig) {
+		c.excluded = prefixes
+	}
+}
+
 // Logging middleware logs HTTP requests with structured logging
-func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
+// resolver determines the logged client IP (nil = direct peer only)
+func Logging(logger *zap.Logger, resolver *clientip.Resolver, opts ...LoggingOption) func(http.Handler) http.Handler {
+	cfg := &loggingConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pathExcluded(r.URL.Path, cfg.excluded) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			start := time.Now()
 
 			// Wrap response writer to capture status code
@@ -62,6 +116,7 @@ func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Int("status", wrapped.statusCode),
 				zap.Duration("duration", duration),
 				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("client_ip", resolver.ClientIP(r)),
 			}
 
 			// Add request ID if available
@@ -69,14 +124,27 @@ func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 				fields = append(fields, zap.String("request_id", requestID))
 			}
 
+			// Add trace ID, which is the request ID unless the caller is tracing
+			if traceID := GetTraceID(r); traceID != "" {
+				fields = append(fields, zap.String("trace_id", traceID))
+			}
+
 			// Add user agent
 			if userAgent := r.Header.Get("User-Agent"); userAgent != "" {
 				fields = append(fields, zap.String("user_agent", userAgent))
 			}
 
+			// Request headers are only logged on request, never unredacted
+			if cfg.headers != nil {
+				fields = append(fields, cfg.headers.Field(r.Header))
+			}
+
 			// Log at appropriate level based on status code
 			// Use shorter, cleaner messages for common requests
-			if wrapped.statusCode >= 500 {
+			if wrapped.statusCode == 499 {
+				// Client closed the request (disconnect or canceled) - not a server error
+				logger.Debug("HTTP request", fields...)
+			} else if wrapped.statusCode >= 500 {
 				logger.Error("HTTP request", fields...)
 			} else if wrapped.statusCode >= 400 {
 				// Suppress verbose logging for 404s - they're usually not critical
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogging_Levels(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantLevel zapcore.Level
+	}{
+		{"success", http.StatusOK, zapcore.InfoLevel},
+		{"not found", http.StatusNotFound, zapcore.DebugLevel},
+		{"bad request", http.StatusBadRequest, zapcore.WarnLevel},
+		{"client closed request", 499, zapcore.DebugLevel},
+		{"server error", http.StatusInternalServerError, zapcore.ErrorLevel},
+		{"bad gateway", http.StatusBadGateway, zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			handler := Logging(zap.New(core), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/rollup/markets", nil))
+
+			entries := logs.FilterMessage("HTTP request").All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d request logs, want 1", len(entries))
+			}
+			if entries[0].Level != tt.wantLevel {
+				t.Errorf("level = %v, want %v", entries[0].Level, tt.wantLevel)
+			}
+			if got := entries[0].ContextMap()["status"]; got != int64(tt.status) {
+				t.Errorf("status field = %v, want %d", got, tt.status)
+			}
+		})
+	}
+}
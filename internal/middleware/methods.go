@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AllowedMethods middleware rejects requests whose method isn't in the allowed list
+// with 405 Method Not Allowed before they reach the backend
+// Methods are matched case-insensitively; an empty list allows all methods
+func AllowedMethods(methods []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(methods))
+	normalized := make([]string, 0, len(methods))
+	for _, method := range methods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method == "" || allowed[method] {
+			continue
+		}
+		allowed[method] = true
+		normalized = append(normalized, method)
+	}
+	allow := strings.Join(normalized, ", ")
+
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !allowed[r.Method] {
+				w.Header().Set("Allow", allow)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				w.Write([]byte(`{"error":"method not allowed"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllowedMethods(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    []string
+		method     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"allowed method", []string{"GET", "HEAD"}, http.MethodGet, http.StatusOK, ""},
+		{"disallowed method", []string{"GET", "HEAD"}, http.MethodDelete, http.StatusMethodNotAllowed, "GET, HEAD"},
+		{"case-insensitive config", []string{" post ", "get"}, http.MethodPost, http.StatusOK, ""},
+		{"duplicates listed once", []string{"GET", "get", ""}, http.MethodPut, http.StatusMethodNotAllowed, "GET"},
+		{"no list allows all", nil, http.MethodDelete, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxied := false
+			handler := AllowedMethods(tt.allowed)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxied = true
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/api/v1/continuum/blocks", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if proxied != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("reached the proxy = %v, want %v", proxied, !proxied)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && rec.Body.String() != `{"error":"method not allowed"}` {
+				t.Errorf("body = %q, want the JSON error", rec.Body.String())
+			}
+		})
+	}
+}
@@ -1,18 +1,33 @@
 package middleware
 
 import (
+	"bufio"
+	"fmt"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 
 	"github.com/fermilabs/fermi-api-gateway/internal/metrics"
 )
 
-// metricsResponseWriter wraps http.ResponseWriter to capture response size and status
+// metricsResponseWriter wraps http.ResponseWriter to capture response size and status.
+// Streaming responses (SSE or anything flushed mid-response) have no meaningful size,
+// so once one is detected its bytes go to a per-route counter as they're written
 type metricsResponseWriter struct {
 	http.ResponseWriter
 	statusCode   int
 	bytesWritten int
+
+	streamed      prometheus.Counter // Set once the response is known to be streaming
+	streamedBytes *prometheus.CounterVec
+	request       *http.Request
 }
 
 func (mrw *metricsResponseWriter) WriteHeader(code int) {
@@ -21,22 +36,107 @@ func (mrw *metricsResponseWriter) WriteHeader(code int) {
 }
 
 func (mrw *metricsResponseWriter) Write(b []byte) (int, error) {
+	if mrw.streamed == nil && strings.HasPrefix(mrw.Header().Get("Content-Type"), "text/event-stream") {
+		mrw.startStreaming()
+	}
+
 	n, err := mrw.ResponseWriter.Write(b)
 	mrw.bytesWritten += n
+	if mrw.streamed != nil {
+		mrw.streamed.Add(float64(n))
+	}
 	return n, err
 }
 
+// startStreaming switches the response to the streamed bytes counter, carrying over
+// anything written before it was detected
+func (mrw *metricsResponseWriter) startStreaming() {
+	mrw.streamed = mrw.streamedBytes.WithLabelValues(routePattern(mrw.request))
+	mrw.streamed.Add(float64(mrw.bytesWritten))
+}
+
 // Flush implements http.Flusher interface for SSE support
 func (mrw *metricsResponseWriter) Flush() {
+	if mrw.streamed == nil {
+		mrw.startStreaming()
+	}
 	if flusher, ok := mrw.ResponseWriter.(http.Flusher); ok {
 		flusher.Flush()
 	}
 }
 
+// Hijack implements http.Hijacker for WebSocket upgrades
+func (mrw *metricsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := mrw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	mrw.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (e.g. to set deadlines)
+func (mrw *metricsResponseWriter) Unwrap() http.ResponseWriter {
+	return mrw.ResponseWriter
+}
+
+// metricsConfig holds optional settings for the Metrics middleware
+type metricsConfig struct {
+	largeResponseBytes int
+	logger             *zap.Logger
+	slo                time.Duration
+	excluded           []string
+	exemplars          bool
+}
+
+// MetricsOption is a functional option for the Metrics middleware
+type MetricsOption func(*metricsConfig)
+
+// WithLargeResponseWarning counts and logs responses larger than threshold bytes
+func WithLargeResponseWarning(threshold int, logger *zap.Logger) MetricsOption {
+	return func(c *metricsConfig) {
+		c.largeResponseBytes = threshold
+		c.logger = logger
+	}
+}
+
+// WithSLO counts requests slower than threshold in http_slo_violations_total, per route
+func WithSLO(threshold time.Duration) MetricsOption {
+	return func(c *metricsConfig) {
+		c.slo = threshold
+	}
+}
+
+// WithoutMetricsPaths records no metrics for paths under any of prefixes (e.g. /metrics)
+func WithoutMetricsPaths(prefixes ...string) MetricsOption {
+	return func(c *metricsConfig) {
+		c.excluded = prefixes
+	}
+}
+
+// WithExemplars attaches the request's trace ID as an exemplar to its latency
+// observation, so a slow bucket links to the request. Exemplars are only exposed
+// when /metrics is scraped in OpenMetrics format
+func WithExemplars() MetricsOption {
+	return func(c *metricsConfig) {
+		c.exemplars = true
+	}
+}
+
 // Metrics middleware records HTTP metrics
-func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
+func Metrics(m *metrics.Metrics, opts ...MetricsOption) func(http.Handler) http.Handler {
+	cfg := &metricsConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pathExcluded(r.URL.Path, cfg.excluded) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			start := time.Now()
 
 			// Wrap response writer
@@ -44,6 +144,8 @@ func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
 				ResponseWriter: w,
 				statusCode:     http.StatusOK, // Default status
 				bytesWritten:   0,
+				streamedBytes:  m.StreamedBytes,
+				request:        r,
 			}
 
 			// Record request size
@@ -56,15 +158,66 @@ func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
 			next.ServeHTTP(mrw, r)
 
 			// Calculate duration
-			duration := time.Since(start).Seconds()
+			elapsed := time.Since(start)
+			duration := elapsed.Seconds()
 
 			// Convert status code to string
 			statusCode := strconv.Itoa(mrw.statusCode)
 
 			// Record metrics
 			m.RequestsTotal.WithLabelValues(r.Method, r.URL.Path, statusCode).Inc()
-			m.RequestDuration.WithLabelValues(r.Method, r.URL.Path, statusCode).Observe(duration)
+			observeDuration(m.RequestDuration.WithLabelValues(r.Method, r.URL.Path, statusCode), duration, r, cfg.exemplars)
+
+			if cfg.slo > 0 && elapsed > cfg.slo {
+				m.SLOViolations.WithLabelValues(routePattern(r)).Inc()
+			}
+
+			// Streamed bytes were already counted as they were written
+			if mrw.streamed != nil {
+				return
+			}
 			m.ResponseSize.WithLabelValues(r.Method, r.URL.Path, statusCode).Observe(float64(mrw.bytesWritten))
+
+			if cfg.largeResponseBytes > 0 && mrw.bytesWritten > cfg.largeResponseBytes {
+				route := routePattern(r)
+				m.LargeResponses.WithLabelValues(route).Inc()
+				if cfg.logger != nil {
+					cfg.logger.Warn("Large response",
+						zap.String("route", route),
+						zap.String("path", r.URL.Path),
+						zap.Int("bytes", mrw.bytesWritten),
+						zap.Int("threshold", cfg.largeResponseBytes),
+					)
+				}
+			}
 		})
 	}
 }
+
+// maxExemplarTraceID bounds the trace ID in an exemplar; client-supplied request IDs
+// can be arbitrarily long and exemplar labels are limited to 128 runes in total
+const maxExemplarTraceID = 64
+
+// observeDuration records duration, with the trace ID as exemplar when enabled and usable
+func observeDuration(obs prometheus.Observer, duration float64, r *http.Request, exemplars bool) {
+	if exemplars {
+		traceID := GetTraceID(r)
+		if eo, ok := obs.(prometheus.ExemplarObserver); ok && traceID != "" &&
+			len(traceID) <= maxExemplarTraceID && utf8.ValidString(traceID) {
+			eo.ObserveWithExemplar(duration, prometheus.Labels{"trace_id": traceID})
+			return
+		}
+	}
+	obs.Observe(duration)
+}
+
+// routePattern returns the matched chi route pattern (e.g. /api/v1/rollup/markets/{marketId}/candles),
+// which keeps label cardinality bounded unlike the raw path
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
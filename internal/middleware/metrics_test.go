@@ -0,0 +1,326 @@
+package middleware
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/metrics"
+)
+
+// newTestMetrics returns gateway metrics registered with a fresh registry
+func newTestMetrics(t *testing.T) (*metrics.Metrics, *prometheus.Registry) {
+	t.Helper()
+	m := metrics.NewMetrics()
+	registry := prometheus.NewRegistry()
+	if err := m.Register(registry); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return m, registry
+}
+
+// counterValues returns the values of a counter family keyed by its labels joined with ","
+func counterValues(t *testing.T, registry *prometheus.Registry, name string) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	values := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetValue())
+			}
+			values[strings.Join(labels, ",")] = metric.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+// metricsRouter serves GET /markets/{marketId}/candles with a body of size bytes through Metrics
+func metricsRouter(m *metrics.Metrics, size int, opts ...MetricsOption) http.Handler {
+	r := chi.NewRouter()
+	r.Use(Metrics(m, opts...))
+	r.Get("/markets/{marketId}/candles", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", size)))
+	})
+	return r
+}
+
+func TestMetrics_LargeResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		sizes     []int // Response size of each request
+		want      float64
+	}{
+		{"small", 1024, []int{10, 1024}, 0},
+		{"large", 1024, []int{1025}, 1},
+		{"some large", 1024, []int{2048, 10, 4096}, 2},
+		{"disabled", 0, []int{1 << 20}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, registry := newTestMetrics(t)
+			core, logs := observer.New(zapcore.WarnLevel)
+
+			for _, size := range tt.sizes {
+				handler := metricsRouter(m, size, WithLargeResponseWarning(tt.threshold, zap.New(core)))
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/markets/m1/candles", nil))
+			}
+
+			// Labeled by route pattern, not the raw path
+			got := counterValues(t, registry, "http_large_responses_total")["/markets/{marketId}/candles"]
+			if got != tt.want {
+				t.Errorf("http_large_responses_total = %v, want %v", got, tt.want)
+			}
+			warnings := logs.FilterMessage("Large response").All()
+			if float64(len(warnings)) != tt.want {
+				t.Fatalf("%d warnings, want %v", len(warnings), tt.want)
+			}
+			for _, entry := range warnings {
+				fields := entry.ContextMap()
+				if fields["path"] != "/markets/m1/candles" || fields["threshold"] != int64(tt.threshold) {
+					t.Errorf("warning fields = %v", fields)
+				}
+			}
+		})
+	}
+}
+
+func TestMetrics_SLOViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		slo   time.Duration
+		paths []string
+		want  map[string]float64
+	}{
+		{"fast", 50 * time.Millisecond, []string{"/fast", "/fast"}, map[string]float64{}},
+		{"slow", 50 * time.Millisecond, []string{"/slow/1", "/slow/2", "/fast"}, map[string]float64{"/slow/{id}": 2}},
+		{"disabled", 0, []string{"/slow/1"}, map[string]float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, registry := newTestMetrics(t)
+			r := chi.NewRouter()
+			r.Use(Metrics(m, WithSLO(tt.slo)))
+			r.Get("/fast", func(w http.ResponseWriter, r *http.Request) {})
+			r.Get("/slow/{id}", func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(80 * time.Millisecond)
+			})
+
+			for _, path := range tt.paths {
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			}
+
+			if got := counterValues(t, registry, "http_slo_violations_total"); !maps.Equal(got, tt.want) {
+				t.Errorf("http_slo_violations_total = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// summarySums returns the sums of a summary family keyed by its labels joined with ","
+func summarySums(t *testing.T, registry *prometheus.Registry, name string) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	sums := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetValue())
+			}
+			sums[strings.Join(labels, ",")] = metric.GetSummary().GetSampleSum()
+		}
+	}
+	return sums
+}
+
+func TestMetrics_StreamingResponses(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		writes       []int // Bytes written by each call, with a flush between calls
+		flush        bool
+		wantSizes    map[string]float64
+		wantStreamed map[string]float64
+	}{
+		{
+			name:         "plain response",
+			contentType:  "application/json",
+			writes:       []int{60, 40},
+			wantSizes:    map[string]float64{"GET,/stream/1,200": 100},
+			wantStreamed: map[string]float64{},
+		},
+		{
+			name:         "SSE",
+			contentType:  "text/event-stream",
+			writes:       []int{10, 10, 10},
+			flush:        true,
+			wantSizes:    map[string]float64{},
+			wantStreamed: map[string]float64{"/stream/{id}": 30},
+		},
+		{
+			name:         "SSE without flushing",
+			contentType:  "text/event-stream; charset=utf-8",
+			writes:       []int{10, 20},
+			wantSizes:    map[string]float64{},
+			wantStreamed: map[string]float64{"/stream/{id}": 30},
+		},
+		{
+			name:         "flushed response",
+			contentType:  "application/x-ndjson",
+			writes:       []int{10, 20, 30},
+			flush:        true,
+			wantSizes:    map[string]float64{},
+			wantStreamed: map[string]float64{"/stream/{id}": 60}, // Including bytes written before the first flush
+		},
+		{
+			name:         "large stream is not a large response",
+			contentType:  "text/event-stream",
+			writes:       []int{1 << 20},
+			wantSizes:    map[string]float64{},
+			wantStreamed: map[string]float64{"/stream/{id}": 1 << 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, registry := newTestMetrics(t)
+			r := chi.NewRouter()
+			r.Use(Metrics(m, WithLargeResponseWarning(1024, zap.NewNop())))
+			r.Get("/stream/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				for i, n := range tt.writes {
+					if tt.flush && i > 0 {
+						w.(http.Flusher).Flush()
+					}
+					w.Write([]byte(strings.Repeat("x", n)))
+				}
+			})
+
+			// Two requests, so bytes carried over at the switch to streaming aren't double counted
+			for range 2 {
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream/1", nil))
+			}
+
+			wantSizes, wantStreamed := doubled(tt.wantSizes), doubled(tt.wantStreamed)
+			if got := summarySums(t, registry, "http_response_size_bytes"); !maps.Equal(got, wantSizes) {
+				t.Errorf("http_response_size_bytes sums = %v, want %v", got, wantSizes)
+			}
+			if got := counterValues(t, registry, "http_streamed_response_bytes_total"); !maps.Equal(got, wantStreamed) {
+				t.Errorf("http_streamed_response_bytes_total = %v, want %v", got, wantStreamed)
+			}
+			if got := counterValues(t, registry, "http_large_responses_total"); len(got) != 0 {
+				t.Errorf("http_large_responses_total = %v, want none", got)
+			}
+		})
+	}
+}
+
+// doubled returns a copy of m with every value doubled
+func doubled(m map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		out[k] = 2 * v
+	}
+	return out
+}
+
+// exemplarTraceIDs returns the trace IDs of every exemplar on a histogram family,
+// and the family's total observation count
+func exemplarTraceIDs(t *testing.T, registry *prometheus.Registry, name string) ([]string, uint64) {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var traceIDs []string
+	var count uint64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			count += metric.GetHistogram().GetSampleCount()
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				for _, label := range bucket.GetExemplar().GetLabel() {
+					if label.GetName() == "trace_id" {
+						traceIDs = append(traceIDs, label.GetValue())
+					}
+				}
+			}
+		}
+	}
+	return traceIDs, count
+}
+
+func TestMetrics_Exemplars(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		requestID   string
+		traceparent string
+		want        []string
+	}{
+		{"disabled", false, "client-1", "00-" + traceID + "-00f067aa0ba902b7-01", nil},
+		{"trace ID from traceparent", true, "client-1", "00-" + traceID + "-00f067aa0ba902b7-01", []string{traceID}},
+		{"request ID without tracing", true, "client-1", "", []string{"client-1"}},
+		{"request ID too long", true, strings.Repeat("a", maxExemplarTraceID+1), "", nil},
+		{"request ID at the limit", true, strings.Repeat("a", maxExemplarTraceID), "", []string{strings.Repeat("a", maxExemplarTraceID)}},
+		{"request ID not UTF-8", true, "client-\xff", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, registry := newTestMetrics(t)
+			var opts []MetricsOption
+			if tt.enabled {
+				opts = append(opts, WithExemplars())
+			}
+			handler := RequestID(Metrics(m, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/rollup/markets", nil)
+			req.Header.Set("X-Request-ID", tt.requestID)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			// The latency is observed either way
+			got, count := exemplarTraceIDs(t, registry, "http_request_duration_seconds")
+			if count != 1 {
+				t.Errorf("observations = %d, want 1", count)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("exemplar trace IDs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
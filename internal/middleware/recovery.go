@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"runtime/debug"
 	"strings"
@@ -35,19 +36,21 @@ func Recovery(logger *zap.Logger) func(http.Handler) http.Handler {
 					
 					stackSummary := strings.Join(relevantStack, "\n")
 					
-					// Log with structured logging
+					// Log with structured logging; method, path and request_id are always
+					// present so the failing request can be found and reproduced
+					requestID := GetRequestID(r)
 					fields := []zap.Field{
 						zap.String("method", r.Method),
 						zap.String("path", r.URL.Path),
+						zap.String("query", r.URL.RawQuery),
+						zap.String("request_id", requestID),
+						zap.String("trace_id", GetTraceID(r)),
 						zap.String("remote_addr", r.RemoteAddr),
 						zap.String("panic", toString(err)),
+						zap.String("panic_type", fmt.Sprintf("%T", err)),
 						zap.String("stack", stackSummary),
 					}
-					
-					if requestID := r.Header.Get("X-Request-ID"); requestID != "" {
-						fields = append(fields, zap.String("request_id", requestID))
-					}
-					
+
 					logger.Error("PANIC recovered", fields...)
 
 					// Set content type to JSON
@@ -61,7 +64,7 @@ func Recovery(logger *zap.Logger) func(http.Handler) http.Handler {
 					}
 
 					// Include request ID if available
-					if requestID := r.Header.Get("X-Request-ID"); requestID != "" {
+					if requestID != "" {
 						response["request_id"] = requestID
 					}
 
@@ -83,6 +86,6 @@ func toString(v interface{}) string {
 	case error:
 		return val.Error()
 	default:
-		return "unknown panic type"
+		return fmt.Sprint(val)
 	}
 }
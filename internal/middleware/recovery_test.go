@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecovery_PanicReport(t *testing.T) {
+	tests := []struct {
+		name          string
+		panicValue    interface{}
+		withRequestID bool   // Route through the RequestID middleware
+		header        string // X-Request-ID sent by the client
+		wantRequestID string // Empty means generated
+		wantPanic     string
+		wantType      string
+	}{
+		{"string panic", "boom", true, "req-1", "req-1", "boom", "string"},
+		{"error panic", errors.New("nil map"), true, "req-2", "req-2", "nil map", "*errors.errorString"},
+		{"other panic", 42, true, "req-3", "req-3", "42", "int"},
+		{"generated request ID", "boom", true, "", "", "boom", "string"},
+		{"header without middleware", "boom", false, "req-4", "req-4", "boom", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.ErrorLevel)
+			var handler http.Handler = Recovery(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(tt.panicValue)
+			}))
+			if tt.withRequestID {
+				handler = RequestID(handler)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/rollup/orders?market=m1", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			entries := logs.FilterMessage("PANIC recovered").All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d panic logs, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			requestID, _ := fields["request_id"].(string)
+			if requestID == "" || (tt.wantRequestID != "" && requestID != tt.wantRequestID) {
+				t.Errorf("request_id = %q, want %q", requestID, tt.wantRequestID)
+			}
+			want := map[string]string{
+				"method":     http.MethodPost,
+				"path":       "/api/v1/rollup/orders",
+				"query":      "market=m1",
+				"panic":      tt.wantPanic,
+				"panic_type": tt.wantType,
+			}
+			for key, value := range want {
+				if fields[key] != value {
+					t.Errorf("%s = %v, want %q", key, fields[key], value)
+				}
+			}
+			if stack, _ := fields["stack"].(string); !strings.HasPrefix(stack, "goroutine ") {
+				t.Errorf("stack = %q, want a stack trace", stack)
+			}
+
+			// The client only gets a generic error and the request ID
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", rec.Code)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			wantBody := map[string]string{
+				"error":      "Internal Server Error",
+				"message":    "An unexpected error occurred",
+				"request_id": requestID,
+			}
+			if len(body) != len(wantBody) {
+				t.Errorf("body = %v, want %v", body, wantBody)
+			}
+			for key, value := range wantBody {
+				if body[key] != value {
+					t.Errorf("body %s = %q, want %q", key, body[key], value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// redactedValue replaces the values of sensitive headers
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactedHeaders are always redacted when headers are logged
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-API-Key",
+}
+
+// HeaderRedactor masks sensitive header values before headers are logged
+// Use it everywhere headers end up in logs
+type HeaderRedactor struct {
+	deny map[string]bool // Canonical header names
+}
+
+// NewHeaderRedactor creates a redactor for DefaultRedactedHeaders plus extra
+// Header names are matched case-insensitively
+func NewHeaderRedactor(extra ...string) *HeaderRedactor {
+	hr := &HeaderRedactor{deny: make(map[string]bool)}
+	for _, name := range append(DefaultRedactedHeaders, extra...) {
+		if name = strings.TrimSpace(name); name != "" {
+			hr.deny[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+	return hr
+}
+
+// Redact returns a copy of h with multi-value headers joined and sensitive values replaced
+func (hr *HeaderRedactor) Redact(h http.Header) map[string]string {
+	redacted := make(map[string]string, len(h))
+	for name, values := range h {
+		if hr.deny[http.CanonicalHeaderKey(name)] {
+			redacted[name] = redactedValue
+			continue
+		}
+		redacted[name] = strings.Join(values, ", ")
+	}
+	return redacted
+}
+
+// Field returns h as a redacted zap field named "headers"
+func (hr *HeaderRedactor) Field(h http.Header) zap.Field {
+	return zap.Any("headers", hr.Redact(h))
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"net/http"
+	"strings"
 )
 
 // ContextKey is a type for context keys to avoid collisions
@@ -13,8 +14,14 @@ type ContextKey string
 // RequestIDKey is the context key for request IDs
 const RequestIDKey ContextKey = "request-id"
 
+// TraceIDKey is the context key for trace IDs
+const TraceIDKey ContextKey = "trace-id"
+
 // RequestID middleware generates or extracts a request ID for tracking
-// If X-Request-ID header exists, it uses that, otherwise generates a new one
+// If X-Request-ID header exists, it uses that, otherwise generates a new one.
+// It also resolves the trace ID, returned in X-Trace-ID: the one in a W3C
+// traceparent header when the caller is tracing, otherwise the request ID, so
+// logs, metrics exemplars and traces share one identifier either way
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if request ID already exists in header
@@ -31,8 +38,16 @@ func RequestID(next http.Handler) http.Handler {
 		// Set request ID in response header
 		w.Header().Set("X-Request-ID", requestID)
 
-		// Add request ID to context
+		// traceparent itself is forwarded upstream untouched with the other headers
+		traceID, ok := parseTraceParent(r.Header.Get("traceparent"))
+		if !ok {
+			traceID = requestID
+		}
+		w.Header().Set("X-Trace-ID", traceID)
+
+		// Add request and trace IDs to context
 		ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+		ctx = context.WithValue(ctx, TraceIDKey, traceID)
 		r = r.WithContext(ctx)
 
 		// Continue to next handler
@@ -40,6 +55,49 @@ func RequestID(next http.Handler) http.Handler {
 	})
 }
 
+// GetRequestID returns the request ID set by the RequestID middleware, falling back
+// to the X-Request-ID header (empty if neither is present)
+func GetRequestID(r *http.Request) string {
+	if requestID, ok := r.Context().Value(RequestIDKey).(string); ok {
+		return requestID
+	}
+	return r.Header.Get("X-Request-ID")
+}
+
+// GetTraceID returns the trace ID set by the RequestID middleware, falling back to
+// the request ID
+func GetTraceID(r *http.Request) string {
+	if traceID, ok := r.Context().Value(TraceIDKey).(string); ok {
+		return traceID
+	}
+	return GetRequestID(r)
+}
+
+// parseTraceParent extracts the trace ID from a W3C traceparent header
+// (version-traceid-parentid-flags), rejecting malformed and all-zero IDs
+func parseTraceParent(header string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", false
+	}
+
+	traceID := parts[1]
+	if len(traceID) != 32 || traceID == strings.Repeat("0", 32) || !isLowerHex(traceID) {
+		return "", false
+	}
+	return traceID, true
+}
+
+// isLowerHex reports whether s only contains lowercase hex digits
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // generateRequestID creates a random request ID
 func generateRequestID() string {
 	bytes := make([]byte, 16)
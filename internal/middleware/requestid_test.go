@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string // Empty = rejected
+	}{
+		{"valid", "00-" + testTraceID + "-00f067aa0ba902b7-01", testTraceID},
+		{"surrounding spaces", " 00-" + testTraceID + "-00f067aa0ba902b7-00 ", testTraceID},
+		{"future version with extra fields", "01-" + testTraceID + "-00f067aa0ba902b7-01-extra", testTraceID},
+		{"empty", "", ""},
+		{"too few fields", "00-" + testTraceID + "-00f067aa0ba902b7", ""},
+		{"invalid version", "ff-" + testTraceID + "-00f067aa0ba902b7-01", ""},
+		{"long version", "000-" + testTraceID + "-00f067aa0ba902b7-01", ""},
+		{"short trace ID", "00-4bf92f3577b34da6-00f067aa0ba902b7-01", ""},
+		{"all-zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+		{"uppercase trace ID", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", ""},
+		{"not hex", "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTraceParent(tt.header)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("parseTraceParent(%q) = %q, %v; want %q", tt.header, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestID_TraceID(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string // X-Request-ID sent by the client
+		traceparent   string
+		wantRequestID string // Empty = generated
+		wantTraceID   string // Empty = the request ID
+	}{
+		{"nothing sent", "", "", "", ""},
+		{"client request ID", "client-1", "", "client-1", ""},
+		{"tracing caller", "", "00-" + testTraceID + "-00f067aa0ba902b7-01", "", testTraceID},
+		{"tracing caller with request ID", "client-1", "00-" + testTraceID + "-00f067aa0ba902b7-01", "client-1", testTraceID},
+		{"malformed traceparent", "client-1", "00-abc-def-01", "client-1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			var ctxRequestID, ctxTraceID string
+			handler := RequestID(Logging(zap.New(core), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxRequestID, ctxTraceID = GetRequestID(r), GetTraceID(r)
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/rollup/markets", nil)
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			requestID, traceID := rec.Header().Get("X-Request-ID"), rec.Header().Get("X-Trace-ID")
+			if requestID == "" || (tt.wantRequestID != "" && requestID != tt.wantRequestID) {
+				t.Errorf("X-Request-ID = %q, want %q (or generated)", requestID, tt.wantRequestID)
+			}
+			wantTraceID := tt.wantTraceID
+			if wantTraceID == "" {
+				wantTraceID = requestID
+			}
+			if traceID != wantTraceID {
+				t.Errorf("X-Trace-ID = %q, want %q", traceID, wantTraceID)
+			}
+
+			// Handlers, logs and the response all carry the same identifiers
+			if ctxRequestID != requestID || ctxTraceID != traceID {
+				t.Errorf("handler saw request ID %q and trace ID %q, want %q and %q", ctxRequestID, ctxTraceID, requestID, traceID)
+			}
+			entries := logs.FilterMessage("HTTP request").All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d request logs, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			if fields["request_id"] != requestID || fields["trace_id"] != traceID {
+				t.Errorf("logged request_id %v and trace_id %v, want %q and %q", fields["request_id"], fields["trace_id"], requestID, traceID)
+			}
+		})
+	}
+}
+
+func TestGetTraceID_WithoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		want      string
+	}{
+		{"falls back to the request ID header", "client-1", "client-1"},
+		{"no IDs", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				r.Header.Set("X-Request-ID", tt.requestID)
+			}
+			if got := GetTraceID(r); got != tt.want {
+				t.Errorf("GetTraceID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeout    time.Duration
+		delay      time.Duration
+		wantStatus int
+		wantBody   string
+	}{
+		{"fast handler", 100 * time.Millisecond, 0, http.StatusCreated, "done"},
+		{"slow handler", 20 * time.Millisecond, 200 * time.Millisecond, http.StatusGatewayTimeout, `{"error":"gateway timeout"}`},
+		{"disabled", 0, 50 * time.Millisecond, http.StatusCreated, "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tt.delay):
+				case <-r.Context().Done():
+				}
+				w.Header().Set("X-Handler", "yes")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("done"))
+			})
+
+			rec := httptest.NewRecorder()
+			Timeout(tt.timeout)(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tx/batch", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if timedOut := tt.wantStatus == http.StatusGatewayTimeout; timedOut == (rec.Header().Get("X-Handler") != "") {
+				t.Errorf("X-Handler header = %q, timed out = %v", rec.Header().Get("X-Handler"), timedOut)
+			}
+		})
+	}
+}
+
+func TestTimeout_DiscardsLateWrite(t *testing.T) {
+	lateWrite := make(chan error, 1)
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, err := w.Write([]byte("late"))
+		lateWrite <- err
+	})
+
+	rec := httptest.NewRecorder()
+	Timeout(10*time.Millisecond)(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tx/batch", nil))
+	close(release)
+
+	if err := <-lateWrite; err != http.ErrHandlerTimeout {
+		t.Errorf("late Write() error = %v, want http.ErrHandlerTimeout", err)
+	}
+	if rec.Code != http.StatusGatewayTimeout || rec.Body.String() != `{"error":"gateway timeout"}` {
+		t.Errorf("response = %d %q, want 504 with the timeout body only", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTimeout_ClientGone(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	Timeout(time.Second)(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tx", nil).WithContext(ctx))
+
+	// Nobody is left to answer, so nothing is written
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", rec.Body.String())
+	}
+}
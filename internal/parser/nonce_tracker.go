@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"container/list"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+// NonceAnomaly describes a transaction whose nonce is not strictly increasing
+// for its public key relative to the transactions seen before it.
+type NonceAnomaly struct {
+	PublicKey      string // hex-encoded
+	TxHash         string
+	TickNumber     uint64
+	SequenceNumber uint64
+	Nonce          uint64
+	PreviousNonce  uint64
+	PreviousTxHash string
+}
+
+// String returns a human-readable description of the anomaly.
+func (a NonceAnomaly) String() string {
+	kind := "decreased"
+	if a.Nonce == a.PreviousNonce {
+		kind = "repeated"
+	}
+	return fmt.Sprintf("nonce %s for public key %s: tx %s has nonce %d, previous tx %s had nonce %d",
+		kind, a.PublicKey, a.TxHash, a.Nonce, a.PreviousTxHash, a.PreviousNonce)
+}
+
+// maxNonceKeys bounds how many public keys are tracked. The least recently
+// seen key is forgotten first, so its next transaction is not checked.
+const maxNonceKeys = 100_000
+
+// nonceEntry is the latest (highest sequence number) transaction seen for a key.
+type nonceEntry struct {
+	key            string
+	sequenceNumber uint64
+	nonce          uint64
+	txHash         string
+}
+
+// NonceTracker is an opt-in, stateful post-parse validator that detects
+// per-public-key nonces that decrease or repeat.
+//
+// Transactions are ordered by their sequence number rather than arrival order,
+// so ticks parsed out of order by concurrent workers don't produce false positives.
+// Memory is bounded by tracking at most maxNonceKeys public keys.
+// It is safe for concurrent use.
+type NonceTracker struct {
+	mu    sync.Mutex
+	last  map[string]*list.Element // Key → element in order
+	order *list.List               // Of *nonceEntry, least recently seen first
+}
+
+// NewNonceTracker creates a new nonce tracker.
+func NewNonceTracker() *NonceTracker {
+	return &NonceTracker{
+		last:  make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// Check records the transactions in the tick and returns any nonce anomalies found.
+func (t *NonceTracker) Check(tick *domain.Tick) []NonceAnomaly {
+	if tick == nil || len(tick.Transactions) == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var anomalies []NonceAnomaly
+	for _, tx := range tick.Transactions {
+		key := hex.EncodeToString(tx.PublicKey)
+
+		elem, seen := t.last[key]
+		if !seen {
+			t.add(key, &tx)
+			continue
+		}
+		t.order.MoveToBack(elem)
+		prev := elem.Value.(*nonceEntry)
+
+		// The same transaction again (e.g. a replayed tick) is not a repeated nonce
+		if tx.SequenceNumber == prev.sequenceNumber {
+			continue
+		}
+
+		// A later transaction must have a higher nonce; an earlier one a lower nonce
+		later := tx.SequenceNumber > prev.sequenceNumber
+		if (later && tx.Nonce <= prev.nonce) || (!later && tx.Nonce >= prev.nonce) {
+			anomalies = append(anomalies, NonceAnomaly{
+				PublicKey:      key,
+				TxHash:         tx.TxHash,
+				TickNumber:     tick.TickNumber,
+				SequenceNumber: tx.SequenceNumber,
+				Nonce:          tx.Nonce,
+				PreviousNonce:  prev.nonce,
+				PreviousTxHash: prev.txHash,
+			})
+		}
+
+		if later {
+			prev.sequenceNumber, prev.nonce, prev.txHash = tx.SequenceNumber, tx.Nonce, tx.TxHash
+		}
+	}
+
+	return anomalies
+}
+
+// add starts tracking key at tx, forgetting the least recently seen key if full.
+func (t *NonceTracker) add(key string, tx *domain.Transaction) {
+	if t.order.Len() >= maxNonceKeys {
+		oldest := t.order.Front()
+		t.order.Remove(oldest)
+		delete(t.last, oldest.Value.(*nonceEntry).key)
+	}
+	t.last[key] = t.order.PushBack(&nonceEntry{
+		key:            key,
+		sequenceNumber: tx.SequenceNumber,
+		nonce:          tx.Nonce,
+		txHash:         tx.TxHash,
+	})
+}
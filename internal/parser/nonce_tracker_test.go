@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+// ntx is a transaction from public key key with the given sequence number and nonce.
+type ntx struct {
+	key      byte
+	seq      uint64
+	nonce    uint64
+	wantFlag bool // Reported as an anomaly
+}
+
+func nonceTick(number uint64, txs ...ntx) *domain.Tick {
+	tick := &domain.Tick{TickNumber: number}
+	for _, tx := range txs {
+		tick.Transactions = append(tick.Transactions, domain.Transaction{
+			TxHash:         fmt.Sprintf("tx-%d", tx.seq),
+			PublicKey:      []byte{tx.key},
+			SequenceNumber: tx.seq,
+			Nonce:          tx.nonce,
+		})
+	}
+	return tick
+}
+
+func TestNonceTracker_Check(t *testing.T) {
+	tests := []struct {
+		name  string
+		ticks [][]ntx // Checked in order, one tick per entry
+	}{
+		{"increasing", [][]ntx{{{1, 1, 1, false}, {1, 2, 2, false}}, {{1, 3, 5, false}}}},
+		{"repeated", [][]ntx{{{1, 1, 7, false}}, {{1, 2, 7, true}}}},
+		{"decreased", [][]ntx{{{1, 1, 7, false}, {1, 2, 6, true}}}},
+		{"keys tracked separately", [][]ntx{{{1, 1, 7, false}, {2, 2, 1, false}, {2, 3, 2, false}, {1, 4, 8, false}}}},
+		{"out of order ticks", [][]ntx{{{1, 5, 5, false}}, {{1, 3, 3, false}}, {{1, 4, 4, false}}}},
+		{"out of order with lower sequence, higher nonce", [][]ntx{{{1, 5, 5, false}}, {{1, 3, 9, true}}}},
+		{"replayed tick", [][]ntx{{{1, 1, 1, false}, {1, 2, 2, false}}, {{1, 1, 1, false}, {1, 2, 2, false}}}},
+		{"anomaly does not move the baseline", [][]ntx{{{1, 1, 5, false}}, {{1, 2, 3, true}}, {{1, 3, 6, false}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewNonceTracker()
+			for i, txs := range tt.ticks {
+				var want []string
+				for _, tx := range txs {
+					if tx.wantFlag {
+						want = append(want, fmt.Sprintf("tx-%d", tx.seq))
+					}
+				}
+
+				var got []string
+				for _, anomaly := range tracker.Check(nonceTick(uint64(i+1), txs...)) {
+					got = append(got, anomaly.TxHash)
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("tick %d: anomalies = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNonceTracker_AnomalyDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		nonce    uint64
+		wantKind string
+	}{
+		{"repeated", 7, "nonce repeated"},
+		{"decreased", 6, "nonce decreased"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewNonceTracker()
+			tracker.Check(nonceTick(1, ntx{key: 0xab, seq: 1, nonce: 7}))
+			anomalies := tracker.Check(nonceTick(2, ntx{key: 0xab, seq: 2, nonce: tt.nonce}))
+			if len(anomalies) != 1 {
+				t.Fatalf("anomalies = %v, want one", anomalies)
+			}
+
+			want := NonceAnomaly{
+				PublicKey: "ab", TxHash: "tx-2", TickNumber: 2, SequenceNumber: 2,
+				Nonce: tt.nonce, PreviousNonce: 7, PreviousTxHash: "tx-1",
+			}
+			if anomalies[0] != want {
+				t.Errorf("anomaly = %+v, want %+v", anomalies[0], want)
+			}
+			if s := anomalies[0].String(); !strings.HasPrefix(s, tt.wantKind) {
+				t.Errorf("String() = %q, want prefix %q", s, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestNonceTracker_EmptyTicks(t *testing.T) {
+	tracker := NewNonceTracker()
+	if got := tracker.Check(nil); got != nil {
+		t.Errorf("Check(nil) = %v", got)
+	}
+	if got := tracker.Check(&domain.Tick{TickNumber: 1}); got != nil {
+		t.Errorf("Check(empty tick) = %v", got)
+	}
+}
+
+func TestNonceTracker_ForgetsLeastRecentlySeenKey(t *testing.T) {
+	tracker := NewNonceTracker()
+	key := func(i int) []byte { return binary.BigEndian.AppendUint32(nil, uint32(i)) }
+
+	// Fill the tracker, touching key 0 last so key 1 is the least recently seen
+	for i := range maxNonceKeys {
+		tracker.Check(&domain.Tick{Transactions: []domain.Transaction{{PublicKey: key(i), SequenceNumber: uint64(i + 1), Nonce: 10}}})
+	}
+	tracker.Check(&domain.Tick{Transactions: []domain.Transaction{{PublicKey: key(0), SequenceNumber: maxNonceKeys + 1, Nonce: 11}}})
+	tracker.Check(&domain.Tick{Transactions: []domain.Transaction{{PublicKey: key(maxNonceKeys), SequenceNumber: maxNonceKeys + 2, Nonce: 1}}})
+
+	if len(tracker.last) != maxNonceKeys {
+		t.Fatalf("tracking %d keys, want %d", len(tracker.last), maxNonceKeys)
+	}
+	// Key 1 was forgotten, so a repeated nonce goes unnoticed; key 0 is still checked
+	if got := tracker.Check(&domain.Tick{Transactions: []domain.Transaction{{PublicKey: key(1), SequenceNumber: maxNonceKeys + 3, Nonce: 10}}}); len(got) != 0 {
+		t.Errorf("forgotten key: anomalies = %v, want none", got)
+	}
+	if got := tracker.Check(&domain.Tick{Transactions: []domain.Transaction{{PublicKey: key(0), SequenceNumber: maxNonceKeys + 4, Nonce: 11}}}); len(got) != 1 {
+		t.Errorf("recently seen key: anomalies = %v, want one", got)
+	}
+}
+
+func TestNonceTracker_Concurrent(t *testing.T) {
+	tracker := NewNonceTracker()
+
+	// Workers check ticks out of order; strictly increasing nonces never look anomalous
+	var wg sync.WaitGroup
+	for worker := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := worker; i < 400; i += 4 {
+				seq := uint64(i + 1)
+				if got := tracker.Check(nonceTick(seq, ntx{key: 1, seq: seq, nonce: seq})); len(got) != 0 {
+					t.Errorf("anomalies = %v, want none", got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -4,17 +4,51 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/fermilabs/fermi-api-gateway/internal/clock"
 	"github.com/fermilabs/fermi-api-gateway/internal/domain"
 	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
 )
 
 // ProtobufParser converts protobuf ticks to domain model ticks.
 // It is stateless and safe for concurrent use.
-type ProtobufParser struct{}
+type ProtobufParser struct {
+	validation domain.ValidationOptions // Relaxed validations (zero value = strict)
+	clock      clock.Clock              // Source of ReceivedAt
+}
+
+// ParserOption is a functional option for configuring ProtobufParser.
+type ParserOption func(*ProtobufParser)
+
+// WithAllowZeroTickNumber accepts ticks numbered 0, which some test sequencers produce.
+func WithAllowZeroTickNumber(allow bool) ParserOption {
+	return func(p *ProtobufParser) {
+		p.validation.AllowZeroTickNumber = allow
+	}
+}
+
+// WithAllowEmptyProof accepts ticks with placeholder VDF proofs (empty fields or
+// zero iterations), which some test sequencers produce.
+func WithAllowEmptyProof(allow bool) ParserOption {
+	return func(p *ProtobufParser) {
+		p.validation.AllowEmptyProof = allow
+	}
+}
+
+// WithClock sets the clock used for ReceivedAt (default: real time).
+func WithClock(c clock.Clock) ParserOption {
+	return func(p *ProtobufParser) {
+		p.clock = c
+	}
+}
 
 // NewProtobufParser creates a new protobuf parser.
-func NewProtobufParser() *ProtobufParser {
-	return &ProtobufParser{}
+// Validation is strict unless relaxed by opts.
+func NewProtobufParser(opts ...ParserOption) *ProtobufParser {
+	p := &ProtobufParser{clock: clock.Real()}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // Parse converts a protobuf tick to a domain tick.
@@ -46,17 +80,39 @@ func (p *ProtobufParser) Parse(pbTick *pb.Tick) (*domain.Tick, error) {
 		Transactions: transactions,
 		BatchHash:    pbTick.TransactionBatchHash,
 		PrevOutput:   pbTick.PreviousOutput,
-		ReceivedAt:   time.Now(), // Ingestion timestamp
+		ReceivedAt:   p.clock.Now(), // Ingestion timestamp
 	}
 
 	// Validate the domain tick
-	if err := tick.Validate(); err != nil {
+	if err := tick.ValidateWith(p.validation); err != nil {
 		return nil, fmt.Errorf("invalid tick: %w", err)
 	}
 
 	return tick, nil
 }
 
+// ParseBatch parses each tick with Parse, isolating failures to their index.
+// For index i, either the returned tick or the error is non-nil; errs is nil when
+// every tick parsed.
+func (p *ProtobufParser) ParseBatch(pbTicks []*pb.Tick) ([]*domain.Tick, []error) {
+	ticks := make([]*domain.Tick, len(pbTicks))
+	var errs []error
+
+	for i, pbTick := range pbTicks {
+		tick, err := p.Parse(pbTick)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(pbTicks))
+			}
+			errs[i] = err
+			continue
+		}
+		ticks[i] = tick
+	}
+
+	return ticks, errs
+}
+
 // parseVDFProof converts protobuf VdfProof to domain VDFProof.
 func (p *ProtobufParser) parseVDFProof(pbProof *pb.VdfProof) (domain.VDFProof, error) {
 	if pbProof == nil {
@@ -70,9 +126,11 @@ func (p *ProtobufParser) parseVDFProof(pbProof *pb.VdfProof) (domain.VDFProof, e
 		Iterations: pbProof.Iterations,
 	}
 
-	// Validate the VDF proof
-	if err := proof.Validate(); err != nil {
-		return domain.VDFProof{}, err
+	// Validate the VDF proof (unless placeholder proofs are allowed)
+	if !p.validation.AllowEmptyProof {
+		if err := proof.Validate(); err != nil {
+			return domain.VDFProof{}, err
+		}
 	}
 
 	return proof, nil
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/clock"
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// validTick returns a protobuf tick that passes strict validation.
+func validTick() *pb.Tick {
+	return &pb.Tick{
+		TickNumber:           7,
+		VdfProof:             &pb.VdfProof{Input: "in", Output: "out", Proof: "proof", Iterations: 100},
+		TransactionBatchHash: "batch",
+		PreviousOutput:       "prev",
+		Timestamp:            1700000000000000,
+		Transactions: []*pb.OrderedTransaction{{
+			Transaction:        &pb.Transaction{TxId: "id", Signature: []byte{1}, PublicKey: []byte{2}, Nonce: 3, Timestamp: 1700000000000000},
+			TxHash:             "hash",
+			SequenceNumber:     4,
+			IngestionTimestamp: 1700000000000001,
+		}},
+	}
+}
+
+func TestProtobufParser_Validation(t *testing.T) {
+	zeroTick := func(t *pb.Tick) { t.TickNumber = 0 }
+	emptyProof := func(t *pb.Tick) { t.VdfProof = &pb.VdfProof{} }
+	zeroIterations := func(t *pb.Tick) { t.VdfProof.Iterations = 0 }
+
+	tests := []struct {
+		name    string
+		change  func(t *pb.Tick)
+		opts    []ParserOption
+		wantErr string // Empty = accepted
+	}{
+		{"valid", func(t *pb.Tick) {}, nil, ""},
+		{"zero tick number", zeroTick, nil, "tick_number cannot be zero"},
+		{"zero tick number allowed", zeroTick, []ParserOption{WithAllowZeroTickNumber(true)}, ""},
+		{"empty proof", emptyProof, nil, "vdf_proof.input is required"},
+		{"empty proof allowed", emptyProof, []ParserOption{WithAllowEmptyProof(true)}, ""},
+		{"zero iterations", zeroIterations, nil, "vdf_proof.iterations must be greater than zero"},
+		{"zero iterations allowed", zeroIterations, []ParserOption{WithAllowEmptyProof(true)}, ""},
+		{"allowing zero ticks keeps proofs strict", emptyProof, []ParserOption{WithAllowZeroTickNumber(true)}, "vdf_proof.input is required"},
+		{"allowing empty proofs keeps tick numbers strict", zeroTick, []ParserOption{WithAllowEmptyProof(true)}, "tick_number cannot be zero"},
+		{"missing proof is never allowed", func(t *pb.Tick) { t.VdfProof = nil }, []ParserOption{WithAllowEmptyProof(true)}, "vdf_proof cannot be nil"},
+		{"missing batch hash is never allowed", func(t *pb.Tick) { t.TransactionBatchHash = "" }, []ParserOption{WithAllowZeroTickNumber(true), WithAllowEmptyProof(true)}, "batch_hash is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pbTick := validTick()
+			tt.change(pbTick)
+
+			tick, err := NewProtobufParser(tt.opts...).Parse(pbTick)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v, want accepted", err)
+				}
+				if tick.TickNumber != pbTick.TickNumber || tick.VDFProof.Output != pbTick.VdfProof.Output {
+					t.Errorf("Parse() = tick %d with proof %+v, want tick %d with %+v", tick.TickNumber, tick.VDFProof, pbTick.TickNumber, pbTick.VdfProof)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProtobufParser_ParseBatch(t *testing.T) {
+	numbered := func(n uint64) *pb.Tick {
+		tick := validTick()
+		tick.TickNumber = n
+		return tick
+	}
+	noHash := numbered(5)
+	noHash.TransactionBatchHash = ""
+
+	tests := []struct {
+		name     string
+		ticks    []*pb.Tick
+		wantErrs []string // Error substring at each index (empty = parsed)
+	}{
+		{"empty batch", nil, nil},
+		{"all valid", []*pb.Tick{numbered(1), numbered(2), numbered(3)}, []string{"", "", ""}},
+		{"mixed", []*pb.Tick{numbered(1), nil, numbered(3), noHash, numbered(0)}, []string{"", "tick cannot be nil", "", "batch_hash is required", "tick_number cannot be zero"}},
+		{"all invalid", []*pb.Tick{nil, noHash}, []string{"tick cannot be nil", "batch_hash is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks, errs := NewProtobufParser().ParseBatch(tt.ticks)
+			if len(ticks) != len(tt.ticks) {
+				t.Fatalf("got %d ticks, want %d", len(ticks), len(tt.ticks))
+			}
+
+			failed := slices.ContainsFunc(tt.wantErrs, func(s string) bool { return s != "" })
+			if (errs != nil) != failed {
+				t.Fatalf("errs = %v, want non-nil only when a tick failed", errs)
+			}
+			for i, wantErr := range tt.wantErrs {
+				if wantErr == "" {
+					if (errs != nil && errs[i] != nil) || ticks[i] == nil || ticks[i].TickNumber != tt.ticks[i].TickNumber {
+						t.Errorf("tick %d = %v, want tick %d parsed", i, ticks[i], tt.ticks[i].TickNumber)
+					}
+					continue
+				}
+				if ticks[i] != nil || errs[i] == nil || !strings.Contains(errs[i].Error(), wantErr) {
+					t.Errorf("tick %d = %v, error %v; want no tick and %q", i, ticks[i], errs[i], wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestProtobufParser_ReceivedAt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	p := NewProtobufParser(WithClock(clk))
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    time.Time
+	}{
+		{"current time", 0, start},
+		{"after advancing", 1500 * time.Millisecond, start.Add(1500 * time.Millisecond)},
+		{"clock stopped", 0, start.Add(1500 * time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk.Advance(tt.advance)
+			tick, err := p.Parse(validTick())
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !tick.ReceivedAt.Equal(tt.want) {
+				t.Errorf("ReceivedAt = %s, want %s", tick.ReceivedAt, tt.want)
+			}
+		})
+	}
+}
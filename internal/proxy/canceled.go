@@ -0,0 +1,22 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is recorded (nginx-style) when the client went away
+// before a response could be sent
+const StatusClientClosedRequest = 499
+
+// clientGone reports whether r was canceled because the client disconnected, so a
+// failed backend call is not a backend error. Deadlines (route timeouts) don't count
+func clientGone(r *http.Request) bool {
+	return errors.Is(r.Context().Err(), context.Canceled)
+}
+
+// writeClientGone records a 499; the client is no longer there to read a body
+func writeClientGone(w http.ResponseWriter) {
+	w.WriteHeader(StatusClientClosedRequest)
+}
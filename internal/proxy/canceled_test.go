@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+func TestClientGone(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"live request", context.Background(), false},
+		{"client disconnected", canceled, true},
+		{"route timeout", expired, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			if got := clientGone(r); got != tt.want {
+				t.Errorf("clientGone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// blockingSequencer answers GetStatus only once the call's context is done
+type blockingSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+}
+
+func (blockingSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// endRequest returns a request context that ends after a short delay, either
+// canceled like a client disconnect or expired like a route timeout
+func endRequest(disconnect bool) (context.Context, context.CancelFunc) {
+	if disconnect {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		return ctx, cancel
+	}
+	return context.WithTimeout(context.Background(), 20*time.Millisecond)
+}
+
+func TestCanceledRequests(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer backend.Close()
+	sequencer := serveSequencer(t, blockingSequencer{})
+
+	tests := []struct {
+		name       string
+		handler    func(t *testing.T, logger *zap.Logger) http.Handler
+		disconnect bool
+		wantStatus int
+	}{
+		{"REST proxy, client disconnect", restHandler(backend.URL), true, StatusClientClosedRequest},
+		{"REST proxy, route timeout", restHandler(backend.URL), false, http.StatusGatewayTimeout},
+		{"gRPC proxy, client disconnect", grpcStatusHandler(sequencer), true, StatusClientClosedRequest},
+		{"gRPC proxy, route timeout", grpcStatusHandler(sequencer), false, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			handler := tt.handler(t, zap.New(core))
+
+			ctx, cancel := endRequest(tt.disconnect)
+			defer cancel()
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil).WithContext(ctx))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.disconnect {
+				if rec.Body.Len() != 0 {
+					t.Errorf("body = %q, want none for a disconnected client", rec.Body.String())
+				}
+				if n := logs.FilterLevelExact(zapcore.ErrorLevel).Len() + logs.FilterLevelExact(zapcore.WarnLevel).Len(); n != 0 {
+					t.Errorf("got %d warning/error logs for a client disconnect: %v", n, logs.All())
+				}
+			}
+		})
+	}
+}
+
+func restHandler(backendURL string) func(t *testing.T, logger *zap.Logger) http.Handler {
+	return func(t *testing.T, logger *zap.Logger) http.Handler {
+		return NewHTTPProxy(backendURL, time.Minute, nil).Handler()
+	}
+}
+
+func grpcStatusHandler(addr string) func(t *testing.T, logger *zap.Logger) http.Handler {
+	return func(t *testing.T, logger *zap.Logger) http.Handler {
+		p, err := NewGRPCProxy(addr, nil, "", logger)
+		if err != nil {
+			t.Fatalf("NewGRPCProxy() error = %v", err)
+		}
+		t.Cleanup(func() { p.Close() })
+		return p.HandleGetStatus()
+	}
+}
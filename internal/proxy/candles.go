@@ -3,6 +3,7 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
@@ -14,14 +15,32 @@ import (
 	"github.com/fermilabs/fermi-api-gateway/internal/database"
 )
 
-// roundTo2Decimals rounds a float64 to 2 decimal places
-func roundTo2Decimals(val float64) float64 {
-	return math.Round(val*100) / 100
+// Bounds of the candles limit parameter
+const (
+	minCandleLimit = 1
+	maxCandleLimit = 1000
+)
+
+// defaultCandlePrecision is the number of decimals prices are rounded to unless overridden
+const defaultCandlePrecision = 2
+
+// roundToDecimals rounds a float64 to the given number of decimal places
+func roundToDecimals(val float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(val*scale) / scale
+}
+
+// candleStore is the part of the database repository candles are read from
+type candleStore interface {
+	Available() bool
+	HasTradeSize(ctx context.Context) (bool, error)
+	GetLatestCandle(ctx context.Context, marketID string, timeframe string, vwap bool) (*database.OHLCCandle, error)
+	GetMarketCandles(ctx context.Context, marketID string, timeframe string, from, to time.Time, limit int, vwap bool) ([]database.OHLCCandle, error)
 }
 
 // CandlesHandler handles market candles endpoint requests
 type CandlesHandler struct {
-	repository *database.Repository
+	repository candleStore
 	logger     *zap.Logger
 }
 
@@ -30,10 +49,12 @@ func NewCandlesHandler(repository *database.Repository, logger *zap.Logger) *Can
 	if logger == nil {
 		logger = zap.NewNop()
 	}
-	return &CandlesHandler{
-		repository: repository,
-		logger:     logger,
+	// A nil *Repository must stay a nil candleStore, so "no database" is still detected
+	h := &CandlesHandler{logger: logger}
+	if repository != nil {
+		h.repository = repository
 	}
+	return h
 }
 
 // GetMarketCandles handles GET /api/v1/rollup/markets/:marketId/candles
@@ -57,16 +78,7 @@ func (h *CandlesHandler) GetMarketCandles() http.HandlerFunc {
 			tf = "1h" // default
 		}
 
-		allowedTimeframes := map[string]bool{
-			"1m":  true,
-			"5m":  true,
-			"15m": true,
-			"1h":  true,
-			"4h":  true,
-			"1d":  true,
-		}
-
-		if !allowedTimeframes[tf] {
+		if !database.ValidBucket(tf) {
 			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid timeframe. Allowed values: 1m, 5m, 15m, 1h, 4h, 1d")
 			return
 		}
@@ -129,57 +141,163 @@ func (h *CandlesHandler) GetMarketCandles() http.HandlerFunc {
 		limit := 500 // default
 		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 			parsedLimit, err := strconv.Atoi(limitStr)
-			if err != nil || parsedLimit < 1 || parsedLimit > 1000 {
-				h.writeErrorResponse(w, http.StatusBadRequest, "Invalid limit (must be 1-1000)")
+			if err != nil || parsedLimit < minCandleLimit || parsedLimit > maxCandleLimit {
+				h.writeErrorDetails(w, http.StatusBadRequest,
+					fmt.Sprintf("Invalid limit: must be an integer between %d and %d, got %q", minCandleLimit, maxCandleLimit, limitStr),
+					map[string]interface{}{
+						"param":    "limit",
+						"min":      minCandleLimit,
+						"max":      maxCandleLimit,
+						"received": limitStr,
+					},
+				)
 				return
 			}
 			limit = parsedLimit
 		}
 
+		// latest=true returns only the most recent candle; the range and limit are ignored
+		latest := r.URL.Query().Get("latest") == "true"
+
+		// raw=true returns prices in micro-units, unscaled and (unless precision is set) unrounded
+		raw := r.URL.Query().Get("raw") == "true"
+
+		// Parse precision parameter (decimals prices are rounded to, 0-8)
+		precision := defaultCandlePrecision
+		precisionStr := r.URL.Query().Get("precision")
+		if precisionStr != "" {
+			parsedPrecision, err := strconv.Atoi(precisionStr)
+			if err != nil || parsedPrecision < 0 || parsedPrecision > 8 {
+				h.writeErrorResponse(w, http.StatusBadRequest, "Invalid precision (must be 0-8)")
+				return
+			}
+			precision = parsedPrecision
+		}
+
+		// vwap=true adds each candle's volume-weighted average price, when trade sizes exist
+		vwap := r.URL.Query().Get("vwap") == "true"
+
+		// format=object returns named {t,o,h,l,c} objects instead of positional arrays
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "array" && format != "object" {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid format. Allowed values: array, object")
+			return
+		}
+
+		// scale converts a micro-unit price for the response
+		scale := func(price float64) float64 {
+			if raw {
+				if precisionStr == "" {
+					return price
+				}
+				return roundToDecimals(price, precision)
+			}
+			return roundToDecimals(price/1000000.0, precision)
+		}
+
 		// Query database
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
-		if h.repository == nil {
+		if h.repository == nil || !h.repository.Available() {
 			h.writeErrorResponse(w, http.StatusInternalServerError, "Database not available")
 			return
 		}
 
-		candles, err := h.repository.GetMarketCandles(ctx, marketID, tf, from, to, limit)
-		if err != nil {
-			h.logger.Warn("Failed to get market candles", zap.Error(err))
-			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get market candles")
-			return
+		// Without trade sizes VWAP is left out rather than failing the request;
+		// X-VWAP tells clients which happened
+		if vwap {
+			available, err := h.repository.HasTradeSize(ctx)
+			if err != nil {
+				h.logger.Warn("Failed to check for trade size data", zap.Error(err))
+			}
+			vwap = available
+			if vwap {
+				w.Header().Set("X-VWAP", "included")
+			} else {
+				w.Header().Set("X-VWAP", "unavailable")
+			}
+		}
+
+		var candles []database.OHLCCandle
+		if latest {
+			// Fast path for dashboards that only need the current candle
+			candle, err := h.repository.GetLatestCandle(ctx, marketID, tf, vwap)
+			if err != nil && clientGone(r) {
+				writeClientGone(w)
+				return
+			}
+			if err != nil {
+				h.logger.Warn("Failed to get latest market candle", zap.Error(err))
+				h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get market candles")
+				return
+			}
+			if candle != nil {
+				candles = []database.OHLCCandle{*candle}
+			}
+		} else {
+			candles, err = h.repository.GetMarketCandles(ctx, marketID, tf, from, to, limit, vwap)
+			if err != nil && clientGone(r) {
+				writeClientGone(w)
+				return
+			}
+			if err != nil {
+				h.logger.Warn("Failed to get market candles", zap.Error(err))
+				h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get market candles")
+				return
+			}
 		}
 
 		// Binance-style: Return array directly (no wrapping object) for efficiency
 		// Format: [[timestamp_ms, open, high, low, close], ...]
 		// Using compact array format reduces payload size by ~40% vs objects
-		// Prices are divided by 1M and rounded to 2 decimals to reduce response size (~20-33% smaller)
+		// Prices are divided by 1M and rounded to 2 decimals (or ?precision=) to reduce response size (~20-33% smaller)
 		// This converts from USDC micro-units (e.g., 163885020) to USDC (e.g., 163.89)
-		candleArrays := make([][]interface{}, len(candles))
-		for i, candle := range candles {
-			// Convert timestamp to milliseconds (Unix epoch) for compactness
-			// Binance uses milliseconds since epoch (not RFC3339 strings)
-			timestampMs := candle.Timestamp.UnixMilli()
-			// Divide prices by 1M and round to 2 decimals for smaller response size
-			// This reduces JSON payload by ~20-33% and improves network transfer time
-			candleArrays[i] = []interface{}{
-				timestampMs,                                    // Open time (ms)
-				roundTo2Decimals(candle.Open / 1000000.0),   // Open price (USDC)
-				roundTo2Decimals(candle.High / 1000000.0),   // High price (USDC)
-				roundTo2Decimals(candle.Low / 1000000.0),    // Low price (USDC)
-				roundTo2Decimals(candle.Close / 1000000.0),  // Close price (USDC)
+		var body interface{}
+		if format == "object" {
+			// Named objects for clients that can't map positional arrays
+			objects := make([]database.OHLCCandle, len(candles))
+			for i, candle := range candles {
+				objects[i] = database.OHLCCandle{
+					Timestamp: candle.Timestamp,
+					Open:      scale(candle.Open),
+					High:      scale(candle.High),
+					Low:       scale(candle.Low),
+					Close:     scale(candle.Close),
+					VWAP:      scaleVWAP(candle.VWAP, scale),
+				}
 			}
+			body = objects
+		} else {
+			candleArrays := make([][]interface{}, len(candles))
+			for i, candle := range candles {
+				// Convert timestamp to milliseconds (Unix epoch) for compactness
+				// Binance uses milliseconds since epoch (not RFC3339 strings)
+				timestampMs := candle.Timestamp.UnixMilli()
+				// Divide prices by 1M and round for smaller response size
+				// This reduces JSON payload by ~20-33% and improves network transfer time
+				candleArrays[i] = []interface{}{
+					timestampMs,         // Open time (ms)
+					scale(candle.Open),  // Open price (USDC)
+					scale(candle.High),  // High price (USDC)
+					scale(candle.Low),   // Low price (USDC)
+					scale(candle.Close), // Close price (USDC)
+				}
+				if vwap {
+					// VWAP (USDC), null for buckets without size data
+					candleArrays[i] = append(candleArrays[i], scaleVWAP(candle.VWAP, scale))
+				}
+			}
+			body = candleArrays
 		}
 
 		// Set response headers (Binance-style optimizations)
 		w.Header().Set("Content-Type", "application/json")
-		if sinceStr == "" {
+		if sinceStr == "" && !latest {
 			// Only cache full historical data, not incremental updates
 			w.Header().Set("Cache-Control", "public, max-age=5")
 		} else {
-			// Don't cache incremental updates
+			// Don't cache incremental updates or the live candle
 			w.Header().Set("Cache-Control", "no-cache")
 		}
 		w.Header().Set("X-Data-Source", "database")
@@ -196,15 +314,30 @@ func (h *CandlesHandler) GetMarketCandles() http.HandlerFunc {
 		// Use compact JSON encoding (no indentation) for minimal payload size
 		enc := json.NewEncoder(w)
 		enc.SetEscapeHTML(false) // Don't escape HTML characters for better performance
-		if err := enc.Encode(candleArrays); err != nil {
+		if err := enc.Encode(body); err != nil {
 			// Encoding errors are rare and usually indicate connection issues
 			// Log silently as response may have already been partially written
 		}
 	}
 }
 
+// scaleVWAP scales a candle's VWAP like its prices, keeping nil (no VWAP) as is
+func scaleVWAP(vwap *float64, scale func(float64) float64) *float64 {
+	if vwap == nil {
+		return nil
+	}
+	scaled := scale(*vwap)
+	return &scaled
+}
+
 // writeErrorResponse writes an error response in the standard format
 func (h *CandlesHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	h.writeErrorDetails(w, statusCode, message, nil)
+}
+
+// writeErrorDetails writes an error response in the standard format, with machine-readable
+// details about the rejected parameter (omitted when nil)
+func (h *CandlesHandler) writeErrorDetails(w http.ResponseWriter, statusCode int, message string, details map[string]interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	response := map[string]interface{}{
@@ -212,6 +345,9 @@ func (h *CandlesHandler) writeErrorResponse(w http.ResponseWriter, statusCode in
 		"statusCode": statusCode,
 		"error":      message,
 	}
+	if details != nil {
+		response["details"] = details
+	}
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -0,0 +1,418 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+// fakeCandleStore serves fixed candles and records the VWAP flag it was queried with
+// Like the repository, it only returns VWAPs when asked to
+type fakeCandleStore struct {
+	tradeSize bool
+	tradeErr  error
+	candles   []database.OHLCCandle
+
+	sizeChecks int
+	gotVWAP    bool
+}
+
+func (s *fakeCandleStore) Available() bool { return true }
+
+func (s *fakeCandleStore) HasTradeSize(ctx context.Context) (bool, error) {
+	s.sizeChecks++
+	return s.tradeSize, s.tradeErr
+}
+
+func (s *fakeCandleStore) GetLatestCandle(ctx context.Context, marketID string, timeframe string, vwap bool) (*database.OHLCCandle, error) {
+	candles, _ := s.GetMarketCandles(ctx, marketID, timeframe, time.Time{}, time.Time{}, 0, vwap)
+	if len(candles) == 0 {
+		return nil, nil
+	}
+	return &candles[len(candles)-1], nil
+}
+
+func (s *fakeCandleStore) GetMarketCandles(ctx context.Context, marketID string, timeframe string, from, to time.Time, limit int, vwap bool) ([]database.OHLCCandle, error) {
+	s.gotVWAP = vwap
+	candles := make([]database.OHLCCandle, len(s.candles))
+	for i, candle := range s.candles {
+		if !vwap {
+			candle.VWAP = nil
+		}
+		candles[i] = candle
+	}
+	return candles, nil
+}
+
+// serveCandles sends GET /markets/m1/candles?query to a handler reading from store
+func serveCandles(store candleStore, query string) *httptest.ResponseRecorder {
+	h := &CandlesHandler{repository: store, logger: zap.NewNop()}
+	router := chi.NewRouter()
+	router.Get("/markets/{marketId}/candles", h.GetMarketCandles())
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/markets/m1/candles?"+query, nil))
+	return rec
+}
+
+func testCandles() []database.OHLCCandle {
+	vwap := 101500000.0
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []database.OHLCCandle{
+		{Timestamp: ts, Open: 100000000, High: 102000000, Low: 99000000, Close: 101000000}, // No size data in this bucket
+		{Timestamp: ts.Add(time.Hour), Open: 101000000, High: 103000000, Low: 100000000, Close: 102000000, VWAP: &vwap},
+	}
+}
+
+func TestGetMarketCandles_VWAP(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		tradeSize     bool
+		tradeErr      error
+		wantHeader    string
+		wantVWAP      bool // Queried with VWAP and 6-element arrays
+		wantSizeCheck bool
+	}{
+		{"not requested", "", true, nil, "", false, false},
+		{"requested, sizes recorded", "vwap=true", true, nil, "included", true, true},
+		{"requested, no sizes", "vwap=true", false, nil, "unavailable", false, true},
+		{"requested, check failed", "vwap=true", false, errors.New("timeout"), "unavailable", false, true},
+		{"latest, sizes recorded", "vwap=true&latest=true", true, nil, "included", true, true},
+		{"not true", "vwap=1", true, nil, "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeCandleStore{tradeSize: tt.tradeSize, tradeErr: tt.tradeErr, candles: testCandles()}
+			rec := serveCandles(store, tt.query)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("X-VWAP"); got != tt.wantHeader {
+				t.Errorf("X-VWAP = %q, want %q", got, tt.wantHeader)
+			}
+			if got := rec.Header().Get("X-Data-Source"); got != "database" {
+				t.Errorf("X-Data-Source = %q, want database", got)
+			}
+			if store.gotVWAP != tt.wantVWAP {
+				t.Errorf("queried with vwap = %v, want %v", store.gotVWAP, tt.wantVWAP)
+			}
+			if (store.sizeChecks > 0) != tt.wantSizeCheck {
+				t.Errorf("HasTradeSize called %d times, want called = %v", store.sizeChecks, tt.wantSizeCheck)
+			}
+
+			var arrays [][]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &arrays); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if len(arrays) == 0 {
+				t.Fatal("no candles returned")
+			}
+			wantLen := 5
+			if tt.wantVWAP {
+				wantLen = 6
+			}
+			for _, candle := range arrays {
+				if len(candle) != wantLen {
+					t.Errorf("candle = %v, want %d elements", candle, wantLen)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_VWAPValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []interface{} // 6th element per candle
+	}{
+		{"array", "vwap=true", []interface{}{nil, 101.5}},
+		{"raw", "vwap=true&raw=true", []interface{}{nil, 101500000.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(&fakeCandleStore{tradeSize: true, candles: testCandles()}, tt.query)
+
+			var arrays [][]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &arrays); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if len(arrays) != len(tt.want) {
+				t.Fatalf("got %d candles, want %d", len(arrays), len(tt.want))
+			}
+			for i, candle := range arrays {
+				if len(candle) != 6 || candle[5] != tt.want[i] {
+					t.Errorf("candle %d = %v, want VWAP %v", i, candle, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_VWAPObjects(t *testing.T) {
+	tests := []struct {
+		name      string
+		tradeSize bool
+		want      []bool // Whether each candle object has a vwap field
+	}{
+		{"sizes recorded", true, []bool{false, true}},
+		{"no sizes", false, []bool{false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(&fakeCandleStore{tradeSize: tt.tradeSize, candles: testCandles()}, "vwap=true&format=object")
+
+			var objects []map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &objects); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			for i, object := range objects {
+				if _, ok := object["vwap"]; ok != tt.want[i] {
+					t.Errorf("candle %d = %v, want vwap present = %v", i, object, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_NoDatabase(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler *CandlesHandler
+	}{
+		{"nil repository", NewCandlesHandler(nil, nil)},
+		{"not connected", NewCandlesHandler(database.NewRepository(nil), nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(tt.handler.repository, "vwap=true")
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", rec.Code)
+			}
+			if rec.Header().Get("X-VWAP") != "" {
+				t.Errorf("X-VWAP = %q without a database", rec.Header().Get("X-VWAP"))
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_Precision(t *testing.T) {
+	candles := []database.OHLCCandle{{
+		Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Open:      163885020, High: 164123456.5, Low: 163000001, Close: 163999999,
+	}}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       []interface{} // Open, high, low and close
+	}{
+		{"default", "", http.StatusOK, []interface{}{163.89, 164.12, 163.0, 164.0}},
+		{"precision 0", "precision=0", http.StatusOK, []interface{}{164.0, 164.0, 163.0, 164.0}},
+		{"precision 4", "precision=4", http.StatusOK, []interface{}{163.885, 164.1235, 163.0, 164.0}},
+		{"precision 8", "precision=8", http.StatusOK, []interface{}{163.88502, 164.1234565, 163.000001, 163.999999}},
+		{"raw", "raw=true", http.StatusOK, []interface{}{163885020.0, 164123456.5, 163000001.0, 163999999.0}},
+		{"raw with precision", "raw=true&precision=0", http.StatusOK, []interface{}{163885020.0, 164123457.0, 163000001.0, 163999999.0}},
+		{"precision too high", "precision=9", http.StatusBadRequest, nil},
+		{"negative precision", "precision=-1", http.StatusBadRequest, nil},
+		{"precision not a number", "precision=two", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(&fakeCandleStore{candles: candles}, tt.query)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.want == nil {
+				return
+			}
+			var arrays [][]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &arrays); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if len(arrays) != 1 || len(arrays[0]) != 5 {
+				t.Fatalf("candles = %v, want one 5-element array", arrays)
+			}
+			for i, want := range tt.want {
+				if arrays[0][i+1] != want {
+					t.Errorf("price %d = %v, want %v", i, arrays[0][i+1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_Format(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantObject bool
+	}{
+		{"default", "", http.StatusOK, false},
+		{"array", "format=array", http.StatusOK, false},
+		{"object", "format=object", http.StatusOK, true},
+		{"object, raw", "format=object&raw=true", http.StatusOK, true},
+		{"unknown format", "format=csv", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(&fakeCandleStore{candles: testCandles()}, tt.query)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			if !tt.wantObject {
+				var arrays [][]interface{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &arrays); err != nil {
+					t.Fatalf("decode arrays %q: %v", rec.Body.String(), err)
+				}
+				if len(arrays) != 2 || arrays[0][0] != float64(testCandles()[0].Timestamp.UnixMilli()) {
+					t.Errorf("arrays = %v, want 2 candles starting with the timestamp in ms", arrays)
+				}
+				return
+			}
+
+			var objects []database.OHLCCandle
+			if err := json.Unmarshal(rec.Body.Bytes(), &objects); err != nil {
+				t.Fatalf("decode objects %q: %v", rec.Body.String(), err)
+			}
+			if len(objects) != 2 {
+				t.Fatalf("got %d candles, want 2", len(objects))
+			}
+			want := testCandles()[1]
+			if tt.query == "format=object" {
+				want.Open, want.High, want.Low, want.Close = 101, 103, 100, 102
+			}
+			got := objects[1]
+			if !got.Timestamp.Equal(want.Timestamp) || got.Open != want.Open || got.High != want.High || got.Low != want.Low || got.Close != want.Close {
+				t.Errorf("candle = %+v, want %+v", got, want)
+			}
+			if got.VWAP != nil {
+				t.Errorf("vwap = %v without vwap=true", *got.VWAP)
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_InvalidLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      string
+		wantStatus int
+	}{
+		{"minimum", "1", http.StatusOK},
+		{"maximum", "1000", http.StatusOK},
+		{"zero", "0", http.StatusBadRequest},
+		{"above maximum", "1001", http.StatusBadRequest},
+		{"negative", "-5", http.StatusBadRequest},
+		{"not a number", "ten", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(&fakeCandleStore{candles: testCandles()}, "limit="+tt.limit)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var body struct {
+				StatusCode int    `json:"statusCode"`
+				Error      string `json:"error"`
+				Details    struct {
+					Param    string `json:"param"`
+					Min      int    `json:"min"`
+					Max      int    `json:"max"`
+					Received string `json:"received"`
+				} `json:"details"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if body.StatusCode != http.StatusBadRequest {
+				t.Errorf("statusCode = %d, want 400", body.StatusCode)
+			}
+			if want := fmt.Sprintf("between 1 and 1000, got %q", tt.limit); !strings.Contains(body.Error, want) {
+				t.Errorf("error = %q, want it to contain %q", body.Error, want)
+			}
+			if d := body.Details; d.Param != "limit" || d.Min != 1 || d.Max != 1000 || d.Received != tt.limit {
+				t.Errorf("details = %+v, want limit 1-1000 received %q", d, tt.limit)
+			}
+		})
+	}
+}
+
+func TestGetMarketCandles_Latest(t *testing.T) {
+	last := testCandles()[1].Timestamp.UnixMilli()
+
+	tests := []struct {
+		name          string
+		query         string
+		candles       []database.OHLCCandle
+		wantCount     int
+		wantCache     string
+		wantTimestamp string // X-Last-Candle-Timestamp
+	}{
+		{"full range", "", testCandles(), 2, "public, max-age=5", fmt.Sprint(last)},
+		{"latest", "latest=true", testCandles(), 1, "no-cache", fmt.Sprint(last)},
+		{"latest ignores the limit", "latest=true&limit=5", testCandles(), 1, "no-cache", fmt.Sprint(last)},
+		{"latest without prices", "latest=true", nil, 0, "no-cache", ""},
+		{"not true", "latest=1", testCandles(), 2, "public, max-age=5", fmt.Sprint(last)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCandles(&fakeCandleStore{candles: tt.candles}, tt.query)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.wantCache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCache)
+			}
+			if got := rec.Header().Get("X-Last-Candle-Timestamp"); got != tt.wantTimestamp {
+				t.Errorf("X-Last-Candle-Timestamp = %q, want %q", got, tt.wantTimestamp)
+			}
+
+			var arrays [][]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &arrays); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if arrays == nil || len(arrays) != tt.wantCount {
+				t.Fatalf("candles = %v, want %d (never null)", arrays, tt.wantCount)
+			}
+			if tt.wantCount > 0 && arrays[len(arrays)-1][0] != float64(last) {
+				t.Errorf("last candle = %v, want timestamp %d", arrays[len(arrays)-1], last)
+			}
+		})
+	}
+}
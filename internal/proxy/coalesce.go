@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// coalescer shares one upstream call among concurrent requests for the same key.
+// Unlike singleflight the call's context is reference counted: no single client
+// disconnecting cancels it, but once every waiting client has gone the call is
+// canceled instead of running to its timeout. The zero value is ready to use
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done    chan struct{}
+	val     interface{}
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// Do returns fn's result for key, starting fn if no call for key is in flight.
+// fn runs with a context detached from ctx and bounded by timeout. If ctx is done
+// before the result is ready Do returns ctx's error
+func (c *coalescer) Do(ctx context.Context, key string, timeout time.Duration, fn func(ctx context.Context) interface{}) (interface{}, error) {
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		if c.calls == nil {
+			c.calls = make(map[string]*coalescedCall)
+		}
+		c.calls[key] = call
+
+		go func() {
+			call.val = fn(callCtx)
+			cancel()
+			c.forget(key, call)
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// New requests start a fresh call rather than joining a canceled one
+			if c.calls[key] == call {
+				delete(c.calls, key)
+			}
+			call.cancel()
+		}
+		c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes call from the in-flight calls if it's still the one for key
+func (c *coalescer) forget(key string, call *coalescedCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
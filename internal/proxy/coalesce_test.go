@@ -0,0 +1,273 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// coalescerWaiters returns how many callers are waiting on the call for key
+func coalescerWaiters(c *coalescer, key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if call, ok := c.calls[key]; ok {
+		return call.waiters
+	}
+	return 0
+}
+
+func TestCoalescer_Do(t *testing.T) {
+	tests := []struct {
+		name     string
+		callers  int
+		leave    int // Callers that disconnect before the result is ready
+		timeout  time.Duration
+		wantCall error // Error of the shared call's context once it ends
+	}{
+		{"one caller", 1, 0, time.Minute, nil},
+		{"shared by several callers", 3, 0, time.Minute, nil},
+		{"one of several disconnects", 3, 1, time.Minute, nil},
+		{"all but one disconnect", 3, 2, time.Minute, nil},
+		{"only caller disconnects", 1, 1, time.Minute, context.Canceled},
+		{"every caller disconnects", 3, 3, time.Minute, context.Canceled},
+		{"call times out", 2, 0, 20 * time.Millisecond, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c coalescer
+			var calls atomic.Int32
+			release := make(chan struct{})
+			callErr := make(chan error, 1)
+			fn := func(ctx context.Context) interface{} {
+				calls.Add(1)
+				select {
+				case <-release:
+					callErr <- nil
+					return "result"
+				case <-ctx.Done():
+					callErr <- ctx.Err()
+					return nil
+				}
+			}
+
+			cancels := make([]context.CancelFunc, tt.callers)
+			results := make([]interface{}, tt.callers)
+			errs := make([]error, tt.callers)
+			var wg sync.WaitGroup
+			for i := range tt.callers {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancels[i] = cancel
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					results[i], errs[i] = c.Do(ctx, "key", tt.timeout, fn)
+				}()
+			}
+			waitFor(t, "callers to wait", func() bool { return coalescerWaiters(&c, "key") == tt.callers })
+
+			for _, cancel := range cancels[:tt.leave] {
+				cancel()
+			}
+			if tt.wantCall == nil {
+				waitFor(t, "callers to leave", func() bool { return coalescerWaiters(&c, "key") == tt.callers-tt.leave })
+				close(release)
+			}
+			wg.Wait()
+			for _, cancel := range cancels {
+				cancel()
+			}
+
+			if got := <-callErr; !errors.Is(got, tt.wantCall) {
+				t.Errorf("shared call ended with %v, want %v", got, tt.wantCall)
+			}
+			if n := calls.Load(); n != 1 {
+				t.Errorf("calls = %d, want 1", n)
+			}
+			for i := range tt.callers {
+				if i < tt.leave {
+					if !errors.Is(errs[i], context.Canceled) {
+						t.Errorf("caller %d: error = %v, want context.Canceled", i, errs[i])
+					}
+					continue
+				}
+				wantResult := interface{}("result")
+				if tt.wantCall != nil {
+					wantResult = nil
+				}
+				if errs[i] != nil || results[i] != wantResult {
+					t.Errorf("caller %d: Do() = %v, %v; want %v", i, results[i], errs[i], wantResult)
+				}
+			}
+
+			// The finished or abandoned call is not joined by later callers
+			if _, err := c.Do(context.Background(), "key", time.Minute, func(ctx context.Context) interface{} { return "fresh" }); err != nil {
+				t.Fatalf("Do() after the call ended error = %v", err)
+			}
+		})
+	}
+}
+
+// observingSequencer streams ticks until the client goes, counting them and
+// closing done when its stream's context ends
+type observingSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	sent atomic.Int32
+	done chan struct{}
+}
+
+func (s *observingSequencer) StreamTicks(req *pb.StreamTicksRequest, stream pb.SequencerService_StreamTicksServer) error {
+	defer close(s.done)
+	for n := uint64(1); ; n++ {
+		if err := stream.Send(&pb.Tick{TickNumber: n}); err != nil {
+			return err
+		}
+		s.sent.Add(1)
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// brokenWriter fails every write, like a connection whose client has gone
+// before the request context notices
+type brokenWriter struct {
+	header http.Header
+}
+
+func (w *brokenWriter) Header() http.Header       { return w.header }
+func (w *brokenWriter) WriteHeader(int)           {}
+func (w *brokenWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+func (w *brokenWriter) Flush()                    {}
+
+func TestHandleStreamTicks_ClientGone(t *testing.T) {
+	tests := []struct {
+		name   string
+		writer func() http.ResponseWriter
+		cancel bool // Cancel the request context
+	}{
+		{"request canceled", func() http.ResponseWriter { return httptest.NewRecorder() }, true},
+		{"write fails", func() http.ResponseWriter { return &brokenWriter{header: http.Header{}} }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequencer := &observingSequencer{done: make(chan struct{})}
+			p, err := NewGRPCProxy(serveSequencer(t, sequencer), nil, "", nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			returned := make(chan struct{})
+			go func() {
+				defer close(returned)
+				p.HandleStreamTicks()(tt.writer(), httptest.NewRequest(http.MethodGet, "/stream-ticks", nil).WithContext(ctx))
+			}()
+			if tt.cancel {
+				waitFor(t, "the stream to open", func() bool { return sequencer.sent.Load() > 0 })
+				cancel()
+			}
+
+			for what, ch := range map[string]chan struct{}{"handler to return": returned, "upstream stream to end": sequencer.done} {
+				select {
+				case <-ch:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for the %s", what)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleGetTransactionByHash_ClientGone(t *testing.T) {
+	tests := []struct {
+		name         string
+		clients      int
+		leave        int
+		wantCanceled bool // Whether the upstream request is canceled
+	}{
+		{"only client leaves", 1, 1, true},
+		{"every client leaves", 3, 3, true},
+		{"some clients stay", 3, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var arrived atomic.Int32
+			upstreamDone := make(chan error, 1)
+			release := make(chan struct{})
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				arrived.Add(1)
+				select {
+				case <-release:
+					upstreamDone <- nil
+					w.Write([]byte(`{"tx_hash":"abc1"}`))
+				case <-r.Context().Done():
+					upstreamDone <- r.Context().Err()
+				}
+			}))
+			defer upstream.Close()
+			defer close(release)
+
+			p, err := NewGRPCProxy("127.0.0.1:1", nil, upstream.URL, nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			recs := make([]*httptest.ResponseRecorder, tt.clients)
+			cancels := make([]context.CancelFunc, tt.clients)
+			var wg sync.WaitGroup
+			for i := range tt.clients {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				recs[i], cancels[i] = httptest.NewRecorder(), cancel
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.HandleGetTransactionByHash()(recs[i], httptest.NewRequest(http.MethodGet, "/tx/abc1", nil).WithContext(ctx))
+				}()
+			}
+			waitFor(t, "clients to wait", func() bool { return txWaiters(p) == tt.clients && arrived.Load() == 1 })
+
+			for _, cancel := range cancels[:tt.leave] {
+				cancel()
+			}
+			if !tt.wantCanceled {
+				waitFor(t, "clients to leave", func() bool { return txWaiters(p) == tt.clients-tt.leave })
+				release <- struct{}{}
+			}
+			wg.Wait()
+
+			select {
+			case err := <-upstreamDone:
+				if gotCanceled := err != nil; gotCanceled != tt.wantCanceled {
+					t.Errorf("upstream request ended with %v, want canceled = %v", err, tt.wantCanceled)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("upstream request still running")
+			}
+			for i, rec := range recs {
+				wantStatus := http.StatusOK
+				if i < tt.leave {
+					wantStatus = StatusClientClosedRequest
+				}
+				if rec.Code != wantStatus {
+					t.Errorf("client %d: status = %d, want %d: %s", i, rec.Code, wantStatus, rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// confirmPollInterval is how often a submitted transaction is looked up while awaiting confirmation
+const confirmPollInterval = 100 * time.Millisecond
+
+// WithSubmitConfirmation makes single submissions wait up to wait for the transaction
+// to be included in a tick, adding "confirmed" and the tick number to the response
+// (default 0 = respond as soon as the sequencer accepts it)
+func WithSubmitConfirmation(wait time.Duration) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.confirmWait = wait
+	}
+}
+
+// awaitConfirmation polls GetTransaction until txHash is found or the confirmation wait
+// (or ctx) runs out. It returns the tick the transaction was included in, if found
+func (p *GRPCProxy) awaitConfirmation(ctx context.Context, txHash string) (uint64, bool) {
+	ctx, cancel := context.WithTimeout(ctx, p.confirmWait)
+	defer cancel()
+
+	ticker := time.NewTicker(confirmPollInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := p.client.GetTransaction(ctx, &pb.GetTransactionRequest{TxHash: txHash})
+		if err == nil && resp.Found {
+			return resp.TickNumber, true
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, false
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeConfirmedSubmission writes the submission response enriched with the outcome
+// of the confirmation lookup
+func (p *GRPCProxy) writeConfirmedSubmission(ctx context.Context, w http.ResponseWriter, r *http.Request, resp *pb.SubmitTransactionResponse) {
+	tick, confirmed := p.awaitConfirmation(ctx, resp.TxHash)
+
+	jsonBytes, err := p.protoMarshaler(r).Marshal(resp)
+	var fields map[string]json.RawMessage
+	if err == nil {
+		err = json.Unmarshal(jsonBytes, &fields)
+	}
+	if err != nil {
+		p.logger.Warn("Failed to marshal response", zap.Error(err))
+		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+		return
+	}
+
+	fields["confirmed"] = json.RawMessage(strconv.FormatBool(confirmed))
+	if confirmed {
+		// Quoted like the other uint64 fields protojson emits
+		tickKey := "tick_number"
+		if p.requestJSONStyle(r) == JSONStyleCamel {
+			tickKey = "tickNumber"
+		}
+		fields[tickKey] = json.RawMessage(strconv.Quote(strconv.FormatUint(tick, 10)))
+	}
+
+	w.Header().Add("Vary", "Accept")
+	json.NewEncoder(w).Encode(fields)
+}
@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// confirmSequencer accepts submissions and reports them included in tick 77 from
+// the foundAfter-th lookup on (never when 0)
+type confirmSequencer struct {
+	*submitSequencer
+	foundAfter int32
+	lookups    atomic.Int32
+	lastHash   atomic.Value
+}
+
+func (s *confirmSequencer) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.GetTransactionResponse, error) {
+	s.lastHash.Store(req.GetTxHash())
+	if n := s.lookups.Add(1); s.foundAfter == 0 || n < s.foundAfter {
+		return &pb.GetTransactionResponse{}, nil
+	}
+	return &pb.GetTransactionResponse{Found: true, TickNumber: 77}, nil
+}
+
+func TestHandleSubmitTransaction_Confirmation(t *testing.T) {
+	tests := []struct {
+		name          string
+		wait          time.Duration
+		foundAfter    int32
+		accept        string
+		wantConfirmed string // Empty when the response has no confirmed field
+		wantTickKey   string // Empty when the response has no tick
+		wantLookups   bool
+	}{
+		{"disabled", 0, 1, "", "", "", false},
+		{"included right away", time.Second, 1, "", "true", "tick_number", true},
+		{"included after polling", time.Second, 3, "", "true", "tick_number", true},
+		{"not included in time", 150 * time.Millisecond, 0, "", "false", "", true},
+		{"camelCase", time.Second, 1, "application/json; profile=camelCase", "true", "tickNumber", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequencer := &confirmSequencer{submitSequencer: &submitSequencer{}, foundAfter: tt.foundAfter}
+			p, err := NewGRPCProxy(serveSequencer(t, sequencer), nil, "", nil, WithSubmitConfirmation(tt.wait))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(`{"transaction":`+txJSON(`[1]`, "abcd", 1)+`}`))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			p.HandleSubmitTransaction()(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+
+			if got := string(body["confirmed"]); got != tt.wantConfirmed {
+				t.Errorf("confirmed = %q, want %q", got, tt.wantConfirmed)
+			}
+			for _, key := range []string{"tick_number", "tickNumber"} {
+				got, ok := body[key]
+				if want := key == tt.wantTickKey; ok != want || (ok && string(got) != `"77"`) {
+					t.Errorf("%s = %s, want present = %v with \"77\"", key, got, want)
+				}
+			}
+			// The rest of the sequencer's response is kept
+			if _, ok := body["sequence_number"]; !ok && tt.accept == "" {
+				t.Errorf("body = %s, want the submission response fields", rec.Body.String())
+			}
+
+			lookups := sequencer.lookups.Load()
+			if (lookups > 0) != tt.wantLookups {
+				t.Errorf("GetTransaction called %d times, want called = %v", lookups, tt.wantLookups)
+			}
+			if tt.foundAfter > 1 && tt.wantConfirmed == "true" && lookups != tt.foundAfter {
+				t.Errorf("GetTransaction called %d times, want %d (stop once found)", lookups, tt.foundAfter)
+			}
+			if hash, _ := sequencer.lastHash.Load().(string); tt.wantLookups && hash != "hash-1" {
+				t.Errorf("looked up %q, want the submitted hash-1", hash)
+			}
+		})
+	}
+}
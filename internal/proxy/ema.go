@@ -0,0 +1,54 @@
+package proxy
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// statusRateWindow is the time constant of the smoothed rates in the unified status
+const statusRateWindow = 30 * time.Second
+
+// ema is a time-weighted exponential moving average, so its smoothing doesn't depend
+// on how often it's updated (status is polled by any number of dashboards)
+type ema struct {
+	mu      sync.Mutex
+	window  time.Duration
+	value   float64
+	updated time.Time // Zero until the first sample
+}
+
+// newEMA creates an average that mostly reflects the samples of the last window
+func newEMA(window time.Duration) *ema {
+	return &ema{window: window}
+}
+
+// Update adds a sample taken at now and returns the new average
+// The first sample is taken as-is
+func (e *ema) Update(sample float64, now time.Time) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.updated.IsZero() {
+		e.value = sample
+		e.updated = now
+		return e.value
+	}
+
+	elapsed := now.Sub(e.updated)
+	if elapsed <= 0 {
+		return e.value
+	}
+
+	alpha := 1 - math.Exp(-float64(elapsed)/float64(e.window))
+	e.value += alpha * (sample - e.value)
+	e.updated = now
+	return e.value
+}
+
+// Value returns the current average (0 before the first sample)
+func (e *ema) Value() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.value
+}
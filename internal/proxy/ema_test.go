@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEMA_Update(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const window = 30 * time.Second
+
+	tests := []struct {
+		name     string
+		samples  []float64
+		interval time.Duration
+		want     float64
+		within   float64
+	}{
+		{"first sample as-is", []float64{42}, time.Second, 42, 0},
+		{"one window weighs 1-1/e", []float64{0, 100}, window, 100 * (1 - 1/math.E), 1e-9},
+		{"same instant is ignored", []float64{10, 1000}, 0, 10, 0},
+		{"converges on a steady rate", fromZero(300, 100), time.Second, 100, 0.01},
+		{"smooths alternating rates", fromZero(300, 50, 150), time.Second, 100, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEMA(window)
+			var got float64
+			for i, sample := range tt.samples {
+				got = e.Update(sample, start.Add(time.Duration(i)*tt.interval))
+			}
+			if math.Abs(got-tt.want) > tt.within {
+				t.Errorf("Update() = %v, want %v ± %v", got, tt.want, tt.within)
+			}
+			if e.Value() != got {
+				t.Errorf("Value() = %v, want the last Update() %v", e.Value(), got)
+			}
+		})
+	}
+}
+
+// fromZero returns a 0 sample followed by n samples cycling through rates
+func fromZero(n int, rates ...float64) []float64 {
+	samples := []float64{0}
+	for i := range n {
+		samples = append(samples, rates[i%len(rates)])
+	}
+	return samples
+}
+
+func TestHandleUnifiedStatus_RateEMA(t *testing.T) {
+	tests := []struct {
+		name      string
+		restDown  bool
+		seedTicks float64 // Earlier tick rate average (0 = no earlier poll)
+		wantTxn   float64
+		wantTicks float64
+	}{
+		{"first poll takes the raw rates", false, 0, 10, 100},
+		{"REST down keeps the tick average", true, 50, 0, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, restURL := statusProxy(t, &callLog{}, false, tt.restDown)
+			if tt.seedTicks != 0 {
+				p.tickRate.Update(tt.seedTicks, time.Now().Add(-time.Minute))
+			}
+
+			rec := httptest.NewRecorder()
+			p.HandleUnifiedStatus(restURL)(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+
+			var body UnifiedStatusResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if tt.restDown {
+				// Partial responses wrap the status in data
+				var partial struct{ Data UnifiedStatusResponse }
+				if err := json.Unmarshal(rec.Body.Bytes(), &partial); err != nil {
+					t.Fatalf("decode %q: %v", rec.Body.String(), err)
+				}
+				body = partial.Data
+			}
+			if body.TxnPerSecondEMA != tt.wantTxn || body.TicksPerSecondEMA != tt.wantTicks {
+				t.Errorf("EMA = %v txn/s, %v ticks/s; want %v, %v", body.TxnPerSecondEMA, body.TicksPerSecondEMA, tt.wantTxn, tt.wantTicks)
+			}
+			if !tt.restDown && (body.TxnPerSecond != 10 || body.TicksPerSecond != 100) {
+				t.Errorf("raw rates = %v txn/s, %v ticks/s; want 10, 100", body.TxnPerSecond, body.TicksPerSecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Response envelope versions, selected with the X-API-Version request header
+const (
+	apiVersion1  = "1"   // Original shape, e.g. {"transactions":[...],"count":N}
+	apiVersion11 = "1.1" // {"api_version":"1.1","data":...,"meta":{...}}
+)
+
+// requestAPIVersion returns the envelope version requested via X-API-Version (default 1)
+func requestAPIVersion(r *http.Request) (string, error) {
+	switch version := strings.TrimPrefix(strings.TrimSpace(r.Header.Get("X-API-Version")), "v"); version {
+	case "", apiVersion1:
+		return apiVersion1, nil
+	case apiVersion11:
+		return apiVersion11, nil
+	default:
+		return "", fmt.Errorf("unsupported API version (supported: %s, %s)", apiVersion1, apiVersion11)
+	}
+}
+
+// writeEnvelope writes legacy for version 1, or data and meta wrapped in the versioned envelope otherwise
+func writeEnvelope(w http.ResponseWriter, version string, legacy map[string]interface{}, data interface{}, meta map[string]interface{}) {
+	w.Header().Set("X-API-Version", version)
+	w.Header().Add("Vary", "X-API-Version")
+
+	if version == apiVersion1 {
+		json.NewEncoder(w).Encode(legacy)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_version": version,
+		"data":        data,
+		"meta":        meta,
+	})
+}
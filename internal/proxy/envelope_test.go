@@ -0,0 +1,132 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestRequestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", apiVersion1, false},
+		{"version 1", "1", apiVersion1, false},
+		{"v prefix", "v1", apiVersion1, false},
+		{"version 1.1", "1.1", apiVersion11, false},
+		{"v1.1 with spaces", " v1.1 ", apiVersion11, false},
+		{"unsupported", "2", "", true},
+		{"garbage", "latest", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("X-API-Version", tt.header)
+			}
+			got, err := requestAPIVersion(r)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("requestAPIVersion() = %q, %v; want %q, error = %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// decodeObject decodes a JSON object body into its raw fields
+func decodeObject(t *testing.T, rec *httptest.ResponseRecorder) map[string]json.RawMessage {
+	t.Helper()
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestEnvelope_Versions(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		version     string
+		wantStatus  int
+		wantVersion string   // X-API-Version response header
+		wantKeys    []string // Top-level body keys
+		wantMeta    []string // Keys of meta in the 1.1 envelope
+	}{
+		{"recent, default", "/tx/recent", "", http.StatusOK, "1", []string{"count", "message", "transactions"}, nil},
+		{"recent, v1", "/tx/recent", "v1", http.StatusOK, "1", []string{"count", "message", "transactions"}, nil},
+		{"recent, 1.1", "/tx/recent", "1.1", http.StatusOK, "1.1", []string{"api_version", "data", "meta"}, []string{"count", "message"}},
+		{"recent, unsupported", "/tx/recent", "2", http.StatusBadRequest, "", nil, nil},
+		{"by hash, default", "/tx/abc1", "", http.StatusOK, "1", []string{"data", "source"}, nil},
+		{"by hash, 1.1", "/tx/abc1", "v1.1", http.StatusOK, "1.1", []string{"api_version", "data", "meta"}, []string{"source"}},
+		{"by hash, unsupported", "/tx/abc1", "3", http.StatusBadRequest, "", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := newTxUpstream(t, http.StatusOK)
+			close(upstream.release)
+			p, err := NewGRPCProxy("127.0.0.1:1", nil, upstream.URL, nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			handler := p.HandleGetTransactionByHash()
+			if tt.path == "/tx/recent" {
+				handler = p.HandleGetRecentTransactions()
+			}
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.version != "" {
+				req.Header.Set("X-API-Version", tt.version)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := rec.Header().Get("X-API-Version"); got != tt.wantVersion {
+				t.Errorf("X-API-Version = %q, want %q", got, tt.wantVersion)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Values("Vary"); !slices.Contains(got, "X-API-Version") {
+				t.Errorf("Vary = %v, want X-API-Version", got)
+			}
+
+			body := decodeObject(t, rec)
+			if got := sortedKeys(body); !slices.Equal(got, tt.wantKeys) {
+				t.Errorf("body keys = %v, want %v: %s", got, tt.wantKeys, rec.Body.String())
+			}
+			if tt.wantMeta == nil {
+				return
+			}
+			if string(body["api_version"]) != `"1.1"` {
+				t.Errorf("api_version = %s, want \"1.1\"", body["api_version"])
+			}
+			var meta map[string]json.RawMessage
+			if err := json.Unmarshal(body["meta"], &meta); err != nil {
+				t.Fatalf("decode meta %s: %v", body["meta"], err)
+			}
+			if got := sortedKeys(meta); !slices.Equal(got, tt.wantMeta) {
+				t.Errorf("meta keys = %v, want %v", got, tt.wantMeta)
+			}
+		})
+	}
+}
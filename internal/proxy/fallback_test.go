@@ -0,0 +1,171 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// readSequencer answers the read methods, failing with code unless it is OK
+type readSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	code codes.Code
+}
+
+func (s *readSequencer) GetTick(ctx context.Context, req *pb.GetTickRequest) (*pb.GetTickResponse, error) {
+	if s.code != codes.OK {
+		return nil, status.Error(s.code, "scripted failure")
+	}
+	return &pb.GetTickResponse{Tick: &pb.Tick{TickNumber: req.GetTickNumber()}}, nil
+}
+
+func (s *readSequencer) GetChainState(ctx context.Context, req *pb.GetChainStateRequest) (*pb.GetChainStateResponse, error) {
+	if s.code != codes.OK {
+		return nil, status.Error(s.code, "scripted failure")
+	}
+	return &pb.GetChainStateResponse{}, nil
+}
+
+func (s *readSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	if s.code != codes.OK {
+		return nil, status.Error(s.code, "scripted failure")
+	}
+	return &pb.GetStatusResponse{CurrentTick: 42}, nil
+}
+
+func (s *readSequencer) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.GetTransactionResponse, error) {
+	if s.code != codes.OK {
+		return nil, status.Error(s.code, "scripted failure")
+	}
+	return &pb.GetTransactionResponse{}, nil
+}
+
+// StreamTicks sends one tick and ends the stream
+func (s *readSequencer) StreamTicks(req *pb.StreamTicksRequest, stream pb.SequencerService_StreamTicksServer) error {
+	if s.code != codes.OK {
+		return status.Error(s.code, "scripted failure")
+	}
+	return stream.Send(&pb.Tick{TickNumber: req.GetStartTick()})
+}
+
+func TestHandleReads_RESTFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(p *GRPCProxy) http.HandlerFunc
+		query      string
+		code       codes.Code // gRPC result
+		restStatus int
+		wantStatus int
+		wantSource string
+		wantPath   string // REST request, empty if REST must not be called
+	}{
+		{"tick from gRPC", (*GRPCProxy).HandleGetTick, "number=42", codes.OK, http.StatusOK, http.StatusOK, "grpc", ""},
+		{"tick falls back", (*GRPCProxy).HandleGetTick, "number=42", codes.Unavailable, http.StatusOK, http.StatusOK, "rest-api", "/tick/42"},
+		{"tick fallback fails", (*GRPCProxy).HandleGetTick, "number=42", codes.Unavailable, http.StatusBadGateway, http.StatusInternalServerError, "", "/tick/42"},
+		{"tick not found", (*GRPCProxy).HandleGetTick, "number=42", codes.NotFound, http.StatusOK, http.StatusInternalServerError, "", ""},
+		{"chain state from gRPC", (*GRPCProxy).HandleGetChainState, "", codes.OK, http.StatusOK, http.StatusOK, "grpc", ""},
+		{"chain state falls back", (*GRPCProxy).HandleGetChainState, "tick_limit=5", codes.Unavailable, http.StatusOK, http.StatusOK, "rest-api", "/chain-state?tick_limit=5"},
+		{"chain state default limit", (*GRPCProxy).HandleGetChainState, "", codes.Unavailable, http.StatusOK, http.StatusOK, "rest-api", "/chain-state?tick_limit=10"},
+		{"chain state internal error", (*GRPCProxy).HandleGetChainState, "", codes.Internal, http.StatusOK, http.StatusInternalServerError, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var restPaths []string
+			rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				restPaths = append(restPaths, r.URL.RequestURI())
+				mu.Unlock()
+				w.WriteHeader(tt.restStatus)
+				w.Write([]byte(`{"from":"rest"}`))
+			}))
+			defer rest.Close()
+
+			addr := serveSequencer(t, &readSequencer{code: tt.code})
+			p, err := NewGRPCProxy(addr, nil, rest.URL, nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			rec := httptest.NewRecorder()
+			tt.handler(p)(rec, httptest.NewRequest(http.MethodGet, "/read?"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := rec.Header().Get("X-Data-Source"); got != tt.wantSource {
+				t.Errorf("X-Data-Source = %q, want %q", got, tt.wantSource)
+			}
+			if tt.wantSource == "rest-api" && rec.Body.String() != `{"from":"rest"}` {
+				t.Errorf("body = %s, want the REST response", rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusInternalServerError && !strings.Contains(rec.Body.String(), "grpc call failed") {
+				t.Errorf("body = %s, want the gRPC error", rec.Body.String())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case tt.wantPath == "" && len(restPaths) != 0:
+				t.Errorf("REST called with %v, want no call", restPaths)
+			case tt.wantPath != "" && (len(restPaths) != 1 || restPaths[0] != tt.wantPath):
+				t.Errorf("REST called with %v, want %s", restPaths, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestHandleReads_DataSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(p *GRPCProxy) http.HandlerFunc
+		query      string
+		code       codes.Code
+		wantStatus int
+		want       string
+	}{
+		{"status", (*GRPCProxy).HandleGetStatus, "", codes.OK, http.StatusOK, "grpc"},
+		{"transaction", (*GRPCProxy).HandleGetTransaction, "hash=abc1", codes.OK, http.StatusOK, "grpc"},
+		{"tick", (*GRPCProxy).HandleGetTick, "number=7", codes.OK, http.StatusOK, "grpc"},
+		{"tick from REST", (*GRPCProxy).HandleGetTick, "number=7", codes.Unavailable, http.StatusOK, "rest-api"},
+		{"chain state", (*GRPCProxy).HandleGetChainState, "", codes.OK, http.StatusOK, "grpc"},
+		{"tick stream", (*GRPCProxy).HandleStreamTicks, "start_tick=7", codes.OK, http.StatusOK, "grpc"},
+		{"recent transactions without a database", (*GRPCProxy).HandleGetRecentTransactions, "", codes.OK, http.StatusOK, "database_unavailable"},
+		{"errors have no source", (*GRPCProxy).HandleGetStatus, "", codes.Internal, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{}`))
+			}))
+			defer rest.Close()
+			addr := serveSequencer(t, &readSequencer{code: tt.code})
+			p, err := NewGRPCProxy(addr, nil, rest.URL, nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			rec := httptest.NewRecorder()
+			tt.handler(p)(rec, httptest.NewRequest(http.MethodGet, "/read?"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := rec.Header().Get("X-Data-Source"); got != tt.want {
+				t.Errorf("X-Data-Source = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
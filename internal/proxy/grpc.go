@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
@@ -8,15 +9,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/grpc/status"
 
 	"github.com/fermilabs/fermi-api-gateway/internal/database"
 	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
@@ -25,43 +32,189 @@ import (
 // GRPCProxy handles gRPC proxying and converts HTTP requests to gRPC calls
 type GRPCProxy struct {
 	target     string
-	conn       *grpc.ClientConn
+	conn       *resettableConn
 	client     pb.SequencerServiceClient
 	repository *database.Repository
 	restURL    string
 	logger     *zap.Logger
+
+	dial         func(target string) (*grpc.ClientConn, error)
+	resetCounter prometheus.Counter
+	degraded     *prometheus.CounterVec   // Partial unified status responses, by failed backend
+	callLatency  *prometheus.HistogramVec // Upstream unary call latency, by method
+
+	maxRecvMsgSize int // Largest response accepted from the sequencer, in bytes
+	maxSendMsgSize int // Largest request sent to the sequencer, in bytes
+
+	txLookups   coalescer      // Coalesces concurrent tx-by-hash lookups
+	txNotFound  *negativeCache // Recently not-found tx hashes
+	notFoundTTL time.Duration
+
+	primary   map[string]Source // Backend queried first per method
+	jsonStyle JSONStyle         // Default field names for protobuf responses
+
+	submitQueue *SubmitQueue  // Optional; smooths submissions to the sequencer
+	nonces      *nonceCache   // Optional; rejects replayed (public key, nonce) pairs
+	confirmWait time.Duration // How long single submissions wait for inclusion (0 = don't wait)
+	pending     PendingLister // Optional; lists mempool transactions
+
+	signatureScheme SignatureScheme // Signature/public key lengths submissions must have (none = unchecked)
+
+	txnRate  *ema // Smoothed unified status rates
+	tickRate *ema
+
+	statusLookups coalescer // Coalesces concurrent unified status fetches
+	statusMu      sync.Mutex
+	statusCache   *statusSnapshot // Last successful unified status, served until it expires
+
+	maxStreams    int64            // Concurrent SSE tick streams allowed (0 = unlimited)
+	activeStreams atomic.Int64     // Open SSE tick streams
+	streamsGauge  prometheus.Gauge // Optional; tracks activeStreams
 }
 
-// NewGRPCProxy creates a new gRPC proxy client
-func NewGRPCProxy(target string, repository *database.Repository, restURL string, logger *zap.Logger) (*GRPCProxy, error) {
-	// Create gRPC connection with connection pooling
-	conn, err := grpc.NewClient(
-		target,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(10*1024*1024), // 10MB
-			grpc.MaxCallSendMsgSize(10*1024*1024), // 10MB
-		),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+// GRPCProxyOption is a functional option for configuring GRPCProxy
+type GRPCProxyOption func(*GRPCProxy)
+
+// WithDialer overrides how the gRPC connection is created (and recreated on reset)
+func WithDialer(dial func(target string) (*grpc.ClientConn, error)) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.dial = dial
+	}
+}
+
+// WithMaxMessageSize sets the largest gRPC messages received from and sent to
+// the sequencer, in bytes (default 10MB each). GetChainState with a large
+// tick_limit needs a larger receive limit. Ignored when WithDialer is used
+func WithMaxMessageSize(recv, send int) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.maxRecvMsgSize = recv
+		p.maxSendMsgSize = send
 	}
+}
 
-	client := pb.NewSequencerServiceClient(conn)
+// WithConnResetCounter sets a counter incremented whenever the connection is reset
+func WithConnResetCounter(counter prometheus.Counter) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.resetCounter = counter
+	}
+}
 
+// WithDegradedCounter sets a counter (labeled by failed_backend) incremented whenever
+// the unified status is served from one backend because the other failed
+func WithDegradedCounter(counter *prometheus.CounterVec) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.degraded = counter
+	}
+}
+
+// WithCallLatency sets a histogram (labeled by method, e.g. "GetStatus") observing the
+// latency of every unary call to the sequencer
+func WithCallLatency(histogram *prometheus.HistogramVec) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.callLatency = histogram
+	}
+}
+
+// WithMaxStreams limits concurrent SSE tick streams, each holding a gRPC stream open;
+// requests beyond the limit get 503 (default 0 = unlimited)
+func WithMaxStreams(max int) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.maxStreams = int64(max)
+	}
+}
+
+// WithActiveStreamsGauge sets a gauge tracking open SSE tick streams
+func WithActiveStreamsGauge(gauge prometheus.Gauge) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.streamsGauge = gauge
+	}
+}
+
+// WithNotFoundTTL sets how long a not-found transaction hash is answered with 404
+// without querying upstreams (default 2s, 0 disables). Keep it short so hashes
+// that confirm shortly after are found
+func WithNotFoundTTL(ttl time.Duration) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.notFoundTTL = ttl
+	}
+}
+
+// NewGRPCProxy creates a new gRPC proxy client
+func NewGRPCProxy(target string, repository *database.Repository, restURL string, logger *zap.Logger, opts ...GRPCProxyOption) (*GRPCProxy, error) {
 	// Use nop logger if none provided
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
-	return &GRPCProxy{
+	p := &GRPCProxy{
 		target:     target,
-		conn:       conn,
-		client:     client,
 		repository: repository,
 		restURL:    restURL,
 		logger:     logger,
-	}, nil
+
+		maxRecvMsgSize: defaultMaxMsgSize,
+		maxSendMsgSize: defaultMaxMsgSize,
+
+		notFoundTTL: 2 * time.Second,
+		jsonStyle:   JSONStyleSnake,
+		primary:     make(map[string]Source, len(defaultPrimarySources)),
+		txnRate:     newEMA(statusRateWindow),
+		tickRate:    newEMA(statusRateWindow),
+	}
+
+	for method, source := range defaultPrimarySources {
+		p.primary[method] = source
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.txNotFound = newNegativeCache(p.notFoundTTL)
+
+	if p.dial == nil {
+		p.dial = func(target string) (*grpc.ClientConn, error) {
+			return dialSequencer(target, p.maxRecvMsgSize, p.maxSendMsgSize)
+		}
+	}
+
+	var onReset func()
+	if p.resetCounter != nil {
+		onReset = p.resetCounter.Inc
+	}
+
+	// The connection is recreated automatically after repeated Unavailable errors
+	conn, err := newResettableConn(target, p.dial, logger, onReset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	if p.callLatency != nil {
+		conn.onCall = func(method string, elapsed time.Duration) {
+			// Full method names look like /continuum.sequencer.v1.SequencerService/GetStatus
+			p.callLatency.WithLabelValues(path.Base(method)).Observe(elapsed.Seconds())
+		}
+	}
+
+	p.conn = conn
+	p.client = pb.NewSequencerServiceClient(conn)
+
+	return p, nil
+}
+
+// defaultMaxMsgSize is the default gRPC message size limit in each direction
+const defaultMaxMsgSize = 10 * 1024 * 1024 // 10MB
+
+// dialSequencer creates the gRPC connection to the sequencer with connection pooling
+func dialSequencer(target string, maxRecvMsgSize, maxSendMsgSize int) (*grpc.ClientConn, error) {
+	return grpc.NewClient(
+		target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(maxRecvMsgSize),
+			grpc.MaxCallSendMsgSize(maxSendMsgSize),
+		),
+	)
 }
 
 // Close closes the gRPC connection
@@ -72,12 +225,27 @@ func (p *GRPCProxy) Close() error {
 	return nil
 }
 
+// HealthCheck verifies the sequencer is reachable
+// A connection in TRANSIENT_FAILURE or SHUTDOWN fails immediately; otherwise a
+// GetStatus call confirms the sequencer responds within ctx's deadline
+func (p *GRPCProxy) HealthCheck(ctx context.Context) error {
+	switch state := p.conn.State(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return fmt.Errorf("grpc connection to %s is %s", p.target, state)
+	}
+
+	if _, err := p.client.GetStatus(ctx, &pb.GetStatusRequest{}); err != nil {
+		return fmt.Errorf("grpc status check failed: %w", err)
+	}
+	return nil
+}
+
 // transactionRequest is an intermediate struct that matches the JSON format
 // It allows JSON to unmarshal arrays directly into []byte (like GIN does)
 type transactionRequest struct {
 	TxID      string      `json:"tx_id"`
-	Payload   interface{} `json:"payload"`   // Can be array [70,82,77,...] or base64 string
-	Signature string      `json:"signature"` // Hex string (needs decoding)
+	Payload   interface{} `json:"payload"`    // Can be array [70,82,77,...] or base64 string
+	Signature string      `json:"signature"`  // Hex string (needs decoding)
 	PublicKey string      `json:"public_key"` // Base58 string (needs decoding)
 	Nonce     uint64      `json:"nonce"`
 	Timestamp interface{} `json:"timestamp"` // Can be string or number
@@ -122,10 +290,14 @@ func (tx *transactionRequest) toProtobuf() (*pb.Transaction, error) {
 	case float64:
 		timestamp = uint64(v)
 	case nil:
-		// Timestamp is optional, use 0
+		// Timestamp is optional, default to the server's current time
+		timestamp = uint64(time.Now().UnixMicro())
 	default:
 		return nil, fmt.Errorf("timestamp must be a string or number")
 	}
+	if err := validateTimestamp(timestamp, time.Now()); err != nil {
+		return nil, err
+	}
 
 	// Decode signature from hex string to bytes
 	signatureBytes, err := decodeHex(tx.Signature)
@@ -149,6 +321,51 @@ func (tx *transactionRequest) toProtobuf() (*pb.Transaction, error) {
 	}, nil
 }
 
+// Submitted timestamps (microseconds since the epoch) must fall within this window around the server clock
+const (
+	maxTimestampFuture = 5 * time.Minute
+	maxTimestampAge    = 24 * time.Hour
+)
+
+// validateTimestamp rejects timestamps far from now, e.g. zero, milliseconds or seconds
+// instead of microseconds, or badly skewed client clocks
+func validateTimestamp(timestamp uint64, now time.Time) error {
+	earliest := uint64(now.Add(-maxTimestampAge).UnixMicro())
+	latest := uint64(now.Add(maxTimestampFuture).UnixMicro())
+	if timestamp < earliest {
+		return fmt.Errorf("timestamp %d is more than %s in the past (expected microseconds)", timestamp, maxTimestampAge)
+	}
+	if timestamp > latest {
+		return fmt.Errorf("timestamp %d is more than %s in the future (expected microseconds)", timestamp, maxTimestampFuture)
+	}
+	return nil
+}
+
+// batchRequest is the intermediate struct for batch submissions; each element
+// uses the same JSON format as a single transaction
+type batchRequest struct {
+	Transactions []transactionRequest `json:"transactions"`
+}
+
+// toProtobuf converts every transaction in the batch and checks it with validate.
+// On failure it returns the index of the offending transaction
+func (b *batchRequest) toProtobuf(validate func(*pb.Transaction) error) (*pb.SubmitBatchRequest, int, error) {
+	req := &pb.SubmitBatchRequest{
+		Transactions: make([]*pb.Transaction, 0, len(b.Transactions)),
+	}
+	for i := range b.Transactions {
+		tx, err := b.Transactions[i].toProtobuf()
+		if err == nil {
+			err = validate(tx)
+		}
+		if err != nil {
+			return nil, i, err
+		}
+		req.Transactions = append(req.Transactions, tx)
+	}
+	return req, 0, nil
+}
+
 // decodeHex decodes a hex string to bytes
 func decodeHex(s string) ([]byte, error) {
 	// Remove 0x prefix if present
@@ -196,7 +413,7 @@ func (p *GRPCProxy) HandleSubmitTransaction() http.HandlerFunc {
 		if len(bodyPreview) > 500 {
 			bodyPreview = bodyPreview[:500] + "... (truncated)"
 		}
-		p.logger.Debug("Received transaction submission request", 
+		p.logger.Debug("Received transaction submission request",
 			zap.String("body_preview", bodyPreview),
 			zap.Int("body_length", len(body)),
 			zap.String("content_type", r.Header.Get("Content-Type")))
@@ -214,7 +431,7 @@ func (p *GRPCProxy) HandleSubmitTransaction() http.HandlerFunc {
 			Transaction transactionRequest `json:"transaction"`
 		}
 		if err := json.Unmarshal(body, &bodyStruct); err != nil {
-			p.logger.Warn("Failed to unmarshal JSON request", 
+			p.logger.Warn("Failed to unmarshal JSON request",
 				zap.Error(err),
 				zap.String("body_preview", bodyPreview))
 			http.Error(w, fmt.Sprintf(`{"error":"invalid request body: %v"}`, err), http.StatusBadRequest)
@@ -223,14 +440,24 @@ func (p *GRPCProxy) HandleSubmitTransaction() http.HandlerFunc {
 
 		// Convert to protobuf transaction (like ToProtobuf() in GIN handler)
 		grpcTx, err := bodyStruct.Transaction.toProtobuf()
+		if err == nil {
+			err = p.validateSignature(grpcTx)
+		}
 		if err != nil {
-			p.logger.Warn("Failed to convert transaction to protobuf", 
+			p.logger.Warn("Failed to convert transaction to protobuf",
 				zap.Error(err),
 				zap.String("body_preview", bodyPreview))
 			http.Error(w, fmt.Sprintf(`{"error":"invalid transaction data: %v"}`, err), http.StatusBadRequest)
 			return
 		}
 
+		// Reject replays before they reach the sequencer
+		nonceKeys, _, ok := p.reserveNonces([]*pb.Transaction{grpcTx})
+		if !ok {
+			writeNonceReplayed(w, grpcTx, 0)
+			return
+		}
+
 		// Create protobuf request
 		req := &pb.SubmitTransactionRequest{
 			Transaction: grpcTx,
@@ -240,24 +467,27 @@ func (p *GRPCProxy) HandleSubmitTransaction() http.HandlerFunc {
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
-		resp, err := p.client.SubmitTransaction(ctx, req)
+		var resp *pb.SubmitTransactionResponse
+		if qerr := p.submit(ctx, func(ctx context.Context) {
+			resp, err = p.client.SubmitTransaction(ctx, req)
+		}); qerr != nil {
+			p.releaseNonces(nonceKeys)
+			writeSubmitQueueFull(w)
+			return
+		}
 		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+			p.releaseNonces(nonceKeys)
+			p.writeGRPCError(w, r, err)
 			return
 		}
 
 		// Return JSON response using protojson for consistency
 		w.Header().Set("Content-Type", "application/json")
-		marshaler := &protojson.MarshalOptions{
-			UseProtoNames: true, // Use snake_case field names as in proto
-		}
-		jsonBytes, err := marshaler.Marshal(resp)
-		if err != nil {
-			p.logger.Warn("Failed to marshal response", zap.Error(err))
-			http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+		if p.confirmWait > 0 && resp.TxHash != "" {
+			p.writeConfirmedSubmission(ctx, w, r, resp)
 			return
 		}
-		w.Write(jsonBytes)
+		p.writeProtoJSON(w, r, resp)
 	}
 }
 
@@ -275,23 +505,53 @@ func (p *GRPCProxy) HandleSubmitBatch() http.HandlerFunc {
 			return
 		}
 
-		var req pb.SubmitBatchRequest
-		if err := json.Unmarshal(body, &req); err != nil {
+		// Same flexible element format as single submissions (payload arrays/base64, hex signatures)
+		var batch batchRequest
+		if err := json.Unmarshal(body, &batch); err != nil {
 			http.Error(w, fmt.Sprintf(`{"error":"invalid request: %v"}`, err), http.StatusBadRequest)
 			return
 		}
 
+		req, index, err := batch.toProtobuf(p.validateSignature)
+		if err != nil {
+			p.logger.Warn("Failed to convert batch transaction to protobuf",
+				zap.Int("index", index),
+				zap.Error(err))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": fmt.Sprintf("invalid transaction data at index %d: %v", index, err),
+				"index": index,
+			})
+			return
+		}
+
+		// Reject replays, including the same nonce twice within the batch
+		nonceKeys, index, ok := p.reserveNonces(req.Transactions)
+		if !ok {
+			writeNonceReplayed(w, req.Transactions[index], index)
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 		defer cancel()
 
-		resp, err := p.client.SubmitBatch(ctx, &req)
+		var resp *pb.SubmitBatchResponse
+		if qerr := p.submit(ctx, func(ctx context.Context) {
+			resp, err = p.client.SubmitBatch(ctx, req)
+		}); qerr != nil {
+			p.releaseNonces(nonceKeys)
+			writeSubmitQueueFull(w)
+			return
+		}
 		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+			p.releaseNonces(nonceKeys)
+			p.writeGRPCError(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		p.writeProtoJSON(w, r, resp)
 	}
 }
 
@@ -308,12 +568,13 @@ func (p *GRPCProxy) HandleGetStatus() http.HandlerFunc {
 
 		resp, err := p.client.GetStatus(ctx, &pb.GetStatusRequest{})
 		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+			p.writeGRPCError(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		w.Header().Set("X-Data-Source", "grpc")
+		p.writeProtoJSON(w, r, resp)
 	}
 }
 
@@ -339,12 +600,13 @@ func (p *GRPCProxy) HandleGetTransaction() http.HandlerFunc {
 			TxHash: txHash,
 		})
 		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+			p.writeGRPCError(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		w.Header().Set("X-Data-Source", "grpc")
+		p.writeProtoJSON(w, r, resp)
 	}
 }
 
@@ -356,15 +618,9 @@ func (p *GRPCProxy) HandleGetTick() http.HandlerFunc {
 			return
 		}
 
-		tickNumberStr := r.URL.Query().Get("number")
-		if tickNumberStr == "" {
-			http.Error(w, `{"error":"missing tick_number parameter"}`, http.StatusBadRequest)
-			return
-		}
-
-		tickNumber, err := strconv.ParseUint(tickNumberStr, 10, 64)
+		tickNumber, err := parseTickNumber(r.URL.Query().Get("number"))
 		if err != nil {
-			http.Error(w, `{"error":"invalid tick_number"}`, http.StatusBadRequest)
+			http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusBadRequest)
 			return
 		}
 
@@ -375,13 +631,40 @@ func (p *GRPCProxy) HandleGetTick() http.HandlerFunc {
 			TickNumber: tickNumber,
 		})
 		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+			if p.serveRESTFallback(ctx, w, err, fmt.Sprintf("/tick/%d", tickNumber)) {
+				return
+			}
+			p.writeGRPCError(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		w.Header().Set("X-Data-Source", "grpc")
+		p.writeProtoJSON(w, r, resp)
+	}
+}
+
+// writeGRPCError reports a failed gRPC call as a 500, or as a 499 logged at debug
+// level when the call failed because the client disconnected
+func (p *GRPCProxy) writeGRPCError(w http.ResponseWriter, r *http.Request, err error) {
+	if clientGone(r) {
+		p.logger.Debug("gRPC call canceled by client disconnect", zap.String("path", r.URL.Path), zap.Error(err))
+		writeClientGone(w)
+		return
 	}
+	http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+}
+
+// parseTickNumber parses a tick number from a query or path parameter
+func parseTickNumber(s string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("missing tick_number parameter")
+	}
+	tickNumber, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tick_number")
+	}
+	return tickNumber, nil
 }
 
 // HandleGetChainState handles GET /api/continuum/grpc/chain-state
@@ -412,12 +695,68 @@ func (p *GRPCProxy) HandleGetChainState() http.HandlerFunc {
 			TickLimit: tickLimit,
 		})
 		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"grpc call failed: %v"}`, err), http.StatusInternalServerError)
+			if p.serveRESTFallback(ctx, w, err, fmt.Sprintf("/chain-state?tick_limit=%d", tickLimit)) {
+				return
+			}
+			p.writeGRPCError(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		w.Header().Set("X-Data-Source", "grpc")
+		p.writeProtoJSON(w, r, resp)
+	}
+}
+
+// serveRESTFallback answers a read from the REST API at path when grpcErr is Unavailable
+// It reports whether a response was written; otherwise the caller reports grpcErr
+func (p *GRPCProxy) serveRESTFallback(ctx context.Context, w http.ResponseWriter, grpcErr error, path string) bool {
+	if status.Code(grpcErr) != codes.Unavailable || ctx.Err() != nil {
+		return false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.restURL+path, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		p.logger.Warn("REST fallback failed", zap.String("path", path), zap.Error(err))
+		return false
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		p.logger.Warn("REST fallback failed", zap.String("path", path), zap.Int("status", resp.StatusCode), zap.Error(err))
+		return false
+	}
+
+	p.logger.Debug("Served read from REST after gRPC was unavailable", zap.String("path", path), zap.Error(grpcErr))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Data-Source", "rest-api")
+	w.Write(body)
+	return true
+}
+
+// acquireStream reserves one of the maxStreams SSE stream slots
+func (p *GRPCProxy) acquireStream() bool {
+	if n := p.activeStreams.Add(1); p.maxStreams > 0 && n > p.maxStreams {
+		p.activeStreams.Add(-1)
+		return false
+	}
+	if p.streamsGauge != nil {
+		p.streamsGauge.Inc()
+	}
+	return true
+}
+
+// releaseStream frees a slot reserved by acquireStream
+func (p *GRPCProxy) releaseStream() {
+	p.activeStreams.Add(-1)
+	if p.streamsGauge != nil {
+		p.streamsGauge.Dec()
 	}
 }
 
@@ -442,12 +781,25 @@ func (p *GRPCProxy) HandleStreamTicks() http.HandlerFunc {
 			startTick = tick
 		}
 
+		if !p.acquireStream() {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"too many open tick streams, retry later"}`))
+			return
+		}
+		defer p.releaseStream()
+
 		// Set headers for SSE
 		w.Header().Set("Content-Type", "text/event-stream")
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Data-Source", "grpc")
+
+		// Canceling on return tears down the upstream stream however the handler exits
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
 
-		ctx := r.Context()
 		stream, err := p.client.StreamTicks(ctx, &pb.StreamTicksRequest{
 			StartTick: startTick,
 		})
@@ -463,6 +815,8 @@ func (p *GRPCProxy) HandleStreamTicks() http.HandlerFunc {
 			return
 		}
 
+		marshaler := p.protoMarshaler(r)
+
 		for {
 			tick, err := stream.Recv()
 			if err == io.EOF {
@@ -473,14 +827,23 @@ func (p *GRPCProxy) HandleStreamTicks() http.HandlerFunc {
 				break
 			}
 
-			// Marshal tick to JSON
-			data, err := json.Marshal(tick)
+			// Marshal tick with protojson so fields match GetTick/GetChainState
+			data, err := marshaler.Marshal(tick)
 			if err != nil {
 				continue
 			}
 
+			// protojson's whitespace is deliberately unstable; compact it so events are byte-for-byte stable
+			var event bytes.Buffer
+			if err := json.Compact(&event, data); err != nil {
+				continue
+			}
+
 			// Write SSE format: data: {...}\n\n
-			fmt.Fprintf(w, "data: %s\n\n", data)
+			// A failed write means the client is gone even if its context isn't done yet
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", event.Bytes()); err != nil {
+				return
+			}
 			flusher.Flush()
 
 			// Check if client disconnected
@@ -513,6 +876,12 @@ func (p *GRPCProxy) HandleGetRecentTransactions() http.HandlerFunc {
 			limit = parsedLimit
 		}
 
+		version, err := requestAPIVersion(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusBadRequest)
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
@@ -523,10 +892,14 @@ func (p *GRPCProxy) HandleGetRecentTransactions() http.HandlerFunc {
 				w.Header().Set("Content-Type", "application/json")
 				w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 				w.Header().Set("X-Data-Source", "database")
-				json.NewEncoder(w).Encode(map[string]interface{}{
-					"transactions": transactions,
-					"count":        len(transactions),
-				})
+				writeEnvelope(w, version,
+					map[string]interface{}{
+						"transactions": transactions,
+						"count":        len(transactions),
+					},
+					transactions,
+					map[string]interface{}{"count": len(transactions)},
+				)
 				return
 			}
 			// Log the database error for debugging
@@ -534,13 +907,101 @@ func (p *GRPCProxy) HandleGetRecentTransactions() http.HandlerFunc {
 		}
 
 		// Return empty result - database not available or not configured
+		message := "Recent transactions unavailable - database not configured or unavailable"
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 		w.Header().Set("X-Data-Source", "database_unavailable")
+		writeEnvelope(w, version,
+			map[string]interface{}{
+				"transactions": []interface{}{},
+				"count":        0,
+				"message":      message,
+			},
+			[]interface{}{},
+			map[string]interface{}{"count": 0, "message": message},
+		)
+	}
+}
+
+// HandleGetTransactionStats handles GET /api/v1/continuum/tx/stats?from=...&to=...&bucket=1m
+func (p *GRPCProxy) HandleGetTransactionStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		bucket := r.URL.Query().Get("bucket")
+		if bucket == "" {
+			bucket = "1m" // default
+		}
+
+		if !database.ValidBucket(bucket) {
+			http.Error(w, `{"error":"invalid bucket (allowed: 1m, 5m, 15m, 1h, 4h, 1d)"}`, http.StatusBadRequest)
+			return
+		}
+
+		// Parse time range (default: last hour)
+		now := time.Now().UTC()
+		from := now.Add(-1 * time.Hour)
+		to := now
+
+		if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+			parsed, err := time.Parse(time.RFC3339, fromStr)
+			if err != nil {
+				http.Error(w, `{"error":"invalid 'from' (use RFC3339, e.g. 2023-01-01T00:00:00Z)"}`, http.StatusBadRequest)
+				return
+			}
+			from = parsed
+		}
+		if toStr := r.URL.Query().Get("to"); toStr != "" {
+			parsed, err := time.Parse(time.RFC3339, toStr)
+			if err != nil {
+				http.Error(w, `{"error":"invalid 'to' (use RFC3339, e.g. 2023-01-01T23:59:59Z)"}`, http.StatusBadRequest)
+				return
+			}
+			to = parsed
+		}
+
+		if from.After(to) {
+			http.Error(w, `{"error":"'from' must be before 'to'"}`, http.StatusBadRequest)
+			return
+		}
+
+		// Limit query range to 7 days to keep aggregation cheap
+		if to.Sub(from) > 7*24*time.Hour {
+			http.Error(w, `{"error":"time range cannot exceed 7 days"}`, http.StatusBadRequest)
+			return
+		}
+
+		if p.repository == nil || !p.repository.Available() {
+			http.Error(w, `{"error":"database not available"}`, http.StatusServiceUnavailable)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		stats, err := p.repository.GetTransactionStats(ctx, from, to, bucket)
+		if err != nil && clientGone(r) {
+			writeClientGone(w)
+			return
+		}
+		if err != nil {
+			p.logger.Warn("Failed to get transaction stats", zap.Error(err))
+			http.Error(w, `{"error":"failed to get transaction stats"}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=5")
+		w.Header().Set("X-Data-Source", "database")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"transactions": []interface{}{},
-			"count":        0,
-			"message":      "Recent transactions unavailable - database not configured or unavailable",
+			"from":    from,
+			"to":      to,
+			"bucket":  bucket,
+			"total":   stats.Total,
+			"buckets": stats.Buckets,
 		})
 	}
 }
@@ -563,56 +1024,100 @@ func (p *GRPCProxy) HandleGetTransactionByHash() http.HandlerFunc {
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-		defer cancel()
-
-		// Try database first (if available)
-		if p.repository != nil {
-			tx, err := p.repository.GetTransaction(ctx, txHash)
-			if err == nil {
-				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("Cache-Control", "private, max-age=1800")
-				w.Header().Set("X-Data-Source", "database")
-				json.NewEncoder(w).Encode(map[string]interface{}{
-					"source": "db",
-					"data":   tx,
-				})
-				return
-			}
-		}
-
-		// Fallback to REST API
-		resp, err := http.Get(p.restURL + "/tx/" + txHash)
+		version, err := requestAPIVersion(r)
 		if err != nil {
-			http.Error(w, `{"error":"service unavailable"}`, http.StatusServiceUnavailable)
+			http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusBadRequest)
 			return
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode == http.StatusNotFound {
+		// Hashes that were just not found are answered without hitting upstreams
+		if p.txNotFound.Has(txHash) {
+			w.Header().Set("X-Data-Source", "cache")
 			http.Error(w, `{"error":"transaction not found"}`, http.StatusNotFound)
 			return
 		}
 
-		if resp.StatusCode != http.StatusOK {
-			http.Error(w, fmt.Sprintf(`{"error":"upstream returned status %d"}`, resp.StatusCode), http.StatusServiceUnavailable)
+		// Concurrent lookups of the same hash (e.g. confirmation polling) share one upstream call,
+		// canceled only once every waiting client has gone
+		v, err := p.txLookups.Do(r.Context(), txHash, 10*time.Second, func(ctx context.Context) interface{} {
+			result := p.lookupTransaction(ctx, txHash)
+			if result.status == http.StatusNotFound {
+				p.txNotFound.Add(txHash)
+			}
+			return result
+		})
+		if err != nil {
+			writeClientGone(w)
 			return
 		}
+		result := v.(*txLookup)
 
-		var data interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-			http.Error(w, `{"error":"failed to decode response"}`, http.StatusInternalServerError)
+		if result.status != http.StatusOK {
+			http.Error(w, result.errBody, result.status)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Cache-Control", "private, max-age=1800")
-		w.Header().Set("X-Data-Source", "rest-api")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"source": "continuum",
-			"data":   data,
-		})
+		w.Header().Set("X-Data-Source", result.dataSource)
+		writeEnvelope(w, version,
+			map[string]interface{}{
+				"source": result.source,
+				"data":   result.data,
+			},
+			result.data,
+			map[string]interface{}{"source": result.source},
+		)
+	}
+}
+
+// txLookup is the outcome of a transaction-by-hash lookup, shared by coalesced requests
+type txLookup struct {
+	status     int    // HTTP status to respond with
+	errBody    string // JSON error body when status is not 200
+	source     string // "db" or "continuum"
+	dataSource string // X-Data-Source header value
+	data       interface{}
+}
+
+// lookupTransaction fetches a transaction from the database, falling back to the REST API
+func (p *GRPCProxy) lookupTransaction(ctx context.Context, txHash string) *txLookup {
+	// Try database first (if available)
+	if p.repository != nil {
+		tx, err := p.repository.GetTransaction(ctx, txHash)
+		if err == nil {
+			return &txLookup{status: http.StatusOK, source: "db", dataSource: "database", data: tx}
+		}
+	}
+
+	// Fallback to REST API
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.restURL+"/tx/"+txHash, nil)
+	if err != nil {
+		return &txLookup{status: http.StatusServiceUnavailable, errBody: `{"error":"service unavailable"}`}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &txLookup{status: http.StatusServiceUnavailable, errBody: `{"error":"service unavailable"}`}
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &txLookup{status: http.StatusNotFound, errBody: `{"error":"transaction not found"}`}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &txLookup{
+			status:  http.StatusServiceUnavailable,
+			errBody: fmt.Sprintf(`{"error":"upstream returned status %d"}`, resp.StatusCode),
+		}
+	}
+
+	var data interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return &txLookup{status: http.StatusInternalServerError, errBody: `{"error":"failed to decode response"}`}
+	}
+
+	return &txLookup{status: http.StatusOK, source: "continuum", dataSource: "rest-api", data: data}
 }
 
 // sanitizeInput removes potentially harmful characters
@@ -0,0 +1,157 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	connResetThreshold  = 5                // Consecutive Unavailable errors before the connection is reset
+	connResetBackoff    = 1 * time.Second  // Minimum time between resets, doubled while resets keep failing
+	connResetMaxBackoff = 30 * time.Second // Cap for the reset backoff
+)
+
+// resettableConn is a grpc.ClientConnInterface that delegates to a *grpc.ClientConn
+// and replaces it after repeated connection failures, so a connection stuck in a
+// bad state recovers without restarting the process
+type resettableConn struct {
+	target  string
+	dial    func(target string) (*grpc.ClientConn, error)
+	logger  *zap.Logger
+	onReset func()
+	onCall  func(method string, elapsed time.Duration) // Optional; observes unary call latency
+
+	mu        sync.RWMutex
+	conn      *grpc.ClientConn
+	lastReset time.Time
+	backoff   time.Duration
+
+	failures  atomic.Int32 // Consecutive connection failures
+	succeeded atomic.Bool  // A call succeeded since the last reset
+}
+
+func newResettableConn(target string, dial func(string) (*grpc.ClientConn, error), logger *zap.Logger, onReset func()) (*resettableConn, error) {
+	conn, err := dial(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resettableConn{
+		target:  target,
+		dial:    dial,
+		logger:  logger,
+		onReset: onReset,
+		conn:    conn,
+		backoff: connResetBackoff,
+	}, nil
+}
+
+// Invoke implements grpc.ClientConnInterface
+func (c *resettableConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := c.current().Invoke(ctx, method, args, reply, opts...)
+	if c.onCall != nil {
+		c.onCall(method, time.Since(start))
+	}
+	c.observe(err)
+	return err
+}
+
+// NewStream implements grpc.ClientConnInterface
+// Only errors opening the stream count towards a reset
+func (c *resettableConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := c.current().NewStream(ctx, desc, method, opts...)
+	c.observe(err)
+	return stream, err
+}
+
+// State returns the connectivity state of the current connection
+func (c *resettableConn) State() connectivity.State {
+	return c.current().GetState()
+}
+
+// Close closes the current connection
+func (c *resettableConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Close()
+}
+
+func (c *resettableConn) current() *grpc.ClientConn {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn
+}
+
+// observe tracks consecutive connection failures and resets the connection
+// once they reach connResetThreshold
+func (c *resettableConn) observe(err error) {
+	if err == nil {
+		c.failures.Store(0)
+		c.succeeded.Store(true)
+		return
+	}
+
+	if status.Code(err) != codes.Unavailable {
+		return
+	}
+
+	if c.failures.Add(1) >= connResetThreshold {
+		c.reset()
+	}
+}
+
+// reset replaces the connection; concurrent callers are serialized and only
+// the first one past the threshold and backoff actually reconnects
+func (c *resettableConn) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures.Load() < connResetThreshold {
+		return // Another request already reset the connection
+	}
+
+	// Back off further if the previous reset didn't help
+	if c.succeeded.Swap(false) {
+		c.backoff = connResetBackoff
+	}
+	if time.Since(c.lastReset) < c.backoff {
+		return
+	}
+
+	conn, err := c.dial(c.target)
+	if err != nil {
+		c.logger.Error("Failed to reset gRPC connection",
+			zap.String("target", c.target),
+			zap.Error(err),
+		)
+		c.lastReset = time.Now()
+		c.backoff = min(c.backoff*2, connResetMaxBackoff)
+		return
+	}
+
+	old := c.conn
+	c.conn = conn
+	c.failures.Store(0)
+	c.lastReset = time.Now()
+	c.backoff = min(c.backoff*2, connResetMaxBackoff)
+
+	// In-flight calls on the old connection fail with Canceled, which doesn't count
+	_ = old.Close()
+
+	c.logger.Warn("Reset gRPC connection after repeated failures",
+		zap.String("target", c.target),
+		zap.Int("failure_threshold", connResetThreshold),
+	)
+	if c.onReset != nil {
+		c.onReset()
+	}
+}
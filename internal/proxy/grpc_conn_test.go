@@ -0,0 +1,236 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// scriptedSequencer fails GetStatus calls with the given codes in order, then succeeds
+type scriptedSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+
+	mu    sync.Mutex
+	codes []codes.Code
+}
+
+func (s *scriptedSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.codes) == 0 {
+		return &pb.GetStatusResponse{}, nil
+	}
+	code := s.codes[0]
+	s.codes = s.codes[1:]
+	if code == codes.OK {
+		return &pb.GetStatusResponse{}, nil
+	}
+	return nil, status.Error(code, "scripted failure")
+}
+
+// repeat returns n copies of code
+func repeat(code codes.Code, n int) []codes.Code {
+	out := make([]codes.Code, n)
+	for i := range out {
+		out[i] = code
+	}
+	return out
+}
+
+// resetProxy creates a proxy for sequencer whose dials are counted
+// Dials after the first fail when failRedial is set
+func resetProxy(t *testing.T, sequencer pb.SequencerServiceServer, failRedial bool) (*GRPCProxy, *atomic.Int32, prometheus.Counter, *observer.ObservedLogs) {
+	t.Helper()
+	addr := serveSequencer(t, sequencer)
+	dials := &atomic.Int32{}
+	dial := func(target string) (*grpc.ClientConn, error) {
+		if dials.Add(1) > 1 && failRedial {
+			return nil, errors.New("dial refused")
+		}
+		return dialSequencer(target, defaultMaxMsgSize, defaultMaxMsgSize)
+	}
+
+	resets := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_grpc_conn_resets_total"})
+	core, logs := observer.New(zapcore.InfoLevel)
+	p, err := NewGRPCProxy(addr, nil, "", zap.New(core), WithDialer(dial), WithConnResetCounter(resets))
+	if err != nil {
+		t.Fatalf("NewGRPCProxy() error = %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p, dials, resets, logs
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	return families[0].GetMetric()[0].GetCounter().GetValue()
+}
+
+func TestGRPCProxy_ConnReset(t *testing.T) {
+	tests := []struct {
+		name       string
+		codes      []codes.Code // Result of each GetStatus call
+		failRedial bool
+		wantResets int
+		wantLog    string
+	}{
+		{"below threshold", repeat(codes.Unavailable, connResetThreshold-1), false, 0, ""},
+		{"at threshold", repeat(codes.Unavailable, connResetThreshold), false, 1, "Reset gRPC connection after repeated failures"},
+		{"other errors don't count", repeat(codes.Internal, 2*connResetThreshold), false, 0, ""},
+		{
+			"success restarts the count",
+			append(append(repeat(codes.Unavailable, connResetThreshold-1), codes.OK), repeat(codes.Unavailable, connResetThreshold-1)...),
+			false, 0, "",
+		},
+		{"backoff between resets", repeat(codes.Unavailable, 3*connResetThreshold), false, 1, "Reset gRPC connection after repeated failures"},
+		{"redial fails", repeat(codes.Unavailable, connResetThreshold), true, 0, "Failed to reset gRPC connection"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, dials, resets, logs := resetProxy(t, &scriptedSequencer{codes: tt.codes}, tt.failRedial)
+
+			for i, want := range tt.codes {
+				_, err := p.client.GetStatus(context.Background(), &pb.GetStatusRequest{})
+				if got := status.Code(err); got != want {
+					t.Fatalf("call %d: code = %v, want %v", i, got, want)
+				}
+			}
+
+			if got := counterValue(t, resets); got != float64(tt.wantResets) {
+				t.Errorf("resets = %v, want %d", got, tt.wantResets)
+			}
+			wantDials := int32(1 + tt.wantResets)
+			if tt.failRedial {
+				wantDials = 2
+			}
+			if got := dials.Load(); got != wantDials {
+				t.Errorf("dials = %d, want %d", got, wantDials)
+			}
+			if tt.wantLog != "" && logs.FilterMessage(tt.wantLog).Len() != 1 {
+				t.Errorf("logs = %v, want one %q", logs.All(), tt.wantLog)
+			}
+			// The proxy keeps working on the new connection
+			if _, err := p.client.GetStatus(context.Background(), &pb.GetStatusRequest{}); err != nil {
+				t.Errorf("GetStatus() after the script error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGRPCProxy_ConcurrentFailuresResetOnce(t *testing.T) {
+	const callers = 4 * connResetThreshold
+	p, dials, resets, _ := resetProxy(t, &scriptedSequencer{codes: repeat(codes.Unavailable, callers)}, false)
+
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.client.GetStatus(context.Background(), &pb.GetStatusRequest{})
+		}()
+	}
+	wg.Wait()
+
+	if got := counterValue(t, resets); got != 1 {
+		t.Errorf("resets = %v, want 1", got)
+	}
+	if got := dials.Load(); got != 2 {
+		t.Errorf("dials = %d, want 2", got)
+	}
+}
+
+// histogramCounts reads the sample count of each method of the single histogram vector in registry
+func histogramCounts(t *testing.T, registry *prometheus.Registry) map[string]uint64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			counts[m.GetLabel()[0].GetValue()] = m.GetHistogram().GetSampleCount()
+		}
+	}
+	return counts
+}
+
+func TestGRPCProxy_CallLatency(t *testing.T) {
+	tests := []struct {
+		name string
+		code codes.Code
+		call func(ctx context.Context, c pb.SequencerServiceClient)
+		want map[string]uint64
+	}{
+		{
+			name: "status",
+			call: func(ctx context.Context, c pb.SequencerServiceClient) { c.GetStatus(ctx, &pb.GetStatusRequest{}) },
+			want: map[string]uint64{"GetStatus": 1},
+		},
+		{
+			name: "per method",
+			call: func(ctx context.Context, c pb.SequencerServiceClient) {
+				c.GetTick(ctx, &pb.GetTickRequest{TickNumber: 1})
+				c.GetTick(ctx, &pb.GetTickRequest{TickNumber: 2})
+				c.GetChainState(ctx, &pb.GetChainStateRequest{})
+			},
+			want: map[string]uint64{"GetTick": 2, "GetChainState": 1},
+		},
+		{
+			name: "failed calls are observed",
+			code: codes.Unavailable,
+			call: func(ctx context.Context, c pb.SequencerServiceClient) {
+				c.GetTransaction(ctx, &pb.GetTransactionRequest{})
+			},
+			want: map[string]uint64{"GetTransaction": 1},
+		},
+		{
+			name: "streams are not",
+			call: func(ctx context.Context, c pb.SequencerServiceClient) {
+				stream, err := c.StreamTicks(ctx, &pb.StreamTicksRequest{})
+				for err == nil {
+					_, err = stream.Recv()
+				}
+			},
+			want: map[string]uint64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "call_seconds"}, []string{"method"})
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(latency)
+
+			p, err := NewGRPCProxy(serveSequencer(t, &readSequencer{code: tt.code}), nil, "", nil, WithCallLatency(latency))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			tt.call(context.Background(), p.client)
+			if got := histogramCounts(t, registry); !maps.Equal(got, tt.want) {
+				t.Errorf("observed calls = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,609 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// fakeSequencer answers GetStatus, failing with statusErr when set
+type fakeSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	statusErr error
+}
+
+func (s *fakeSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	if s.statusErr != nil {
+		return nil, s.statusErr
+	}
+	return &pb.GetStatusResponse{}, nil
+}
+
+// serveSequencer serves srv on a local port and returns its address
+func serveSequencer(t *testing.T, srv pb.SequencerServiceServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterSequencerServiceServer(server, srv)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+// newTestGRPCProxy creates a proxy whose sequencer connection is never dialed
+// unless a handler calls it. repository may be nil
+func newTestGRPCProxy(t *testing.T, repository *database.Repository, opts ...GRPCProxyOption) *GRPCProxy {
+	t.Helper()
+	p, err := NewGRPCProxy("127.0.0.1:1", repository, "", nil, opts...)
+	if err != nil {
+		t.Fatalf("NewGRPCProxy() error = %v", err)
+	}
+	return p
+}
+
+func TestGRPCProxy_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		sequencer *fakeSequencer // nil = nothing listening
+		closed    bool
+		wantErr   string
+	}{
+		{"reachable", &fakeSequencer{}, false, ""},
+		{"dead address", nil, false, "grpc status check failed"},
+		{"status call fails", &fakeSequencer{statusErr: status.Error(codes.Internal, "boom")}, false, "grpc status check failed"},
+		{"closed", &fakeSequencer{}, true, "is SHUTDOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "127.0.0.1:1"
+			if tt.sequencer != nil {
+				target = serveSequencer(t, tt.sequencer)
+			}
+			p, err := NewGRPCProxy(target, nil, "", nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+			if tt.closed {
+				p.Close()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err = p.HealthCheck(ctx)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("HealthCheck() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("HealthCheck() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGRPCProxy_HealthCheckTransientFailure(t *testing.T) {
+	p := newTestGRPCProxy(t, nil)
+	defer p.Close()
+
+	// A failed call leaves the connection in TRANSIENT_FAILURE, which fails without another call
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	p.HealthCheck(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for p.conn.State() != connectivity.TransientFailure && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	expired, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := p.HealthCheck(expired)
+	if err == nil || !strings.Contains(err.Error(), "is TRANSIENT_FAILURE") {
+		t.Errorf("HealthCheck() error = %v, want the connection state", err)
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Errorf("HealthCheck() called the sequencer in TRANSIENT_FAILURE: %v", err)
+	}
+}
+
+func TestHandleGetTransactionStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		repository *database.Repository
+		wantStatus int
+		wantError  string
+	}{
+		{"method not allowed", http.MethodPost, "", database.NewRepository(nil), http.StatusMethodNotAllowed, "method not allowed"},
+		{"invalid bucket", http.MethodGet, "?bucket=2m", database.NewRepository(nil), http.StatusBadRequest, "invalid bucket"},
+		{"invalid from", http.MethodGet, "?from=yesterday", database.NewRepository(nil), http.StatusBadRequest, "invalid 'from'"},
+		{"invalid to", http.MethodGet, "?to=1700000000", database.NewRepository(nil), http.StatusBadRequest, "invalid 'to'"},
+		{"from after to", http.MethodGet, "?from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z", database.NewRepository(nil), http.StatusBadRequest, "'from' must be before 'to'"},
+		{"range over 7 days", http.MethodGet, "?from=2024-01-01T00:00:00Z&to=2024-01-08T00:00:01Z", database.NewRepository(nil), http.StatusBadRequest, "cannot exceed 7 days"},
+		{"range of exactly 7 days, DB unavailable", http.MethodGet, "?from=2024-01-01T00:00:00Z&to=2024-01-08T00:00:00Z", database.NewRepository(nil), http.StatusServiceUnavailable, "database not available"},
+		{"defaults, DB unavailable", http.MethodGet, "", database.NewRepository(nil), http.StatusServiceUnavailable, "database not available"},
+		{"valid bucket, no repository", http.MethodGet, "?bucket=1h", nil, http.StatusServiceUnavailable, "database not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestGRPCProxy(t, tt.repository)
+
+			rec := httptest.NewRecorder()
+			p.HandleGetTransactionStats()(rec, httptest.NewRequest(tt.method, "/api/v1/continuum/tx/stats"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantError) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantError)
+			}
+		})
+	}
+}
+
+// txUpstream is a REST API serving /tx/{hash} with status, counting requests
+// Requests block until release is closed
+type txUpstream struct {
+	*httptest.Server
+	status  atomic.Int32
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func newTxUpstream(t *testing.T, status int) *txUpstream {
+	t.Helper()
+	u := &txUpstream{release: make(chan struct{})}
+	u.status.Store(int32(status))
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.calls.Add(1)
+		<-u.release
+		status := int(u.status.Load())
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			fmt.Fprintf(w, `{"tx_hash":%q}`, strings.TrimPrefix(r.URL.Path, "/tx/"))
+		}
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+// txWaiters returns how many requests are waiting on in-flight tx lookups
+func txWaiters(p *GRPCProxy) int {
+	p.txLookups.mu.Lock()
+	defer p.txLookups.mu.Unlock()
+	waiters := 0
+	for _, call := range p.txLookups.calls {
+		waiters += call.waiters
+	}
+	return waiters
+}
+
+func TestHandleGetTransactionByHash_Coalesces(t *testing.T) {
+	tests := []struct {
+		name       string
+		hashes     []string // One concurrent request each
+		upstream   int
+		wantCalls  int32
+		wantStatus int
+	}{
+		{"same hash", []string{"abc1", "abc1", "abc1", "abc1", "abc1", "abc1", "abc1", "abc1"}, http.StatusOK, 1, http.StatusOK},
+		{"different hashes", []string{"abc1", "abc2", "abc3"}, http.StatusOK, 3, http.StatusOK},
+		{"mixed", []string{"abc1", "abc2", "abc1", "abc2"}, http.StatusOK, 2, http.StatusOK},
+		{"shared upstream error", []string{"abc1", "abc1", "abc1"}, http.StatusBadGateway, 1, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := newTxUpstream(t, tt.upstream)
+			p, err := NewGRPCProxy("127.0.0.1:1", nil, upstream.URL, nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			recs := make([]*httptest.ResponseRecorder, len(tt.hashes))
+			var wg sync.WaitGroup
+			for i, hash := range tt.hashes {
+				recs[i] = httptest.NewRecorder()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.HandleGetTransactionByHash()(recs[i], httptest.NewRequest(http.MethodGet, "/tx/"+hash, nil))
+				}()
+			}
+
+			// Answer only once every request is waiting on a lookup
+			deadline := time.Now().Add(5 * time.Second)
+			for txWaiters(p) < len(tt.hashes) && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			close(upstream.release)
+			wg.Wait()
+
+			if got := upstream.calls.Load(); got != tt.wantCalls {
+				t.Errorf("upstream calls = %d, want %d", got, tt.wantCalls)
+			}
+			for i, rec := range recs {
+				if rec.Code != tt.wantStatus {
+					t.Errorf("request %d: status = %d, want %d", i, rec.Code, tt.wantStatus)
+				}
+				if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), tt.hashes[i]) {
+					t.Errorf("request %d: body = %s, want transaction %s", i, rec.Body.String(), tt.hashes[i])
+				}
+			}
+		})
+	}
+}
+
+// submitSequencer accepts submissions, recording every transaction received
+type submitSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+
+	mu      sync.Mutex
+	txs     []*pb.Transaction
+	batches int
+}
+
+func (s *submitSequencer) SubmitTransaction(ctx context.Context, req *pb.SubmitTransactionRequest) (*pb.SubmitTransactionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs = append(s.txs, req.GetTransaction())
+	return &pb.SubmitTransactionResponse{SequenceNumber: uint64(len(s.txs)), TxHash: fmt.Sprintf("hash-%d", len(s.txs))}, nil
+}
+
+func (s *submitSequencer) SubmitBatch(ctx context.Context, req *pb.SubmitBatchRequest) (*pb.SubmitBatchResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches++
+	resp := &pb.SubmitBatchResponse{}
+	for _, tx := range req.GetTransactions() {
+		s.txs = append(s.txs, tx)
+		resp.Responses = append(resp.Responses, &pb.SubmitTransactionResponse{SequenceNumber: uint64(len(s.txs))})
+	}
+	return resp, nil
+}
+
+func (s *submitSequencer) received() []*pb.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.txs)
+}
+
+// submitProxy creates a proxy submitting to a fresh submitSequencer
+func submitProxy(t *testing.T, opts ...GRPCProxyOption) (*GRPCProxy, *submitSequencer) {
+	t.Helper()
+	sequencer := &submitSequencer{}
+	p, err := NewGRPCProxy(serveSequencer(t, sequencer), nil, "", nil, opts...)
+	if err != nil {
+		t.Fatalf("NewGRPCProxy() error = %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p, sequencer
+}
+
+// txJSON is a transaction in the frontend's format, timestamped now
+func txJSON(payload, signature string, nonce int) string {
+	return fmt.Sprintf(`{"tx_id":"tx-%d","payload":%s,"signature":%q,"public_key":"CRbNEfDGMKHiWcibuJCbxP6KKvdGEDm2EZE46cBX4kHa","nonce":%d,"timestamp":%d}`,
+		nonce, payload, signature, nonce, time.Now().UnixMicro())
+}
+
+func TestHandleSubmitBatch_Elements(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantIndex  int // For 400s
+		wantTxs    []*pb.Transaction
+	}{
+		{
+			name:       "array and base64 payloads",
+			body:       `{"transactions":[` + txJSON(`[70,82,77]`, "0xabcd", 1) + `,` + txJSON(`"RlJN"`, "abcd", 2) + `]}`,
+			wantStatus: http.StatusOK,
+			wantTxs: []*pb.Transaction{
+				{TxId: "tx-1", Payload: []byte("FRM"), Signature: []byte{0xab, 0xcd}, PublicKey: []byte("CRbNEfDGMKHiWcibuJCbxP6KKvdGEDm2EZE46cBX4kHa"), Nonce: 1},
+				{TxId: "tx-2", Payload: []byte("FRM"), Signature: []byte{0xab, 0xcd}, PublicKey: []byte("CRbNEfDGMKHiWcibuJCbxP6KKvdGEDm2EZE46cBX4kHa"), Nonce: 2},
+			},
+		},
+		{
+			name:       "bad signature in second element",
+			body:       `{"transactions":[` + txJSON(`[1]`, "abcd", 1) + `,` + txJSON(`[1]`, "zz", 2) + `]}`,
+			wantStatus: http.StatusBadRequest,
+			wantIndex:  1,
+		},
+		{
+			name:       "bad payload in first element",
+			body:       `{"transactions":[` + txJSON(`"not base64!"`, "abcd", 1) + `]}`,
+			wantStatus: http.StatusBadRequest,
+			wantIndex:  0,
+		},
+		{
+			name:       "non-numeric payload array",
+			body:       `{"transactions":[` + txJSON(`[1]`, "abcd", 1) + `,` + txJSON(`[1]`, "abcd", 2) + `,` + txJSON(`["a"]`, "abcd", 3) + `]}`,
+			wantStatus: http.StatusBadRequest,
+			wantIndex:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, sequencer := submitProxy(t)
+
+			rec := httptest.NewRecorder()
+			p.HandleSubmitBatch()(rec, httptest.NewRequest(http.MethodPost, "/tx/batch", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			got := sequencer.received()
+			if tt.wantStatus != http.StatusOK {
+				var body struct {
+					Error string `json:"error"`
+					Index *int   `json:"index"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("decode %s: %v", rec.Body.String(), err)
+				}
+				if body.Index == nil || *body.Index != tt.wantIndex || !strings.Contains(body.Error, fmt.Sprintf("at index %d", tt.wantIndex)) {
+					t.Errorf("body = %s, want index %d", rec.Body.String(), tt.wantIndex)
+				}
+				if len(got) != 0 {
+					t.Errorf("sequencer received %d transactions from a rejected batch", len(got))
+				}
+				return
+			}
+
+			if len(got) != len(tt.wantTxs) {
+				t.Fatalf("sequencer received %d transactions, want %d", len(got), len(tt.wantTxs))
+			}
+			for i, want := range tt.wantTxs {
+				got[i].Timestamp = 0
+				if !proto.Equal(got[i], want) {
+					t.Errorf("transaction %d = %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleSubmitBatch_InvalidJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not JSON", `transactions`},
+		{"transactions not an array", `{"transactions":{}}`},
+		{"element not an object", `{"transactions":[1]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, sequencer := submitProxy(t)
+
+			rec := httptest.NewRecorder()
+			p.HandleSubmitBatch()(rec, httptest.NewRequest(http.MethodPost, "/tx/batch", strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid request") {
+				t.Errorf("response = %d %s, want 400 invalid request", rec.Code, rec.Body.String())
+			}
+			if len(sequencer.received()) != 0 {
+				t.Error("sequencer called for an invalid batch")
+			}
+		})
+	}
+}
+
+func TestValidateTimestamp(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	micros := func(t time.Time) uint64 { return uint64(t.UnixMicro()) }
+
+	tests := []struct {
+		name      string
+		timestamp uint64
+		wantErr   string // Empty = valid
+	}{
+		{"now", micros(now), ""},
+		{"an hour ago", micros(now.Add(-time.Hour)), ""},
+		{"oldest allowed", micros(now.Add(-maxTimestampAge)), ""},
+		{"latest allowed", micros(now.Add(maxTimestampFuture)), ""},
+		{"too old", micros(now.Add(-maxTimestampAge - time.Second)), "in the past"},
+		{"too far ahead", micros(now.Add(maxTimestampFuture + time.Second)), "in the future"},
+		{"zero", 0, "in the past"},
+		{"milliseconds", uint64(now.UnixMilli()), "in the past"},
+		{"nanoseconds", uint64(now.UnixNano()), "in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimestamp(tt.timestamp, now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateTimestamp() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateTimestamp() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleSubmitTransaction_Timestamp(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		timestamp  string // JSON value, empty to omit
+		wantStatus int
+		wantError  string
+	}{
+		{"number", fmt.Sprint(now.UnixMicro()), http.StatusOK, ""},
+		{"string", fmt.Sprintf("%q", fmt.Sprint(now.UnixMicro())), http.StatusOK, ""},
+		{"missing", "", http.StatusOK, ""},
+		{"far future", fmt.Sprint(now.Add(time.Hour).UnixMicro()), http.StatusBadRequest, "in the future"},
+		{"zero", "0", http.StatusBadRequest, "in the past"},
+		{"seconds", fmt.Sprint(now.Unix()), http.StatusBadRequest, "expected microseconds"},
+		{"not a number", `"yesterday"`, http.StatusBadRequest, "invalid timestamp"},
+		{"wrong type", `true`, http.StatusBadRequest, "timestamp must be a string or number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, sequencer := submitProxy(t)
+
+			timestamp := ""
+			if tt.timestamp != "" {
+				timestamp = `,"timestamp":` + tt.timestamp
+			}
+			body := `{"transaction":{"payload":[1,2],"signature":"abcd","public_key":"abcd","nonce":1` + timestamp + `}}`
+			rec := httptest.NewRecorder()
+			p.HandleSubmitTransaction()(rec, httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if !strings.Contains(rec.Body.String(), tt.wantError) {
+					t.Errorf("body = %s, want %q", rec.Body.String(), tt.wantError)
+				}
+				if len(sequencer.received()) != 0 {
+					t.Error("sequencer received a rejected transaction")
+				}
+				return
+			}
+
+			got := sequencer.received()
+			if len(got) != 1 {
+				t.Fatalf("sequencer received %d transactions, want 1", len(got))
+			}
+			// Missing timestamps default to the server's time in microseconds
+			if err := validateTimestamp(got[0].GetTimestamp(), time.Now()); err != nil || got[0].GetTimestamp() < uint64(now.Add(-time.Second).UnixMicro()) {
+				t.Errorf("timestamp = %d, want about %d: %v", got[0].GetTimestamp(), now.UnixMicro(), err)
+			}
+		})
+	}
+}
+
+// streamSequencer streams ticks and ends the stream
+type streamSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	ticks []*pb.Tick
+}
+
+func (s *streamSequencer) StreamTicks(req *pb.StreamTicksRequest, stream pb.SequencerService_StreamTicksServer) error {
+	for _, tick := range s.ticks {
+		if err := stream.Send(tick); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sseEvents returns the data of each event in an SSE body
+func sseEvents(body string) []string {
+	var events []string
+	for _, line := range strings.Split(body, "\n") {
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	return events
+}
+
+func TestHandleStreamTicks_ProtoJSON(t *testing.T) {
+	ticks := []*pb.Tick{
+		{
+			TickNumber:           7,
+			VdfProof:             &pb.VdfProof{Input: "in", Output: "out", Iterations: 100},
+			Transactions:         []*pb.OrderedTransaction{{Transaction: &pb.Transaction{TxId: "tx-1", PublicKey: []byte{1, 2}}}},
+			TransactionBatchHash: "batch",
+			Timestamp:            1700000000000000,
+			PreviousOutput:       "prev",
+		},
+		{TickNumber: 8},
+	}
+
+	tests := []struct {
+		name       string
+		accept     string
+		wantFields []string
+		notFields  []string
+	}{
+		{
+			name:       "proto names",
+			wantFields: []string{`"tick_number":"7"`, `"vdf_proof":{`, `"transaction_batch_hash":"batch"`, `"previous_output":"prev"`, `"tx_id":"tx-1"`, `"public_key":"AQI="`},
+			notFields:  []string{"tickNumber", "vdfProof", "txId"},
+		},
+		{
+			name:       "camelCase profile",
+			accept:     "text/event-stream; profile=camelCase",
+			wantFields: []string{`"tickNumber":"7"`, `"vdfProof":{`, `"transactionBatchHash":"batch"`, `"txId":"tx-1"`},
+			notFields:  []string{"tick_number", "tx_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewGRPCProxy(serveSequencer(t, &streamSequencer{ticks: ticks}), nil, "", nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			req := httptest.NewRequest(http.MethodGet, "/stream-ticks", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			p.HandleStreamTicks()(rec, req)
+
+			events := sseEvents(rec.Body.String())
+			if len(events) != len(ticks) {
+				t.Fatalf("got %d events, want %d: %s", len(events), len(ticks), rec.Body.String())
+			}
+			for _, field := range tt.wantFields {
+				if !strings.Contains(events[0], field) {
+					t.Errorf("event %s missing %s", events[0], field)
+				}
+			}
+			for _, field := range tt.notFields {
+				if strings.Contains(events[0], field) {
+					t.Errorf("event %s has %s", events[0], field)
+				}
+			}
+			// Compact, so identical ticks always produce identical events
+			for _, event := range events {
+				if strings.Contains(event, ": ") || strings.Contains(event, ", ") {
+					t.Errorf("event %s is not compact", event)
+				}
+			}
+			if events[1] != `{"tick_number":"8"}` && events[1] != `{"tickNumber":"8"}` {
+				t.Errorf("event = %s, want only the tick number", events[1])
+			}
+		})
+	}
+}
@@ -9,17 +9,37 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/clientip"
 )
 
 // HTTPProxy handles HTTP reverse proxying to backend services
 type HTTPProxy struct {
-	target  string
-	timeout time.Duration
-	client  *http.Client
+	target   string
+	timeout  time.Duration
+	client   *http.Client
+	resolver *clientip.Resolver
+
+	stripPrefix string // Removed from the backend path after the gateway route prefix
+	addPrefix   string // Prepended to the backend path after stripPrefix
+}
+
+// HTTPProxyOption is a functional option for configuring HTTPProxy
+type HTTPProxyOption func(*HTTPProxy)
+
+// WithPathRewrite rewrites backend paths for backends that expect a different prefix:
+// strip is removed if the path starts with it, then add is prepended (either may be empty).
+// E.g. strip "/v1" and add "/api/v2" turns /api/v1/continuum/v1/blocks into /api/v2/blocks
+func WithPathRewrite(strip, add string) HTTPProxyOption {
+	return func(p *HTTPProxy) {
+		p.stripPrefix = strings.TrimSuffix(strip, "/")
+		p.addPrefix = strings.TrimSuffix(add, "/")
+	}
 }
 
 // NewHTTPProxy creates a new HTTP reverse proxy
-func NewHTTPProxy(targetURL string, timeout time.Duration) *HTTPProxy {
+// resolver determines the client IP forwarded in X-Forwarded-For (nil = direct peer only)
+func NewHTTPProxy(targetURL string, timeout time.Duration, resolver *clientip.Resolver, opts ...HTTPProxyOption) *HTTPProxy {
 	// Create HTTP client with connection pooling and timeout
 	client := &http.Client{
 		Timeout: timeout,
@@ -42,11 +62,18 @@ func NewHTTPProxy(targetURL string, timeout time.Duration) *HTTPProxy {
 		},
 	}
 
-	return &HTTPProxy{
-		target:  strings.TrimSuffix(targetURL, "/"),
-		timeout: timeout,
-		client:  client,
+	p := &HTTPProxy{
+		target:   strings.TrimSuffix(targetURL, "/"),
+		timeout:  timeout,
+		client:   client,
+		resolver: resolver,
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	return p
 }
 
 // Handler returns an http.Handler that proxies requests to the backend
@@ -58,6 +85,8 @@ func (p *HTTPProxy) Handler() http.Handler {
 
 // proxyRequest handles the actual proxying logic
 func (p *HTTPProxy) proxyRequest(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
 	// Build target URL
 	targetURL, err := url.Parse(p.target)
 	if err != nil {
@@ -79,7 +108,7 @@ func (p *HTTPProxy) proxyRequest(w http.ResponseWriter, r *http.Request) {
 		requestPath = strings.TrimPrefix(requestPath, "/api/v1/rollup")
 	}
 
-	targetURL.Path = basePath + requestPath
+	targetURL.Path = basePath + p.rewritePath(requestPath)
 	targetURL.RawQuery = r.URL.RawQuery
 
 	// Create new request to backend
@@ -93,13 +122,21 @@ func (p *HTTPProxy) proxyRequest(w http.ResponseWriter, r *http.Request) {
 	copyHeaders(proxyReq.Header, r.Header)
 
 	// Set/override important headers
-	proxyReq.Header.Set("X-Forwarded-For", getClientIP(r))
+	proxyReq.Header.Set("X-Forwarded-For", p.resolver.ClientIP(r))
 	proxyReq.Header.Set("X-Forwarded-Proto", getScheme(r))
 	proxyReq.Header.Set("X-Forwarded-Host", r.Host)
 
 	// Make request to backend
+	upstreamStart := time.Now()
 	resp, err := p.client.Do(proxyReq)
+	upstream := time.Since(upstreamStart) // Until response headers
 	if err != nil {
+		// The client disconnected; not a backend failure
+		if clientGone(r) {
+			writeClientGone(w)
+			return
+		}
+
 		// Check if it's a timeout error (and not a connection error)
 		if isTimeoutError(err) && !isConnectionError(err) {
 			http.Error(w, `{"error":"gateway timeout"}`, http.StatusGatewayTimeout)
@@ -112,14 +149,49 @@ func (p *HTTPProxy) proxyRequest(w http.ResponseWriter, r *http.Request) {
 	}
 	defer resp.Body.Close()
 
-	// Copy response headers
+	// Copy response headers, keeping the gateway's request and trace IDs (set by the
+	// RequestID middleware) and exposing a different upstream request ID separately
+	requestID := w.Header().Get("X-Request-ID")
+	traceID := w.Header().Get("X-Trace-ID")
+	upstreamRequestID := resp.Header.Get("X-Request-ID")
 	copyHeaders(w.Header(), resp.Header)
+	if requestID != "" {
+		w.Header().Set("X-Request-ID", requestID)
+		if upstreamRequestID != "" && upstreamRequestID != requestID {
+			w.Header().Set("X-Upstream-Request-ID", upstreamRequestID)
+		}
+	}
+	if traceID != "" {
+		w.Header().Set("X-Trace-ID", traceID)
+	}
+
+	// Upstream Server-Timing metrics were copied above; add the proxy hop
+	addServerTiming(w.Header(), "upstream", upstream)
+	addServerTiming(w.Header(), "gateway", time.Since(start)-upstream)
 
 	// Copy status code
 	w.WriteHeader(resp.StatusCode)
 
 	// Copy response body
 	io.Copy(w, resp.Body)
+
+	// Trailers are only known once the body has been read
+	for key, values := range resp.Trailer {
+		w.Header()[http.TrailerPrefix+key] = values
+	}
+}
+
+// addServerTiming appends a Server-Timing metric with d in milliseconds
+func addServerTiming(h http.Header, name string, d time.Duration) {
+	h.Add("Server-Timing", fmt.Sprintf("%s;dur=%.1f", name, float64(d.Microseconds())/1000))
+}
+
+// rewritePath applies the configured prefix rewrite to a path relative to the gateway route
+func (p *HTTPProxy) rewritePath(path string) string {
+	if p.stripPrefix != "" && (path == p.stripPrefix || strings.HasPrefix(path, p.stripPrefix+"/")) {
+		path = strings.TrimPrefix(path, p.stripPrefix)
+	}
+	return p.addPrefix + path
 }
 
 // copyHeaders copies HTTP headers from src to dst
@@ -157,23 +229,6 @@ func isHopByHopHeader(header string) bool {
 	return false
 }
 
-// getClientIP extracts the client IP from the request
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header first
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		return xff
-	}
-
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
-	}
-
-	// Fall back to RemoteAddr
-	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
-	return ip
-}
-
 // getScheme returns the request scheme (http or https)
 func getScheme(r *http.Request) string {
 	if r.TLS != nil {
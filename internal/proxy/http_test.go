@@ -0,0 +1,189 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPProxy_PathRewrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string // Backend URL path
+		strip    string
+		add      string
+		path     string // Requested through the gateway
+		wantPath string // Seen by the backend
+	}{
+		{"no rewrite", "", "", "", "/api/v1/continuum/ticks/recent", "/ticks/recent"},
+		{"rollup route", "", "", "", "/api/v1/rollup/markets", "/markets"},
+		{"strip", "", "/v1", "", "/api/v1/continuum/v1/blocks", "/blocks"},
+		{"strip the whole path", "", "/v1", "", "/api/v1/continuum/v1", "/"},
+		{"strip only whole segments", "", "/v1", "", "/api/v1/continuum/v10/blocks", "/v10/blocks"},
+		{"strip not matching", "", "/v1", "", "/api/v1/continuum/blocks", "/blocks"},
+		{"add", "", "", "/api/v2", "/api/v1/continuum/blocks", "/api/v2/blocks"},
+		{"strip and add", "", "/v1", "/api/v2", "/api/v1/continuum/v1/blocks", "/api/v2/blocks"},
+		{"trailing slashes", "", "/v1/", "/api/v2/", "/api/v1/continuum/v1/blocks", "/api/v2/blocks"},
+		{"after the backend base path", "/rest", "/v1", "/v2", "/api/v1/continuum/v1/blocks", "/rest/v2/blocks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotQuery string
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+			}))
+			defer backend.Close()
+
+			p := NewHTTPProxy(backend.URL+tt.base, time.Minute, nil, WithPathRewrite(tt.strip, tt.add))
+			rec := httptest.NewRecorder()
+			p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path+"?limit=5", nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("backend path = %q, want %q", gotPath, tt.wantPath)
+			}
+			if gotQuery != "limit=5" {
+				t.Errorf("backend query = %q, want limit=5", gotQuery)
+			}
+		})
+	}
+}
+
+// serverTimings returns the Server-Timing metric names and durations in milliseconds
+func serverTimings(t *testing.T, h http.Header) ([]string, map[string]float64) {
+	t.Helper()
+	var names []string
+	durs := make(map[string]float64)
+	for _, value := range h.Values("Server-Timing") {
+		name, dur, _ := strings.Cut(value, ";dur=")
+		ms, err := strconv.ParseFloat(dur, 64)
+		if err != nil {
+			t.Fatalf("Server-Timing %q: %v", value, err)
+		}
+		names = append(names, name)
+		durs[name] = ms
+	}
+	return names, durs
+}
+
+func TestHTTPProxy_ResponseHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string            // Set by the gateway's RequestID middleware
+		traceID     string            // Likewise
+		upstream    map[string]string // Response headers set by the backend
+		delay       time.Duration     // Before the backend responds
+		trailer     string            // Sent by the backend as X-Checksum
+		wantTimings []string
+		wantHeaders map[string]string // Empty = absent
+	}{
+		{
+			name:        "proxy hop timing",
+			wantTimings: []string{"upstream", "gateway"},
+		},
+		{
+			name:        "upstream timings kept",
+			upstream:    map[string]string{"Server-Timing": "db;dur=12.5"},
+			wantTimings: []string{"db", "upstream", "gateway"},
+		},
+		{
+			name:        "slow upstream",
+			delay:       20 * time.Millisecond,
+			wantTimings: []string{"upstream", "gateway"},
+		},
+		{
+			name:        "gateway request ID kept",
+			requestID:   "gw-1",
+			upstream:    map[string]string{"X-Request-ID": "up-1"},
+			wantTimings: []string{"upstream", "gateway"},
+			wantHeaders: map[string]string{"X-Request-ID": "gw-1", "X-Upstream-Request-ID": "up-1"},
+		},
+		{
+			name:        "same request ID",
+			requestID:   "gw-1",
+			upstream:    map[string]string{"X-Request-ID": "gw-1"},
+			wantTimings: []string{"upstream", "gateway"},
+			wantHeaders: map[string]string{"X-Request-ID": "gw-1", "X-Upstream-Request-ID": ""},
+		},
+		{
+			name:        "upstream request ID without a gateway one",
+			upstream:    map[string]string{"X-Request-ID": "up-1"},
+			wantTimings: []string{"upstream", "gateway"},
+			wantHeaders: map[string]string{"X-Request-ID": "up-1", "X-Upstream-Request-ID": ""},
+		},
+		{
+			name:        "gateway trace ID kept",
+			requestID:   "gw-1",
+			traceID:     "4bf92f3577b34da6a3ce929d0e0e4736",
+			upstream:    map[string]string{"X-Trace-ID": "up-trace"},
+			wantTimings: []string{"upstream", "gateway"},
+			wantHeaders: map[string]string{"X-Request-ID": "gw-1", "X-Trace-ID": "4bf92f3577b34da6a3ce929d0e0e4736"},
+		},
+		{
+			name:        "hop-by-hop headers dropped",
+			upstream:    map[string]string{"Keep-Alive": "timeout=5", "X-Backend": "rest-1"},
+			wantTimings: []string{"upstream", "gateway"},
+			wantHeaders: map[string]string{"Keep-Alive": "", "X-Backend": "rest-1"},
+		},
+		{
+			name:        "trailers forwarded",
+			trailer:     "abc123",
+			wantTimings: []string{"upstream", "gateway"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+				for key, value := range tt.upstream {
+					w.Header().Set(key, value)
+				}
+				if tt.trailer != "" {
+					w.Header().Set("Trailer", "X-Checksum")
+				}
+				w.Write([]byte(`{"ok":true}`))
+				if tt.trailer != "" {
+					w.Header().Set("X-Checksum", tt.trailer)
+				}
+			}))
+			defer backend.Close()
+
+			rec := httptest.NewRecorder()
+			if tt.requestID != "" {
+				rec.Header().Set("X-Request-ID", tt.requestID)
+			}
+			if tt.traceID != "" {
+				rec.Header().Set("X-Trace-ID", tt.traceID)
+			}
+			NewHTTPProxy(backend.URL, time.Minute, nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/continuum/status", nil))
+			resp := rec.Result()
+
+			if resp.StatusCode != http.StatusOK || rec.Body.String() != `{"ok":true}` {
+				t.Fatalf("response = %d %s, want 200 with the backend body", resp.StatusCode, rec.Body.String())
+			}
+			names, durs := serverTimings(t, resp.Header)
+			if !slices.Equal(names, tt.wantTimings) {
+				t.Errorf("Server-Timing metrics = %v, want %v", names, tt.wantTimings)
+			}
+			if durs["upstream"] < float64(tt.delay.Milliseconds()) || durs["gateway"] < 0 {
+				t.Errorf("Server-Timing durations = %v, want upstream of at least %s", durs, tt.delay)
+			}
+			for key, want := range tt.wantHeaders {
+				if got := resp.Header.Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+			if got := resp.Trailer.Get("X-Checksum"); got != tt.trailer {
+				t.Errorf("X-Checksum trailer = %q, want %q", got, tt.trailer)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSONStyle selects the field names used when marshaling protobuf responses
+type JSONStyle string
+
+const (
+	JSONStyleSnake JSONStyle = "snake_case" // Proto field names, e.g. tick_number
+	JSONStyleCamel JSONStyle = "camelCase"  // JSON names, e.g. tickNumber
+)
+
+// WithJSONStyle sets the default field-name style for protobuf responses (default snake_case).
+// Clients can override it per request with an Accept profile, e.g. "application/json; profile=camelCase"
+func WithJSONStyle(style JSONStyle) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.jsonStyle = style
+	}
+}
+
+// requestJSONStyle returns the style requested via the Accept profile parameter, or the default
+func (p *GRPCProxy) requestJSONStyle(r *http.Request) JSONStyle {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch style := JSONStyle(params["profile"]); style {
+		case JSONStyleSnake, JSONStyleCamel:
+			return style
+		}
+	}
+	return p.jsonStyle
+}
+
+// protoMarshaler returns protojson options for the request's field-name style
+func (p *GRPCProxy) protoMarshaler(r *http.Request) protojson.MarshalOptions {
+	return protojson.MarshalOptions{
+		UseProtoNames: p.requestJSONStyle(r) == JSONStyleSnake,
+	}
+}
+
+// writeProtoJSON writes msg with protojson in the request's field-name style
+func (p *GRPCProxy) writeProtoJSON(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	jsonBytes, err := p.protoMarshaler(r).Marshal(msg)
+	if err != nil {
+		p.logger.Warn("Failed to marshal response", zap.Error(err))
+		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	w.Write(jsonBytes)
+}
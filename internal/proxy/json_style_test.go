@@ -0,0 +1,81 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGRPCProxy_RequestJSONStyle(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultStyle JSONStyle
+		accept       string
+		want         JSONStyle
+	}{
+		{"default", "", "", JSONStyleSnake},
+		{"configured camelCase", JSONStyleCamel, "", JSONStyleCamel},
+		{"profile camelCase", JSONStyleSnake, "application/json; profile=camelCase", JSONStyleCamel},
+		{"profile snake_case", JSONStyleCamel, "application/json; profile=snake_case", JSONStyleSnake},
+		{"quoted profile", JSONStyleSnake, `application/json; profile="camelCase"`, JSONStyleCamel},
+		{"profile in second type", JSONStyleSnake, "text/html, application/json;profile=camelCase", JSONStyleCamel},
+		{"unknown profile", JSONStyleCamel, "application/json; profile=kebab-case", JSONStyleCamel},
+		{"plain JSON", JSONStyleCamel, "application/json", JSONStyleCamel},
+		{"malformed", JSONStyleSnake, "application/json; profile", JSONStyleSnake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []GRPCProxyOption
+			if tt.defaultStyle != "" {
+				opts = append(opts, WithJSONStyle(tt.defaultStyle))
+			}
+			p := newTestGRPCProxy(t, nil, opts...)
+			defer p.Close()
+
+			req := httptest.NewRequest(http.MethodGet, "/status", nil)
+			req.Header.Set("Accept", tt.accept)
+			if got := p.requestJSONStyle(req); got != tt.want {
+				t.Errorf("requestJSONStyle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleGetStatus_JSONStyle(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     JSONStyle
+		accept    string
+		wantField string
+		otherName string
+	}{
+		{"snake_case", JSONStyleSnake, "", `"current_tick":"42"`, "currentTick"},
+		{"camelCase", JSONStyleCamel, "", `"currentTick":"42"`, "current_tick"},
+		{"camelCase per request", JSONStyleSnake, "application/json; profile=camelCase", `"currentTick":"42"`, "current_tick"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewGRPCProxy(serveSequencer(t, &readSequencer{}), nil, "", nil, WithJSONStyle(tt.style))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			req := httptest.NewRequest(http.MethodGet, "/status", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			p.HandleGetStatus()(rec, req)
+
+			body := rec.Body.String()
+			if rec.Code != http.StatusOK || !strings.Contains(body, tt.wantField) || strings.Contains(body, tt.otherName) {
+				t.Errorf("response = %d %s, want %s only", rec.Code, body, tt.wantField)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary = %q, want Accept", got)
+			}
+		})
+	}
+}
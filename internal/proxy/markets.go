@@ -0,0 +1,223 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+// MarketMetadata describes a market beyond what market_prices records
+type MarketMetadata struct {
+	Symbol   string // e.g. "BTC-USDC"
+	Decimals *int   // Price decimals shown for the market (nil = unknown)
+}
+
+// marketResponse is one entry of the markets endpoint
+type marketResponse struct {
+	MarketID    string    `json:"market_id"`
+	Symbol      string    `json:"symbol,omitempty"`
+	Decimals    *int      `json:"decimals,omitempty"`
+	LastPrice   float64   `json:"last_price"` // USDC, like candle prices
+	LastPriceAt time.Time `json:"last_price_at"`
+}
+
+// marketStore is the part of the database repository markets are read from
+type marketStore interface {
+	Available() bool
+	ListMarkets(ctx context.Context) ([]database.Market, error)
+	GetPriceAt(ctx context.Context, marketID string, at time.Time) (*database.MarketPrice, error)
+}
+
+// MarketsHandler lists the markets that have prices, for market selectors
+type MarketsHandler struct {
+	repository marketStore
+	logger     *zap.Logger
+	metadata   map[string]MarketMetadata // By market ID
+}
+
+// MarketsOption is a functional option for configuring MarketsHandler
+type MarketsOption func(*MarketsHandler)
+
+// WithMarketMetadata adds symbols and decimals to the listed markets, by market ID
+// Markets without metadata are still listed. IDs are matched case-insensitively,
+// as the database returns lowercase UUIDs
+func WithMarketMetadata(metadata map[string]MarketMetadata) MarketsOption {
+	return func(h *MarketsHandler) {
+		h.metadata = make(map[string]MarketMetadata, len(metadata))
+		for market, meta := range metadata {
+			h.metadata[strings.ToLower(market)] = meta
+		}
+	}
+}
+
+// NewMarketsHandler creates a new markets handler
+func NewMarketsHandler(repository *database.Repository, logger *zap.Logger, opts ...MarketsOption) *MarketsHandler {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	// A nil *Repository must stay a nil marketStore, so "no database" is still detected
+	h := &MarketsHandler{logger: logger}
+	if repository != nil {
+		h.repository = repository
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// ListMarkets handles GET /api/v1/rollup/markets
+func (h *MarketsHandler) ListMarkets() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		if h.repository == nil || !h.repository.Available() {
+			writeMarketsError(w, http.StatusInternalServerError, "Database not available")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		markets, err := h.repository.ListMarkets(ctx)
+		if err != nil && clientGone(r) {
+			writeClientGone(w)
+			return
+		}
+		if err != nil {
+			h.logger.Warn("Failed to list markets", zap.Error(err))
+			writeMarketsError(w, http.StatusInternalServerError, "Failed to list markets")
+			return
+		}
+
+		response := make([]marketResponse, len(markets))
+		for i, market := range markets {
+			meta := h.metadata[market.MarketID]
+			response[i] = marketResponse{
+				MarketID:    market.MarketID,
+				Symbol:      meta.Symbol,
+				Decimals:    meta.Decimals,
+				LastPrice:   market.LastPrice / 1000000.0, // USDC micro-units to USDC
+				LastPriceAt: market.LastPriceAt,
+			}
+		}
+
+		// New markets are rare; a short cache keeps selectors from hammering the database
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=30")
+		w.Header().Set("X-Data-Source", "database")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"markets": response,
+		})
+	}
+}
+
+// priceAtCacheAge is how old a requested time must be before its price can be cached;
+// prices for more recent times may still be ingested
+const priceAtCacheAge = time.Minute
+
+// GetPriceAt handles GET /api/v1/rollup/markets/{marketId}/price?at=
+// It returns the most recent price at or before at, given as RFC3339 or Unix milliseconds
+func (h *MarketsHandler) GetPriceAt() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		marketID := chi.URLParam(r, "marketId")
+		if !marketIDPattern.MatchString(marketID) {
+			writeMarketsError(w, http.StatusBadRequest, "Invalid market ID (expected a UUID)")
+			return
+		}
+
+		atStr := r.URL.Query().Get("at")
+		if atStr == "" {
+			writeMarketsError(w, http.StatusBadRequest, "Missing 'at' parameter")
+			return
+		}
+		at, ok := parsePriceTime(atStr)
+		if !ok {
+			writeMarketsError(w, http.StatusBadRequest, "Invalid 'at' format. Use RFC3339 (e.g., 2024-01-01T00:00:00Z) or Unix milliseconds")
+			return
+		}
+		now := time.Now()
+		if at.After(now) {
+			writeMarketsError(w, http.StatusBadRequest, "'at' must not be in the future")
+			return
+		}
+
+		if h.repository == nil || !h.repository.Available() {
+			writeMarketsError(w, http.StatusInternalServerError, "Database not available")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		price, err := h.repository.GetPriceAt(ctx, marketID, at)
+		if err != nil && clientGone(r) {
+			writeClientGone(w)
+			return
+		}
+		if err != nil {
+			h.logger.Warn("Failed to get market price", zap.Error(err))
+			writeMarketsError(w, http.StatusInternalServerError, "Failed to get market price")
+			return
+		}
+		if price == nil {
+			writeMarketsError(w, http.StatusNotFound, "No price for market at or before 'at'")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if now.Sub(at) > priceAtCacheAge {
+			w.Header().Set("Cache-Control", "public, max-age=300")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		w.Header().Set("X-Data-Source", "database")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"market_id": price.MarketID,
+			"price":     price.Price / 1000000.0, // USDC micro-units to USDC
+			"ts":        price.Timestamp,
+			"at":        at.UTC(),
+		})
+	}
+}
+
+// parsePriceTime parses an RFC3339 timestamp or Unix milliseconds
+func parsePriceTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil && ms >= 0 {
+		return time.UnixMilli(ms).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// writeMarketsError writes an error response in the same format as the candles endpoint
+func writeMarketsError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":       nil,
+		"statusCode": statusCode,
+		"error":      message,
+	})
+}
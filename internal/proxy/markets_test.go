@@ -0,0 +1,256 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+// fakeMarketStore serves fixed markets and prices
+type fakeMarketStore struct {
+	unavailable bool
+	err         error
+	markets     []database.Market
+	price       *database.MarketPrice
+
+	gotMarket string
+	gotAt     time.Time
+}
+
+func (s *fakeMarketStore) Available() bool { return !s.unavailable }
+
+func (s *fakeMarketStore) ListMarkets(ctx context.Context) ([]database.Market, error) {
+	return s.markets, s.err
+}
+
+func (s *fakeMarketStore) GetPriceAt(ctx context.Context, marketID string, at time.Time) (*database.MarketPrice, error) {
+	s.gotMarket, s.gotAt = marketID, at
+	return s.price, s.err
+}
+
+func TestListMarkets(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	two, six := 2, 6
+	markets := []database.Market{
+		{MarketID: marketA, LastPrice: 163885020, LastPriceAt: ts},
+		{MarketID: marketB, LastPrice: 1500000, LastPriceAt: ts.Add(time.Minute)},
+	}
+
+	tests := []struct {
+		name       string
+		store      *fakeMarketStore
+		metadata   map[string]MarketMetadata
+		wantStatus int
+		want       []marketResponse
+	}{
+		{
+			name:       "without metadata",
+			store:      &fakeMarketStore{markets: markets},
+			wantStatus: http.StatusOK,
+			want: []marketResponse{
+				{MarketID: marketA, LastPrice: 163.88502, LastPriceAt: ts},
+				{MarketID: marketB, LastPrice: 1.5, LastPriceAt: ts.Add(time.Minute)},
+			},
+		},
+		{
+			name:  "with metadata",
+			store: &fakeMarketStore{markets: markets},
+			metadata: map[string]MarketMetadata{
+				marketA:                                {Symbol: "BTC-USDC", Decimals: &two},
+				marketB:                                {Decimals: &six},
+				"33333333-3333-3333-3333-333333333333": {Symbol: "SOL-USDC"}, // No prices, not listed
+			},
+			wantStatus: http.StatusOK,
+			want: []marketResponse{
+				{MarketID: marketA, Symbol: "BTC-USDC", Decimals: &two, LastPrice: 163.88502, LastPriceAt: ts},
+				{MarketID: marketB, Decimals: &six, LastPrice: 1.5, LastPriceAt: ts.Add(time.Minute)},
+			},
+		},
+		{
+			name:       "uppercase metadata ids",
+			store:      &fakeMarketStore{markets: []database.Market{{MarketID: "aaaaaaaa-0000-0000-0000-00000000000a", LastPrice: 2000000, LastPriceAt: ts}}},
+			metadata:   map[string]MarketMetadata{"AAAAAAAA-0000-0000-0000-00000000000A": {Symbol: "ETH-USDC"}},
+			wantStatus: http.StatusOK,
+			want:       []marketResponse{{MarketID: "aaaaaaaa-0000-0000-0000-00000000000a", Symbol: "ETH-USDC", LastPrice: 2, LastPriceAt: ts}},
+		},
+		{
+			name:       "no markets",
+			store:      &fakeMarketStore{},
+			wantStatus: http.StatusOK,
+			want:       []marketResponse{},
+		},
+		{
+			name:       "query fails",
+			store:      &fakeMarketStore{err: errors.New("connection reset")},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "database unavailable",
+			store:      &fakeMarketStore{unavailable: true, markets: markets},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewMarketsHandler(nil, zap.NewNop(), WithMarketMetadata(tt.metadata))
+			h.repository = tt.store
+
+			rec := httptest.NewRecorder()
+			h.ListMarkets()(rec, httptest.NewRequest(http.MethodGet, "/markets", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "public, max-age=30" {
+				t.Errorf("Cache-Control = %q, want public, max-age=30", got)
+			}
+
+			var body struct {
+				Markets []marketResponse `json:"markets"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if body.Markets == nil {
+				t.Fatalf("markets = null, want a list: %s", rec.Body.String())
+			}
+			if len(body.Markets) != len(tt.want) {
+				t.Fatalf("markets = %+v, want %+v", body.Markets, tt.want)
+			}
+			for i, got := range body.Markets {
+				want := tt.want[i]
+				if got.MarketID != want.MarketID || got.Symbol != want.Symbol || got.LastPrice != want.LastPrice ||
+					!got.LastPriceAt.Equal(want.LastPriceAt) || !equalDecimals(got.Decimals, want.Decimals) {
+					t.Errorf("market %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func equalDecimals(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestListMarkets_WithoutDatabase(t *testing.T) {
+	h := NewMarketsHandler(nil, nil)
+
+	rec := httptest.NewRecorder()
+	h.ListMarkets()(rec, httptest.NewRequest(http.MethodGet, "/markets", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestParsePriceTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		want   time.Time
+		wantOK bool
+	}{
+		{"RFC3339", "2024-01-01T10:00:00Z", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), true},
+		{"RFC3339 with offset", "2024-01-01T12:00:00+02:00", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), true},
+		{"RFC3339 with fraction", "2024-01-01T10:00:00.5Z", time.Date(2024, 1, 1, 10, 0, 0, 500000000, time.UTC), true},
+		{"Unix milliseconds", "1704103200000", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), true},
+		{"Unix epoch", "0", time.Unix(0, 0), true},
+		{"negative milliseconds", "-1", time.Time{}, false},
+		{"date only", "2024-01-01", time.Time{}, false},
+		{"garbage", "yesterday", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parsePriceTime(tt.s)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("parsePriceTime(%q) = %v, %v; want %v, %v", tt.s, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetPriceAt(t *testing.T) {
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	recent := time.Now().Add(-time.Second).UTC().Truncate(time.Second)
+	price := &database.MarketPrice{MarketID: marketA, Price: 163885020, Timestamp: at.Add(-time.Minute)}
+
+	tests := []struct {
+		name       string
+		market     string
+		at         string
+		store      *fakeMarketStore
+		wantStatus int
+		wantAt     time.Time // Time the store was queried at
+		wantCache  string
+	}{
+		{"RFC3339", marketA, "2024-01-01T10:00:00Z", &fakeMarketStore{price: price}, http.StatusOK, at, "public, max-age=300"},
+		{"Unix milliseconds", marketA, strconv.FormatInt(at.UnixMilli(), 10), &fakeMarketStore{price: price}, http.StatusOK, at, "public, max-age=300"},
+		{"recent time is not cached", marketA, recent.Format(time.RFC3339), &fakeMarketStore{price: price}, http.StatusOK, recent, "no-cache"},
+		{"no price by then", marketA, "2024-01-01T10:00:00Z", &fakeMarketStore{}, http.StatusNotFound, at, ""},
+		{"query fails", marketA, "2024-01-01T10:00:00Z", &fakeMarketStore{err: errors.New("connection reset")}, http.StatusInternalServerError, at, ""},
+		{"database unavailable", marketA, "2024-01-01T10:00:00Z", &fakeMarketStore{unavailable: true}, http.StatusInternalServerError, time.Time{}, ""},
+		{"invalid market id", "BTC-USDC", "2024-01-01T10:00:00Z", &fakeMarketStore{price: price}, http.StatusBadRequest, time.Time{}, ""},
+		{"missing at", marketA, "", &fakeMarketStore{price: price}, http.StatusBadRequest, time.Time{}, ""},
+		{"invalid at", marketA, "yesterday", &fakeMarketStore{price: price}, http.StatusBadRequest, time.Time{}, ""},
+		{"future at", marketA, time.Now().Add(time.Hour).Format(time.RFC3339), &fakeMarketStore{price: price}, http.StatusBadRequest, time.Time{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewMarketsHandler(nil, zap.NewNop())
+			h.repository = tt.store
+			router := chi.NewRouter()
+			router.Get("/markets/{marketId}/price", h.GetPriceAt())
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/markets/"+tt.market+"/price?at="+tt.at, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !tt.store.gotAt.Equal(tt.wantAt) {
+				t.Errorf("queried at %v, want %v", tt.store.gotAt, tt.wantAt)
+			}
+			if !tt.wantAt.IsZero() && tt.store.gotMarket != tt.market {
+				t.Errorf("queried market %q, want %q", tt.store.gotMarket, tt.market)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.wantCache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCache)
+			}
+
+			var body struct {
+				MarketID string    `json:"market_id"`
+				Price    float64   `json:"price"`
+				Ts       time.Time `json:"ts"`
+				At       time.Time `json:"at"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if body.MarketID != marketA || body.Price != 163.88502 || !body.Ts.Equal(price.Timestamp) || !body.At.Equal(tt.wantAt) {
+				t.Errorf("body = %+v, want %s at 163.88502 from %v, asked at %v", body, marketA, price.Timestamp, tt.wantAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// Mempool listing limits
+const (
+	defaultMempoolLimit = 100
+	maxMempoolLimit     = 1000
+)
+
+// PendingLister lists transactions accepted by the sequencer but not yet included in a tick
+// The sequencer API has no such call yet (it needs a GetPending RPC in continuum.proto),
+// so until one exists the mempool endpoint only reports the pending count from GetStatus
+type PendingLister interface {
+	ListPending(ctx context.Context, limit int) ([]*pb.Transaction, error)
+}
+
+// WithPendingLister sets where the mempool endpoint lists pending transactions from
+func WithPendingLister(lister PendingLister) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.pending = lister
+	}
+}
+
+// mempoolResponse is the body of the mempool endpoint
+type mempoolResponse struct {
+	PendingCount uint64            `json:"pending_count"`
+	Transactions []json.RawMessage `json:"transactions"`        // protojson, in the request's field style
+	Listed       bool              `json:"transactions_listed"` // False when the sequencer can't list pending transactions
+}
+
+// HandleGetMempool handles GET /api/v1/continuum/mempool?limit=100
+func (p *GRPCProxy) HandleGetMempool() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit := defaultMempoolLimit
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed < 1 || parsed > maxMempoolLimit {
+				http.Error(w, fmt.Sprintf(`{"error":"invalid limit (must be 1-%d)"}`, maxMempoolLimit), http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		status, err := p.client.GetStatus(ctx, &pb.GetStatusRequest{})
+		if err != nil {
+			p.writeGRPCError(w, r, err)
+			return
+		}
+
+		resp := mempoolResponse{
+			PendingCount: status.PendingTransactions,
+			Transactions: []json.RawMessage{},
+		}
+
+		if p.pending != nil {
+			txs, err := p.pending.ListPending(ctx, limit)
+			if err != nil {
+				p.writeGRPCError(w, r, err)
+				return
+			}
+
+			marshaler := p.protoMarshaler(r)
+			for _, tx := range txs {
+				txJSON, err := marshaler.Marshal(tx)
+				if err != nil {
+					p.logger.Warn("Failed to marshal pending transaction", zap.Error(err))
+					http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+					return
+				}
+				resp.Transactions = append(resp.Transactions, txJSON)
+			}
+			resp.Listed = true
+		}
+
+		// The mempool changes every tick; let explorers poll without hammering the sequencer
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=1")
+		w.Header().Set("X-Data-Source", "grpc")
+		w.Header().Add("Vary", "Accept")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// pendingSequencer reports pending transactions in GetStatus, or fails with code
+type pendingSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	pending uint64
+	code    codes.Code
+}
+
+func (s *pendingSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	if s.code != codes.OK {
+		return nil, status.Error(s.code, "sequencer down")
+	}
+	return &pb.GetStatusResponse{PendingTransactions: s.pending}, nil
+}
+
+// fakePendingLister lists fixed pending transactions and records the limit asked for
+type fakePendingLister struct {
+	txs      []*pb.Transaction
+	err      error
+	gotLimit int
+}
+
+func (l *fakePendingLister) ListPending(ctx context.Context, limit int) ([]*pb.Transaction, error) {
+	l.gotLimit = limit
+	return l.txs, l.err
+}
+
+func TestHandleGetMempool(t *testing.T) {
+	pending := []*pb.Transaction{{TxId: "tx-1", Nonce: 1}, {TxId: "tx-2", Nonce: 2}}
+
+	tests := []struct {
+		name       string
+		query      string
+		accept     string
+		lister     *fakePendingLister
+		code       codes.Code
+		wantStatus int
+		wantTxIDs  []string
+		wantListed bool
+		wantLimit  int
+		wantField  string // Transaction ID field name
+	}{
+		{"count only", "", "", nil, codes.OK, http.StatusOK, nil, false, 0, ""},
+		{"listed", "", "", &fakePendingLister{txs: pending}, codes.OK, http.StatusOK, []string{"tx-1", "tx-2"}, true, defaultMempoolLimit, "tx_id"},
+		{"limit", "limit=5", "", &fakePendingLister{txs: pending[:1]}, codes.OK, http.StatusOK, []string{"tx-1"}, true, 5, "tx_id"},
+		{"camelCase", "", "application/json; profile=camelCase", &fakePendingLister{txs: pending}, codes.OK, http.StatusOK, []string{"tx-1", "tx-2"}, true, defaultMempoolLimit, "txId"},
+		{"nothing pending", "", "", &fakePendingLister{}, codes.OK, http.StatusOK, nil, true, defaultMempoolLimit, "tx_id"},
+		{"limit too low", "limit=0", "", nil, codes.OK, http.StatusBadRequest, nil, false, 0, ""},
+		{"limit too high", "limit=1001", "", nil, codes.OK, http.StatusBadRequest, nil, false, 0, ""},
+		{"listing failed", "", "", &fakePendingLister{err: errors.New("boom")}, codes.OK, http.StatusInternalServerError, nil, false, defaultMempoolLimit, ""},
+		{"sequencer down", "", "", nil, codes.Unavailable, http.StatusInternalServerError, nil, false, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []GRPCProxyOption
+			if tt.lister != nil {
+				opts = append(opts, WithPendingLister(tt.lister))
+			}
+			p, err := NewGRPCProxy(serveSequencer(t, &pendingSequencer{pending: 7, code: tt.code}), nil, "", nil, opts...)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			req := httptest.NewRequest(http.MethodGet, "/mempool?"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			p.HandleGetMempool()(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.lister != nil && tt.lister.gotLimit != tt.wantLimit {
+				t.Errorf("ListPending limit = %d, want %d", tt.lister.gotLimit, tt.wantLimit)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "public, max-age=1" {
+				t.Errorf("Cache-Control = %q, want public, max-age=1", got)
+			}
+
+			var body struct {
+				PendingCount uint64                       `json:"pending_count"`
+				Transactions []map[string]json.RawMessage `json:"transactions"`
+				Listed       bool                         `json:"transactions_listed"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if body.PendingCount != 7 || body.Listed != tt.wantListed {
+				t.Errorf("pending_count = %d, listed = %v; want 7, %v", body.PendingCount, body.Listed, tt.wantListed)
+			}
+			if body.Transactions == nil || len(body.Transactions) != len(tt.wantTxIDs) {
+				t.Fatalf("transactions = %v, want %d (never null)", body.Transactions, len(tt.wantTxIDs))
+			}
+			for i, tx := range body.Transactions {
+				if got := strings.Trim(string(tx[tt.wantField]), `"`); got != tt.wantTxIDs[i] {
+					t.Errorf("transaction %d %s = %q, want %q", i, tt.wantField, got, tt.wantTxIDs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// largeChainSequencer answers GetChainState with a tick whose proof is size bytes
+type largeChainSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	size int
+}
+
+func (s *largeChainSequencer) GetChainState(ctx context.Context, req *pb.GetChainStateRequest) (*pb.GetChainStateResponse, error) {
+	return &pb.GetChainStateResponse{
+		RecentTicks: []*pb.Tick{{TickNumber: 1, VdfProof: &pb.VdfProof{Proof: strings.Repeat("a", s.size)}}},
+	}, nil
+}
+
+func TestGRPCProxy_MaxMessageSize(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name       string
+		response   int // Size of the sequencer's response, roughly
+		opts       []GRPCProxyOption
+		wantStatus int
+	}{
+		{"default limit", 2 * mb, nil, http.StatusOK},
+		{"over the default limit", 12 * mb, nil, http.StatusInternalServerError},
+		{"raised receive limit", 12 * mb, []GRPCProxyOption{WithMaxMessageSize(100*mb, defaultMaxMsgSize)}, http.StatusOK},
+		{"lowered receive limit", 2 * mb, []GRPCProxyOption{WithMaxMessageSize(mb, defaultMaxMsgSize)}, http.StatusInternalServerError},
+		{"lowered send limit", 0, []GRPCProxyOption{WithMaxMessageSize(defaultMaxMsgSize, 1)}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := serveSequencer(t, &largeChainSequencer{size: tt.response})
+			p, err := NewGRPCProxy(addr, nil, "", nil, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			rec := httptest.NewRecorder()
+			p.HandleGetChainState()(rec, httptest.NewRequest(http.MethodGet, "/chain-state", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %.200s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK && !strings.Contains(rec.Body.String(), "ResourceExhausted") {
+				t.Errorf("body = %.200s, want a ResourceExhausted error", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// NDJSON submission limits
+const (
+	ndjsonChunkSize    = 100        // Transactions forwarded per SubmitBatch call
+	ndjsonMaxLineBytes = 256 * 1024 // Longest accepted line (one transaction)
+)
+
+// ndjsonResult is the streamed outcome of one submitted line
+type ndjsonResult struct {
+	Line           int    `json:"line"` // 1-based line number in the request body
+	TxHash         string `json:"tx_hash,omitempty"`
+	SequenceNumber uint64 `json:"sequence_number,omitempty"`
+	ExpectedTick   uint64 `json:"expected_tick,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ndjsonChunk collects the results of the lines read since the last flush, and the
+// transactions among them still to be submitted
+type ndjsonChunk struct {
+	results      []ndjsonResult
+	transactions []*pb.Transaction
+	pending      []int    // Index into results of each transaction
+	nonceKeys    []string // Nonces reserved by the transactions, released if submission fails
+}
+
+// HandleSubmitNDJSON handles POST /api/v1/continuum/tx/ndjson
+// The body holds one transaction per line (same JSON format as single submissions).
+// Lines are forwarded to the sequencer in chunks and a result line is streamed back
+// per input line, so huge submissions never have to be held in memory
+func (p *GRPCProxy) HandleSubmitNDJSON() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		// A large submission outlives the server's read and write timeouts; the stream
+		// ends when the body does or the client goes away
+		rc := http.NewResponseController(w)
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("X-Data-Source", "grpc")
+		flusher, _ := w.(http.Flusher)
+		encoder := json.NewEncoder(w)
+
+		var chunk ndjsonChunk
+		flush := func() bool {
+			p.submitNDJSONChunk(r, &chunk)
+			for _, result := range chunk.results {
+				if err := encoder.Encode(result); err != nil {
+					return false
+				}
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			chunk = ndjsonChunk{}
+			return true
+		}
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), ndjsonMaxLineBytes)
+
+		line := 0
+		for scanner.Scan() {
+			line++
+			raw := bytes.TrimSpace(scanner.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+
+			var txReq transactionRequest
+			if err := json.Unmarshal(raw, &txReq); err != nil {
+				chunk.results = append(chunk.results, ndjsonResult{Line: line, Error: fmt.Sprintf("invalid JSON: %v", err)})
+			} else if tx, err := txReq.toProtobuf(); err != nil {
+				chunk.results = append(chunk.results, ndjsonResult{Line: line, Error: fmt.Sprintf("invalid transaction data: %v", err)})
+			} else if err := p.validateSignature(tx); err != nil {
+				chunk.results = append(chunk.results, ndjsonResult{Line: line, Error: fmt.Sprintf("invalid transaction data: %v", err)})
+			} else if keys, _, ok := p.reserveNonces([]*pb.Transaction{tx}); !ok {
+				chunk.results = append(chunk.results, ndjsonResult{Line: line, Error: "nonce already used for this public key"})
+			} else {
+				chunk.pending = append(chunk.pending, len(chunk.results))
+				chunk.transactions = append(chunk.transactions, tx)
+				chunk.results = append(chunk.results, ndjsonResult{Line: line})
+				chunk.nonceKeys = append(chunk.nonceKeys, keys...)
+			}
+
+			if len(chunk.transactions) >= ndjsonChunkSize || len(chunk.results) >= 4*ndjsonChunkSize {
+				if !flush() || clientGone(r) {
+					return
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			// Report where reading stopped; lines after it were not submitted
+			chunk.results = append(chunk.results, ndjsonResult{Line: line + 1, Error: fmt.Sprintf("failed to read request body: %v", err)})
+		}
+		flush()
+	}
+}
+
+// submitNDJSONChunk forwards the chunk's transactions in one SubmitBatch call and
+// records the outcome in their results
+func (p *GRPCProxy) submitNDJSONChunk(r *http.Request, chunk *ndjsonChunk) {
+	if len(chunk.transactions) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var resp *pb.SubmitBatchResponse
+	var err error
+	if qerr := p.submit(ctx, func(ctx context.Context) {
+		resp, err = p.client.SubmitBatch(ctx, &pb.SubmitBatchRequest{Transactions: chunk.transactions})
+	}); qerr != nil {
+		err = qerr
+	}
+	if err == nil && len(resp.Responses) != len(chunk.transactions) {
+		err = fmt.Errorf("sequencer returned %d results for %d transactions", len(resp.Responses), len(chunk.transactions))
+	}
+	if err != nil {
+		if !clientGone(r) {
+			p.logger.Warn("NDJSON chunk submission failed",
+				zap.Int("transactions", len(chunk.transactions)),
+				zap.Error(err))
+		}
+		p.releaseNonces(chunk.nonceKeys)
+		for _, i := range chunk.pending {
+			chunk.results[i].Error = fmt.Sprintf("grpc call failed: %v", err)
+		}
+		return
+	}
+
+	for n, i := range chunk.pending {
+		chunk.results[i].TxHash = resp.Responses[n].TxHash
+		chunk.results[i].SequenceNumber = resp.Responses[n].SequenceNumber
+		chunk.results[i].ExpectedTick = resp.Responses[n].ExpectedTick
+	}
+}
@@ -0,0 +1,163 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ndjsonLines joins transactions into an NDJSON body
+func ndjsonLines(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestHandleSubmitNDJSON(t *testing.T) {
+	var many []string
+	for nonce := 1; nonce <= 2*ndjsonChunkSize+50; nonce++ {
+		many = append(many, txJSON(`[1]`, "abcd", nonce))
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		wantLines   []int          // Line number of each result, in order
+		wantErrors  map[int]string // Error substring per failed line
+		wantTxs     int
+		wantBatches int
+	}{
+		{
+			name:        "valid lines",
+			body:        ndjsonLines(txJSON(`[1]`, "abcd", 1), txJSON(`"RlJN"`, "abcd", 2), txJSON(`[2]`, "0xabcd", 3)),
+			wantLines:   []int{1, 2, 3},
+			wantTxs:     3,
+			wantBatches: 1,
+		},
+		{
+			name: "bad lines reported in place",
+			body: ndjsonLines(
+				txJSON(`[1]`, "abcd", 1),
+				`{"tx_id":`,
+				"",
+				txJSON(`[1]`, "zz", 4),
+				txJSON(`[1]`, "abcd", 5),
+			),
+			wantLines:   []int{1, 2, 4, 5},
+			wantErrors:  map[int]string{2: "invalid JSON", 4: "invalid transaction data"},
+			wantTxs:     2,
+			wantBatches: 1,
+		},
+		{
+			name:       "only bad lines",
+			body:       ndjsonLines(`[]`, txJSON(`"not base64!"`, "abcd", 2)),
+			wantLines:  []int{1, 2},
+			wantErrors: map[int]string{1: "invalid JSON", 2: "invalid transaction data"},
+		},
+		{
+			name:        "several chunks",
+			body:        ndjsonLines(many...),
+			wantTxs:     len(many),
+			wantBatches: 3,
+		},
+		{
+			name:        "line too long",
+			body:        ndjsonLines(txJSON(`[1]`, "abcd", 1), strings.Repeat("x", ndjsonMaxLineBytes+1)),
+			wantLines:   []int{1, 2},
+			wantErrors:  map[int]string{2: "failed to read request body"},
+			wantTxs:     1,
+			wantBatches: 1,
+		},
+		{
+			name: "empty body",
+			body: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, sequencer := submitProxy(t)
+			srv := httptest.NewServer(p.HandleSubmitNDJSON())
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL, "application/x-ndjson", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("POST error = %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+				t.Fatalf("response = %d %s, want 200 NDJSON", resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+
+			var results []ndjsonResult
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var result ndjsonResult
+				if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+					t.Fatalf("decode %q: %v", scanner.Text(), err)
+				}
+				results = append(results, result)
+			}
+
+			wantLines := tt.wantLines
+			if wantLines == nil {
+				for line := 1; line <= tt.wantTxs; line++ {
+					wantLines = append(wantLines, line)
+				}
+			}
+			if len(results) != len(wantLines) {
+				t.Fatalf("got %d results, want %d", len(results), len(wantLines))
+			}
+			var sequence uint64
+			for i, result := range results {
+				if result.Line != wantLines[i] {
+					t.Errorf("result %d: line = %d, want %d", i, result.Line, wantLines[i])
+				}
+				if want, failed := tt.wantErrors[result.Line]; failed {
+					if !strings.Contains(result.Error, want) || result.SequenceNumber != 0 {
+						t.Errorf("line %d = %+v, want error %q", result.Line, result, want)
+					}
+					continue
+				}
+				// The sequencer numbers transactions in the order received
+				sequence++
+				if result.Error != "" || result.SequenceNumber != sequence {
+					t.Errorf("line %d = %+v, want sequence number %d", result.Line, result, sequence)
+				}
+			}
+
+			if got := len(sequencer.received()); got != tt.wantTxs {
+				t.Errorf("sequencer received %d transactions, want %d", got, tt.wantTxs)
+			}
+			sequencer.mu.Lock()
+			batches := sequencer.batches
+			sequencer.mu.Unlock()
+			if batches != tt.wantBatches {
+				t.Errorf("SubmitBatch called %d times, want %d", batches, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestHandleSubmitNDJSON_Method(t *testing.T) {
+	tests := []struct {
+		method     string
+		wantStatus int
+	}{
+		{http.MethodGet, http.StatusMethodNotAllowed},
+		{http.MethodPut, http.StatusMethodNotAllowed},
+		{http.MethodPost, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			p, _ := submitProxy(t)
+			rec := httptest.NewRecorder()
+			p.HandleSubmitNDJSON()(rec, httptest.NewRequest(tt.method, "/tx/ndjson", strings.NewReader("")))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
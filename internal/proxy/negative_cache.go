@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+// negativeCacheSweepSize is the entry count above which expired entries are swept on insert
+const negativeCacheSweepSize = 10000
+
+// negativeCache remembers keys that recently resolved to "not found" for a short TTL,
+// so repeated lookups don't hit upstreams while the key can still appear shortly after
+type negativeCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]time.Time // Key → expiry
+}
+
+func newNegativeCache(ttl time.Duration) *negativeCache {
+	return &negativeCache{
+		ttl:     ttl,
+		entries: make(map[string]time.Time),
+	}
+}
+
+// Has reports whether key was recorded as not found within the TTL
+func (c *negativeCache) Has(key string) bool {
+	if c == nil || c.ttl <= 0 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiry, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiry) {
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+// Add records key as not found for the TTL
+func (c *negativeCache) Add(key string) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= negativeCacheSweepSize {
+		for k, expiry := range c.entries {
+			if now.After(expiry) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNegativeCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache *negativeCache
+		add   []string
+		wait  time.Duration
+		key   string
+		want  bool
+	}{
+		{"recorded", newNegativeCache(time.Minute), []string{"abc1"}, 0, "abc1", true},
+		{"other key", newNegativeCache(time.Minute), []string{"abc1"}, 0, "abc2", false},
+		{"expired", newNegativeCache(10 * time.Millisecond), []string{"abc1"}, 30 * time.Millisecond, "abc1", false},
+		{"disabled", newNegativeCache(0), []string{"abc1"}, 0, "abc1", false},
+		{"nil", nil, []string{"abc1"}, 0, "abc1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range tt.add {
+				tt.cache.Add(key)
+			}
+			time.Sleep(tt.wait)
+			if got := tt.cache.Has(tt.key); got != tt.want {
+				t.Errorf("Has(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegativeCache_Sweep(t *testing.T) {
+	c := newNegativeCache(10 * time.Millisecond)
+	for i := range negativeCacheSweepSize {
+		c.Add(fmt.Sprintf("old-%d", i))
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	c.Add("new")
+	if len(c.entries) != 1 || !c.Has("new") {
+		t.Errorf("entries = %d after sweeping, want only the new key", len(c.entries))
+	}
+}
+
+func TestHandleGetTransactionByHash_NotFoundCache(t *testing.T) {
+	type lookup struct {
+		upstream   int           // Upstream status from this lookup on
+		wait       time.Duration // Before the lookup
+		wantStatus int
+		wantCalls  int32 // Upstream calls so far
+		wantSource string
+	}
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		lookups []lookup
+	}{
+		{
+			name: "repeat within TTL is cached",
+			ttl:  time.Minute,
+			lookups: []lookup{
+				{http.StatusNotFound, 0, http.StatusNotFound, 1, ""},
+				{http.StatusNotFound, 0, http.StatusNotFound, 1, "cache"},
+				{http.StatusNotFound, 0, http.StatusNotFound, 1, "cache"},
+			},
+		},
+		{
+			name: "found once the TTL expires",
+			ttl:  20 * time.Millisecond,
+			lookups: []lookup{
+				{http.StatusNotFound, 0, http.StatusNotFound, 1, ""},
+				{http.StatusOK, 0, http.StatusNotFound, 1, "cache"},
+				{http.StatusOK, 40 * time.Millisecond, http.StatusOK, 2, "rest-api"},
+			},
+		},
+		{
+			name: "upstream errors are not cached",
+			ttl:  time.Minute,
+			lookups: []lookup{
+				{http.StatusBadGateway, 0, http.StatusServiceUnavailable, 1, ""},
+				{http.StatusOK, 0, http.StatusOK, 2, "rest-api"},
+			},
+		},
+		{
+			name: "disabled",
+			ttl:  0,
+			lookups: []lookup{
+				{http.StatusNotFound, 0, http.StatusNotFound, 1, ""},
+				{http.StatusNotFound, 0, http.StatusNotFound, 2, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := newTxUpstream(t, http.StatusNotFound)
+			close(upstream.release)
+			p, err := NewGRPCProxy("127.0.0.1:1", nil, upstream.URL, nil, WithNotFoundTTL(tt.ttl))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			for i, l := range tt.lookups {
+				time.Sleep(l.wait)
+				upstream.status.Store(int32(l.upstream))
+
+				rec := httptest.NewRecorder()
+				p.HandleGetTransactionByHash()(rec, httptest.NewRequest(http.MethodGet, "/tx/abc1", nil))
+
+				if rec.Code != l.wantStatus {
+					t.Errorf("lookup %d: status = %d, want %d", i, rec.Code, l.wantStatus)
+				}
+				if got := upstream.calls.Load(); got != l.wantCalls {
+					t.Errorf("lookup %d: upstream calls = %d, want %d", i, got, l.wantCalls)
+				}
+				if got := rec.Header().Get("X-Data-Source"); got != l.wantSource {
+					t.Errorf("lookup %d: X-Data-Source = %q, want %q", i, got, l.wantSource)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package proxy
+
+import (
+	"container/list"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// nonceCache remembers the (public key, nonce) pairs submitted within a window so
+// accidental replays are rejected before reaching the sequencer. Entries share one
+// TTL, so insertion order is expiry order and the oldest entry is evicted first
+// when the cache is full
+type nonceCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	entries    map[string]*list.Element // Key → element in order
+	order      *list.List               // Of *nonceEntry, oldest first
+}
+
+type nonceEntry struct {
+	key    string
+	expiry time.Time
+}
+
+func newNonceCache(window time.Duration, maxEntries int) *nonceCache {
+	return &nonceCache{
+		window:     window,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// nonceKey identifies a transaction's nonce for its signer
+func nonceKey(tx *pb.Transaction) string {
+	return hex.EncodeToString(tx.PublicKey) + ":" + strconv.FormatUint(tx.Nonce, 10)
+}
+
+// Reserve records key, reporting false if it was already recorded within the window
+func (c *nonceCache) Reserve(key string) bool {
+	if c == nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for front := c.order.Front(); front != nil && !now.Before(front.Value.(*nonceEntry).expiry); front = c.order.Front() {
+		c.removeFront()
+	}
+
+	if _, ok := c.entries[key]; ok {
+		return false
+	}
+	// Only make room once the key is known to be new, so a replay never evicts itself
+	if c.order.Len() >= c.maxEntries {
+		c.removeFront()
+	}
+	c.entries[key] = c.order.PushBack(&nonceEntry{key: key, expiry: now.Add(c.window)})
+	return true
+}
+
+// removeFront forgets the oldest entry. The caller must hold mu
+func (c *nonceCache) removeFront() {
+	entry := c.order.Remove(c.order.Front()).(*nonceEntry)
+	delete(c.entries, entry.key)
+}
+
+// Release forgets key, so a submission that failed can be retried with the same nonce
+func (c *nonceCache) Release(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// reserveNonces reserves every transaction's nonce, returning the keys to release
+// if the submission fails. If one was already used the reservations made are
+// undone and the index of the replayed transaction is returned
+func (p *GRPCProxy) reserveNonces(txs []*pb.Transaction) ([]string, int, bool) {
+	if p.nonces == nil {
+		return nil, 0, true
+	}
+
+	keys := make([]string, 0, len(txs))
+	for i, tx := range txs {
+		key := nonceKey(tx)
+		if !p.nonces.Reserve(key) {
+			p.releaseNonces(keys)
+			return nil, i, false
+		}
+		keys = append(keys, key)
+	}
+	return keys, 0, true
+}
+
+// releaseNonces undoes reservations made by reserveNonces
+func (p *GRPCProxy) releaseNonces(keys []string) {
+	for _, key := range keys {
+		p.nonces.Release(key)
+	}
+}
+
+// writeNonceReplayed responds 409 for a transaction whose nonce was already submitted
+func writeNonceReplayed(w http.ResponseWriter, tx *pb.Transaction, index int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": "nonce already used for this public key",
+		"nonce": tx.Nonce,
+		"index": index,
+	})
+}
+
+// WithNonceCache rejects submissions with 409 when the same public key and nonce
+// were submitted within window, remembering up to maxEntries pairs (default: disabled).
+// It's a gateway-side guard against accidental replays; the sequencer stays authoritative
+func WithNonceCache(window time.Duration, maxEntries int) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		if window > 0 && maxEntries > 0 {
+			p.nonces = newNonceCache(window, maxEntries)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+func TestNonceCache(t *testing.T) {
+	// A step reserves key (want = the result), releases it, or waits
+	type step struct {
+		op   string
+		key  string
+		want bool
+	}
+	reserve := func(key string, want bool) step { return step{"reserve", key, want} }
+	release := func(key string) step { return step{op: "release", key: key} }
+	wait := step{op: "wait"}
+
+	tests := []struct {
+		name       string
+		window     time.Duration
+		maxEntries int
+		steps      []step
+	}{
+		{"fresh keys", time.Minute, 10, []step{reserve("a:1", true), reserve("a:2", true), reserve("b:1", true)}},
+		{"repeated key", time.Minute, 10, []step{reserve("a:1", true), reserve("a:1", false), reserve("a:1", false)}},
+		{"released key", time.Minute, 10, []step{reserve("a:1", true), release("a:1"), reserve("a:1", true), reserve("a:1", false)}},
+		{"releasing an unknown key", time.Minute, 10, []step{release("a:1"), reserve("a:1", true)}},
+		{"expired key", 20 * time.Millisecond, 10, []step{reserve("a:1", true), wait, reserve("a:1", true), reserve("a:1", false)}},
+		{"replay into a full cache", time.Minute, 2, []step{reserve("a:1", true), reserve("a:2", true), reserve("a:1", false), reserve("a:2", false)}},
+		{"full cache evicts the oldest", time.Minute, 2, []step{
+			reserve("a:1", true), reserve("a:2", true), reserve("a:3", true),
+			reserve("a:1", true), // Evicted by a:3
+			reserve("a:3", false),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newNonceCache(tt.window, tt.maxEntries)
+			for i, s := range tt.steps {
+				switch s.op {
+				case "reserve":
+					if got := c.Reserve(s.key); got != s.want {
+						t.Errorf("step %d: Reserve(%q) = %v, want %v", i, s.key, got, s.want)
+					}
+				case "release":
+					c.Release(s.key)
+				case "wait":
+					time.Sleep(2 * tt.window)
+				}
+			}
+			if n := c.order.Len(); n > tt.maxEntries || n != len(c.entries) {
+				t.Errorf("cache holds %d entries (%d indexed), want at most %d", n, len(c.entries), tt.maxEntries)
+			}
+		})
+	}
+
+	// A nil cache (the option not set) accepts everything
+	var disabled *nonceCache
+	if !disabled.Reserve("a:1") || !disabled.Reserve("a:1") {
+		t.Error("nil cache rejected a nonce")
+	}
+	disabled.Release("a:1")
+}
+
+// flakySubmitSequencer fails submissions with Unavailable while fail is set
+type flakySubmitSequencer struct {
+	submitSequencer
+	fail atomic.Bool
+}
+
+func (s *flakySubmitSequencer) SubmitTransaction(ctx context.Context, req *pb.SubmitTransactionRequest) (*pb.SubmitTransactionResponse, error) {
+	if s.fail.Load() {
+		return nil, status.Error(codes.Unavailable, "sequencer restarting")
+	}
+	return s.submitSequencer.SubmitTransaction(ctx, req)
+}
+
+// signedTx is a transaction from publicKey with nonce in the frontend's format
+func signedTx(publicKey string, nonce int) string {
+	return fmt.Sprintf(`{"tx_id":"tx-%d","payload":[1],"signature":"abcd","public_key":%q,"nonce":%d,"timestamp":%d}`,
+		nonce, publicKey, nonce, time.Now().UnixMicro())
+}
+
+func TestHandleSubmit_NonceReplay(t *testing.T) {
+	// A submission is a single transaction, or a batch if it has several nonces
+	type submission struct {
+		key        string
+		nonces     []int
+		fail       bool // The sequencer fails it
+		wait       time.Duration
+		wantStatus int
+		wantIndex  int // Of the replayed transaction, for 409s
+	}
+	single := func(nonce, wantStatus int) submission {
+		return submission{key: "keyA", nonces: []int{nonce}, wantStatus: wantStatus}
+	}
+	batch := func(wantStatus, wantIndex int, nonces ...int) submission {
+		return submission{key: "keyA", nonces: nonces, wantStatus: wantStatus, wantIndex: wantIndex}
+	}
+
+	tests := []struct {
+		name        string
+		window      time.Duration
+		submissions []submission
+	}{
+		{"fresh nonces", time.Minute, []submission{single(1, http.StatusOK), single(2, http.StatusOK)}},
+		{"repeated nonce", time.Minute, []submission{single(1, http.StatusOK), single(1, http.StatusConflict)}},
+		{"same nonce from another key", time.Minute, []submission{
+			single(1, http.StatusOK),
+			{key: "keyB", nonces: []int{1}, wantStatus: http.StatusOK},
+		}},
+		{"cache disabled", 0, []submission{single(1, http.StatusOK), single(1, http.StatusOK)}},
+		{"window expired", 20 * time.Millisecond, []submission{
+			single(1, http.StatusOK),
+			{key: "keyA", nonces: []int{1}, wait: 50 * time.Millisecond, wantStatus: http.StatusOK},
+		}},
+		{"repeat within a batch", time.Minute, []submission{
+			batch(http.StatusConflict, 2, 1, 2, 1),
+			single(2, http.StatusOK), // The rejected batch reserved nothing
+		}},
+		{"batch replaying a single", time.Minute, []submission{
+			single(2, http.StatusOK),
+			batch(http.StatusConflict, 1, 1, 2),
+			batch(http.StatusOK, 0, 1, 3),
+		}},
+		{"failed submission can be retried", time.Minute, []submission{
+			{key: "keyA", nonces: []int{1}, fail: true, wantStatus: http.StatusInternalServerError},
+			single(1, http.StatusOK),
+			single(1, http.StatusConflict),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequencer := &flakySubmitSequencer{}
+			p, err := NewGRPCProxy(serveSequencer(t, sequencer), nil, "", nil, WithNonceCache(tt.window, 100))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			for i, s := range tt.submissions {
+				time.Sleep(s.wait)
+				sequencer.fail.Store(s.fail)
+
+				var txs []string
+				for _, nonce := range s.nonces {
+					txs = append(txs, signedTx(s.key, nonce))
+				}
+				rec := httptest.NewRecorder()
+				if len(txs) == 1 {
+					p.HandleSubmitTransaction()(rec, httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(`{"transaction":`+txs[0]+`}`)))
+				} else {
+					body := `{"transactions":[` + strings.Join(txs, ",") + `]}`
+					p.HandleSubmitBatch()(rec, httptest.NewRequest(http.MethodPost, "/tx/batch", strings.NewReader(body)))
+				}
+
+				if rec.Code != s.wantStatus {
+					t.Fatalf("submission %d: status = %d, want %d: %s", i, rec.Code, s.wantStatus, rec.Body.String())
+				}
+				if s.wantStatus != http.StatusConflict {
+					continue
+				}
+				var body struct {
+					Error string `json:"error"`
+					Nonce int    `json:"nonce"`
+					Index int    `json:"index"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("submission %d: decode %s: %v", i, rec.Body.String(), err)
+				}
+				if body.Index != s.wantIndex || body.Nonce != s.nonces[s.wantIndex] || body.Error != "nonce already used for this public key" {
+					t.Errorf("submission %d: body = %s, want nonce %d at index %d", i, rec.Body.String(), s.nonces[s.wantIndex], s.wantIndex)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleSubmitNDJSON_NonceReplay(t *testing.T) {
+	tests := []struct {
+		name       string
+		nonces     []int
+		wantErrors []int // Lines rejected as replays
+	}{
+		{"fresh nonces", []int{1, 2, 3}, nil},
+		{"repeated line", []int{1, 2, 1}, []int{3}},
+		{"repeated several times", []int{1, 1, 1, 2}, []int{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, sequencer := submitProxy(t, WithNonceCache(time.Minute, 100))
+			srv := httptest.NewServer(p.HandleSubmitNDJSON())
+			defer srv.Close()
+
+			var lines []string
+			for _, nonce := range tt.nonces {
+				lines = append(lines, signedTx("keyA", nonce))
+			}
+			resp, err := http.Post(srv.URL, "application/x-ndjson", strings.NewReader(ndjsonLines(lines...)))
+			if err != nil {
+				t.Fatalf("POST error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			var gotErrors []int
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var result ndjsonResult
+				if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+					t.Fatalf("decode %q: %v", scanner.Text(), err)
+				}
+				if result.Error != "" {
+					if result.Error != "nonce already used for this public key" {
+						t.Errorf("line %d error = %q, want a replay", result.Line, result.Error)
+					}
+					gotErrors = append(gotErrors, result.Line)
+				}
+			}
+			if fmt.Sprint(gotErrors) != fmt.Sprint(tt.wantErrors) {
+				t.Errorf("replayed lines = %v, want %v", gotErrors, tt.wantErrors)
+			}
+			if got, want := len(sequencer.received()), len(tt.nonces)-len(tt.wantErrors); got != want {
+				t.Errorf("sequencer received %d transactions, want %d", got, want)
+			}
+		})
+	}
+}
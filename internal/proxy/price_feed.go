@@ -0,0 +1,262 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+// Price feed connection timing and limits
+const (
+	priceFeedWriteWait    = 10 * time.Second
+	priceFeedPongWait     = 60 * time.Second
+	priceFeedPingPeriod   = priceFeedPongWait * 9 / 10
+	priceFeedMaxMessage   = 4096
+	maxPriceSubscriptions = 20
+)
+
+// marketIDPattern matches market IDs (UUIDs, as stored in market_prices)
+var marketIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// priceStore is the part of the database repository prices are polled from
+type priceStore interface {
+	GetLatestPrices(ctx context.Context, marketIDs []string) ([]database.MarketPrice, error)
+}
+
+// PriceFeedHandler pushes the latest price of subscribed markets to WebSocket clients
+// Prices are polled from market_prices and only pushed when they change
+type PriceFeedHandler struct {
+	repository   priceStore
+	logger       *zap.Logger
+	pollInterval time.Duration
+	upgrader     websocket.Upgrader
+}
+
+// PriceFeedOption is a functional option for configuring PriceFeedHandler
+type PriceFeedOption func(*PriceFeedHandler)
+
+// WithPollInterval sets how often subscribed markets are polled (default 1s)
+func WithPollInterval(interval time.Duration) PriceFeedOption {
+	return func(h *PriceFeedHandler) {
+		h.pollInterval = interval
+	}
+}
+
+// WithOriginCheck only accepts browser connections whose Origin passes allowed
+// Connections without an Origin header (non-browser clients) are always accepted
+func WithOriginCheck(allowed func(origin string) bool) PriceFeedOption {
+	return func(h *PriceFeedHandler) {
+		h.upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || allowed(origin)
+		}
+	}
+}
+
+// NewPriceFeedHandler creates a new price feed handler
+func NewPriceFeedHandler(repository *database.Repository, logger *zap.Logger, opts ...PriceFeedOption) *PriceFeedHandler {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	h := &PriceFeedHandler{
+		repository:   repository,
+		logger:       logger,
+		pollInterval: time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// priceFeedRequest is sent by clients to change their subscriptions
+// e.g. {"type":"subscribe","markets":["<market uuid>"]}
+type priceFeedRequest struct {
+	Type    string   `json:"type"` // subscribe or unsubscribe
+	Markets []string `json:"markets"`
+}
+
+// priceFeedEvent is sent to clients
+type priceFeedEvent struct {
+	Type      string     `json:"type"` // price, subscribed, unsubscribed or error
+	MarketID  string     `json:"market_id,omitempty"`
+	Price     float64    `json:"price,omitempty"` // USDC, rounded like candles
+	Timestamp *time.Time `json:"ts,omitempty"`
+	Markets   []string   `json:"markets,omitempty"` // Current subscriptions, for subscribed/unsubscribed
+	Error     string     `json:"error,omitempty"`
+}
+
+// HandlePriceFeed handles GET /api/v1/rollup/ws/prices (WebSocket)
+func (h *PriceFeedHandler) HandlePriceFeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade has already written an HTTP error response
+			h.logger.Debug("WebSocket upgrade failed", zap.Error(err))
+			return
+		}
+		defer conn.Close()
+
+		requests := make(chan priceFeedRequest)
+		closed := make(chan struct{}) // Closed by the reader when the connection fails
+		stop := make(chan struct{})   // Closed when serve returns, so the reader never blocks
+		defer close(stop)
+		go h.readRequests(conn, requests, closed, stop)
+
+		h.serve(conn, requests, closed)
+	}
+}
+
+// readRequests reads client messages until the connection fails, then closes closed
+func (h *PriceFeedHandler) readRequests(conn *websocket.Conn, requests chan<- priceFeedRequest, closed chan<- struct{}, stop <-chan struct{}) {
+	defer close(closed)
+
+	conn.SetReadLimit(priceFeedMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(priceFeedPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(priceFeedPongWait))
+	})
+
+	for {
+		var req priceFeedRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			if _, ok := err.(*websocket.CloseError); !ok {
+				h.logger.Debug("Price feed read failed", zap.Error(err))
+			}
+			return
+		}
+		select {
+		case requests <- req:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// serve owns all writes to conn: subscription replies, price updates and pings
+func (h *PriceFeedHandler) serve(conn *websocket.Conn, requests <-chan priceFeedRequest, closed <-chan struct{}) {
+	poll := time.NewTicker(h.pollInterval)
+	defer poll.Stop()
+	ping := time.NewTicker(priceFeedPingPeriod)
+	defer ping.Stop()
+
+	// Last pushed price timestamp per subscribed market (zero until the first push)
+	subscriptions := make(map[string]time.Time)
+
+	for {
+		var events []priceFeedEvent
+
+		select {
+		case <-closed:
+			return
+		case req := <-requests:
+			events = h.applyRequest(subscriptions, req)
+			if req.Type == "subscribe" {
+				// Push current prices right away rather than on the next poll
+				events = append(events, h.pollPrices(subscriptions)...)
+			}
+		case <-poll.C:
+			events = h.pollPrices(subscriptions)
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(priceFeedWriteWait)); err != nil {
+				return
+			}
+			continue
+		}
+
+		for _, event := range events {
+			conn.SetWriteDeadline(time.Now().Add(priceFeedWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// applyRequest updates subscriptions and returns the reply for the client
+func (h *PriceFeedHandler) applyRequest(subscriptions map[string]time.Time, req priceFeedRequest) []priceFeedEvent {
+	for i, market := range req.Markets {
+		if !marketIDPattern.MatchString(market) {
+			return []priceFeedEvent{{Type: "error", Error: "invalid market id: " + market}}
+		}
+		// market_prices returns lowercase UUIDs
+		req.Markets[i] = strings.ToLower(market)
+	}
+
+	switch req.Type {
+	case "subscribe":
+		for _, market := range req.Markets {
+			if _, ok := subscriptions[market]; ok {
+				continue
+			}
+			if len(subscriptions) >= maxPriceSubscriptions {
+				return []priceFeedEvent{{Type: "error", Error: "too many subscriptions (max 20)"}}
+			}
+			subscriptions[market] = time.Time{}
+		}
+	case "unsubscribe":
+		for _, market := range req.Markets {
+			delete(subscriptions, market)
+		}
+	default:
+		return []priceFeedEvent{{Type: "error", Error: "unknown message type (expected subscribe or unsubscribe)"}}
+	}
+
+	markets := make([]string, 0, len(subscriptions))
+	for market := range subscriptions {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+
+	return []priceFeedEvent{{Type: req.Type + "d", Markets: markets}}
+}
+
+// pollPrices fetches the latest price of every subscribed market and returns those that changed
+func (h *PriceFeedHandler) pollPrices(subscriptions map[string]time.Time) []priceFeedEvent {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	markets := make([]string, 0, len(subscriptions))
+	for market := range subscriptions {
+		markets = append(markets, market)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prices, err := h.repository.GetLatestPrices(ctx, markets)
+	if err != nil {
+		h.logger.Debug("Failed to poll market prices", zap.Error(err))
+		return nil
+	}
+
+	var events []priceFeedEvent
+	for _, p := range prices {
+		if !p.Timestamp.After(subscriptions[p.MarketID]) {
+			continue
+		}
+		subscriptions[p.MarketID] = p.Timestamp
+
+		ts := p.Timestamp
+		events = append(events, priceFeedEvent{
+			Type:      "price",
+			MarketID:  p.MarketID,
+			Price:     roundToDecimals(p.Price/1000000.0, defaultCandlePrecision),
+			Timestamp: &ts,
+		})
+	}
+
+	return events
+}
@@ -0,0 +1,235 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+const (
+	marketA = "11111111-1111-1111-1111-111111111111" // Has a price
+	marketB = "22222222-2222-2222-2222-222222222222" // Has none
+)
+
+// fakePriceStore serves the latest price set per market
+type fakePriceStore struct {
+	mu     sync.Mutex
+	prices map[string]database.MarketPrice
+}
+
+func newFakePriceStore() *fakePriceStore {
+	s := &fakePriceStore{prices: make(map[string]database.MarketPrice)}
+	s.set(marketA, 163885020, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return s
+}
+
+func (s *fakePriceStore) set(market string, price float64, ts time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[market] = database.MarketPrice{MarketID: market, Price: price, Timestamp: ts}
+}
+
+func (s *fakePriceStore) GetLatestPrices(ctx context.Context, marketIDs []string) ([]database.MarketPrice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var prices []database.MarketPrice
+	for _, market := range marketIDs {
+		if price, ok := s.prices[market]; ok {
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+// dialPriceFeed connects a WebSocket client to a price feed server
+func dialPriceFeed(t *testing.T, h *PriceFeedHandler) *websocket.Conn {
+	t.Helper()
+	srv := httptest.NewServer(h.HandlePriceFeed())
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readEvent reads the next price feed event, failing the test if none arrives in time
+func readEvent(t *testing.T, conn *websocket.Conn) priceFeedEvent {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event priceFeedEvent
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return event
+}
+
+func TestPriceFeed_Subscriptions(t *testing.T) {
+	var tooMany []string
+	for i := range maxPriceSubscriptions + 1 {
+		tooMany = append(tooMany, fmt.Sprintf("%08d-0000-0000-0000-000000000000", i))
+	}
+
+	subscribed := func(markets ...string) priceFeedEvent { return priceFeedEvent{Type: "subscribed", Markets: markets} }
+	priceA := priceFeedEvent{Type: "price", MarketID: marketA, Price: 163.89}
+
+	tests := []struct {
+		name     string
+		requests []priceFeedRequest
+		want     []priceFeedEvent
+	}{
+		{
+			name:     "subscribe pushes the current price",
+			requests: []priceFeedRequest{{Type: "subscribe", Markets: []string{marketA}}},
+			want:     []priceFeedEvent{subscribed(marketA), priceA},
+		},
+		{
+			name:     "market without a price",
+			requests: []priceFeedRequest{{Type: "subscribe", Markets: []string{marketB}}},
+			want:     []priceFeedEvent{subscribed(marketB)},
+		},
+		{
+			name:     "market ids are lowercased",
+			requests: []priceFeedRequest{{Type: "subscribe", Markets: []string{strings.ToUpper("aaaaaaaa-0000-0000-0000-00000000000a")}}},
+			want:     []priceFeedEvent{subscribed("aaaaaaaa-0000-0000-0000-00000000000a")},
+		},
+		{
+			name: "unchanged price is not pushed again",
+			requests: []priceFeedRequest{
+				{Type: "subscribe", Markets: []string{marketA}},
+				{Type: "subscribe", Markets: []string{marketA}},
+			},
+			want: []priceFeedEvent{subscribed(marketA), priceA, subscribed(marketA)},
+		},
+		{
+			name: "unsubscribe",
+			requests: []priceFeedRequest{
+				{Type: "subscribe", Markets: []string{marketB, marketA}},
+				{Type: "unsubscribe", Markets: []string{marketA}},
+			},
+			want: []priceFeedEvent{subscribed(marketA, marketB), priceA, {Type: "unsubscribed", Markets: []string{marketB}}},
+		},
+		{
+			name:     "invalid market id",
+			requests: []priceFeedRequest{{Type: "subscribe", Markets: []string{marketA, "BTC-USDC"}}},
+			want:     []priceFeedEvent{{Type: "error", Error: "invalid market id: BTC-USDC"}},
+		},
+		{
+			name:     "too many subscriptions",
+			requests: []priceFeedRequest{{Type: "subscribe", Markets: tooMany}},
+			want:     []priceFeedEvent{{Type: "error", Error: "too many subscriptions (max 20)"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Prices are only pushed on subscribe: the poll never fires
+			h := NewPriceFeedHandler(nil, nil, WithPollInterval(time.Hour))
+			h.repository = newFakePriceStore()
+			conn := dialPriceFeed(t, h)
+
+			for _, req := range tt.requests {
+				if err := conn.WriteJSON(req); err != nil {
+					t.Fatalf("WriteJSON() error = %v", err)
+				}
+			}
+			// The reply to an unknown message marks the end of the expected events
+			if err := conn.WriteJSON(priceFeedRequest{Type: "ping"}); err != nil {
+				t.Fatalf("WriteJSON() error = %v", err)
+			}
+			want := append(tt.want, priceFeedEvent{Type: "error", Error: "unknown message type (expected subscribe or unsubscribe)"})
+
+			for i, w := range want {
+				got := readEvent(t, conn)
+				if got.Type != w.Type || got.MarketID != w.MarketID || got.Price != w.Price || got.Error != w.Error || !slices.Equal(got.Markets, w.Markets) {
+					t.Fatalf("event %d = %+v, want %+v", i, got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceFeed_PushesUpdates(t *testing.T) {
+	store := newFakePriceStore()
+	h := NewPriceFeedHandler(nil, nil, WithPollInterval(10*time.Millisecond))
+	h.repository = store
+	conn := dialPriceFeed(t, h)
+
+	if err := conn.WriteJSON(priceFeedRequest{Type: "subscribe", Markets: []string{marketA, marketB}}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if got := readEvent(t, conn); got.Type != "subscribed" {
+		t.Fatalf("first event = %+v, want subscribed", got)
+	}
+
+	updated := time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC)
+	tests := []struct {
+		name  string
+		set   func()
+		want  string
+		price float64
+	}{
+		{"current price", func() {}, marketA, 163.89},
+		{"price changed", func() { store.set(marketA, 164000000, updated) }, marketA, 164},
+		{"first price of a market", func() { store.set(marketB, 1500000, updated) }, marketB, 1.5},
+	}
+
+	// Unchanged prices are polled repeatedly but never pushed again, so events arrive in order
+	for _, tt := range tests {
+		tt.set()
+		got := readEvent(t, conn)
+		if got.Type != "price" || got.MarketID != tt.want || got.Price != tt.price {
+			t.Fatalf("%s: event = %+v, want price %v for %s", tt.name, got, tt.price, tt.want)
+		}
+		if got.Timestamp == nil {
+			t.Errorf("%s: event without a timestamp", tt.name)
+		}
+	}
+}
+
+func TestPriceFeed_OriginCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+	}{
+		{"no origin", "", http.StatusSwitchingProtocols},
+		{"allowed origin", "https://app.fermi.xyz", http.StatusSwitchingProtocols},
+		{"other origin", "https://evil.example.com", http.StatusForbidden},
+	}
+
+	h := NewPriceFeedHandler(nil, nil, WithOriginCheck(func(origin string) bool { return origin == "https://app.fermi.xyz" }))
+	srv := httptest.NewServer(h.HandlePriceFeed())
+	defer srv.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), header)
+			if conn != nil {
+				conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("Dial() error = %v, no response", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
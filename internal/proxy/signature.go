@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// SignatureScheme selects the signature and public key lengths submissions are checked against
+type SignatureScheme string
+
+const (
+	SignatureSchemeNone      SignatureScheme = ""          // No length checks
+	SignatureSchemeEd25519   SignatureScheme = "ed25519"   // 64-byte signatures, 32-byte keys
+	SignatureSchemeSecp256k1 SignatureScheme = "secp256k1" // 64/65-byte signatures, 33/65-byte keys
+)
+
+// signatureLengths are the accepted signature and public key lengths of a scheme, in bytes
+type signatureLengths struct {
+	signature []int
+	publicKey []int
+}
+
+var schemeLengths = map[SignatureScheme]signatureLengths{
+	SignatureSchemeEd25519:   {signature: []int{64}, publicKey: []int{32}},
+	SignatureSchemeSecp256k1: {signature: []int{64, 65}, publicKey: []int{33, 65}},
+}
+
+// WithSignatureScheme rejects submissions whose signature or public key length doesn't
+// fit scheme with 400 before calling the sequencer (default: no checks). Only lengths
+// are checked; verifying signatures is left to the sequencer
+func WithSignatureScheme(scheme SignatureScheme) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.signatureScheme = scheme
+	}
+}
+
+// validateSignature checks tx's signature and public key lengths against the scheme
+func (p *GRPCProxy) validateSignature(tx *pb.Transaction) error {
+	lengths, ok := schemeLengths[p.signatureScheme]
+	if !ok {
+		return nil
+	}
+
+	if !slices.Contains(lengths.signature, len(tx.Signature)) {
+		return fmt.Errorf("invalid signature: %d bytes, %s signatures are %s bytes",
+			len(tx.Signature), p.signatureScheme, joinLengths(lengths.signature))
+	}
+	if n := publicKeyLength(tx.PublicKey, lengths.publicKey); !slices.Contains(lengths.publicKey, n) {
+		return fmt.Errorf("invalid public_key: %d bytes, %s public keys are %s bytes",
+			n, p.signatureScheme, joinLengths(lengths.publicKey))
+	}
+	return nil
+}
+
+// publicKeyLength returns the decoded length of key. Base58 keys are forwarded as
+// their text (see decodeBase58), so when the raw length doesn't fit, key is measured
+// as base58
+func publicKeyLength(key []byte, accepted []int) int {
+	if slices.Contains(accepted, len(key)) {
+		return len(key)
+	}
+	if n, ok := base58Length(string(key)); ok {
+		return n
+	}
+	return len(key)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Length returns the number of bytes s decodes to, or false if s isn't base58
+func base58Length(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	leadingZeros := 0
+	for i, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return 0, false
+		}
+		// Each leading '1' encodes a zero byte
+		if digit == 0 && i == leadingZeros {
+			leadingZeros++
+		}
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	return leadingZeros + len(value.Bytes()), true
+}
+
+// joinLengths formats accepted lengths for error messages, e.g. "33 or 65"
+func joinLengths(lengths []int) string {
+	parts := make([]string, len(lengths))
+	for i, n := range lengths {
+		parts[i] = fmt.Sprint(n)
+	}
+	return strings.Join(parts, " or ")
+}
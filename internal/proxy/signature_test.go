@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// base58Key is a 32-byte key in base58, as sent by the frontend
+const base58Key = "CRbNEfDGMKHiWcibuJCbxP6KKvdGEDm2EZE46cBX4kHa"
+
+func TestBase58Length(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		want   int
+		wantOK bool
+	}{
+		{"32-byte key", base58Key, 32, true},
+		{"zero byte", "1", 1, true},
+		{"leading zero bytes", "112", 3, true},
+		{"one byte", "z", 1, true},
+		{"two bytes", "5R", 2, true}, // 5*58+16 = 306
+		{"empty", "", 0, false},
+		{"not base58", "0OIl", 0, false},
+		{"hex-looking but with 0", "0a1b", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := base58Length(tt.s)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("base58Length(%q) = %d, %v; want %d, %v", tt.s, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateSignature(t *testing.T) {
+	key := func(n int) []byte { return bytes.Repeat([]byte{2}, n) }
+
+	tests := []struct {
+		name      string
+		scheme    SignatureScheme
+		signature int
+		publicKey []byte
+		wantErr   string // Empty = valid
+	}{
+		{"unchecked", SignatureSchemeNone, 3, key(5), ""},
+		{"unknown scheme is unchecked", "rsa", 3, key(5), ""},
+		{"ed25519", SignatureSchemeEd25519, 64, key(32), ""},
+		{"ed25519 base58 key", SignatureSchemeEd25519, 64, []byte(base58Key), ""},
+		{"ed25519 short signature", SignatureSchemeEd25519, 63, key(32), "invalid signature: 63 bytes, ed25519 signatures are 64 bytes"},
+		{"ed25519 long signature", SignatureSchemeEd25519, 65, key(32), "invalid signature: 65 bytes, ed25519 signatures are 64 bytes"},
+		{"ed25519 empty signature", SignatureSchemeEd25519, 0, key(32), "invalid signature: 0 bytes"},
+		{"ed25519 short key", SignatureSchemeEd25519, 64, key(31), "invalid public_key: 31 bytes, ed25519 public keys are 32 bytes"},
+		{"ed25519 text key", SignatureSchemeEd25519, 64, []byte("not-a-key"), "invalid public_key: 9 bytes"},
+		{"secp256k1 compact", SignatureSchemeSecp256k1, 64, key(33), ""},
+		{"secp256k1 recoverable", SignatureSchemeSecp256k1, 65, key(65), ""},
+		{"secp256k1 bad signature", SignatureSchemeSecp256k1, 66, key(33), "invalid signature: 66 bytes, secp256k1 signatures are 64 or 65 bytes"},
+		{"secp256k1 ed25519 key", SignatureSchemeSecp256k1, 64, key(32), "invalid public_key: 32 bytes, secp256k1 public keys are 33 or 65 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &GRPCProxy{}
+			WithSignatureScheme(tt.scheme)(p)
+			err := p.validateSignature(&pb.Transaction{Signature: bytes.Repeat([]byte{1}, tt.signature), PublicKey: tt.publicKey})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateSignature() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateSignature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// schemeTx is a transaction with a signature of sigBytes bytes, in the frontend's format
+func schemeTx(sigBytes int, publicKey string, nonce int) string {
+	return fmt.Sprintf(`{"tx_id":"tx-%d","payload":[1],"signature":"0x%s","public_key":%q,"nonce":%d,"timestamp":%d}`,
+		nonce, strings.Repeat("ab", sigBytes), publicKey, nonce, time.Now().UnixMicro())
+}
+
+func TestHandleSubmit_SignatureScheme(t *testing.T) {
+	hexKey := strings.Repeat("cd", 32)
+
+	tests := []struct {
+		name       string
+		single     bool
+		txs        []string
+		wantStatus int
+		wantError  string
+	}{
+		{"valid", true, []string{schemeTx(64, base58Key, 1)}, http.StatusOK, ""},
+		{"valid hex key", true, []string{schemeTx(64, hexKey, 1)}, http.StatusOK, ""},
+		{"short signature", true, []string{schemeTx(32, base58Key, 1)}, http.StatusBadRequest, "invalid signature: 32 bytes"},
+		{"short key", true, []string{schemeTx(64, hexKey[:62], 1)}, http.StatusBadRequest, "invalid public_key: 31 bytes"},
+		{"valid batch", false, []string{schemeTx(64, base58Key, 1), schemeTx(64, hexKey, 2)}, http.StatusOK, ""},
+		{"batch with a bad signature", false, []string{schemeTx(64, base58Key, 1), schemeTx(65, base58Key, 2)}, http.StatusBadRequest, "at index 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, sequencer := submitProxy(t, WithSignatureScheme(SignatureSchemeEd25519))
+
+			rec := httptest.NewRecorder()
+			if tt.single {
+				p.HandleSubmitTransaction()(rec, httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(`{"transaction":`+tt.txs[0]+`}`)))
+			} else {
+				body := `{"transactions":[` + strings.Join(tt.txs, ",") + `]}`
+				p.HandleSubmitBatch()(rec, httptest.NewRequest(http.MethodPost, "/tx/batch", strings.NewReader(body)))
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantError) {
+				t.Errorf("body = %s, want %q", rec.Body.String(), tt.wantError)
+			}
+			// Rejected before the gRPC call
+			wantTxs := len(tt.txs)
+			if tt.wantStatus != http.StatusOK {
+				wantTxs = 0
+			}
+			if got := len(sequencer.received()); got != wantTxs {
+				t.Errorf("sequencer received %d transactions, want %d", got, wantTxs)
+			}
+		})
+	}
+}
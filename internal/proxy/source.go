@@ -0,0 +1,40 @@
+package proxy
+
+// Source identifies which Continuum backend serves a read
+type Source string
+
+const (
+	SourceGRPC Source = "grpc"
+	SourceREST Source = "rest"
+)
+
+// other returns the fallback for s
+func (s Source) other() Source {
+	if s == SourceGRPC {
+		return SourceREST
+	}
+	return SourceGRPC
+}
+
+// defaultPrimarySources are used for methods not overridden by WithPrimarySources
+var defaultPrimarySources = map[string]Source{
+	"status": SourceREST,
+}
+
+// WithPrimarySources sets which backend is queried first per method (e.g. "status");
+// the other backend is used as the fallback
+func WithPrimarySources(sources map[string]Source) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		for method, source := range sources {
+			p.primary[method] = source
+		}
+	}
+}
+
+// primarySource returns the backend to query first for method
+func (p *GRPCProxy) primarySource(method string) Source {
+	if source, ok := p.primary[method]; ok {
+		return source
+	}
+	return SourceGRPC
+}
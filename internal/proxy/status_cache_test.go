@@ -0,0 +1,127 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// gatedStatusSequencer holds GetStatus calls until release is closed
+type gatedStatusSequencer struct {
+	statusSequencer
+	release chan struct{}
+}
+
+func (s *gatedStatusSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	<-s.release
+	return s.statusSequencer.GetStatus(ctx, req)
+}
+
+// statusWaiters returns how many requests wait on the in-flight status fetch for key
+func statusWaiters(p *GRPCProxy, key string) int {
+	p.statusLookups.mu.Lock()
+	defer p.statusLookups.mu.Unlock()
+	if call, ok := p.statusLookups.calls[key]; ok {
+		return call.waiters
+	}
+	return 0
+}
+
+func TestHandleUnifiedStatus_Cache(t *testing.T) {
+	tests := []struct {
+		name       string
+		grpcDown   bool
+		restDown   bool
+		expire     bool // Expire the cache before polling again
+		wantStatus int
+		wantCache  []string // X-Cache of each poll
+		wantCalls  int      // Backend calls over both polls
+	}{
+		{"second poll is cached", false, false, false, http.StatusOK, []string{"MISS", "HIT"}, 2},
+		{"partial response is cached", true, false, false, http.StatusOK, []string{"MISS", "HIT"}, 2},
+		{"expired cache is refetched", false, false, true, http.StatusOK, []string{"MISS", "MISS"}, 4},
+		{"errors are not cached", true, true, false, http.StatusServiceUnavailable, []string{"MISS", "MISS"}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &callLog{}
+			p, restURL := statusProxy(t, log, tt.grpcDown, tt.restDown)
+
+			for i, want := range tt.wantCache {
+				if i > 0 && tt.expire {
+					p.statusMu.Lock()
+					p.statusCache.expires = time.Now().Add(-time.Millisecond)
+					p.statusMu.Unlock()
+				}
+				rec := httptest.NewRecorder()
+				p.HandleUnifiedStatus(restURL)(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("poll %d: status = %d, want %d: %s", i+1, rec.Code, tt.wantStatus, rec.Body.String())
+				}
+				if got := rec.Header().Get("X-Cache"); got != want {
+					t.Errorf("poll %d: X-Cache = %q, want %q", i+1, got, want)
+				}
+			}
+			if got := len(log.get()); got != tt.wantCalls {
+				t.Errorf("backend calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHandleUnifiedStatus_ConcurrentPollers(t *testing.T) {
+	tests := []struct {
+		name    string
+		pollers int
+	}{
+		{"one poller", 1},
+		{"many pollers", 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &callLog{}
+			seq := &gatedStatusSequencer{statusSequencer: statusSequencer{log: log}, release: make(chan struct{})}
+			rest := restStatusServer(t, log, false)
+			p, err := NewGRPCProxy(serveSequencer(t, seq), nil, rest.URL, nil)
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			recs := make([]*httptest.ResponseRecorder, tt.pollers)
+			var wg sync.WaitGroup
+			for i := range recs {
+				recs[i] = httptest.NewRecorder()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.HandleUnifiedStatus(rest.URL)(recs[i], httptest.NewRequest(http.MethodGet, "/status", nil))
+				}()
+			}
+			waitFor(t, "every poller to join the fetch", func() bool { return statusWaiters(p, rest.URL) == tt.pollers })
+			close(seq.release)
+			wg.Wait()
+
+			for i, rec := range recs {
+				if rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != "MISS" {
+					t.Errorf("poller %d: status = %d, X-Cache = %q; want 200, MISS", i, rec.Code, rec.Header().Get("X-Cache"))
+				}
+				if rec.Body.String() != recs[0].Body.String() {
+					t.Errorf("poller %d body = %s, want the shared %s", i, rec.Body.String(), recs[0].Body.String())
+				}
+			}
+			if got := log.get(); !slices.Equal(got, []string{"rest", "grpc"}) {
+				t.Errorf("backends called = %v, want one fetch of each", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// holdingSequencer keeps every tick stream open until the client goes
+type holdingSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+}
+
+func (s *holdingSequencer) StreamTicks(req *pb.StreamTicksRequest, stream pb.SequencerService_StreamTicksServer) error {
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestHandleStreamTicks_MaxStreams(t *testing.T) {
+	tests := []struct {
+		name       string
+		max        int
+		open       int // Streams already open
+		wantStatus int // Of one more stream
+	}{
+		{"unlimited", 0, 5, http.StatusOK},
+		{"below the limit", 3, 2, http.StatusOK},
+		{"at the limit", 3, 3, http.StatusServiceUnavailable},
+		{"limit of one", 1, 1, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "active_streams"})
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(gauge)
+
+			p, err := NewGRPCProxy(serveSequencer(t, &holdingSequencer{}), nil, "", nil, WithMaxStreams(tt.max), WithActiveStreamsGauge(gauge))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			stream := func() *httptest.ResponseRecorder {
+				rec := httptest.NewRecorder()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.HandleStreamTicks()(rec, httptest.NewRequest(http.MethodGet, "/stream-ticks", nil).WithContext(ctx))
+				}()
+				return rec
+			}
+			opened := func(n int) func() bool {
+				return func() bool { return p.activeStreams.Load() == int64(n) && gaugeValue(t, registry) == float64(n) }
+			}
+			for range tt.open {
+				stream()
+			}
+			waitFor(t, "streams to open", opened(tt.open))
+
+			if tt.wantStatus == http.StatusOK {
+				rec := stream()
+				waitFor(t, "one more stream to open", opened(tt.open+1))
+				cancel()
+				wg.Wait()
+				if rec.Code != http.StatusOK {
+					t.Errorf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+				}
+			} else {
+				rec := httptest.NewRecorder()
+				p.HandleStreamTicks()(rec, httptest.NewRequest(http.MethodGet, "/stream-ticks", nil))
+				if rec.Code != tt.wantStatus || rec.Header().Get("Retry-After") == "" {
+					t.Errorf("status = %d, Retry-After = %q; want %d with Retry-After", rec.Code, rec.Header().Get("Retry-After"), tt.wantStatus)
+				}
+				if got := gaugeValue(t, registry); got != float64(tt.open) {
+					t.Errorf("gauge = %v, want %d (rejected streams aren't counted)", got, tt.open)
+				}
+				cancel()
+				wg.Wait()
+			}
+
+			if !opened(0)() {
+				t.Errorf("after close: active = %d, gauge = %v; want 0", p.activeStreams.Load(), gaugeValue(t, registry))
+			}
+		})
+	}
+}
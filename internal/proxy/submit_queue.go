@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrSubmitQueueFull is returned when a submission arrives while the queue is at capacity
+var ErrSubmitQueueFull = errors.New("submission queue full")
+
+// submitJob is a queued submission; done is closed once it ran
+type submitJob struct {
+	ctx  context.Context
+	fn   func(ctx context.Context)
+	done chan struct{}
+}
+
+// SubmitQueue smooths submissions to the sequencer through a bounded queue drained by
+// a fixed pool of workers. Submissions beyond the queue capacity are rejected
+type SubmitQueue struct {
+	jobs  chan submitJob
+	depth prometheus.Gauge // Optional; tracks queued submissions
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+// NewSubmitQueue starts workers draining a queue of size submissions
+// depth may be nil
+func NewSubmitQueue(size, workers int, depth prometheus.Gauge) *SubmitQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	q := &SubmitQueue{
+		jobs:  make(chan submitJob, size),
+		depth: depth,
+	}
+
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+// work runs queued submissions until the queue is closed
+func (q *SubmitQueue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		if q.depth != nil {
+			q.depth.Dec()
+		}
+		// Submissions whose client gave up while queued fail fast on their done ctx
+		job.fn(job.ctx)
+		close(job.done)
+	}
+}
+
+// Do queues fn and waits for a worker to run it with ctx
+// It returns ErrSubmitQueueFull without waiting when the queue is at capacity
+func (q *SubmitQueue) Do(ctx context.Context, fn func(ctx context.Context)) error {
+	job := submitJob{ctx: ctx, fn: fn, done: make(chan struct{})}
+
+	// Counted before sending so a worker never decrements first
+	if q.depth != nil {
+		q.depth.Inc()
+	}
+	select {
+	case q.jobs <- job:
+	default:
+		if q.depth != nil {
+			q.depth.Dec()
+		}
+		return ErrSubmitQueueFull
+	}
+
+	<-job.done
+	return nil
+}
+
+// Close stops accepting submissions and waits for queued ones to finish
+func (q *SubmitQueue) Close() {
+	q.once.Do(func() {
+		close(q.jobs)
+	})
+	q.wg.Wait()
+}
+
+// WithSubmitQueue routes submissions to the sequencer through q (default: sent directly)
+func WithSubmitQueue(q *SubmitQueue) GRPCProxyOption {
+	return func(p *GRPCProxy) {
+		p.submitQueue = q
+	}
+}
+
+// submit runs fn through the submission queue when one is configured
+func (p *GRPCProxy) submit(ctx context.Context, fn func(ctx context.Context)) error {
+	if p.submitQueue == nil {
+		fn(ctx)
+		return nil
+	}
+	return p.submitQueue.Do(ctx, fn)
+}
+
+// writeSubmitQueueFull responds 503 asking the client to retry shortly
+func writeSubmitQueueFull(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "1")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write([]byte(`{"error":"submission queue full, retry later"}`))
+}
@@ -0,0 +1,168 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gaugeValue reads the single gauge registered in registry
+func gaugeValue(t *testing.T, registry *prometheus.Registry) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if len(families) != 1 {
+		t.Fatalf("got %d metric families, want 1", len(families))
+	}
+	return families[0].GetMetric()[0].GetGauge().GetValue()
+}
+
+// waitFor polls cond until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// fillQueue occupies every worker and queue slot of q with submissions blocked until
+// release is closed. It returns once they are all running or queued
+func fillQueue(t *testing.T, q *SubmitQueue, workers, size int, release <-chan struct{}, wg *sync.WaitGroup) {
+	t.Helper()
+	var mu sync.Mutex
+	running := 0
+	for i := range workers + size {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := q.Do(context.Background(), func(ctx context.Context) {
+				mu.Lock()
+				running++
+				mu.Unlock()
+				<-release
+			})
+			if err != nil {
+				t.Errorf("submission %d: Do() error = %v", i, err)
+			}
+		}()
+		// Queue slots are only taken once every worker is busy
+		if i < workers {
+			waitFor(t, "a busy worker", func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return running == i+1
+			})
+		}
+	}
+	waitFor(t, "a full queue", func() bool { return len(q.jobs) == size })
+}
+
+func TestSubmitQueue(t *testing.T) {
+	tests := []struct {
+		name          string
+		size, workers int
+	}{
+		{"one worker", 2, 1},
+		{"several workers", 3, 2},
+		{"single slot", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depth := prometheus.NewGauge(prometheus.GaugeOpts{Name: "submit_queue_depth"})
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(depth)
+			q := NewSubmitQueue(tt.size, tt.workers, depth)
+			defer q.Close()
+
+			release := make(chan struct{})
+			var wg sync.WaitGroup
+			fillQueue(t, q, tt.workers, tt.size, release, &wg)
+
+			if got := gaugeValue(t, registry); got != float64(tt.size) {
+				t.Errorf("depth with a full queue = %v, want %d", got, tt.size)
+			}
+
+			// Rejected right away rather than waiting for a slot
+			ran := false
+			if err := q.Do(context.Background(), func(ctx context.Context) { ran = true }); err != ErrSubmitQueueFull {
+				t.Errorf("Do() on a full queue error = %v, want ErrSubmitQueueFull", err)
+			}
+			if ran || gaugeValue(t, registry) != float64(tt.size) {
+				t.Errorf("rejected submission ran = %v, depth = %v", ran, gaugeValue(t, registry))
+			}
+
+			close(release)
+			wg.Wait()
+
+			// Drained: submissions run again
+			if err := q.Do(context.Background(), func(ctx context.Context) { ran = true }); err != nil || !ran {
+				t.Errorf("Do() after draining = %v, ran = %v; want the submission to run", err, ran)
+			}
+			if got := gaugeValue(t, registry); got != 0 {
+				t.Errorf("depth after draining = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func TestHandleSubmit_QueueFull(t *testing.T) {
+	tests := []struct {
+		name    string
+		full    bool
+		handler func(p *GRPCProxy) http.HandlerFunc
+		body    string
+	}{
+		{"transaction", false, (*GRPCProxy).HandleSubmitTransaction, `{"transaction":` + txJSON(`[1]`, "abcd", 1) + `}`},
+		{"transaction, queue full", true, (*GRPCProxy).HandleSubmitTransaction, `{"transaction":` + txJSON(`[1]`, "abcd", 1) + `}`},
+		{"batch", false, (*GRPCProxy).HandleSubmitBatch, `{"transactions":[` + txJSON(`[1]`, "abcd", 1) + `]}`},
+		{"batch, queue full", true, (*GRPCProxy).HandleSubmitBatch, `{"transactions":[` + txJSON(`[1]`, "abcd", 1) + `]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewSubmitQueue(1, 1, nil)
+			defer q.Close()
+			p, sequencer := submitProxy(t, WithSubmitQueue(q))
+
+			release := make(chan struct{})
+			var wg sync.WaitGroup
+			if tt.full {
+				fillQueue(t, q, 1, 1, release, &wg)
+			}
+
+			rec := httptest.NewRecorder()
+			tt.handler(p)(rec, httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(tt.body)))
+			close(release)
+			wg.Wait()
+
+			if !tt.full {
+				if rec.Code != http.StatusOK || len(sequencer.received()) != 1 {
+					t.Errorf("response = %d %s, sequencer received %d; want 200 and 1 transaction", rec.Code, rec.Body.String(), len(sequencer.received()))
+				}
+				return
+			}
+			if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "1" {
+				t.Errorf("response = %d, Retry-After %q; want 503 with Retry-After 1", rec.Code, rec.Header().Get("Retry-After"))
+			}
+			if !strings.Contains(rec.Body.String(), "submission queue full") {
+				t.Errorf("body = %s, want the queue full error", rec.Body.String())
+			}
+			if len(sequencer.received()) != 0 {
+				t.Error("sequencer received a rejected submission")
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+// maxTickRange is the most ticks a single range query may span
+const maxTickRange = 1000
+
+// TicksHandler serves tick queries from the database written by the tick ingester
+type TicksHandler struct {
+	repository *database.Repository
+	logger     *zap.Logger
+}
+
+// NewTicksHandler creates a new ticks handler
+func NewTicksHandler(repository *database.Repository, logger *zap.Logger) *TicksHandler {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &TicksHandler{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// GetTicks handles GET /api/v1/continuum/ticks?from=100&to=200&limit=100
+func (h *TicksHandler) GetTicks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+
+		from, to, err := parseTickRange(query)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		limit := maxTickRange
+		if limitStr := query.Get("limit"); limitStr != "" {
+			parsedLimit, err := strconv.Atoi(limitStr)
+			if err != nil || parsedLimit < 1 || parsedLimit > maxTickRange {
+				h.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid limit (must be 1-%d)", maxTickRange))
+				return
+			}
+			limit = parsedLimit
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		ticks, err := h.repository.GetTicks(ctx, from, to, limit)
+		if err != nil {
+			h.writeQueryError(w, r, "Failed to fetch ticks", err, zap.Uint64("from", from), zap.Uint64("to", to))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Data-Source", "database")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"ticks": ticks,
+			"count": len(ticks),
+		})
+	}
+}
+
+// GetTickStats handles GET /api/v1/continuum/ticks/stats?from=100&to=200
+func (h *TicksHandler) GetTickStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		from, to, err := parseTickRange(r.URL.Query())
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		stats, err := h.repository.GetTickStats(ctx, from, to)
+		if err != nil {
+			h.writeQueryError(w, r, "Failed to fetch tick stats", err, zap.Uint64("from", from), zap.Uint64("to", to))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Data-Source", "database")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"ticks": stats,
+			"count": len(stats),
+		})
+	}
+}
+
+// parseTickRange parses the from/to tick numbers of a range query. to defaults to
+// the widest allowed range, and ranges wider than maxTickRange are rejected
+func parseTickRange(query url.Values) (uint64, uint64, error) {
+	from, err := strconv.ParseUint(query.Get("from"), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid or missing 'from' tick number")
+	}
+	// Tick numbers are stored as BIGINT
+	if from > math.MaxInt64 {
+		return 0, 0, fmt.Errorf("'from' must not exceed %d", int64(math.MaxInt64))
+	}
+
+	to := min(from+maxTickRange-1, math.MaxInt64)
+	if toStr := query.Get("to"); toStr != "" {
+		to, err = strconv.ParseUint(toStr, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid 'to' tick number")
+		}
+		if to > math.MaxInt64 {
+			return 0, 0, fmt.Errorf("'to' must not exceed %d", int64(math.MaxInt64))
+		}
+	}
+
+	if from > to {
+		return 0, 0, fmt.Errorf("'from' must not be greater than 'to'")
+	}
+	if to-from >= maxTickRange {
+		return 0, 0, fmt.Errorf("Tick range cannot exceed %d ticks", maxTickRange)
+	}
+
+	return from, to, nil
+}
+
+// GetTickByBatchHash handles GET /api/v1/continuum/ticks/by-batch-hash/{hash}
+func (h *TicksHandler) GetTickByBatchHash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Batch hashes use the same hex format as transaction hashes
+		batchHash := chi.URLParam(r, "hash")
+		if err := validateTransactionHash(batchHash); err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid batch hash: %v", err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		tick, err := h.repository.GetTickByBatchHash(ctx, batchHash)
+		if errors.Is(err, database.ErrTickNotFound) {
+			h.writeErrorResponse(w, http.StatusNotFound, "Tick not found")
+			return
+		}
+		if err != nil {
+			h.writeQueryError(w, r, "Failed to fetch tick", err, zap.String("batch_hash", batchHash))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Data-Source", "database")
+		json.NewEncoder(w).Encode(tick)
+	}
+}
+
+// GetTransactionsByTick handles GET /api/v1/continuum/tick/{number}/transactions
+func (h *TicksHandler) GetTransactionsByTick() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+
+		tickNumber, err := parseTickNumber(chi.URLParam(r, "number"))
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Tick numbers are stored as BIGINT
+		if tickNumber > math.MaxInt64 {
+			h.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("tick_number must not exceed %d", int64(math.MaxInt64)))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		transactions, err := h.repository.GetTransactionsByTick(ctx, tickNumber)
+		if err != nil {
+			h.writeQueryError(w, r, "Failed to fetch tick transactions", err, zap.Uint64("tick_number", tickNumber))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Data-Source", "database")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"tick_number":  tickNumber,
+			"transactions": transactions,
+			"count":        len(transactions),
+		})
+	}
+}
+
+// writeQueryError logs a failed query and responds 503 when the database is down, 500 otherwise.
+// Queries cut short by a client disconnect are only logged at debug level
+func (h *TicksHandler) writeQueryError(w http.ResponseWriter, r *http.Request, msg string, err error, fields ...zap.Field) {
+	if clientGone(r) {
+		h.logger.Debug(msg, append(fields, zap.Error(err))...)
+		writeClientGone(w)
+		return
+	}
+	h.logger.Error(msg, append(fields, zap.Error(err))...)
+	if errors.Is(err, database.ErrDatabaseUnavailable) {
+		h.writeErrorResponse(w, http.StatusServiceUnavailable, "Database unavailable")
+		return
+	}
+	h.writeErrorResponse(w, http.StatusInternalServerError, msg)
+}
+
+// writeErrorResponse writes an error response in the standard format
+func (h *TicksHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":       nil,
+		"statusCode": statusCode,
+		"error":      message,
+	})
+}
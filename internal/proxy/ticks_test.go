@@ -0,0 +1,230 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/database"
+)
+
+const (
+	maxInt64Str    = "9223372036854775807"
+	maxInt64Plus1  = "9223372036854775808"
+	maxUint64Str   = "18446744073709551615"
+	maxUint64Plus1 = "18446744073709551616"
+)
+
+func TestParseTickRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantFrom uint64
+		wantTo   uint64
+		wantErr  string
+	}{
+		{"from only", "from=100", 100, 100 + maxTickRange - 1, ""},
+		{"from and to", "from=100&to=200", 100, 200, ""},
+		{"single tick", "from=5&to=5", 5, 5, ""},
+		{"widest range", "from=1&to=1000", 1, 1000, ""},
+		{"missing from", "to=10", 0, 0, "Invalid or missing 'from'"},
+		{"negative from", "from=-1", 0, 0, "Invalid or missing 'from'"},
+		{"invalid to", "from=1&to=abc", 0, 0, "Invalid 'to'"},
+		{"from after to", "from=10&to=9", 0, 0, "must not be greater than 'to'"},
+		{"range too wide", "from=1&to=1001", 0, 0, "cannot exceed 1000 ticks"},
+		{"from at MaxInt64", "from=" + maxInt64Str, 1<<63 - 1, 1<<63 - 1, ""},
+		{"to at MaxInt64", "from=9223372036854775000&to=" + maxInt64Str, 9223372036854775000, 1<<63 - 1, ""},
+		{"from at MaxInt64+1", "from=" + maxInt64Plus1, 0, 0, "'from' must not exceed"},
+		{"from at MaxUint64", "from=" + maxUint64Str, 0, 0, "'from' must not exceed"},
+		{"from above MaxUint64", "from=" + maxUint64Plus1, 0, 0, "Invalid or missing 'from'"},
+		{"to at MaxInt64+1", "from=1&to=" + maxInt64Plus1, 0, 0, "'to' must not exceed"},
+		{"to at MaxUint64", "from=1&to=" + maxUint64Str, 0, 0, "'to' must not exceed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			from, to, err := parseTickRange(query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseTickRange() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTickRange() error = %v", err)
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("parseTickRange() = %d, %d; want %d, %d", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestParseTickNumber(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    uint64
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"42", 42, false},
+		{maxInt64Str, 1<<63 - 1, false},
+		{maxUint64Str, 1<<64 - 1, false}, // Valid for the sequencer; database lookups reject it
+		{maxUint64Plus1, 0, true},
+		{"", 0, true},
+		{"-1", 0, true},
+		{"1e3", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTickNumber(tt.input)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseTickNumber(%q) = %d, %v; want %d, error %v", tt.input, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetTransactionsByTick_TickNumber(t *testing.T) {
+	tests := []struct {
+		name       string
+		number     string
+		wantStatus int
+		wantError  string
+	}{
+		{"at MaxInt64", maxInt64Str, http.StatusServiceUnavailable, "Database unavailable"},
+		{"at MaxInt64+1", maxInt64Plus1, http.StatusBadRequest, "must not exceed"},
+		{"at MaxUint64", maxUint64Str, http.StatusBadRequest, "must not exceed"},
+		{"above MaxUint64", maxUint64Plus1, http.StatusBadRequest, "invalid tick_number"},
+		{"not a number", "abc", http.StatusBadRequest, "invalid tick_number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Valid tick numbers reach the (unconnected) database
+			h := NewTicksHandler(database.NewRepository(nil), nil)
+			router := chi.NewRouter()
+			router.Get("/tick/{number}/transactions", h.GetTransactionsByTick())
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tick/"+tt.number+"/transactions", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if !strings.Contains(body.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", body.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGetTicks_TickRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"to at MaxInt64", "from=9223372036854775000&to=" + maxInt64Str, http.StatusServiceUnavailable},
+		{"from at MaxInt64+1", "from=" + maxInt64Plus1, http.StatusBadRequest},
+		{"to at MaxUint64", "from=1&to=" + maxUint64Str, http.StatusBadRequest},
+		{"invalid limit", "from=1&limit=0", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTicksHandler(database.NewRepository(nil), nil)
+			rec := httptest.NewRecorder()
+			h.GetTicks()(rec, httptest.NewRequest(http.MethodGet, "/ticks?"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetTickByBatchHash_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		hash       string
+		wantStatus int
+		wantError  string
+	}{
+		{"valid hash", "ab01CD", http.StatusServiceUnavailable, "Database unavailable"},
+		{"not hex", "xyz", http.StatusBadRequest, "must be a valid hex string"},
+		{"too long", strings.Repeat("a", 129), http.StatusBadRequest, "hash too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Valid hashes reach the (unconnected) database
+			h := NewTicksHandler(database.NewRepository(nil), nil)
+			router := chi.NewRouter()
+			router.Get("/ticks/by-batch-hash/{hash}", h.GetTickByBatchHash())
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ticks/by-batch-hash/"+tt.hash, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if !strings.Contains(body.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", body.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGetTickStats_TickRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		wantStatus int
+	}{
+		{"valid range", http.MethodGet, "from=1&to=10", http.StatusServiceUnavailable},
+		{"from only", http.MethodGet, "from=1", http.StatusServiceUnavailable},
+		{"missing from", http.MethodGet, "to=10", http.StatusBadRequest},
+		{"range too wide", http.MethodGet, "from=1&to=1001", http.StatusBadRequest},
+		{"from after to", http.MethodGet, "from=10&to=1", http.StatusBadRequest},
+		{"to at MaxUint64", http.MethodGet, "from=1&to=" + maxUint64Str, http.StatusBadRequest},
+		{"wrong method", http.MethodPost, "from=1", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Valid ranges reach the (unconnected) database
+			h := NewTicksHandler(database.NewRepository(nil), nil)
+			rec := httptest.NewRecorder()
+			h.GetTickStats()(rec, httptest.NewRequest(tt.method, "/ticks/stats?"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"time"
 
+	"go.uber.org/zap"
+
 	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
 )
 
@@ -33,9 +35,27 @@ type UnifiedStatusResponse struct {
 	TxnPerSecond      float64 `json:"txn_per_second"`      // Calculated: REST.total_transactions / 60
 	TicksPerSecond    float64 `json:"ticks_per_second"`    // From REST.last_60_seconds
 	AverageTickTime   float64 `json:"average_tick_time"`   // From REST.last_60_seconds (microseconds)
+
+	// Smoothed across polls (30s time constant) so dashboards show a stable rate
+	TxnPerSecondEMA   float64 `json:"txn_per_second_ema"`
+	TicksPerSecondEMA float64 `json:"ticks_per_second_ema"`
+}
+
+// statusTTL is how long a unified status response is served from cache
+const statusTTL = 500 * time.Millisecond
+
+// statusSnapshot is a rendered unified status response
+type statusSnapshot struct {
+	status     int
+	body       []byte
+	dataSource string // X-Data-Source value (empty on error)
+	expires    time.Time
 }
 
 // HandleUnifiedStatus creates a unified status endpoint that merges REST status and gRPC GetStatus
+// The primary source for "status" is queried first; if either backend fails the other's data is
+// returned alone as a partial response. Successful responses are cached for statusTTL and
+// concurrent pollers share one upstream fetch
 func (p *GRPCProxy) HandleUnifiedStatus(restURL string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -43,94 +63,178 @@ func (p *GRPCProxy) HandleUnifiedStatus(restURL string) http.HandlerFunc {
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-		defer cancel()
+		cacheStatus := "HIT"
+		snapshot := p.cachedStatus()
+		if snapshot == nil {
+			cacheStatus = "MISS"
+			// The shared fetch is only canceled once every polling client has gone
+			v, err := p.statusLookups.Do(r.Context(), restURL, 10*time.Second, func(ctx context.Context) interface{} {
+				snapshot := p.fetchUnifiedStatus(ctx, restURL)
+				if snapshot.status == http.StatusOK {
+					p.storeStatus(snapshot)
+				}
+				return snapshot
+			})
+			if err != nil {
+				writeClientGone(w)
+				return
+			}
+			snapshot = v.(*statusSnapshot)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
-
-		// Fetch gRPC GetStatus (optional - don't fail if unavailable)
-		var grpcResp *pb.GetStatusResponse
-		var grpcErr error
-		grpcResp, grpcErr = p.client.GetStatus(ctx, &pb.GetStatusRequest{})
-		if grpcErr != nil {
-			// Log but don't fail - we'll use REST data only
-			grpcResp = nil
+		w.Header().Set("X-Cache", cacheStatus)
+		if snapshot.dataSource != "" {
+			w.Header().Set("X-Data-Source", snapshot.dataSource)
 		}
+		w.WriteHeader(snapshot.status)
+		w.Write(snapshot.body)
+	}
+}
 
-		// Fetch REST /status
-		restStatusURL := fmt.Sprintf("%s/status", restURL)
-		httpReq, err := http.NewRequestWithContext(ctx, "GET", restStatusURL, nil)
-		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"failed to create REST request: %v"}`, err), http.StatusInternalServerError)
-			return
-		}
+// cachedStatus returns the cached unified status, or nil if there is none or it expired
+func (p *GRPCProxy) cachedStatus() *statusSnapshot {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	if p.statusCache == nil || time.Now().After(p.statusCache.expires) {
+		return nil
+	}
+	return p.statusCache
+}
 
-		httpResp, err := http.DefaultClient.Do(httpReq)
-		if err != nil {
-			// If REST also fails, return error
-			if grpcErr != nil {
-				http.Error(w, fmt.Sprintf(`{"error":"both backends unavailable: gRPC: %v, REST: %v"}`, grpcErr, err), http.StatusServiceUnavailable)
-			} else {
-				http.Error(w, fmt.Sprintf(`{"error":"failed to fetch REST status: %v"}`, err), http.StatusServiceUnavailable)
-			}
-			return
-		}
-		defer httpResp.Body.Close()
-
-		if httpResp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(httpResp.Body)
-			if grpcErr != nil {
-				http.Error(w, fmt.Sprintf(`{"error":"REST returned %d: %s, gRPC unavailable: %v"}`, httpResp.StatusCode, string(body), grpcErr), http.StatusServiceUnavailable)
-			} else {
-				http.Error(w, fmt.Sprintf(`{"error":"REST returned %d: %s"}`, httpResp.StatusCode, string(body)), http.StatusServiceUnavailable)
-			}
-			return
-		}
+// storeStatus caches snapshot for statusTTL
+func (p *GRPCProxy) storeStatus(snapshot *statusSnapshot) {
+	snapshot.expires = time.Now().Add(statusTTL)
+	p.statusMu.Lock()
+	p.statusCache = snapshot
+	p.statusMu.Unlock()
+}
 
-		body, err := io.ReadAll(httpResp.Body)
-		if err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"failed to read REST response: %v"}`, err), http.StatusInternalServerError)
-			return
+// fetchUnifiedStatus queries both backends and renders the merged status
+func (p *GRPCProxy) fetchUnifiedStatus(ctx context.Context, restURL string) *statusSnapshot {
+	var grpcResp *pb.GetStatusResponse
+	var restResp *RESTStatusResponse
+	var grpcErr, restErr error
+
+	fetch := func(source Source) {
+		if source == SourceGRPC {
+			grpcResp, grpcErr = p.client.GetStatus(ctx, &pb.GetStatusRequest{})
+		} else {
+			restResp, restErr = fetchRESTStatus(ctx, restURL)
 		}
+	}
 
-		var restResp RESTStatusResponse
-		if err := json.Unmarshal(body, &restResp); err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"failed to parse REST response: %v"}`, err), http.StatusInternalServerError)
-			return
-		}
+	primary := p.primarySource("status")
+	fetch(primary)
+	fetch(primary.other())
 
-		// Calculate txn_per_second: REST total_transactions is for last 60 seconds
-		txnPerSecond := float64(restResp.TotalTransactions) / 60.0
-
-		// Build unified response with fallback values if gRPC unavailable
-		unified := UnifiedStatusResponse{
-			ChainHeight:       restResp.ChainHeight,
-			TotalTransactions: restResp.TotalTransactions, // Fallback to REST value if gRPC unavailable
-			Status:            restResp.Status,
-			UptimeSeconds:     0, // Only available from gRPC
-			TxnPerSecond:      txnPerSecond,
-			TicksPerSecond:    restResp.Last60Seconds.TicksPerSecond,
-			AverageTickTime:   restResp.Last60Seconds.MeanTickTimeMicros,
+	if grpcErr != nil && restErr != nil {
+		return &statusSnapshot{
+			status: http.StatusServiceUnavailable,
+			body:   []byte(fmt.Sprintf(`{"error":"both backends unavailable: gRPC: %v, REST: %v"}`+"\n", grpcErr, restErr)),
 		}
+	}
 
-		// If gRPC available, use its values
-		if grpcResp != nil {
-			unified.TotalTransactions = grpcResp.TotalTransactions // Lifetime total from gRPC
-			unified.UptimeSeconds = grpcResp.UptimeSeconds
-		}
+	unified := UnifiedStatusResponse{}
+
+	if restResp != nil {
+		unified.ChainHeight = restResp.ChainHeight
+		unified.TotalTransactions = restResp.TotalTransactions // Fallback to REST value if gRPC unavailable
+		unified.Status = restResp.Status
+		// REST total_transactions is for last 60 seconds
+		unified.TxnPerSecond = float64(restResp.TotalTransactions) / 60.0
+		unified.TicksPerSecond = restResp.Last60Seconds.TicksPerSecond
+		unified.AverageTickTime = restResp.Last60Seconds.MeanTickTimeMicros
+	} else {
+		// gRPC only: no 60s window or status string, use its own counters
+		unified.ChainHeight = grpcResp.CurrentTick
+		unified.Status = "unknown"
+		unified.TxnPerSecond = grpcResp.TransactionsPerSecond
+	}
 
-		// Return merged JSON response (include warning if gRPC unavailable)
-		if grpcErr != nil {
-			// Add a note that gRPC data is partial
-			unifiedJson, _ := json.Marshal(unified)
-			w.WriteHeader(http.StatusOK)
-			fmt.Fprintf(w, `{"status":"partial","warnings":["gRPC backend unavailable, using REST data only"],"data":%s}`, string(unifiedJson))
-			return
-		}
+	// If gRPC available, use its values
+	if grpcResp != nil {
+		unified.TotalTransactions = grpcResp.TotalTransactions // Lifetime total from gRPC
+		unified.UptimeSeconds = grpcResp.UptimeSeconds
+	}
 
-		if err := json.NewEncoder(w).Encode(unified); err != nil {
-			http.Error(w, fmt.Sprintf(`{"error":"failed to encode response: %v"}`, err), http.StatusInternalServerError)
-			return
+	now := time.Now()
+	unified.TxnPerSecondEMA = p.txnRate.Update(unified.TxnPerSecond, now)
+	if restResp != nil {
+		// gRPC has no tick rate; keep the last average rather than pulling it toward 0
+		unified.TicksPerSecondEMA = p.tickRate.Update(unified.TicksPerSecond, now)
+	} else {
+		unified.TicksPerSecondEMA = p.tickRate.Value()
+	}
+
+	// Merged JSON response (include warning if one backend was unavailable)
+	snapshot := &statusSnapshot{status: http.StatusOK}
+	var warning string
+	switch {
+	case grpcErr != nil:
+		warning = "gRPC backend unavailable, using REST data only"
+		snapshot.dataSource = "rest-api"
+		p.recordDegraded(SourceGRPC, grpcErr)
+	case restErr != nil:
+		warning = "REST backend unavailable, using gRPC data only"
+		snapshot.dataSource = "grpc"
+		p.recordDegraded(SourceREST, restErr)
+	default:
+		snapshot.dataSource = "grpc+rest-api"
+	}
+
+	unifiedJson, err := json.Marshal(unified)
+	if err != nil {
+		return &statusSnapshot{
+			status: http.StatusInternalServerError,
+			body:   []byte(fmt.Sprintf(`{"error":"failed to encode response: %v"}`+"\n", err)),
 		}
 	}
+
+	if warning != "" {
+		snapshot.body = []byte(fmt.Sprintf(`{"status":"partial","warnings":[%q],"data":%s}`, warning, string(unifiedJson)))
+	} else {
+		snapshot.body = append(unifiedJson, '\n')
+	}
+	return snapshot
+}
+
+// recordDegraded counts and logs a partial status response caused by the failed backend
+func (p *GRPCProxy) recordDegraded(failed Source, err error) {
+	if p.degraded != nil {
+		p.degraded.WithLabelValues(string(failed)).Inc()
+	}
+	p.logger.Warn("Serving partial unified status",
+		zap.String("failed_backend", string(failed)),
+		zap.Error(err))
+}
+
+// fetchRESTStatus fetches and decodes the REST /status endpoint
+func fetchRESTStatus(ctx context.Context, restURL string) (*RESTStatusResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/status", restURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST request: %w", err)
+	}
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch REST status: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read REST response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("REST returned %d: %s", httpResp.StatusCode, string(body))
+	}
+
+	var restResp RESTStatusResponse
+	if err := json.Unmarshal(body, &restResp); err != nil {
+		return nil, fmt.Errorf("failed to parse REST response: %w", err)
+	}
+
+	return &restResp, nil
 }
@@ -0,0 +1,215 @@
+package proxy
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+)
+
+// callLog records which backends were called, in order
+type callLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (l *callLog) add(backend string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, backend)
+}
+
+func (l *callLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.calls)
+}
+
+// statusSequencer answers GetStatus, logging each call, or fails when down
+type statusSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+	log  *callLog
+	down bool
+}
+
+func (s *statusSequencer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	s.log.add("grpc")
+	if s.down {
+		return nil, status.Error(codes.Unavailable, "sequencer down")
+	}
+	return &pb.GetStatusResponse{CurrentTick: 100, TotalTransactions: 5000, UptimeSeconds: 60}, nil
+}
+
+// restStatusServer serves the REST /status endpoint, logging each call, or fails when down
+func restStatusServer(t *testing.T, log *callLog, down bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.add("rest")
+		if down {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"chain_height":100,"total_transactions":600,"latest_tick":100,"status":"running",` +
+			`"last_60_seconds":{"tick_count":6000,"mean_tick_time_micros":10000,"ticks_per_second":100}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// statusProxy creates a proxy backed by a fake sequencer and REST API sharing log
+func statusProxy(t *testing.T, log *callLog, grpcDown, restDown bool, opts ...GRPCProxyOption) (*GRPCProxy, string) {
+	t.Helper()
+	addr := serveSequencer(t, &statusSequencer{log: log, down: grpcDown})
+	rest := restStatusServer(t, log, restDown)
+	p, err := NewGRPCProxy(addr, nil, rest.URL, nil, opts...)
+	if err != nil {
+		t.Fatalf("NewGRPCProxy() error = %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p, rest.URL
+}
+
+func TestHandleUnifiedStatus_PrimarySource(t *testing.T) {
+	tests := []struct {
+		name       string
+		sources    map[string]Source
+		grpcDown   bool
+		restDown   bool
+		wantCalls  []string
+		wantSource string
+	}{
+		{"default is REST first", nil, false, false, []string{"rest", "grpc"}, "grpc+rest-api"},
+		{"gRPC first", map[string]Source{"status": SourceGRPC}, false, false, []string{"grpc", "rest"}, "grpc+rest-api"},
+		{"REST first", map[string]Source{"status": SourceREST}, false, false, []string{"rest", "grpc"}, "grpc+rest-api"},
+		{"other methods don't change status", map[string]Source{"tick": SourceGRPC}, false, false, []string{"rest", "grpc"}, "grpc+rest-api"},
+		{"gRPC primary down falls back to REST", map[string]Source{"status": SourceGRPC}, true, false, []string{"grpc", "rest"}, "rest-api"},
+		{"REST primary down falls back to gRPC", map[string]Source{"status": SourceREST}, false, true, []string{"rest", "grpc"}, "grpc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &callLog{}
+			p, restURL := statusProxy(t, log, tt.grpcDown, tt.restDown, WithPrimarySources(tt.sources))
+
+			rec := httptest.NewRecorder()
+			p.HandleUnifiedStatus(restURL)(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			if got := log.get(); !slices.Equal(got, tt.wantCalls) {
+				t.Errorf("backends called = %v, want %v", got, tt.wantCalls)
+			}
+			if got := rec.Header().Get("X-Data-Source"); got != tt.wantSource {
+				t.Errorf("X-Data-Source = %q, want %q", got, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestGRPCProxy_PrimarySource(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]Source
+		method  string
+		want    Source
+	}{
+		{"status default", nil, "status", SourceREST},
+		{"unlisted method", nil, "tick", SourceGRPC},
+		{"overridden", map[string]Source{"status": SourceGRPC}, "status", SourceGRPC},
+		{"new method", map[string]Source{"tick": SourceREST}, "tick", SourceREST},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestGRPCProxy(t, nil, WithPrimarySources(tt.sources))
+			defer p.Close()
+			if got := p.primarySource(tt.method); got != tt.want {
+				t.Errorf("primarySource(%q) = %q, want %q", tt.method, got, tt.want)
+			}
+			if got := p.primarySource(tt.method).other(); got == tt.want {
+				t.Errorf("fallback for %q = %q, want the other source", tt.method, got)
+			}
+		})
+	}
+}
+
+// labeledCounts reads the value of each label of the single counter vector in registry
+func labeledCounts(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	counts := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			counts[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+		}
+	}
+	return counts
+}
+
+func TestHandleUnifiedStatus_Degraded(t *testing.T) {
+	tests := []struct {
+		name       string
+		grpcDown   bool
+		restDown   bool
+		wantStatus int
+		wantCounts map[string]float64
+	}{
+		{"both up", false, false, http.StatusOK, map[string]float64{}},
+		{"gRPC down", true, false, http.StatusOK, map[string]float64{"grpc": 1}},
+		{"REST down", false, true, http.StatusOK, map[string]float64{"rest": 1}},
+		{"both down is not partial", true, true, http.StatusServiceUnavailable, map[string]float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			degraded := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "degraded_total"}, []string{"failed_backend"})
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(degraded)
+			core, logs := observer.New(zapcore.WarnLevel)
+
+			log := &callLog{}
+			rest := restStatusServer(t, log, tt.restDown)
+			p, err := NewGRPCProxy(serveSequencer(t, &statusSequencer{log: log, down: tt.grpcDown}), nil, rest.URL, zap.New(core), WithDegradedCounter(degraded))
+			if err != nil {
+				t.Fatalf("NewGRPCProxy() error = %v", err)
+			}
+			defer p.Close()
+
+			rec := httptest.NewRecorder()
+			p.HandleUnifiedStatus(rest.URL)(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := labeledCounts(t, registry); !maps.Equal(got, tt.wantCounts) {
+				t.Errorf("partial responses = %v, want %v", got, tt.wantCounts)
+			}
+
+			entries := logs.FilterMessage("Serving partial unified status").All()
+			if len(entries) != len(tt.wantCounts) {
+				t.Fatalf("logged %d partial responses, want %d", len(entries), len(tt.wantCounts))
+			}
+			for _, entry := range entries {
+				if backend := entry.ContextMap()["failed_backend"].(string); tt.wantCounts[backend] != 1 {
+					t.Errorf("logged failed_backend = %q, want one of %v", backend, tt.wantCounts)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// GlobalLimiter is a single token bucket shared by all clients
+// It caps total throughput regardless of how many IPs the traffic comes from
+type GlobalLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewGlobalLimiter creates a gateway-wide rate limiter
+// rate: requests per second across all clients (0 = unlimited)
+// burst: maximum burst size
+func NewGlobalLimiter(r float64, b int) *GlobalLimiter {
+	g := &GlobalLimiter{limiter: rate.NewLimiter(rate.Inf, b)}
+	g.SetRate(r, b)
+	return g
+}
+
+// SetRate changes the gateway-wide rate and burst (rate 0 = unlimited)
+func (g *GlobalLimiter) SetRate(r float64, b int) {
+	limit := rate.Limit(r)
+	if r <= 0 {
+		limit = rate.Inf
+	}
+	g.limiter.SetLimit(limit)
+	g.limiter.SetBurst(b)
+}
+
+// Allow checks if the gateway has capacity for another request
+func (g *GlobalLimiter) Allow() bool {
+	return g.limiter.Allow()
+}
+
+// GlobalMiddleware creates a middleware that rejects requests with 429 once the
+// whole gateway is over capacity. Apply it before any per-IP Middleware.
+// onReject is called for every rejected request (may be nil)
+func GlobalMiddleware(limiter *GlobalLimiter, onReject func()) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.Allow() {
+				if onReject != nil {
+					onReject()
+				}
+				writeRateLimited(w, r, limiter.limiter.Burst(), "Server is over capacity. Please try again later.")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// serveFrom sends GET /api/v1/x from ip (with a request ID) to handler
+func serveFrom(handler http.Handler, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/x", nil)
+	req.RemoteAddr = ip + ":5000"
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGlobalMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		rps         float64
+		burst       int
+		ips         []string // One request per entry
+		wantAllowed int
+	}{
+		{"unlimited", 0, 0, []string{"10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.1"}, 4},
+		{"within burst", 0.001, 3, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, 3},
+		{"over burst from one IP", 0.001, 2, []string{"10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.1"}, 2},
+		{"over burst across IPs", 0.001, 2, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected := 0
+			handler := GlobalMiddleware(NewGlobalLimiter(tt.rps, tt.burst), func() { rejected++ })(okHandler)
+
+			allowed := 0
+			for _, ip := range tt.ips {
+				rec := serveFrom(handler, ip)
+				if rec.Code == http.StatusOK {
+					allowed++
+					continue
+				}
+
+				if rec.Code != http.StatusTooManyRequests {
+					t.Fatalf("status = %d, want 200 or 429", rec.Code)
+				}
+				if rec.Header().Get("X-RateLimit-Remaining") != "0" || rec.Header().Get("X-RateLimit-Limit") == "" {
+					t.Errorf("rate limit headers = %v", rec.Header())
+				}
+				var body map[string]string
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				if body["message"] != "Server is over capacity. Please try again later." || body["request_id"] != "req-1" {
+					t.Errorf("body = %v", body)
+				}
+			}
+
+			if allowed != tt.wantAllowed {
+				t.Errorf("allowed %d of %d requests, want %d", allowed, len(tt.ips), tt.wantAllowed)
+			}
+			if rejected != len(tt.ips)-allowed {
+				t.Errorf("onReject called %d times, want %d", rejected, len(tt.ips)-allowed)
+			}
+		})
+	}
+}
+
+func TestGlobalMiddleware_BeforePerIPLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		globalRPS float64
+		ips       []string
+		want      []int // Status per request
+	}{
+		{
+			name:      "global limit hit first",
+			globalRPS: 0.001,
+			ips:       []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+			want:      []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:      "per-IP limit still applies",
+			globalRPS: 0,
+			ips:       []string{"10.0.0.1", "10.0.0.1", "10.0.0.2"},
+			want:      []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			global := GlobalMiddleware(NewGlobalLimiter(tt.globalRPS, 2), nil)
+			perIP := Middleware(NewIPRateLimiter(0.001, 1), nil)
+			handler := global(perIP(okHandler))
+
+			for i, ip := range tt.ips {
+				if rec := serveFrom(handler, ip); rec.Code != tt.want[i] {
+					t.Errorf("request %d from %s: status = %d, want %d", i, ip, rec.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGlobalLimiter_SetRate(t *testing.T) {
+	g := NewGlobalLimiter(0.001, 1)
+	if !g.Allow() || g.Allow() {
+		t.Fatal("want one request allowed at burst 1")
+	}
+
+	g.SetRate(0, 1)
+	for i := range 5 {
+		if !g.Allow() {
+			t.Fatalf("request %d denied after disabling the limit", i)
+		}
+	}
+}
@@ -1,12 +1,27 @@
 package ratelimit
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
+// Limiter decides whether a request identified by key (the client IP) may proceed
+// Implementations: IPRateLimiter (per-instance, in-memory) and RedisRateLimiter (shared across replicas)
+type Limiter interface {
+	// Check consumes one request for key and reports whether it is allowed
+	// and how many requests remain in the bucket
+	Check(ctx context.Context, key string) (allowed bool, remaining int, err error)
+
+	// Limit returns the burst size advertised in X-RateLimit-Limit
+	Limit() int
+
+	// SetRate changes the rate (requests per second) and burst, e.g. on config reload
+	SetRate(r float64, b int)
+}
+
 // ipLimiter holds a rate limiter and the last time it was used
 type ipLimiter struct {
 	limiter  *rate.Limiter
@@ -65,6 +80,34 @@ func (i *IPRateLimiter) Allow(ip string) bool {
 	return limiter.Allow()
 }
 
+// Check implements Limiter
+func (i *IPRateLimiter) Check(_ context.Context, ip string) (bool, int, error) {
+	limiter := i.GetLimiter(ip)
+	allowed := limiter.Allow()
+	return allowed, int(limiter.Tokens()), nil
+}
+
+// Limit implements Limiter
+func (i *IPRateLimiter) Limit() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.burst
+}
+
+// SetRate implements Limiter
+// Existing per-IP buckets keep their tokens but refill at the new rate
+func (i *IPRateLimiter) SetRate(r float64, b int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rate = rate.Limit(r)
+	i.burst = b
+	for _, limiterInfo := range i.limiters {
+		limiterInfo.limiter.SetLimit(i.rate)
+		limiterInfo.limiter.SetBurst(i.burst)
+	}
+}
+
 // cleanup removes old unused limiters to prevent memory leaks
 func (i *IPRateLimiter) cleanup(stop chan struct{}) {
 	ticker := time.NewTicker(i.cleanupInterval)
@@ -7,45 +7,59 @@ import (
 )
 
 // Middleware creates a rate limiting middleware
-func Middleware(limiter *IPRateLimiter) func(http.Handler) http.Handler {
+// extractIP determines the client IP each request is limited by (nil = ExtractIP)
+// If the limiter backend fails (e.g. Redis is unreachable) requests are allowed through
+func Middleware(limiter Limiter, extractIP IPExtractor) func(http.Handler) http.Handler {
+	if extractIP == nil {
+		extractIP = ExtractIP
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Extract client IP
-			ip := ExtractIP(r)
-
-			// Get limiter for this IP
-			l := limiter.GetLimiter(ip)
+			ip := extractIP(r)
 
 			// Check if request is allowed
-			if !l.Allow() {
-				// Set rate limit headers
-				w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%v", limiter.burst))
-				w.Header().Set("X-RateLimit-Remaining", "0")
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusTooManyRequests)
-
-				// Return error response
-				response := map[string]interface{}{
-					"error":   "Rate Limit Exceeded",
-					"message": "Too many requests. Please try again later.",
-				}
-
-				// Include request ID if available
-				if requestID := r.Header.Get("X-Request-ID"); requestID != "" {
-					response["request_id"] = requestID
-				}
-
-				json.NewEncoder(w).Encode(response)
+			allowed, remaining, err := limiter.Check(r.Context(), ip)
+			if err != nil {
+				// Fail open: an unavailable limiter backend must not take the gateway down
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				writeRateLimited(w, r, limiter.Limit(), "Too many requests. Please try again later.")
 				return
 			}
 
 			// Request allowed - set rate limit headers
-			tokens := int(l.Tokens())
-			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.burst))
-			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", tokens))
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.Limit()))
+			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
 
 			// Continue to next handler
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// writeRateLimited writes a 429 JSON response with rate limit headers
+func writeRateLimited(w http.ResponseWriter, r *http.Request, limit int, message string) {
+	// Set rate limit headers
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
+	w.Header().Set("X-RateLimit-Remaining", "0")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+
+	// Return error response
+	response := map[string]interface{}{
+		"error":   "Rate Limit Exceeded",
+		"message": message,
+	}
+
+	// Include request ID if available
+	if requestID := r.Header.Get("X-Request-ID"); requestID != "" {
+		response["request_id"] = requestID
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// request is one request in a rate limit scenario
+type request struct {
+	method, path, ip string
+	want             int
+}
+
+func TestMiddleware_StricterSubmitLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		groupRPM  int
+		submitRPM int
+		requests  []request
+	}{
+		{
+			name:      "submissions limited before the group limit",
+			groupRPM:  10,
+			submitRPM: 2,
+			requests: []request{
+				{http.MethodPost, "/tx", "10.0.0.1", http.StatusOK},
+				{http.MethodPost, "/tx/batch", "10.0.0.1", http.StatusOK},
+				{http.MethodPost, "/tx", "10.0.0.1", http.StatusTooManyRequests},
+				{http.MethodGet, "/tx/recent", "10.0.0.1", http.StatusOK},
+			},
+		},
+		{
+			name:      "submissions are per IP",
+			groupRPM:  10,
+			submitRPM: 1,
+			requests: []request{
+				{http.MethodPost, "/tx", "10.0.0.1", http.StatusOK},
+				{http.MethodPost, "/tx", "10.0.0.1", http.StatusTooManyRequests},
+				{http.MethodPost, "/tx", "10.0.0.2", http.StatusOK},
+			},
+		},
+		{
+			name:      "submissions also count against the group limit",
+			groupRPM:  2,
+			submitRPM: 10,
+			requests: []request{
+				{http.MethodPost, "/tx", "10.0.0.1", http.StatusOK},
+				{http.MethodPost, "/tx", "10.0.0.1", http.StatusOK},
+				{http.MethodGet, "/tx/recent", "10.0.0.1", http.StatusTooManyRequests},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wired like the gateway's /continuum routes, with no refill during the test
+			group := NewIPRateLimiter(0.0001, tt.groupRPM)
+			submitLimiter := NewIPRateLimiter(0.0001, tt.submitRPM)
+			r := chi.NewRouter()
+			r.Use(Middleware(group, nil))
+			submit := r.With(Middleware(submitLimiter, nil))
+			submit.Post("/tx", okHandler)
+			submit.Post("/tx/batch", okHandler)
+			r.Get("/tx/recent", okHandler)
+
+			for i, req := range tt.requests {
+				httpReq := httptest.NewRequest(req.method, req.path, nil)
+				httpReq.RemoteAddr = req.ip + ":5000"
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, httpReq)
+
+				if rec.Code != req.want {
+					t.Errorf("request %d (%s %s from %s): status = %d, want %d", i, req.method, req.path, req.ip, rec.Code, req.want)
+				}
+			}
+		})
+	}
+}
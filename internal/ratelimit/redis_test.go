@@ -0,0 +1,299 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis runs the token bucket script in Go against an in-memory store
+// Limiters sharing one fakeRedis behave like gateway replicas sharing a Redis server
+type fakeRedis struct {
+	redis.Scripter // Only EvalSha is used
+
+	mu      sync.Mutex
+	now     time.Time
+	buckets map[string]*fakeBucket
+	keys    []string
+	result  []interface{} // Returned instead of running the script when set
+	err     error
+}
+
+type fakeBucket struct {
+	tokens float64
+	ts     time.Time
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{now: time.Unix(1700000000, 0), buckets: make(map[string]*fakeBucket)}
+}
+
+func (f *fakeRedis) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func (f *fakeRedis) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = append(f.keys, keys[0])
+	if f.err != nil || f.result != nil {
+		return redis.NewCmdResult(f.result, f.err)
+	}
+
+	rate, burst := args[0].(float64), float64(args[1].(int))
+	b, ok := f.buckets[keys[0]]
+	if !ok {
+		b = &fakeBucket{tokens: burst, ts: f.now}
+		f.buckets[keys[0]] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+f.now.Sub(b.ts).Seconds()*rate)
+	b.ts = f.now
+
+	allowed := int64(0)
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = 1
+	}
+	return redis.NewCmdResult([]interface{}{allowed, int64(math.Floor(b.tokens))}, nil)
+}
+
+// check makes n requests from ip and returns how many were allowed
+func check(t *testing.T, l Limiter, ip string, n int) int {
+	t.Helper()
+	allowed := 0
+	for range n {
+		ok, _, err := l.Check(context.Background(), ip)
+		if err != nil {
+			t.Fatalf("Check(%q) error = %v", ip, err)
+		}
+		if ok {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestRedisRateLimiter_SharedAcrossInstances(t *testing.T) {
+	type call struct {
+		instance int
+		prefix   string
+		ip       string
+		n        int
+		want     int // Allowed
+	}
+	tests := []struct {
+		name  string
+		calls []call
+	}{
+		{
+			name: "one instance",
+			calls: []call{
+				{0, "rollup", "10.0.0.1", 5, 3},
+			},
+		},
+		{
+			name: "replicas share a bucket",
+			calls: []call{
+				{0, "rollup", "10.0.0.1", 2, 2},
+				{1, "rollup", "10.0.0.1", 2, 1},
+				{2, "rollup", "10.0.0.1", 2, 0},
+			},
+		},
+		{
+			name: "IPs are limited separately",
+			calls: []call{
+				{0, "rollup", "10.0.0.1", 3, 3},
+				{1, "rollup", "10.0.0.2", 3, 3},
+			},
+		},
+		{
+			name: "prefixes are limited separately",
+			calls: []call{
+				{0, "rollup", "10.0.0.1", 3, 3},
+				{1, "continuum", "10.0.0.1", 3, 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeRedis()
+			type replicaGroup struct {
+				instance int
+				prefix   string
+			}
+			limiters := make(map[replicaGroup]*RedisRateLimiter)
+			for i, c := range tt.calls {
+				key := replicaGroup{c.instance, c.prefix}
+				if limiters[key] == nil {
+					limiters[key] = NewRedisRateLimiter(server, c.prefix, 0.001, 3)
+				}
+				if got := check(t, limiters[key], c.ip, c.n); got != c.want {
+					t.Errorf("call %d: instance %d allowed %d of %d from %s, want %d", i, c.instance, got, c.n, c.ip, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisRateLimiter_Refill(t *testing.T) {
+	server := newFakeRedis()
+	a := NewRedisRateLimiter(server, "rollup", 1, 2)
+	b := NewRedisRateLimiter(server, "rollup", 1, 2)
+
+	if got := check(t, a, "10.0.0.1", 3); got != 2 {
+		t.Fatalf("allowed %d, want the burst of 2", got)
+	}
+	server.advance(time.Second)
+	if got := check(t, b, "10.0.0.1", 2); got != 1 {
+		t.Errorf("allowed %d after one second at 1 rps, want 1", got)
+	}
+	server.advance(time.Minute)
+	if got := check(t, b, "10.0.0.1", 3); got != 2 {
+		t.Errorf("allowed %d after a long pause, want the burst of 2", got)
+	}
+}
+
+func TestRedisRateLimiter_Check(t *testing.T) {
+	tests := []struct {
+		name          string
+		rate          float64
+		result        []interface{}
+		err           error
+		wantAllowed   bool
+		wantRemaining int
+		wantErr       bool
+		wantCalls     int
+	}{
+		{"allowed", 1, []interface{}{int64(1), int64(4)}, nil, true, 4, false, 1},
+		{"denied", 1, []interface{}{int64(0), int64(0)}, nil, false, 0, false, 1},
+		{"negative remaining", 1, []interface{}{int64(1), int64(-2)}, nil, true, 0, false, 1},
+		{"redis down", 1, nil, errors.New("connection refused"), false, 0, true, 1},
+		{"malformed result", 1, []interface{}{int64(1)}, nil, false, 0, true, 1},
+		{"disabled", 0, nil, nil, false, 0, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeRedis()
+			server.result, server.err = tt.result, tt.err
+			l := NewRedisRateLimiter(server, "submit", tt.rate, 5)
+
+			allowed, remaining, err := l.Check(context.Background(), "10.0.0.1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if allowed != tt.wantAllowed || remaining != tt.wantRemaining {
+				t.Errorf("Check() = %v, %d; want %v, %d", allowed, remaining, tt.wantAllowed, tt.wantRemaining)
+			}
+			if len(server.keys) != tt.wantCalls {
+				t.Fatalf("script ran %d times, want %d", len(server.keys), tt.wantCalls)
+			}
+			if tt.wantCalls > 0 && server.keys[0] != "ratelimit:submit:10.0.0.1" {
+				t.Errorf("key = %q, want ratelimit:submit:10.0.0.1", server.keys[0])
+			}
+		})
+	}
+}
+
+func TestRedisRateLimiter_SetRate(t *testing.T) {
+	server := newFakeRedis()
+	l := NewRedisRateLimiter(server, "rollup", 0.001, 1)
+	l.SetRate(0.001, 4)
+
+	if l.Limit() != 4 {
+		t.Errorf("Limit() = %d, want 4", l.Limit())
+	}
+	if got := check(t, l, "10.0.0.1", 5); got != 4 {
+		t.Errorf("allowed %d, want the new burst of 4", got)
+	}
+}
+
+func TestMiddleware_Backends(t *testing.T) {
+	tests := []struct {
+		name       string
+		limiter    func() Limiter
+		wantStatus []int
+		wantLimit  string
+	}{
+		{
+			name:       "in-memory",
+			limiter:    func() Limiter { return NewIPRateLimiter(0.001, 2) },
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantLimit:  "2",
+		},
+		{
+			name:       "redis",
+			limiter:    func() Limiter { return NewRedisRateLimiter(newFakeRedis(), "rollup", 0.001, 2) },
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantLimit:  "2",
+		},
+		{
+			name: "redis down fails open",
+			limiter: func() Limiter {
+				server := newFakeRedis()
+				server.err = errors.New("connection refused")
+				return NewRedisRateLimiter(server, "rollup", 0.001, 2)
+			},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Middleware(tt.limiter(), nil)(okHandler)
+
+			for i, want := range tt.wantStatus {
+				rec := serveFrom(handler, "10.0.0.1")
+				if rec.Code != want {
+					t.Fatalf("request %d: status = %d, want %d", i, rec.Code, want)
+				}
+				if got := rec.Header().Get("X-RateLimit-Limit"); got != tt.wantLimit {
+					t.Errorf("request %d: X-RateLimit-Limit = %q, want %q", i, got, tt.wantLimit)
+				}
+				if want == http.StatusTooManyRequests && !strings.Contains(rec.Body.String(), `"request_id":"req-1"`) {
+					t.Errorf("request %d: body = %s, want the request ID", i, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// TestRedisRateLimiter_Server runs the real script against TEST_REDIS_URL,
+// with one client per limiter like separate replicas
+func TestRedisRateLimiter_Server(t *testing.T) {
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("parse TEST_REDIS_URL: %v", err)
+	}
+
+	prefix := "test-" + time.Now().Format("150405.000000000")
+	var limiters []*RedisRateLimiter
+	for range 2 {
+		client := redis.NewClient(opts)
+		t.Cleanup(func() { client.Close() })
+		limiters = append(limiters, NewRedisRateLimiter(client, prefix, 0.001, 3))
+	}
+
+	if got := check(t, limiters[0], "10.0.0.1", 2) + check(t, limiters[1], "10.0.0.1", 2); got != 3 {
+		t.Errorf("replicas allowed %d requests together, want the shared burst of 3", got)
+	}
+	if got := check(t, limiters[1], "10.0.0.2", 3); got != 3 {
+		t.Errorf("allowed %d from another IP, want 3", got)
+	}
+}
@@ -28,6 +28,13 @@ type GRPCReader struct {
 	maxBackoff     time.Duration
 	backoffFactor  float64
 	reconnectDelay time.Duration
+
+	// Resume after the highest received tick on reconnect instead of startTick
+	resumeOnReconnect bool
+
+	// Last tick received; only touched by the readLoop goroutine
+	lastTickAt     time.Time
+	lastTickNumber uint64
 }
 
 // GRPCReaderOption is a functional option for configuring GRPCReader.
@@ -40,6 +47,15 @@ func WithStartTick(tick uint64) GRPCReaderOption {
 	}
 }
 
+// WithResumeOnReconnect controls where the stream restarts after a disconnect:
+// true resumes after the highest tick received so far, false restarts from the
+// configured start tick.
+func WithResumeOnReconnect(resume bool) GRPCReaderOption {
+	return func(r *GRPCReader) {
+		r.resumeOnReconnect = resume
+	}
+}
+
 // WithMaxRetries sets the maximum number of reconnection attempts (0 = infinite).
 func WithMaxRetries(max int) GRPCReaderOption {
 	return func(r *GRPCReader) {
@@ -74,6 +90,8 @@ func NewGRPCReader(serverAddr string, opts ...GRPCReaderOption) *GRPCReader {
 		backoffFactor:  2.0,
 		reconnectDelay: 500 * time.Millisecond,
 		logger:         zap.NewNop(), // Default: no-op logger
+
+		resumeOnReconnect: true, // Default: resume without gaps or duplicates
 	}
 
 	for _, opt := range opts {
@@ -111,6 +129,12 @@ func (r *GRPCReader) readLoop(ctx context.Context, tickCh chan<- *pb.Tick, errCh
 	attempts := 0
 	backoff := r.baseBackoff
 
+	// Reconnect attempts since the last received tick, for the reconnect log
+	reconnects := 0
+	downSince := time.Now()
+	r.lastTickAt = time.Time{}
+	r.lastTickNumber = 0
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -129,19 +153,22 @@ func (r *GRPCReader) readLoop(ctx context.Context, tickCh chan<- *pb.Tick, errCh
 			}
 
 			attempts++
+			reconnects++
+			r.logReconnect(reconnects, backoff, downSince, err)
 			r.sleep(ctx, backoff)
 			backoff = r.nextBackoff(backoff)
 			continue
 		}
 
 		// Start streaming
+		startTick := r.nextStartTick()
 		r.logger.Info("Starting tick stream",
 			zap.String("server", r.serverAddr),
-			zap.Uint64("start_tick", r.startTick),
+			zap.Uint64("start_tick", startTick),
 		)
 
 		stream, err := r.client.StreamTicks(ctx, &pb.StreamTicksRequest{
-			StartTick: r.startTick,
+			StartTick: startTick,
 		})
 		if err != nil {
 			r.logger.Error("Failed to start stream",
@@ -149,6 +176,8 @@ func (r *GRPCReader) readLoop(ctx context.Context, tickCh chan<- *pb.Tick, errCh
 				zap.Error(err),
 			)
 			errCh <- fmt.Errorf("failed to start stream: %w", err)
+			reconnects++
+			r.logReconnect(reconnects, r.reconnectDelay, downSince, err)
 			r.sleep(ctx, r.reconnectDelay)
 			continue
 		}
@@ -160,11 +189,22 @@ func (r *GRPCReader) readLoop(ctx context.Context, tickCh chan<- *pb.Tick, errCh
 		attempts = 0
 
 		// Read from stream
-		if shouldReconnect := r.readStream(ctx, stream, tickCh, errCh); !shouldReconnect {
+		lastTickBefore := r.lastTickAt
+		shouldReconnect, streamErr := r.readStream(ctx, stream, tickCh, errCh)
+		if !shouldReconnect {
 			return
 		}
 
+		// Downtime counts from the last tick; a stream that delivered nothing
+		// extends the outage that was already in progress
+		if r.lastTickAt.After(lastTickBefore) {
+			reconnects = 0
+			downSince = r.lastTickAt
+		}
+
 		// Wait before reconnecting
+		reconnects++
+		r.logReconnect(reconnects, r.reconnectDelay, downSince, streamErr)
 		r.sleep(ctx, r.reconnectDelay)
 	}
 }
@@ -202,12 +242,13 @@ func (r *GRPCReader) connect(ctx context.Context) error {
 }
 
 // readStream reads ticks from the stream until an error occurs.
-// Returns true if we should reconnect, false if we should stop.
-func (r *GRPCReader) readStream(ctx context.Context, stream pb.SequencerService_StreamTicksClient, tickCh chan<- *pb.Tick, errCh chan<- error) bool {
+// Returns true if we should reconnect, false if we should stop, along with
+// the error that ended the stream.
+func (r *GRPCReader) readStream(ctx context.Context, stream pb.SequencerService_StreamTicksClient, tickCh chan<- *pb.Tick, errCh chan<- error) (bool, error) {
 	for {
 		select {
 		case <-ctx.Done():
-			return false
+			return false, ctx.Err()
 		default:
 		}
 
@@ -216,23 +257,51 @@ func (r *GRPCReader) readStream(ctx context.Context, stream pb.SequencerService_
 			// Check if error is recoverable
 			if r.isRecoverableError(err) {
 				errCh <- fmt.Errorf("stream error (will reconnect): %w", err)
-				return true
+				return true, err
 			}
 
 			// Non-recoverable error or context canceled
 			errCh <- fmt.Errorf("stream error (fatal): %w", err)
-			return false
+			return false, err
+		}
+		r.lastTickAt = time.Now()
+		if tick.GetTickNumber() > r.lastTickNumber {
+			r.lastTickNumber = tick.GetTickNumber()
 		}
 
 		// Send tick to channel
 		select {
 		case tickCh <- tick:
 		case <-ctx.Done():
-			return false
+			return false, ctx.Err()
 		}
 	}
 }
 
+// nextStartTick returns the tick to request when (re)opening the stream.
+func (r *GRPCReader) nextStartTick() uint64 {
+	if r.resumeOnReconnect && r.lastTickNumber > 0 {
+		return r.lastTickNumber + 1
+	}
+	return r.startTick
+}
+
+// logReconnect records a reconnect attempt with the wait before it and the
+// downtime since the last received tick (or since Read started).
+func (r *GRPCReader) logReconnect(attempt int, wait time.Duration, downSince time.Time, err error) {
+	fields := []zap.Field{
+		zap.String("server", r.serverAddr),
+		zap.Int("attempt", attempt),
+		zap.Duration("backoff", wait),
+		zap.Duration("downtime", time.Since(downSince)),
+		zap.Error(err),
+	}
+	if !r.lastTickAt.IsZero() {
+		fields = append(fields, zap.Time("last_tick_at", r.lastTickAt))
+	}
+	r.logger.Warn("Reconnecting to tick stream", fields...)
+}
+
 // sleep sleeps for the given duration or until context is canceled.
 func (r *GRPCReader) sleep(ctx context.Context, d time.Duration) {
 	timer := time.NewTimer(d)
@@ -0,0 +1,197 @@
+package stream
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const reconnectDelay = 5 * time.Millisecond
+
+// newTestGRPCReader returns a reader for addr that reconnects quickly and logs to an observer.
+func newTestGRPCReader(addr string, opts ...GRPCReaderOption) (*GRPCReader, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	r := NewGRPCReader(addr, append([]GRPCReaderOption{
+		WithLogger(zap.New(core)),
+		WithBackoffConfig(reconnectDelay, reconnectDelay, 1),
+	}, opts...)...)
+	r.reconnectDelay = reconnectDelay
+	return r, logs
+}
+
+// readUntil reads from r until cond holds for the logs, then stops the reader.
+func readUntil(t *testing.T, r *GRPCReader, logs *observer.ObservedLogs, cond func(logs *observer.ObservedLogs) bool) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks, errs := r.Read(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		drain(t, ticks, errs)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond(logs) {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met; logs: %v", logs.All())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	r.Close()
+}
+
+// unreachableAddr returns an address nothing listens on.
+func unreachableAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	return addr
+}
+
+func TestGRPCReader_ReconnectLogs(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "sequencer restarting")
+
+	tests := []struct {
+		name         string
+		sessions     []streamSession
+		unreachable  bool
+		wantAttempts []int64
+		wantLastTick bool // last_tick_at logged
+	}{
+		{
+			name:         "stream drops after ticks",
+			sessions:     []streamSession{{[]uint64{1, 2, 3}, unavailable}, {[]uint64{4}, unavailable}},
+			wantAttempts: []int64{1, 1},
+			wantLastTick: true,
+		},
+		{
+			name:         "stream drops before any tick",
+			sessions:     []streamSession{{nil, unavailable}, {nil, unavailable}},
+			wantAttempts: []int64{1, 2},
+		},
+		{
+			name:         "outage after ticks",
+			sessions:     []streamSession{{[]uint64{1}, unavailable}, {nil, unavailable}, {nil, unavailable}},
+			wantAttempts: []int64{1, 2, 3},
+			wantLastTick: true,
+		},
+		{
+			name:         "server unreachable",
+			unreachable:  true,
+			wantAttempts: []int64{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := unreachableAddr(t)
+			if !tt.unreachable {
+				addr = serveSequencer(t, &fakeSequencer{sessions: tt.sessions})
+			}
+			r, logs := newTestGRPCReader(addr)
+
+			reconnects := func(logs *observer.ObservedLogs) []observer.LoggedEntry {
+				return logs.FilterMessage("Reconnecting to tick stream").All()
+			}
+			readUntil(t, r, logs, func(logs *observer.ObservedLogs) bool {
+				return len(reconnects(logs)) >= len(tt.wantAttempts)
+			})
+
+			entries := reconnects(logs)[:len(tt.wantAttempts)]
+			var attempts []int64
+			var lastDowntime time.Duration
+			for i, entry := range entries {
+				fields := entry.ContextMap()
+				attempts = append(attempts, fields["attempt"].(int64))
+
+				if fields["backoff"] != reconnectDelay {
+					t.Errorf("reconnect %d: backoff = %v, want %v", i, fields["backoff"], reconnectDelay)
+				}
+				if _, ok := fields["last_tick_at"]; ok != tt.wantLastTick {
+					t.Errorf("reconnect %d: last_tick_at logged = %v, want %v", i, ok, tt.wantLastTick)
+				}
+				if fields["error"] == nil || fields["server"] != addr {
+					t.Errorf("reconnect %d: fields = %v, want error and server", i, fields)
+				}
+
+				// Downtime keeps growing while attempts pile up
+				downtime := fields["downtime"].(time.Duration)
+				if attempts[i] > 1 && downtime < lastDowntime {
+					t.Errorf("reconnect %d: downtime = %v, want at least %v", i, downtime, lastDowntime)
+				}
+				lastDowntime = downtime
+			}
+			if !slices.Equal(attempts, tt.wantAttempts) {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestGRPCReader_ResumeOnReconnect(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "sequencer restarting")
+
+	tests := []struct {
+		name       string
+		startTick  uint64
+		resume     bool
+		sessions   []streamSession
+		wantStarts []uint64
+	}{
+		{
+			name:       "resume after last tick",
+			resume:     true,
+			sessions:   []streamSession{{[]uint64{1, 2, 3}, unavailable}, {[]uint64{4}, unavailable}},
+			wantStarts: []uint64{0, 4, 5},
+		},
+		{
+			name:       "resume after highest tick",
+			startTick:  5,
+			resume:     true,
+			sessions:   []streamSession{{[]uint64{5, 7, 6}, unavailable}},
+			wantStarts: []uint64{5, 8},
+		},
+		{
+			name:       "resume before any tick",
+			startTick:  100,
+			resume:     true,
+			sessions:   []streamSession{{nil, unavailable}, {nil, unavailable}},
+			wantStarts: []uint64{100, 100, 100},
+		},
+		{
+			name:       "restart from start tick",
+			startTick:  10,
+			sessions:   []streamSession{{[]uint64{10, 11}, unavailable}, {[]uint64{10, 11, 12}, unavailable}},
+			wantStarts: []uint64{10, 10, 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &fakeSequencer{sessions: tt.sessions}
+			r, logs := newTestGRPCReader(serveSequencer(t, srv), WithStartTick(tt.startTick), WithResumeOnReconnect(tt.resume))
+
+			readUntil(t, r, logs, func(*observer.ObservedLogs) bool {
+				return len(srv.startTicks()) >= len(tt.wantStarts)
+			})
+
+			if got := srv.startTicks()[:len(tt.wantStarts)]; !slices.Equal(got, tt.wantStarts) {
+				t.Errorf("StreamTicks start ticks = %v, want %v", got, tt.wantStarts)
+			}
+		})
+	}
+}
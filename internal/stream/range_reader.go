@@ -0,0 +1,185 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// RangeReader backfills a fixed range of ticks by calling GetTick for each
+// tick number, instead of subscribing to StreamTicks.
+// It implements the same StreamReader contract as GRPCReader, so the
+// existing parser/writer pipeline can be reused; both channels are closed
+// once the last tick in the range has been emitted.
+type RangeReader struct {
+	serverAddr string
+	startTick  uint64
+	endTick    uint64 // inclusive
+	conn       *grpc.ClientConn
+	client     pb.SequencerServiceClient
+	logger     *zap.Logger
+
+	maxAttempts    int           // GetTick attempts per tick before giving up on it
+	retryDelay     time.Duration // Delay between attempts
+	requestTimeout time.Duration // Timeout for each GetTick call
+}
+
+// NewRangeReader creates a reader that fetches ticks startTick..endTick (inclusive).
+func NewRangeReader(serverAddr string, startTick, endTick uint64, logger *zap.Logger) *RangeReader {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &RangeReader{
+		serverAddr:     serverAddr,
+		startTick:      startTick,
+		endTick:        endTick,
+		logger:         logger,
+		maxAttempts:    3,
+		retryDelay:     1 * time.Second,
+		requestTimeout: 10 * time.Second,
+	}
+}
+
+// Read starts fetching the tick range.
+// Returns two channels: one for ticks and one for errors.
+// Both channels are closed when the range is exhausted or the context is canceled.
+func (r *RangeReader) Read(ctx context.Context) (<-chan *pb.Tick, <-chan error) {
+	tickCh := make(chan *pb.Tick, 100)
+	errCh := make(chan error, 10)
+
+	// Connect before starting the goroutine, so Close never races with it for conn
+	if err := r.connect(); err != nil {
+		errCh <- fmt.Errorf("failed to connect: %w", err)
+		close(tickCh)
+		close(errCh)
+		return tickCh, errCh
+	}
+
+	go r.readRange(ctx, tickCh, errCh)
+
+	return tickCh, errCh
+}
+
+// connect creates the gRPC client. No I/O happens until the first call.
+func (r *RangeReader) connect() error {
+	conn, err := grpc.NewClient(
+		r.serverAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(100*1024*1024), // 100MB max message size
+		),
+	)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	r.client = pb.NewSequencerServiceClient(conn)
+	return nil
+}
+
+// Close closes the gRPC connection.
+func (r *RangeReader) Close() error {
+	if r.conn != nil {
+		return r.conn.Close()
+	}
+	return nil
+}
+
+// readRange fetches each tick in the range in order.
+func (r *RangeReader) readRange(ctx context.Context, tickCh chan<- *pb.Tick, errCh chan<- error) {
+	defer close(tickCh)
+	defer close(errCh)
+
+	if r.startTick > r.endTick {
+		r.sendError(ctx, errCh, fmt.Errorf("invalid backfill range: start %d > end %d", r.startTick, r.endTick))
+		return
+	}
+
+	r.logger.Info("Starting tick backfill",
+		zap.String("server", r.serverAddr),
+		zap.Uint64("start_tick", r.startTick),
+		zap.Uint64("end_tick", r.endTick),
+	)
+
+	var fetched, missing uint64
+	for tickNumber := r.startTick; tickNumber <= r.endTick; tickNumber++ {
+		tick, err := r.fetchTick(ctx, tickNumber)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			missing++
+			r.sendError(ctx, errCh, err)
+		} else {
+			select {
+			case tickCh <- tick:
+				fetched++
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		// Guard against overflow when endTick is the max uint64
+		if tickNumber == r.endTick {
+			break
+		}
+	}
+
+	r.logger.Info("Tick backfill complete",
+		zap.Uint64("fetched", fetched),
+		zap.Uint64("missing", missing),
+	)
+}
+
+// fetchTick calls GetTick, retrying up to maxAttempts times.
+func (r *RangeReader) fetchTick(ctx context.Context, tickNumber uint64) (*pb.Tick, error) {
+	var lastErr error
+
+	for attempt := 1; attempt <= r.maxAttempts; attempt++ {
+		reqCtx, cancel := context.WithTimeout(ctx, r.requestTimeout)
+		resp, err := r.client.GetTick(reqCtx, &pb.GetTickRequest{TickNumber: tickNumber})
+		cancel()
+
+		if err == nil {
+			if !resp.GetFound() || resp.GetTick() == nil {
+				return nil, fmt.Errorf("tick %d not found", tickNumber)
+			}
+			return resp.GetTick(), nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		r.logger.Warn("GetTick failed, retrying",
+			zap.Uint64("tick_number", tickNumber),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(r.retryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("failed to fetch tick %d after %d attempts: %w", tickNumber, r.maxAttempts, lastErr)
+}
+
+// sendError delivers an error without blocking past context cancellation.
+func (r *RangeReader) sendError(ctx context.Context, errCh chan<- error, err error) {
+	select {
+	case errCh <- err:
+	case <-ctx.Done():
+	}
+}
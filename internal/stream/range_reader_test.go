@@ -0,0 +1,186 @@
+package stream
+
+import (
+	"context"
+	"math"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/fermilabs/fermi-api-gateway/proto/continuumv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSequencer serves GetTick for every tick number except those in missing.
+// A tick listed in failures fails with Unavailable that many times first.
+// Each StreamTicks call plays the next of sessions; once they run out, streams
+// stay open without sending anything.
+type fakeSequencer struct {
+	pb.UnimplementedSequencerServiceServer
+
+	mu       sync.Mutex
+	missing  map[uint64]bool
+	failures map[uint64]int
+	sessions []streamSession
+	starts   []uint64 // StartTick of each StreamTicks request
+}
+
+// streamSession is one StreamTicks call: the ticks sent, then the error it ends with.
+type streamSession struct {
+	ticks []uint64
+	err   error
+}
+
+func (s *fakeSequencer) StreamTicks(req *pb.StreamTicksRequest, stream grpc.ServerStreamingServer[pb.Tick]) error {
+	s.mu.Lock()
+	s.starts = append(s.starts, req.GetStartTick())
+	var session *streamSession
+	if len(s.sessions) > 0 {
+		session = &s.sessions[0]
+		s.sessions = s.sessions[1:]
+	}
+	s.mu.Unlock()
+
+	if session == nil {
+		<-stream.Context().Done()
+		return nil
+	}
+	for _, n := range session.ticks {
+		if err := stream.Send(&pb.Tick{TickNumber: n}); err != nil {
+			return err
+		}
+	}
+	return session.err
+}
+
+// startTicks returns the StartTick of each StreamTicks request so far.
+func (s *fakeSequencer) startTicks() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.starts)
+}
+
+func (s *fakeSequencer) GetTick(ctx context.Context, req *pb.GetTickRequest) (*pb.GetTickResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := req.GetTickNumber()
+	if s.failures[n] > 0 {
+		s.failures[n]--
+		return nil, status.Error(codes.Unavailable, "sequencer restarting")
+	}
+	if s.missing[n] {
+		return &pb.GetTickResponse{Found: false}, nil
+	}
+	return &pb.GetTickResponse{Found: true, Tick: &pb.Tick{TickNumber: n}}, nil
+}
+
+// serveSequencer starts a gRPC server for srv on a local port and returns its address.
+func serveSequencer(t *testing.T, srv pb.SequencerServiceServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterSequencerServiceServer(server, srv)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+// drain reads both channels until they are closed, returning the tick numbers and errors.
+func drain(t *testing.T, ticks <-chan *pb.Tick, errs <-chan error) ([]uint64, []error) {
+	t.Helper()
+	var gotTicks []uint64
+	var gotErrs []error
+	timeout := time.After(10 * time.Second)
+	for ticks != nil || errs != nil {
+		select {
+		case tick, ok := <-ticks:
+			if !ok {
+				ticks = nil
+				continue
+			}
+			gotTicks = append(gotTicks, tick.GetTickNumber())
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			gotErrs = append(gotErrs, err)
+		case <-timeout:
+			t.Fatal("reader channels were not closed")
+		}
+	}
+	return gotTicks, gotErrs
+}
+
+func TestRangeReader_Read(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     uint64
+		end       uint64
+		missing   []uint64
+		failures  map[uint64]int
+		wantTicks []uint64
+		wantErrs  []string
+	}{
+		{name: "whole range", start: 1, end: 5, wantTicks: []uint64{1, 2, 3, 4, 5}},
+		{name: "single tick", start: 7, end: 7, wantTicks: []uint64{7}},
+		{name: "missing tick skipped", start: 1, end: 4, missing: []uint64{3}, wantTicks: []uint64{1, 2, 4}, wantErrs: []string{"tick 3 not found"}},
+		{name: "transient failure retried", start: 1, end: 3, failures: map[uint64]int{2: 2}, wantTicks: []uint64{1, 2, 3}},
+		{
+			name: "persistent failure skipped", start: 1, end: 3, failures: map[uint64]int{2: 10},
+			wantTicks: []uint64{1, 3}, wantErrs: []string{"failed to fetch tick 2 after 3 attempts"},
+		},
+		{name: "start after end", start: 5, end: 4, wantErrs: []string{"invalid backfill range"}},
+		{name: "ends at max uint64", start: math.MaxUint64 - 1, end: math.MaxUint64, wantTicks: []uint64{math.MaxUint64 - 1, math.MaxUint64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &fakeSequencer{missing: make(map[uint64]bool), failures: tt.failures}
+			for _, n := range tt.missing {
+				srv.missing[n] = true
+			}
+
+			r := NewRangeReader(serveSequencer(t, srv), tt.start, tt.end, nil)
+			r.retryDelay = time.Millisecond
+			defer r.Close()
+
+			ticks, errs := r.Read(context.Background())
+			gotTicks, gotErrs := drain(t, ticks, errs)
+
+			if !slices.Equal(gotTicks, tt.wantTicks) {
+				t.Errorf("ticks = %v, want %v", gotTicks, tt.wantTicks)
+			}
+			if len(gotErrs) != len(tt.wantErrs) {
+				t.Fatalf("errors = %v, want %d", gotErrs, len(tt.wantErrs))
+			}
+			for i, err := range gotErrs {
+				if !strings.Contains(err.Error(), tt.wantErrs[i]) {
+					t.Errorf("error %d = %v, want %q", i, err, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRangeReader_Canceled(t *testing.T) {
+	r := NewRangeReader(serveSequencer(t, &fakeSequencer{}), 1, math.MaxUint64, nil)
+	defer r.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks, errs := r.Read(ctx)
+	<-ticks
+	cancel()
+
+	// Both channels close without reading the rest of the range
+	drain(t, ticks, errs)
+}
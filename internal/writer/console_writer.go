@@ -1,12 +1,17 @@
 package writer
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/fermilabs/fermi-api-gateway/internal/domain"
 )
@@ -21,14 +26,43 @@ const (
 	FormatCompact OutputFormat = "compact"
 	// FormatTable outputs ticks in a human-readable table format.
 	FormatTable OutputFormat = "table"
+	// FormatCSV outputs one comma-separated row per tick, after a single header row.
+	FormatCSV OutputFormat = "csv"
+	// FormatTSV outputs one tab-separated row per tick, after a single header row.
+	FormatTSV OutputFormat = "tsv"
 )
 
+// ANSI color codes used when color output is enabled.
+const (
+	colorReset = "\033[0m"
+	colorCyan  = "\033[36m"
+	colorGreen = "\033[32m"
+)
+
+// delimitedHeader is the header row for CSV/TSV output.
+var delimitedHeader = []string{"tick_number", "timestamp", "batch_hash", "tx_count", "vdf_iterations"}
+
 // ConsoleWriter writes ticks to stdout for debugging purposes.
 // It implements the Writer interface and is safe for concurrent use.
 type ConsoleWriter struct {
-	format OutputFormat
-	output io.Writer
-	mu     sync.Mutex // Protects concurrent writes
+	format        OutputFormat
+	output        io.Writer
+	verboseTable  bool       // List individual transactions in table format
+	headerDone    bool       // CSV/TSV header already written (guarded by mu)
+	color         bool       // Colorize table headers and summary lines
+	payloadBase64 bool       // Add base64-encoded payloads to JSON output
+	mu            sync.Mutex // Protects concurrent writes
+
+	// Periodic summary (disabled when both are zero)
+	summaryEvery    int           // Emit a summary every N ticks
+	summaryInterval time.Duration // Emit a summary at most this often
+
+	// Counters for summary stats (guarded by mu)
+	totalTicks        uint64
+	totalTxs          uint64
+	startedAt         time.Time
+	lastSummaryAt     time.Time
+	ticksSinceSummary int
 }
 
 // ConsoleWriterOption is a functional option for configuring ConsoleWriter.
@@ -48,6 +82,39 @@ func WithOutput(output io.Writer) ConsoleWriterOption {
 	}
 }
 
+// WithVerboseTable lists each transaction (hash, sequence number, nonce) under
+// its tick in table format. Has no effect on other formats.
+func WithVerboseTable(verbose bool) ConsoleWriterOption {
+	return func(w *ConsoleWriter) {
+		w.verboseTable = verbose
+	}
+}
+
+// WithColor enables ANSI colors for table headers and summary lines.
+func WithColor(color bool) ConsoleWriterOption {
+	return func(w *ConsoleWriter) {
+		w.color = color
+	}
+}
+
+// WithPayloadBase64 adds a base64-encoded "payload_base64" field to each transaction
+// in JSON and compact output. Has no effect on other formats.
+func WithPayloadBase64(payloadBase64 bool) ConsoleWriterOption {
+	return func(w *ConsoleWriter) {
+		w.payloadBase64 = payloadBase64
+	}
+}
+
+// WithSummary emits a summary line (ticks/sec, total ticks, avg tx per tick)
+// every n ticks and/or whenever interval has elapsed since the last summary.
+// A zero value disables the corresponding trigger.
+func WithSummary(n int, interval time.Duration) ConsoleWriterOption {
+	return func(w *ConsoleWriter) {
+		w.summaryEvery = n
+		w.summaryInterval = interval
+	}
+}
+
 // NewConsoleWriter creates a new console writer with the specified options.
 func NewConsoleWriter(opts ...ConsoleWriterOption) *ConsoleWriter {
 	w := &ConsoleWriter{
@@ -71,7 +138,10 @@ func (w *ConsoleWriter) Write(ctx context.Context, tick *domain.Tick) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	return w.writeTick(tick)
+	if err := w.writeTick(tick); err != nil {
+		return err
+	}
+	return w.recordTick(tick)
 }
 
 // WriteBatch writes multiple ticks to the console.
@@ -87,6 +157,9 @@ func (w *ConsoleWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick) er
 		if err := w.writeTick(tick); err != nil {
 			return fmt.Errorf("failed to write tick %d: %w", tick.TickNumber, err)
 		}
+		if err := w.recordTick(tick); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
 	}
 
 	return nil
@@ -107,35 +180,94 @@ func (w *ConsoleWriter) writeTick(tick *domain.Tick) error {
 		return w.writeJSON(tick, false)
 	case FormatTable:
 		return w.writeTable(tick)
+	case FormatCSV:
+		return w.writeDelimited(tick, ',')
+	case FormatTSV:
+		return w.writeDelimited(tick, '\t')
 	default:
 		return fmt.Errorf("unknown output format: %s", w.format)
 	}
 }
 
-// writeJSON writes a tick as JSON.
-func (w *ConsoleWriter) writeJSON(tick *domain.Tick, pretty bool) error {
-	var data []byte
-	var err error
+// recordTick updates the summary counters and writes a summary line when due.
+// Must be called with w.mu held.
+func (w *ConsoleWriter) recordTick(tick *domain.Tick) error {
+	if w.summaryEvery <= 0 && w.summaryInterval <= 0 {
+		return nil
+	}
 
-	if pretty {
-		data, err = json.MarshalIndent(tick, "", "  ")
-	} else {
-		data, err = json.Marshal(tick)
+	now := time.Now()
+	if w.startedAt.IsZero() {
+		w.startedAt = now
+		w.lastSummaryAt = now
 	}
 
+	w.totalTicks++
+	w.totalTxs += uint64(len(tick.Transactions))
+	w.ticksSinceSummary++
+
+	due := (w.summaryEvery > 0 && w.ticksSinceSummary >= w.summaryEvery) ||
+		(w.summaryInterval > 0 && now.Sub(w.lastSummaryAt) >= w.summaryInterval)
+	if !due {
+		return nil
+	}
+
+	// Rate over the period since the last summary
+	var ticksPerSec float64
+	if elapsed := now.Sub(w.lastSummaryAt).Seconds(); elapsed > 0 {
+		ticksPerSec = float64(w.ticksSinceSummary) / elapsed
+	}
+	avgTxPerTick := float64(w.totalTxs) / float64(w.totalTicks)
+
+	line := fmt.Sprintf("[SUMMARY] %s | Rate: %.2f ticks/sec | Total ticks: %d | Avg tx/tick: %.2f | Uptime: %s",
+		now.Format("15:04:05.000"),
+		ticksPerSec,
+		w.totalTicks,
+		avgTxPerTick,
+		now.Sub(w.startedAt).Truncate(time.Second),
+	)
+	if w.color {
+		line = colorGreen + line + colorReset
+	}
+
+	w.lastSummaryAt = now
+	w.ticksSinceSummary = 0
+
+	_, err := fmt.Fprintln(w.output, line)
+	return err
+}
+
+// writeJSON writes a tick as JSON.
+func (w *ConsoleWriter) writeJSON(tick *domain.Tick, pretty bool) error {
+	data, err := tick.MarshalJSONWithOptions(domain.JSONOptions{PayloadBase64: w.payloadBase64})
 	if err != nil {
 		return fmt.Errorf("failed to marshal tick: %w", err)
 	}
 
+	if pretty {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err != nil {
+			return fmt.Errorf("failed to indent tick: %w", err)
+		}
+		data = indented.Bytes()
+	}
+
 	_, err = fmt.Fprintln(w.output, string(data))
 	return err
 }
 
 // writeTable writes a tick in a human-readable table format.
 func (w *ConsoleWriter) writeTable(tick *domain.Tick) error {
-	_, err := fmt.Fprintf(w.output, `
+	var b strings.Builder
+
+	title := fmt.Sprintf("Tick #%d", tick.TickNumber)
+	if w.color {
+		title = colorCyan + title + colorReset
+	}
+
+	fmt.Fprintf(&b, `
 ┌─────────────────────────────────────────────────────────────────
-│ Tick #%d
+│ %s
 ├─────────────────────────────────────────────────────────────────
 │ Timestamp:       %s
 │ Batch Hash:      %s
@@ -143,9 +275,8 @@ func (w *ConsoleWriter) writeTable(tick *domain.Tick) error {
 │ VDF Iterations:  %d
 │ Transactions:    %d
 │ Received At:     %s
-└─────────────────────────────────────────────────────────────────
 `,
-		tick.TickNumber,
+		title,
 		tick.Timestamp.Format("2006-01-02 15:04:05.000000"),
 		truncate(tick.BatchHash, 32),
 		truncate(tick.VDFProof.Output, 32),
@@ -154,9 +285,53 @@ func (w *ConsoleWriter) writeTable(tick *domain.Tick) error {
 		tick.ReceivedAt.Format("2006-01-02 15:04:05.000000"),
 	)
 
+	if w.verboseTable && len(tick.Transactions) > 0 {
+		b.WriteString("├─────────────────────────────────────────────────────────────────\n")
+		fmt.Fprintf(&b, "│ %-4s %-40s %12s %12s\n", "#", "Tx Hash", "Sequence", "Nonce")
+		for i, tx := range tick.Transactions {
+			fmt.Fprintf(&b, "│ %-4d %-40s %12d %12d\n",
+				i,
+				truncate(tx.TxHash, 37),
+				tx.SequenceNumber,
+				tx.Nonce,
+			)
+		}
+	}
+
+	b.WriteString("└─────────────────────────────────────────────────────────────────\n")
+
+	_, err := io.WriteString(w.output, b.String())
 	return err
 }
 
+// writeDelimited writes a tick as a CSV/TSV row, preceded by the header on first use.
+// Must be called with w.mu held so the header is written exactly once.
+func (w *ConsoleWriter) writeDelimited(tick *domain.Tick, delimiter rune) error {
+	cw := csv.NewWriter(w.output)
+	cw.Comma = delimiter
+
+	if !w.headerDone {
+		if err := cw.Write(delimitedHeader); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		w.headerDone = true
+	}
+
+	record := []string{
+		strconv.FormatUint(tick.TickNumber, 10),
+		tick.Timestamp.Format(time.RFC3339Nano),
+		tick.BatchHash,
+		strconv.Itoa(len(tick.Transactions)),
+		strconv.FormatUint(tick.VDFProof.Iterations, 10),
+	}
+	if err := cw.Write(record); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
 // truncate truncates a string to maxLen characters and adds "..." if truncated.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
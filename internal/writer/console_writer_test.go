@@ -0,0 +1,250 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+// writeConsole writes ticks through a console writer and returns its output.
+func writeConsole(t *testing.T, ticks []*domain.Tick, opts ...ConsoleWriterOption) string {
+	t.Helper()
+	var out bytes.Buffer
+	w := NewConsoleWriter(append([]ConsoleWriterOption{WithOutput(&out)}, opts...)...)
+	if err := w.WriteBatch(context.Background(), ticks); err != nil {
+		t.Fatalf("WriteBatch() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestConsoleWriter_VerboseTable(t *testing.T) {
+	noTxs := makeTick(2)
+	noTxs.Transactions = nil
+
+	tests := []struct {
+		name     string
+		format   OutputFormat
+		verbose  bool
+		tick     *domain.Tick
+		wantRows bool
+	}{
+		{"verbose table", FormatTable, true, makeTick(1), true},
+		{"plain table", FormatTable, false, makeTick(1), false},
+		{"verbose table without transactions", FormatTable, true, noTxs, false},
+		{"verbose has no effect on JSON", FormatCompact, true, makeTick(1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := writeConsole(t, []*domain.Tick{tt.tick}, WithFormat(tt.format), WithVerboseTable(tt.verbose))
+
+			if got := strings.Contains(out, "Tx Hash"); got != tt.wantRows {
+				t.Errorf("transaction header present = %v, want %v:\n%s", got, tt.wantRows, out)
+			}
+			for i, tx := range tt.tick.Transactions {
+				row := fmt.Sprintf("│ %-4d %-40s %12d %12d", i, tx.TxHash, tx.SequenceNumber, tx.Nonce)
+				if got := strings.Contains(out, row); got != tt.wantRows {
+					t.Errorf("row for %s present = %v, want %v:\n%s", tx.TxHash, got, tt.wantRows, out)
+				}
+			}
+		})
+	}
+}
+
+func TestConsoleWriter_Delimited(t *testing.T) {
+	quoted := makeTick(3)
+	quoted.BatchHash = "has,comma\tand tab"
+
+	tests := []struct {
+		name      string
+		format    OutputFormat
+		delimiter rune
+		batches   [][]*domain.Tick
+	}{
+		{"csv", FormatCSV, ',', [][]*domain.Tick{makeTicks(1, 2)}},
+		{"tsv", FormatTSV, '\t', [][]*domain.Tick{makeTicks(1, 2)}},
+		{"csv header once across batches", FormatCSV, ',', [][]*domain.Tick{makeTicks(1, 1), makeTicks(2, 3)}},
+		{"csv quotes delimiters", FormatCSV, ',', [][]*domain.Tick{{quoted}}},
+		{"tsv quotes delimiters", FormatTSV, '\t', [][]*domain.Tick{{quoted}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := NewConsoleWriter(WithOutput(&out), WithFormat(tt.format))
+			var want [][]string
+			for _, batch := range tt.batches {
+				if err := w.WriteBatch(context.Background(), batch); err != nil {
+					t.Fatalf("WriteBatch() error = %v", err)
+				}
+				for _, tick := range batch {
+					want = append(want, []string{
+						strconv.FormatUint(tick.TickNumber, 10),
+						tick.Timestamp.Format(time.RFC3339Nano),
+						tick.BatchHash,
+						strconv.Itoa(len(tick.Transactions)),
+						strconv.FormatUint(tick.VDFProof.Iterations, 10),
+					})
+				}
+			}
+
+			r := csv.NewReader(&out)
+			r.Comma = tt.delimiter
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("parse output: %v", err)
+			}
+			if len(records) == 0 || !slices.Equal(records[0], delimitedHeader) {
+				t.Fatalf("records = %q, want the header first", records)
+			}
+			if !slices.EqualFunc(records[1:], want, slices.Equal) {
+				t.Errorf("rows = %q, want %q", records[1:], want)
+			}
+		})
+	}
+}
+
+// summaryLines returns the summary lines in console output.
+func summaryLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "[SUMMARY]") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestConsoleWriter_Summary(t *testing.T) {
+	tests := []struct {
+		name      string
+		every     int
+		interval  time.Duration
+		batches   [][]*domain.Tick
+		pause     time.Duration // Between batches
+		wantTotal []string      // "Total ticks" of each summary line
+	}{
+		{"disabled", 0, 0, [][]*domain.Tick{makeTicks(1, 10)}, 0, nil},
+		{"every tick", 1, 0, [][]*domain.Tick{makeTicks(1, 3)}, 0, []string{"1", "2", "3"}},
+		{"every 4 ticks", 4, 0, [][]*domain.Tick{makeTicks(1, 3), makeTicks(4, 10)}, 0, []string{"4", "8"}},
+		{"interval not reached", 0, time.Hour, [][]*domain.Tick{makeTicks(1, 10)}, 0, nil},
+		{"interval elapsed", 0, 20 * time.Millisecond, [][]*domain.Tick{makeTicks(1, 2), makeTicks(3, 3)}, 40 * time.Millisecond, []string{"3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := NewConsoleWriter(WithOutput(&out), WithFormat(FormatCompact), WithSummary(tt.every, tt.interval))
+			for i, batch := range tt.batches {
+				if i > 0 {
+					time.Sleep(tt.pause)
+				}
+				if err := w.WriteBatch(context.Background(), batch); err != nil {
+					t.Fatalf("WriteBatch() error = %v", err)
+				}
+			}
+
+			lines := summaryLines(out.String())
+			if len(lines) != len(tt.wantTotal) {
+				t.Fatalf("summaries = %q, want %d", lines, len(tt.wantTotal))
+			}
+			for i, line := range lines {
+				if !strings.Contains(line, "Total ticks: "+tt.wantTotal[i]+" ") {
+					t.Errorf("summary %d = %q, want total ticks %s", i, line, tt.wantTotal[i])
+				}
+				if want := fmt.Sprintf("Avg tx/tick: %d.00", txPerTick); !strings.Contains(line, want) {
+					t.Errorf("summary %d = %q, want %q", i, line, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConsoleWriter_Color(t *testing.T) {
+	tests := []struct {
+		name   string
+		format OutputFormat
+		color  bool
+		want   []string
+	}{
+		{"table", FormatTable, true, []string{colorCyan + "Tick #1" + colorReset, colorGreen + "[SUMMARY]"}},
+		{"compact summary", FormatCompact, true, []string{colorGreen + "[SUMMARY]"}},
+		{"disabled", FormatTable, false, []string{"│ Tick #1\n", "\n[SUMMARY]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := writeConsole(t, makeTicks(1, 1), WithFormat(tt.format), WithColor(tt.color), WithSummary(1, 0))
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			if !tt.color && strings.Contains(out, "\033[") {
+				t.Errorf("uncolored output has escape codes:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestConsoleWriter_PayloadBase64(t *testing.T) {
+	// makeTick payloads are "payload".
+	const field = `"payload_base64":"cGF5bG9hZA=="`
+
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		enabled bool
+		want    int // Transactions with the field
+	}{
+		{"compact", FormatCompact, true, txPerTick},
+		{"compact, disabled", FormatCompact, false, 0},
+		{"pretty JSON", FormatJSON, true, txPerTick},
+		{"no effect on tables", FormatTable, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := writeConsole(t, makeTicks(1, 1), WithFormat(tt.format), WithPayloadBase64(tt.enabled))
+
+			// Pretty output puts a space after the colon.
+			compact := strings.ReplaceAll(out, `": "`, `":"`)
+			if got := strings.Count(compact, field); got != tt.want {
+				t.Errorf("%s appears %d times, want %d:\n%s", field, got, tt.want, out)
+			}
+			if tt.format != FormatTable && !strings.Contains(compact, `"payload":"7061796c6f6164"`) {
+				t.Errorf("output missing the hex payload:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a-much-longer-hash", 6, "a-much..."},
+		{"", 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := truncate(tt.in, tt.maxLen); got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
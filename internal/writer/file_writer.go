@@ -0,0 +1,281 @@
+package writer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+// segmentTimeFormat names rotated files after the UTC time they were opened.
+const segmentTimeFormat = "20060102T150405Z"
+
+// FileWriter appends ticks as JSON lines to rotating files for archival.
+// Each segment is named after the configured path plus its opening time, e.g.
+// ticks.jsonl becomes ticks-20240101T120000Z.jsonl. Writes are buffered and
+// flushed at the end of every Write/WriteBatch. It is safe for concurrent use.
+//
+// With compression, every Write/WriteBatch is finalized as its own gzip member
+// (concatenated members are a valid gzip stream), so a crash never leaves more
+// than the batch being written unreadable.
+type FileWriter struct {
+	dir    string // Directory of the configured path
+	prefix string // File name without extension
+	ext    string // Extension including the dot (e.g. ".jsonl")
+
+	maxBytes    int64         // Rotate once a segment reaches this size (0 = no size limit)
+	rotateEvery time.Duration // Rotate segments older than this (0 = no time limit)
+	compress    bool          // gzip segments
+
+	mu       sync.Mutex
+	file     *os.File
+	buf      *bufio.Writer
+	gz       *gzip.Writer // Open gzip member of the current batch (compression only)
+	written  int64        // Uncompressed bytes written to the current segment
+	openedAt time.Time    // When the current segment was opened
+	closed   bool
+}
+
+// FileWriterOption is a functional option for configuring FileWriter.
+type FileWriterOption func(*FileWriter)
+
+// WithMaxFileBytes rotates to a new file once the current one reaches n bytes.
+// A single tick is never split across files. 0 disables size-based rotation.
+func WithMaxFileBytes(n int64) FileWriterOption {
+	return func(w *FileWriter) {
+		w.maxBytes = n
+	}
+}
+
+// WithRotateInterval rotates to a new file once the current one is older than d.
+// 0 disables time-based rotation.
+func WithRotateInterval(d time.Duration) FileWriterOption {
+	return func(w *FileWriter) {
+		w.rotateEvery = d
+	}
+}
+
+// WithCompression gzip-compresses segments, which get a ".gz" suffix.
+// Size-based rotation still counts uncompressed bytes.
+func WithCompression(compress bool) FileWriterOption {
+	return func(w *FileWriter) {
+		w.compress = compress
+	}
+}
+
+// NewFileWriter creates a file writer for path (e.g. /var/lib/ticks/ticks.jsonl).
+// The directory must exist; segments are only created once ticks are written.
+func NewFileWriter(path string, opts ...FileWriterOption) (*FileWriter, error) {
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("output directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("output directory %s is not a directory", dir)
+	}
+
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	if ext == "" {
+		ext = ".jsonl"
+	}
+
+	w := &FileWriter{
+		dir:    dir,
+		prefix: strings.TrimSuffix(base, filepath.Ext(base)),
+		ext:    ext,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.compress {
+		w.ext += ".gz"
+	}
+
+	return w, nil
+}
+
+// Write appends a single tick and flushes it to the file.
+func (w *FileWriter) Write(ctx context.Context, tick *domain.Tick) error {
+	return w.WriteBatch(ctx, []*domain.Tick{tick})
+}
+
+// WriteBatch appends multiple ticks, one JSON line each, and flushes them to the file.
+func (w *FileWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("file writer is closed")
+	}
+
+	for _, tick := range ticks {
+		if err := w.writeTick(tick); err != nil {
+			return err
+		}
+	}
+
+	return w.flushSegment()
+}
+
+// Close flushes buffered ticks and closes the current file.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return w.closeSegment()
+}
+
+// writeTick encodes a tick as one line, rotating first if it doesn't fit the current segment.
+// Must be called with w.mu held.
+func (w *FileWriter) writeTick(tick *domain.Tick) error {
+	if tick == nil {
+		return fmt.Errorf("tick cannot be nil")
+	}
+
+	data, err := json.Marshal(tick)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tick %d: %w", tick.TickNumber, err)
+	}
+	data = append(data, '\n')
+
+	if w.file != nil && w.shouldRotate(int64(len(data))) {
+		if err := w.closeSegment(); err != nil {
+			return err
+		}
+	}
+
+	if w.file == nil {
+		if err := w.openSegment(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.output().Write(data)
+	w.written += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write tick %d: %w", tick.TickNumber, err)
+	}
+	return nil
+}
+
+// shouldRotate reports whether the current segment is full or too old for another n bytes.
+// An empty segment always takes the next tick, however large.
+func (w *FileWriter) shouldRotate(n int64) bool {
+	if w.written == 0 {
+		return false
+	}
+	if w.maxBytes > 0 && w.written+n > w.maxBytes {
+		return true
+	}
+	return w.rotateEvery > 0 && time.Since(w.openedAt) >= w.rotateEvery
+}
+
+// openSegment creates a new file named after the current time.
+// Must be called with w.mu held.
+func (w *FileWriter) openSegment() error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s", w.prefix, now.UTC().Format(segmentTimeFormat))
+
+	// Segments opened within the same second get a numeric suffix
+	for i := 0; ; i++ {
+		path := filepath.Join(w.dir, name+w.ext)
+		if i > 0 {
+			path = filepath.Join(w.dir, fmt.Sprintf("%s-%d%s", name, i, w.ext))
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+
+		w.file = file
+		w.buf = bufio.NewWriterSize(file, 256*1024)
+		w.written = 0
+		w.openedAt = now
+		return nil
+	}
+}
+
+// output returns where tick lines are written: the buffer, or with compression
+// the current batch's gzip member (started on first use).
+// Must be called with w.mu held.
+func (w *FileWriter) output() io.Writer {
+	if !w.compress {
+		return w.buf
+	}
+	if w.gz == nil {
+		w.gz = gzip.NewWriter(w.buf)
+	}
+	return w.gz
+}
+
+// flushSegment finalizes the open gzip member, if any, and flushes buffered
+// bytes to the current file.
+// Must be called with w.mu held.
+func (w *FileWriter) flushSegment() error {
+	if w.file == nil {
+		return nil
+	}
+
+	if w.gz != nil {
+		err := w.gz.Close()
+		w.gz = nil
+		if err != nil {
+			return fmt.Errorf("failed to finalize gzip member in %s: %w", w.file.Name(), err)
+		}
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush %s: %w", w.file.Name(), err)
+	}
+	return nil
+}
+
+// closeSegment flushes and closes the current file, if any.
+// Must be called with w.mu held.
+func (w *FileWriter) closeSegment() error {
+	if w.file == nil {
+		return nil
+	}
+
+	name := w.file.Name()
+	flushErr := w.flushSegment()
+	closeErr := w.file.Close()
+	w.file = nil
+	w.buf = nil
+
+	if flushErr != nil {
+		return flushErr
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close %s: %w", name, closeErr)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package writer
+
+import (
+	"bufio"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+// segmentOrder returns the opening time and same-second suffix of a segment file,
+// e.g. ("20240101T120000Z", 2) for ticks-20240101T120000Z-2.jsonl.
+func segmentOrder(path string) (string, int) {
+	name := strings.TrimPrefix(filepath.Base(path), "ticks-")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".jsonl")
+	opened, suffix, _ := strings.Cut(name, "-")
+	n, _ := strconv.Atoi(suffix)
+	return opened, n
+}
+
+// readSegments returns the tick numbers in each segment file in dir, oldest first.
+// Compressed segments are decompressed.
+func readSegments(t *testing.T, dir string) [][]uint64 {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "ticks-*.jsonl*"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	slices.SortFunc(paths, func(a, b string) int {
+		openedA, nA := segmentOrder(a)
+		openedB, nB := segmentOrder(b)
+		return cmp.Or(strings.Compare(openedA, openedB), cmp.Compare(nA, nB))
+	})
+
+	var segments [][]uint64
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(path, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("%s: %v", filepath.Base(path), err)
+			}
+			r = gz
+		}
+
+		var numbers []uint64
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var tick struct {
+				TickNumber   uint64            `json:"tick_number"`
+				Transactions []json.RawMessage `json:"transactions"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &tick); err != nil {
+				t.Fatalf("%s: invalid JSON line %q: %v", filepath.Base(path), scanner.Text(), err)
+			}
+			if len(tick.Transactions) != txPerTick {
+				t.Errorf("%s: tick %d has %d transactions, want %d", filepath.Base(path), tick.TickNumber, len(tick.Transactions), txPerTick)
+			}
+			numbers = append(numbers, tick.TickNumber)
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		segments = append(segments, numbers)
+	}
+	return segments
+}
+
+// lineBytes is the size of the makeTick(n) line, for size-based rotation.
+// Lines grow slightly with n.
+func lineBytes(t *testing.T, n uint64) int64 {
+	t.Helper()
+	data, err := json.Marshal(makeTick(n))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	return int64(len(data) + 1)
+}
+
+func TestFileWriter(t *testing.T) {
+	line := lineBytes(t, 5)
+
+	tests := []struct {
+		name    string
+		opts    []FileWriterOption
+		batches [][]*domain.Tick
+		want    [][]uint64 // Tick numbers in each segment
+	}{
+		{"nothing written", nil, nil, nil},
+		{"one batch", nil, [][]*domain.Tick{makeTicks(1, 3)}, [][]uint64{{1, 2, 3}}},
+		{"appends batches", nil, [][]*domain.Tick{makeTicks(1, 2), makeTicks(3, 4), {makeTick(5)}}, [][]uint64{{1, 2, 3, 4, 5}}},
+		{
+			"rotates by size",
+			[]FileWriterOption{WithMaxFileBytes(2 * line)},
+			[][]*domain.Tick{makeTicks(1, 5)},
+			[][]uint64{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			"oversized tick gets its own segment",
+			[]FileWriterOption{WithMaxFileBytes(1)},
+			[][]*domain.Tick{makeTicks(1, 2)},
+			[][]uint64{{1}, {2}},
+		},
+		{
+			"rotates by age",
+			[]FileWriterOption{WithRotateInterval(time.Nanosecond)},
+			[][]*domain.Tick{makeTicks(1, 2), {makeTick(3)}},
+			[][]uint64{{1}, {2}, {3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := NewFileWriter(filepath.Join(dir, "ticks.jsonl"), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFileWriter() error = %v", err)
+			}
+
+			for _, batch := range tt.batches {
+				if err := w.WriteBatch(context.Background(), batch); err != nil {
+					t.Fatalf("WriteBatch() error = %v", err)
+				}
+			}
+			// Batches are flushed as they're written.
+			if got := readSegments(t, dir); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("before Close: segments = %v, want %v", got, tt.want)
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := readSegments(t, dir); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("segments = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileWriter_Compression(t *testing.T) {
+	line := lineBytes(t, 5)
+
+	tests := []struct {
+		name    string
+		opts    []FileWriterOption
+		batches [][]*domain.Tick
+		want    [][]uint64 // Tick numbers in each segment
+	}{
+		{"one batch", nil, [][]*domain.Tick{makeTicks(1, 3)}, [][]uint64{{1, 2, 3}}},
+		{"batch per gzip member", nil, [][]*domain.Tick{makeTicks(1, 2), {makeTick(3)}, makeTicks(4, 5)}, [][]uint64{{1, 2, 3, 4, 5}}},
+		{
+			// Sizes count uncompressed bytes.
+			"rotates by size",
+			[]FileWriterOption{WithMaxFileBytes(2 * line)},
+			[][]*domain.Tick{makeTicks(1, 3), makeTicks(4, 5)},
+			[][]uint64{{1, 2}, {3, 4}, {5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := NewFileWriter(filepath.Join(dir, "ticks.jsonl"), append(tt.opts, WithCompression(true))...)
+			if err != nil {
+				t.Fatalf("NewFileWriter() error = %v", err)
+			}
+
+			for _, batch := range tt.batches {
+				if err := w.WriteBatch(context.Background(), batch); err != nil {
+					t.Fatalf("WriteBatch() error = %v", err)
+				}
+			}
+			// Every batch is a finished gzip member, so a crash before Close loses nothing.
+			if got := readSegments(t, dir); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("before Close: segments = %v, want %v", got, tt.want)
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := readSegments(t, dir); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("segments = %v, want %v", got, tt.want)
+			}
+			if plain, _ := filepath.Glob(filepath.Join(dir, "*.jsonl")); len(plain) != 0 {
+				t.Errorf("uncompressed segments %v, want only .jsonl.gz", plain)
+			}
+		})
+	}
+}
+
+func TestFileWriter_Errors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		write   func(w *FileWriter) error
+		wantErr string
+	}{
+		{"missing directory", filepath.Join(dir, "missing", "ticks.jsonl"), nil, "output directory"},
+		{"not a directory", filepath.Join(file, "ticks.jsonl"), nil, "is not a directory"},
+		{"nil tick", filepath.Join(dir, "ticks.jsonl"), func(w *FileWriter) error {
+			return w.Write(context.Background(), nil)
+		}, "tick cannot be nil"},
+		{"write after close", filepath.Join(dir, "ticks.jsonl"), func(w *FileWriter) error {
+			w.Close()
+			return w.Write(context.Background(), makeTick(1))
+		}, "file writer is closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewFileWriter(tt.path)
+			if tt.write != nil {
+				if err != nil {
+					t.Fatalf("NewFileWriter() error = %v", err)
+				}
+				defer w.Close()
+				err = tt.write(w)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileWriter_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(filepath.Join(dir, "ticks.jsonl"), WithMaxFileBytes(5*lineBytes(t, 200)))
+	if err != nil {
+		t.Fatalf("NewFileWriter() error = %v", err)
+	}
+
+	const writers, perWriter = 8, 25
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range perWriter {
+				n := uint64(i*perWriter + j + 1)
+				if err := w.Write(context.Background(), makeTick(n)); err != nil {
+					t.Errorf("Write() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Every tick is written exactly once, as a whole line.
+	var got []uint64
+	for _, segment := range readSegments(t, dir) {
+		if len(segment) > 5 {
+			t.Errorf("segment of %d ticks, want at most 5", len(segment))
+		}
+		got = append(got, segment...)
+	}
+	slices.Sort(got)
+	for i, n := range got {
+		if n != uint64(i+1) {
+			t.Fatalf("ticks written = %v, want 1 to %d once each", got, writers*perWriter)
+		}
+	}
+	if len(got) != writers*perWriter {
+		t.Errorf("wrote %d ticks, want %d", len(got), writers*perWriter)
+	}
+}
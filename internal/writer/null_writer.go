@@ -0,0 +1,58 @@
+package writer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+// NullWriter discards ticks without persisting them.
+// It is used for dry runs that exercise the stream reader, parser and pipeline
+// (including metrics) without touching a database. It is safe for concurrent use.
+type NullWriter struct {
+	ticks        atomic.Uint64
+	transactions atomic.Uint64
+}
+
+// NewNullWriter creates a new null writer.
+func NewNullWriter() *NullWriter {
+	return &NullWriter{}
+}
+
+// Write discards a single tick.
+func (w *NullWriter) Write(ctx context.Context, tick *domain.Tick) error {
+	if tick == nil {
+		return fmt.Errorf("tick cannot be nil")
+	}
+
+	w.ticks.Add(1)
+	w.transactions.Add(uint64(len(tick.Transactions)))
+	return nil
+}
+
+// WriteBatch discards multiple ticks.
+func (w *NullWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick) error {
+	for _, tick := range ticks {
+		if err := w.Write(ctx, tick); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TickCount returns the number of ticks discarded so far.
+func (w *NullWriter) TickCount() uint64 {
+	return w.ticks.Load()
+}
+
+// TransactionCount returns the number of transactions discarded so far.
+func (w *NullWriter) TransactionCount() uint64 {
+	return w.transactions.Load()
+}
+
+// Close is a no-op for the null writer.
+func (w *NullWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package writer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/fermilabs/fermi-api-gateway/internal/domain"
+)
+
+func TestNullWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		batches   [][]*domain.Tick
+		wantTicks uint64
+		wantTxs   uint64
+		wantErr   bool
+	}{
+		{"nothing written", nil, 0, 0, false},
+		{"single batch", [][]*domain.Tick{makeTicks(1, 4)}, 4, 4 * txPerTick, false},
+		{"several batches", [][]*domain.Tick{makeTicks(1, 2), makeTicks(3, 3), {}}, 3, 3 * txPerTick, false},
+		{"nil tick", [][]*domain.Tick{{makeTick(1), nil, makeTick(2)}}, 1, txPerTick, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewNullWriter()
+			var err error
+			for _, batch := range tt.batches {
+				if batchErr := w.WriteBatch(context.Background(), batch); batchErr != nil {
+					err = batchErr
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WriteBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if w.TickCount() != tt.wantTicks || w.TransactionCount() != tt.wantTxs {
+				t.Errorf("counts = %d ticks, %d txs; want %d, %d", w.TickCount(), w.TransactionCount(), tt.wantTicks, tt.wantTxs)
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestNullWriter_Concurrent(t *testing.T) {
+	w := NewNullWriter()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			from := uint64(i*10 + 1)
+			w.WriteBatch(context.Background(), makeTicks(from, from+9))
+		}()
+	}
+	wg.Wait()
+
+	if w.TickCount() != 80 || w.TransactionCount() != 80*txPerTick {
+		t.Errorf("counts = %d ticks, %d txs; want 80, %d", w.TickCount(), w.TransactionCount(), 80*txPerTick)
+	}
+}
@@ -2,6 +2,7 @@ package writer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/fermilabs/fermi-api-gateway/internal/domain"
@@ -13,16 +14,55 @@ import (
 // TimescaleWriter writes ticks to TimescaleDB using pgx COPY protocol.
 // This is 10-50x faster than individual INSERT statements.
 type TimescaleWriter struct {
-	pool   *pgxpool.Pool
-	logger *zap.Logger
+	pool       *pgxpool.Pool
+	logger     *zap.Logger
+	checkpoint string // Checkpoint name (empty = checkpointing disabled)
+}
+
+// TimescaleWriterOption is a functional option for configuring TimescaleWriter.
+type TimescaleWriterOption func(*TimescaleWriter)
+
+// WithCheckpoint advances the named checkpoint (schema/003) in the same
+// transaction as every batch and skips rows that are already stored, so ticks
+// replayed from behind the checkpoint after a crash are not written twice.
+func WithCheckpoint(name string) TimescaleWriterOption {
+	return func(w *TimescaleWriter) {
+		w.checkpoint = name
+	}
 }
 
 // NewTimescaleWriter creates a new TimescaleDB writer.
-func NewTimescaleWriter(pool *pgxpool.Pool, logger *zap.Logger) *TimescaleWriter {
-	return &TimescaleWriter{
+func NewTimescaleWriter(pool *pgxpool.Pool, logger *zap.Logger, opts ...TimescaleWriterOption) *TimescaleWriter {
+	w := &TimescaleWriter{
 		pool:   pool,
 		logger: logger,
 	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Checkpoint returns the highest tick number committed under the configured
+// checkpoint. ok is false if checkpointing is disabled or nothing was committed yet.
+func (w *TimescaleWriter) Checkpoint(ctx context.Context) (tickNumber uint64, ok bool, err error) {
+	if w.checkpoint == "" {
+		return 0, false, nil
+	}
+
+	err = w.pool.QueryRow(ctx,
+		`SELECT tick_number FROM ingest_checkpoints WHERE name = $1`,
+		w.checkpoint,
+	).Scan(&tickNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to load checkpoint %q: %w", w.checkpoint, err)
+	}
+	return tickNumber, true, nil
 }
 
 // Write writes a single tick (uses WriteBatch internally).
@@ -35,6 +75,9 @@ func (w *TimescaleWriter) Write(ctx context.Context, tick *domain.Tick) error {
 // 1. ticks (tick metadata)
 // 2. vdf_proofs (VDF proof data)
 // 3. tick_transactions (transaction data)
+// With a checkpoint, rows are copied into temporary staging tables and merged
+// with ON CONFLICT DO NOTHING, so replayed ticks are skipped even when another
+// worker commits them concurrently, and the checkpoint is advanced before commit.
 func (w *TimescaleWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick) error {
 	if len(ticks) == 0 {
 		return nil
@@ -47,21 +90,39 @@ func (w *TimescaleWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick)
 	}
 	defer tx.Rollback(ctx) // Rollback if not committed
 
+	tables := targetTables
+	if w.checkpoint != "" {
+		if err := createStaging(ctx, tx); err != nil {
+			return fmt.Errorf("failed to create staging tables: %w", err)
+		}
+		tables = stagingTables
+	}
+
 	// 1. Insert ticks using COPY
-	if err := w.copyTicks(ctx, tx, ticks); err != nil {
+	if err := w.copyTicks(ctx, tx, tables.ticks, ticks); err != nil {
 		return fmt.Errorf("failed to copy ticks: %w", err)
 	}
 
 	// 2. Insert VDF proofs using COPY
-	if err := w.copyVDFProofs(ctx, tx, ticks); err != nil {
+	if err := w.copyVDFProofs(ctx, tx, tables.vdfProofs, ticks); err != nil {
 		return fmt.Errorf("failed to copy vdf_proofs: %w", err)
 	}
 
 	// 3. Insert transactions using COPY
-	if err := w.copyTransactions(ctx, tx, ticks); err != nil {
+	if err := w.copyTransactions(ctx, tx, tables.transactions, ticks); err != nil {
 		return fmt.Errorf("failed to copy transactions: %w", err)
 	}
 
+	// 4. Merge the staged rows and advance the checkpoint atomically with the batch
+	if w.checkpoint != "" {
+		if err := w.mergeStaging(ctx, tx, len(ticks)); err != nil {
+			return fmt.Errorf("failed to merge staged rows: %w", err)
+		}
+		if err := w.advanceCheckpoint(ctx, tx, ticks); err != nil {
+			return fmt.Errorf("failed to advance checkpoint: %w", err)
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -76,12 +137,84 @@ func (w *TimescaleWriter) WriteBatch(ctx context.Context, ticks []*domain.Tick)
 	return nil
 }
 
+// batchTables names the tables a batch is copied into.
+type batchTables struct {
+	ticks        string
+	vdfProofs    string
+	transactions string
+}
+
+var (
+	targetTables  = batchTables{ticks: "ticks", vdfProofs: "vdf_proofs", transactions: "tick_transactions"}
+	stagingTables = batchTables{ticks: "staged_ticks", vdfProofs: "staged_vdf_proofs", transactions: "staged_tick_transactions"}
+)
+
+// stagedPairs lists each staging table with its target, parents before children.
+var stagedPairs = [][2]string{
+	{stagingTables.ticks, targetTables.ticks},
+	{stagingTables.vdfProofs, targetTables.vdfProofs},
+	{stagingTables.transactions, targetTables.transactions},
+}
+
+// createStaging creates the staging tables for one batch. They are dropped on commit.
+func createStaging(ctx context.Context, tx pgx.Tx) error {
+	for _, pair := range stagedPairs {
+		staged, target := pgx.Identifier{pair[0]}.Sanitize(), pgx.Identifier{pair[1]}.Sanitize()
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			`CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP`, staged, target,
+		)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeStaging inserts the staged rows into their target tables, skipping rows
+// that are already stored. Concurrent inserts of the same tick wait for each
+// other instead of failing with a duplicate key.
+func (w *TimescaleWriter) mergeStaging(ctx context.Context, tx pgx.Tx, tickCount int) error {
+	for i, pair := range stagedPairs {
+		staged, target := pgx.Identifier{pair[0]}.Sanitize(), pgx.Identifier{pair[1]}.Sanitize()
+		tag, err := tx.Exec(ctx, fmt.Sprintf(
+			`INSERT INTO %s SELECT * FROM %s ON CONFLICT DO NOTHING`, target, staged,
+		))
+		if err != nil {
+			return fmt.Errorf("%s: %w", pair[1], err)
+		}
+		if i == 0 && tag.RowsAffected() < int64(tickCount) {
+			w.logger.Debug("Skipped already-stored ticks",
+				zap.Int64("skipped", int64(tickCount)-tag.RowsAffected()),
+			)
+		}
+	}
+	return nil
+}
+
+// advanceCheckpoint raises the checkpoint to the highest tick in the batch.
+// Batches commit out of order across workers, so it never moves backwards.
+func (w *TimescaleWriter) advanceCheckpoint(ctx context.Context, tx pgx.Tx, ticks []*domain.Tick) error {
+	var highest uint64
+	for _, tick := range ticks {
+		highest = max(highest, tick.TickNumber)
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO ingest_checkpoints (name, tick_number, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET tick_number = GREATEST(ingest_checkpoints.tick_number, EXCLUDED.tick_number),
+		    updated_at = NOW()`,
+		w.checkpoint, highest,
+	)
+	return err
+}
+
 // copyTicks uses pgx COPY to bulk insert ticks.
-func (w *TimescaleWriter) copyTicks(ctx context.Context, tx pgx.Tx, ticks []*domain.Tick) error {
+func (w *TimescaleWriter) copyTicks(ctx context.Context, tx pgx.Tx, table string, ticks []*domain.Tick) error {
 	// COPY ticks (tick_number, timestamp, batch_hash) FROM STDIN
 	_, err := tx.CopyFrom(
 		ctx,
-		pgx.Identifier{"ticks"},
+		pgx.Identifier{table},
 		[]string{"tick_number", "timestamp", "batch_hash"},
 		pgx.CopyFromSlice(len(ticks), func(i int) ([]any, error) {
 			tick := ticks[i]
@@ -97,11 +230,11 @@ func (w *TimescaleWriter) copyTicks(ctx context.Context, tx pgx.Tx, ticks []*dom
 }
 
 // copyVDFProofs uses pgx COPY to bulk insert VDF proofs.
-func (w *TimescaleWriter) copyVDFProofs(ctx context.Context, tx pgx.Tx, ticks []*domain.Tick) error {
+func (w *TimescaleWriter) copyVDFProofs(ctx context.Context, tx pgx.Tx, table string, ticks []*domain.Tick) error {
 	// COPY vdf_proofs (tick_number, input, output, proof, iterations) FROM STDIN
 	_, err := tx.CopyFrom(
 		ctx,
-		pgx.Identifier{"vdf_proofs"},
+		pgx.Identifier{table},
 		[]string{"tick_number", "input", "output", "proof", "iterations"},
 		pgx.CopyFromSlice(len(ticks), func(i int) ([]any, error) {
 			tick := ticks[i]
@@ -119,7 +252,7 @@ func (w *TimescaleWriter) copyVDFProofs(ctx context.Context, tx pgx.Tx, ticks []
 }
 
 // copyTransactions uses pgx COPY to bulk insert all transactions from all ticks.
-func (w *TimescaleWriter) copyTransactions(ctx context.Context, tx pgx.Tx, ticks []*domain.Tick) error {
+func (w *TimescaleWriter) copyTransactions(ctx context.Context, tx pgx.Tx, table string, ticks []*domain.Tick) error {
 	// Count total transactions
 	totalTxs := 0
 	for _, tick := range ticks {
@@ -134,7 +267,7 @@ func (w *TimescaleWriter) copyTransactions(ctx context.Context, tx pgx.Tx, ticks
 	// COPY tick_transactions (tx_hash, tx_id, tick_number, sequence_number, payload, signature, public_key, nonce, timestamp, tick_timestamp) FROM STDIN
 	_, err := tx.CopyFrom(
 		ctx,
-		pgx.Identifier{"tick_transactions"},
+		pgx.Identifier{table},
 		[]string{"tx_hash", "tx_id", "tick_number", "sequence_number", "payload", "signature", "public_key", "nonce", "timestamp", "tick_timestamp"},
 		pgx.CopyFromSlice(totalTxs, func(i int) ([]any, error) {
 			// Find which tick and transaction this index corresponds to
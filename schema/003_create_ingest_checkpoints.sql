@@ -0,0 +1,25 @@
+-- Migration: 003_create_ingest_checkpoints
+-- Description: Adds the checkpoint table used by the tick ingester to resume after a crash
+-- Date: 2026-10-15
+
+-- ==============================================================================
+-- INGEST CHECKPOINTS TABLE
+-- ==============================================================================
+-- One row per ingester (CHECKPOINT_NAME), advanced in the same transaction as
+-- each tick batch, so the checkpoint never runs ahead of committed ticks.
+-- tick_number is the highest tick committed; with parallel batch writers some
+-- lower ticks may still be in flight, so the ingester resumes a replay window
+-- behind it and skips ticks that are already stored.
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS ingest_checkpoints (
+    name TEXT PRIMARY KEY,
+    tick_number BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- ==============================================================================
+-- ROLLBACK
+-- ==============================================================================
+-- DROP TABLE IF EXISTS ingest_checkpoints;
+-- ==============================================================================